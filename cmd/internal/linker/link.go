@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/DDP-Projekt/Kompilierer/cmd/internal/gcc"
@@ -33,6 +34,16 @@ type Options struct {
 	ExternGCCFlags string
 	// wether the inbuilt list-defs need to be linked in
 	LinkInListDefs bool
+	// wether a shared library is created instead of an executable
+	// the runtime, stdlib, list-defs and MainFile are not linked in,
+	// they are provided by the executable that loads the library (see ExportRuntime)
+	// InputFile must be position independent code
+	Shared bool
+	// wether the whole runtime and stdlib are linked in and exported
+	// so that shared libraries loaded at runtime (see Shared) can use them
+	// the functions the stdlib uses from Duden modules (e.g. Setze_Fehler) are left unresolved
+	// until the shared library containing the module is loaded
+	ExportRuntime bool
 }
 
 func validateOptions(options *Options) error {
@@ -66,6 +77,9 @@ func LinkDDPFiles(options Options) ([]byte, error) {
 		extern_gcc_flags = []string{}
 	}
 	extern_gcc_flags = append(extern_gcc_flags, "-I"+filepath.Join(ddppath.Lib, "runtime/include/"), "-I"+filepath.Join(ddppath.Lib, "stdlib/include"))
+	if options.Shared {
+		extern_gcc_flags = append(extern_gcc_flags, "-fPIC")
+	}
 
 	var (
 		link_objects = map[string][]string{}       // library-search-paths to library-filename map
@@ -76,7 +90,7 @@ func LinkDDPFiles(options Options) ([]byte, error) {
 	if options.DeleteIntermediateFiles {
 		defer options.Log("Lösche temporäre Dateien")
 	}
-	for path := range options.Dependencies.Dependencies {
+	for _, path := range options.Dependencies.SortedDependencies() {
 		filename := filepath.Base(path)
 		// stdlib and runtime are linked by default
 		// ignore them because of the Duden
@@ -109,9 +123,19 @@ func LinkDDPFiles(options Options) ([]byte, error) {
 	}
 
 	args := append(make([]string, 0), "-o", options.OutputFile, "-O2", "-L"+ddppath.Lib)
+	if options.Shared {
+		args = append(args, "-shared")
+	}
 
-	// add all librarie-search-paths
+	// sort the library-search-paths to get a deterministic command
+	lib_paths := make([]string, 0, len(link_objects))
 	for k := range link_objects {
+		lib_paths = append(lib_paths, k)
+	}
+	slices.Sort(lib_paths)
+
+	// add all librarie-search-paths
+	for _, k := range lib_paths {
 		args = append(args, "-L"+k)
 	}
 
@@ -119,20 +143,29 @@ func LinkDDPFiles(options Options) ([]byte, error) {
 	args = append(args, input_files...)
 
 	// add external dependencies
-	for _, libs := range link_objects {
-		for _, lib := range libs {
+	for _, k := range lib_paths {
+		for _, lib := range link_objects[k] {
 			args = append(args, "-l:"+lib)
 		}
 	}
 
 	// add default dependencies at the end, because dependencies might depend on the ddp runtime and list_types_defs
-	args = append(args, "-lddpstdlib")
-	if options.LinkInListDefs {
-		args = append(args, ddppath.DDP_List_Types_Defs_O)
+	if !options.Shared {
+		if options.ExportRuntime {
+			args = append(args, "-rdynamic", "-Wl,--whole-archive")
+		}
+		args = append(args, "-lddpstdlib")
+		if options.LinkInListDefs {
+			args = append(args, ddppath.DDP_List_Types_Defs_O)
+		}
+		args = append(args, "-lddpruntime")
+		if options.ExportRuntime {
+			args = append(args, "-Wl,--no-whole-archive", "-Wl,--unresolved-symbols=ignore-in-object-files", "-Wl,-z,lazy", "-ldl")
+		}
+		args = append(args, "-lm")
+		args = append(args, options.MainFile)
+		args = append(args, "-lpcre2-8")
 	}
-	args = append(args, "-lddpruntime", "-lm")
-	args = append(args, options.MainFile)
-	args = append(args, "-lpcre2-8")
 
 	// add additional gcc-flags such as other needed libraries
 	if options.GCCFlags != "" {
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/spf13/cobra"
+)
+
+var astDiffCmd = &cobra.Command{
+	Use:   "ast-diff <alte Datei> <neue Datei>",
+	Short: "Gibt die Unterschiede zwischen den Syntaxbäumen zweier .ddp Dateien aus",
+	Long: `Parst beide .ddp Dateien und gibt aus, welche Knoten des Abstrakten Syntaxbaums hinzugefügt, entfernt oder geändert wurden.
+Kommentare, Formatierung und Klammern werden ignoriert, daher kann so geprüft werden, ob eine Umformatierung oder mechanische Änderung die Bedeutung des Programms verändert hat.
+Eingebundene Module werden nicht verglichen, nur welche Symbole woher eingebunden werden.
+Wurden Unterschiede gefunden, wird der Befehl mit Exit Code 1 beendet.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		oldModule, err := parseForDiff(args[0])
+		if err != nil {
+			return err
+		}
+		newModule, err := parseForDiff(args[1])
+		if err != nil {
+			return err
+		}
+
+		differences := ast.Diff(oldModule.Ast, newModule.Ast)
+		writeDifferences(os.Stdout, args[0], args[1], differences)
+		if len(differences) > 0 {
+			return fmt.Errorf("Es wurden %d Unterschiede gefunden", len(differences))
+		}
+		return nil
+	},
+}
+
+// parses the file at filePath and reports its errors
+// returns an error if the file could not be read or contains errors
+func parseForDiff(filePath string) (*ast.Module, error) {
+	if filepath.Ext(filePath) != ".ddp" {
+		return nil, fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+	}
+
+	src, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+	}
+
+	errorCount := 0
+	errorHandler := makeErrorHandler(filePath, src)
+	module, err := parser.Parse(parser.Options{
+		FileName: filePath,
+		Source:   src,
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Level == ddperror.LEVEL_ERROR {
+				errorCount++
+				errorHandler(err)
+			}
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+	}
+
+	if errorCount > 0 {
+		return nil, fmt.Errorf("Es wurden %d Fehler in %s gefunden", errorCount, filePath)
+	}
+	return module, nil
+}
+
+// writes one line per difference to w
+func writeDifferences(w io.Writer, oldPath, newPath string, differences []ast.Difference) {
+	position := func(path string, node ast.Node) string {
+		start := node.GetRange().Start
+		return fmt.Sprintf("%s (Z: %d, S: %d)", path, start.Line, start.Column)
+	}
+
+	for _, diff := range differences {
+		switch diff.Kind {
+		case ast.DIFF_ADDED:
+			fmt.Fprintf(w, "+ %s: %s\n", position(newPath, diff.New), diff.NewDesc)
+		case ast.DIFF_REMOVED:
+			fmt.Fprintf(w, "- %s: %s\n", position(oldPath, diff.Old), diff.OldDesc)
+		case ast.DIFF_CHANGED:
+			fmt.Fprintf(w, "~ %s -> %s: %s -> %s\n", position(oldPath, diff.Old), position(newPath, diff.New), diff.OldDesc, diff.NewDesc)
+		}
+	}
+}
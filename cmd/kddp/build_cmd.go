@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"text/tabwriter"
+	"time"
 
 	"github.com/DDP-Projekt/Kompilierer/cmd/internal/gcc"
 	"github.com/DDP-Projekt/Kompilierer/cmd/internal/linker"
@@ -15,7 +17,7 @@ import (
 )
 
 var buildCmd = &cobra.Command{
-	Use:   "kompiliere [-o Ausgabe-Datei [--main main.o] [--gcc-flags GCC-Flags] [--extern-gcc-flags Externe-GCC-Flags] [--nodeletes] [--verbose] [--link-modules] [--link-list-defs] [--gcc-executable Pfad-zu-GCC>] <Datei>",
+	Use:   "kompiliere [-o Ausgabe-Datei [--main main.o] [--gcc-flags GCC-Flags] [--extern-gcc-flags Externe-GCC-Flags] [--nodeletes] [--verbose] [--link-modules] [--link-list-defs] [--gcc-executable Pfad-zu-GCC>] [--emit-llvm] [--emit-llvm-only] [--ignoriere-warnungen Codes] [--timings] [--speicher-profil] [--verifiziere-ir] <Datei>",
 	Short: "Kompiliert eine .ddp Datei",
 	Long:  `Kompiliert eine .ddp Datei in eine ausführbare, llvm oder objekt Datei.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -67,11 +69,13 @@ var buildCmd = &cobra.Command{
 			targetExe = true
 		}
 
-		// disable comments if the .ll files are deleted anyways
-		if compOutType != compiler.OutputIR && !buildNoDeletes {
-			compiler.Comments_Enabled = false
+		if buildEmitLLVMOnly { // stop after generating llvm ir
+			compOutType, extension, targetExe = compiler.OutputIR, ".ll", false
 		}
 
+		// disable comments if the .ll files are deleted anyways
+		disableComments := compOutType != compiler.OutputIR && !buildNoDeletes && !buildEmitLLVM
+
 		// create the path to the output file
 		if buildOutputPath == "" { // if no output file was specified, we use the name of the input .ddp file
 			if filePath == "" {
@@ -111,6 +115,19 @@ var buildCmd = &cobra.Command{
 		}
 		defer to.Close()
 
+		// the .ll file lands next to the output file
+		var irOut io.Writer
+		if buildEmitLLVM && compOutType != compiler.OutputIR {
+			llPath := changeExtension(buildOutputPath, ".ll")
+			print("Schreibe llvm-ir nach %s", llPath)
+			llFile, err := os.OpenFile(llPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+			if err != nil {
+				return err
+			}
+			defer llFile.Close()
+			irOut = llFile
+		}
+
 		var src []byte
 		// if no input file was specified, we read from stdin
 		if filePath == "" {
@@ -124,7 +141,17 @@ var buildCmd = &cobra.Command{
 			}
 		}
 
-		errorHandler := ddperror.MakeAdvancedHandler(filePath, src, os.Stderr)
+		ignoredWarnings := make([]ddperror.Code, 0, len(buildIgnoredWarnings))
+		for _, code := range buildIgnoredWarnings {
+			ignoredWarnings = append(ignoredWarnings, ddperror.Code(code))
+		}
+		errorHandler := ddperror.MakeWarningFilter(makeErrorHandler(filePath, src), ignoredWarnings...)
+
+		// printed even if an error occured, to see where the time was spent
+		timings, linking := &compiler.Timings{}, time.Duration(0)
+		if buildTimings {
+			defer func() { writeTimings(os.Stderr, timings, linking) }()
+		}
 
 		print("Kompiliere DDP-Quellcode nach %s", buildOutputPath)
 		result, err := compiler.Compile(compiler.Options{
@@ -132,6 +159,7 @@ var buildCmd = &cobra.Command{
 			Source:                  src,
 			From:                    nil,
 			To:                      to,
+			IROut:                   irOut,
 			OutputType:              compOutType,
 			ErrorHandler:            errorHandler,
 			Log:                     print,
@@ -139,6 +167,10 @@ var buildCmd = &cobra.Command{
 			LinkInModules:           buildLinkModules,
 			LinkInListDefs:          buildLinkListDefs,
 			OptimizationLevel:       buildOptimizationLevel,
+			MemoryProfiling:         buildMemoryProfiling,
+			Verify:                  buildVerify,
+			DisableComments:         disableComments,
+			Timings:                 timings,
 		})
 		if err != nil {
 			return fmt.Errorf("Fehler beim Kompilieren: %w", err)
@@ -150,6 +182,8 @@ var buildCmd = &cobra.Command{
 
 		// the target is an executable so we link the produced object file
 		print("Objekte werden gelinkt")
+		linkStart := time.Now()
+		defer func() { linking = time.Since(linkStart) }()
 		if output, err := linker.LinkDDPFiles(linker.Options{
 			InputFile:               objPath,
 			OutputFile:              buildOutputPath,
@@ -177,6 +211,12 @@ var (
 	buildLinkListDefs      bool   // flag for kompiliere
 	buildGCCExecutable     string // flag for kompiliere
 	buildOptimizationLevel uint   // flag for kompiliere
+	buildEmitLLVM          bool   // flag for kompiliere
+	buildEmitLLVMOnly      bool   // flag for kompiliere
+	buildIgnoredWarnings   []uint // flag for kompiliere
+	buildTimings           bool   // flag for kompiliere
+	buildMemoryProfiling   bool   // flag for kompiliere
+	buildVerify            bool   // flag for kompiliere
 )
 
 func init() {
@@ -189,6 +229,36 @@ func init() {
 	buildCmd.Flags().BoolVar(&buildLinkListDefs, "list-defs-linken", true, "Ob die eingebauten Listen Definitionen in das Hauptmodul gelinkt werden sollen")
 	buildCmd.Flags().StringVar(&buildGCCExecutable, "gcc-executable", gcc.Cmd(), "Pfad zur gcc executable, die genutzt werden soll")
 	buildCmd.Flags().UintVarP(&buildOptimizationLevel, "optimierungs-stufe", "O", 1, "Menge und Art der Optimierungen, die angewandt werden")
+	buildCmd.Flags().BoolVar(&buildEmitLLVM, "emit-llvm", false, "Schreibt zusätzlich das llvm-ir als .ll Datei neben die Ausgabedatei")
+	buildCmd.Flags().BoolVar(&buildEmitLLVMOnly, "emit-llvm-only", false, "Erzeugt nur das llvm-ir (als .ll Datei neben der Ausgabedatei) ohne gcc aufzurufen")
+	buildCmd.Flags().UintSliceVar(&buildIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+	buildCmd.Flags().BoolVar(&buildTimings, "timings", false, "Gibt auf stderr aus, wie lange die einzelnen Phasen des Kompilierens gedauert haben")
+	buildCmd.Flags().BoolVar(&buildMemoryProfiling, "speicher-profil", false, "Das kompilierte Programm gibt beim Beenden auf stderr aus, welcher Speicher nie freigegeben wurde")
+	buildCmd.Flags().BoolVar(&buildVerify, "verifiziere-ir", false, "Prüft das erzeugte llvm-ir auf Fehler des Kompilierers, bevor es an llvm übergeben wird (in Debug-Builds immer aktiv)")
+}
+
+// writes how long each phase took to w
+// linking is the time spent linking the executable
+func writeTimings(w io.Writer, timings *compiler.Timings, linking time.Duration) {
+	phases := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"Scannen", timings.Scanning},
+		{"Parsen", timings.Parsing},
+		{"Auflösen", timings.Resolving},
+		{"Typprüfung", timings.Typechecking},
+		{"Kompilieren", timings.Compiling},
+		{"LLVM", timings.LLVM},
+		{"Linken", linking},
+		{"Gesamt", timings.Total() + linking},
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, phase := range phases {
+		fmt.Fprintf(tw, "%s:\t%s\n", phase.name, phase.duration.Round(time.Microsecond))
+	}
+	tw.Flush()
 }
 
 // helper function
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/parser/typechecker"
+	"github.com/spf13/cobra"
+)
+
+var builtinsCmd = &cobra.Command{
+	Use:     "builtins",
+	Aliases: []string{"operatoren"},
+	Short:   "Listet die eingebauten Operatoren auf",
+	Long: `Listet alle eingebauten Operatoren mit den Typen ihrer Operanden und ihrem Rückgabetyp auf.
+Die Liste wird aus dem Typechecker erzeugt und enthält daher genau die Typenkombinationen, die der Kompilierer unterstützt.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var lastOperator ast.Operator
+		for _, signature := range typechecker.BuiltinOperators() {
+			if signature.Operator != lastOperator {
+				fmt.Printf("%s:\n", signature.Operator)
+				lastOperator = signature.Operator
+			}
+
+			operands := make([]string, 0, len(signature.Operands))
+			for _, operand := range signature.Operands {
+				operands = append(operands, operand.String())
+			}
+			fmt.Printf("\t%s -> %s\n", strings.Join(operands, ", "), signature.Result)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:     "check [--ignoriere-warnungen Codes] <Datei>",
+	Aliases: []string{"prüfe"},
+	Short:   "Prüft eine .ddp Datei auf Fehler ohne sie zu kompilieren",
+	Long: `Parst die gegebene .ddp Datei und alle eingebundenen Module, prüft sie auf Fehler und gibt alle Fehler und Warnungen aus.
+Es wird kein Code generiert und gcc wird nicht aufgerufen.
+Wurden Fehler gefunden, wird der Befehl je nach Art des ersten Fehlers mit einem Exit Code ungleich 0 beendet (siehe README), Warnungen ändern den Exit Code nicht.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		if filepath.Ext(filePath) != ".ddp" {
+			return fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+		}
+
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+		}
+
+		ignoredWarnings := make([]ddperror.Code, 0, len(checkIgnoredWarnings))
+		for _, code := range checkIgnoredWarnings {
+			ignoredWarnings = append(ignoredWarnings, ddperror.Code(code))
+		}
+
+		errorCount := 0
+		errorHandler := ddperror.MakeWarningFilter(makeErrorHandler(filePath, src), ignoredWarnings...)
+		if _, err := parser.Parse(parser.Options{
+			FileName: filePath,
+			Source:   src,
+			ErrorHandler: func(err ddperror.Error) {
+				if err.Level == ddperror.LEVEL_ERROR {
+					errorCount++
+				}
+				errorHandler(err)
+			},
+		}); err != nil {
+			return fmt.Errorf("Fehler beim Parsen: %w", err)
+		}
+
+		if errorCount > 0 {
+			return fmt.Errorf("Es wurden %d Fehler gefunden", errorCount)
+		}
+		return nil
+	},
+}
+
+var checkIgnoredWarnings []uint // flag for check
+
+func init() {
+	checkCmd.Flags().UintSliceVar(&checkIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+}
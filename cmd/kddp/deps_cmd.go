@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddppath"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:     "deps [--dot] [--markiere-zyklen] <Datei>",
+	Aliases: []string{"abhängigkeiten"},
+	Short:   "Zeigt die Abhängigkeiten einer .ddp Datei",
+	Long: `Parst die gegebene .ddp Datei und alle eingebundenen Module und gibt aus, welche Datei welche anderen Dateien einbindet.
+Externe Abhängigkeiten (z.B. .c Dateien) werden ebenfalls aufgelistet.
+Mit --dot wird ein Graphviz DOT Graph ausgegeben.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		if filepath.Ext(filePath) != ".ddp" {
+			return fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+		}
+
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+		}
+
+		absPath, err := filepath.Abs(filePath)
+		if err != nil {
+			return fmt.Errorf("Es konnte kein Absoluter Dateipfad für die Datei '%s' gefunden werden: %w", filePath, err)
+		}
+
+		// errors (e.g. circular imports) are reported, but the graph is still printed
+		errorHandler := makeErrorHandler(absPath, src)
+		module, err := parser.Parse(parser.Options{
+			FileName: absPath,
+			Source:   src,
+			ErrorHandler: func(err ddperror.Error) {
+				if err.Level == ddperror.LEVEL_ERROR { // warnings are not interesting here
+					errorHandler(err)
+				}
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("Fehler beim Parsen: %w", err)
+		}
+
+		graph := newDependencyGraph(module)
+		if depsDot {
+			graph.writeDot(cmd.OutOrStdout(), depsMarkCycles)
+		} else {
+			graph.writeList(cmd.OutOrStdout())
+		}
+		return nil
+	},
+}
+
+var (
+	depsDot        bool // flag for deps
+	depsMarkCycles bool // flag for deps
+)
+
+func init() {
+	depsCmd.Flags().BoolVar(&depsDot, "dot", false, "Gibt die Abhängigkeiten als Graphviz DOT Graph aus")
+	depsCmd.Flags().BoolVar(&depsMarkCycles, "markiere-zyklen", false, "Färbt zyklische Einbindungen im DOT Graph rot")
+}
+
+// an edge of a dependencyGraph
+type dependencyEdge struct {
+	from, to string
+}
+
+// a graph of the files a module depends on
+// nodes are absolute file paths
+type dependencyGraph struct {
+	mainDir  string              // directory of the main module, used for labels
+	external map[string]struct{} // external dependencies (.c, .lib, .a, .o files)
+	edges    map[dependencyEdge]struct{}
+	nodes    map[string]struct{}
+}
+
+func newDependencyGraph(module *ast.Module) *dependencyGraph {
+	graph := &dependencyGraph{
+		mainDir:  filepath.Dir(module.FileName),
+		external: make(map[string]struct{}),
+		edges:    make(map[dependencyEdge]struct{}),
+		nodes:    make(map[string]struct{}),
+	}
+
+	graph.nodes[module.FileName] = struct{}{}
+	ast.IterateModuleImports(module, func(module *ast.Module) {
+		for _, imprt := range module.Imports {
+			// Path is also set for circular imports, which is why it is used instead of imprt.Module
+			if imprt.Path != "" {
+				graph.nodes[imprt.Path] = struct{}{}
+				graph.edges[dependencyEdge{from: module.FileName, to: imprt.Path}] = struct{}{}
+			}
+		}
+		for path := range module.ExternalDependencies {
+			if abspath, err := filepath.Abs(filepath.Join(filepath.Dir(module.FileName), path)); err == nil {
+				path = abspath
+			}
+			graph.nodes[path] = struct{}{}
+			graph.external[path] = struct{}{}
+			graph.edges[dependencyEdge{from: module.FileName, to: path}] = struct{}{}
+		}
+	})
+	return graph
+}
+
+// returns the nodes sorted by their label
+func (graph *dependencyGraph) sortedNodes() []string {
+	nodes := make([]string, 0, len(graph.nodes))
+	for node := range graph.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return graph.label(nodes[i]) < graph.label(nodes[j])
+	})
+	return nodes
+}
+
+// returns the direct dependencies of node sorted by their label
+func (graph *dependencyGraph) dependencies(node string) []string {
+	var deps []string
+	for edge := range graph.edges {
+		if edge.from == node {
+			deps = append(deps, edge.to)
+		}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		return graph.label(deps[i]) < graph.label(deps[j])
+	})
+	return deps
+}
+
+// wether to can be reached from from
+func (graph *dependencyGraph) reaches(from, to string) bool {
+	visited := make(map[string]struct{}, len(graph.nodes))
+	stack := []string{from}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == to {
+			return true
+		}
+		if _, ok := visited[node]; ok {
+			continue
+		}
+		visited[node] = struct{}{}
+		stack = append(stack, graph.dependencies(node)...)
+	}
+	return false
+}
+
+// returns the path of node relative to the main module
+// or relative to the installation directory for Duden modules
+// so that the output does not depend on where the project lies
+func (graph *dependencyGraph) label(node string) string {
+	dir := graph.mainDir
+	if ddppath.InstallDir != "" && strings.HasPrefix(node, ddppath.InstallDir+string(filepath.Separator)) {
+		dir = ddppath.InstallDir
+	}
+	if rel, err := filepath.Rel(dir, node); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(node)
+}
+
+// writes every file followed by its direct dependencies
+func (graph *dependencyGraph) writeList(w io.Writer) {
+	for _, node := range graph.sortedNodes() {
+		if _, isExternal := graph.external[node]; isExternal {
+			continue
+		}
+		fmt.Fprintf(w, "%s\n", graph.label(node))
+		for _, dep := range graph.dependencies(node) {
+			fmt.Fprintf(w, "\t-> %s\n", graph.label(dep))
+		}
+	}
+}
+
+// writes the graph in the Graphviz DOT format
+// external dependencies are drawn as boxes
+// if markCycles is true, edges that are part of a cycle are colored red
+func (graph *dependencyGraph) writeDot(w io.Writer, markCycles bool) {
+	fmt.Fprintln(w, "digraph Abhängigkeiten {")
+	nodes := graph.sortedNodes()
+	for _, node := range nodes {
+		if _, isExternal := graph.external[node]; isExternal {
+			fmt.Fprintf(w, "\t%s [shape=box];\n", strconv.Quote(graph.label(node)))
+		} else {
+			fmt.Fprintf(w, "\t%s;\n", strconv.Quote(graph.label(node)))
+		}
+	}
+	for _, node := range nodes {
+		for _, dep := range graph.dependencies(node) {
+			attributes := ""
+			if markCycles && graph.reaches(dep, node) {
+				attributes = " [color=red]"
+			}
+			fmt.Fprintf(w, "\t%s -> %s%s;\n", strconv.Quote(graph.label(node)), strconv.Quote(graph.label(dep)), attributes)
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
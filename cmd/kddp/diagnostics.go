@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+)
+
+// creates the error handler used to print diagnostics to stderr
+// diagnostics are colored if stderr is a terminal
+// unless --no-color or the NO_COLOR environment variable is set
+// the same diagnostic is only printed once
+// the first reported error is remembered for the exit code
+func makeErrorHandler(file string, src []byte) ddperror.Handler {
+	handler := ddperror.Deduplicate(ddperror.MakeColoredAdvancedHandler(file, src, os.Stderr, shouldColorDiagnostics()))
+	return func(err ddperror.Error) {
+		if err.Level == ddperror.LEVEL_ERROR && firstReportedError == nil {
+			firstReportedError = &err
+		}
+		handler(err)
+	}
+}
+
+// the first error reported to a handler from makeErrorHandler
+// later errors are often caused by the first one, so its category decides the exit code
+var firstReportedError *ddperror.Error
+
+func shouldColorDiagnostics() bool {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
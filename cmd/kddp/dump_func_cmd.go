@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DDP-Projekt/Kompilierer/src/compiler"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/spf13/cobra"
+)
+
+var dumpFuncCmd = &cobra.Command{
+	Use:     "dump-func --name <Funktion> [-O Stufe] [--ignoriere-warnungen Codes] <Datei>",
+	Aliases: []string{"funktion"},
+	Short:   "Gibt das llvm-ir einer einzelnen Funktion aus einer .ddp Datei aus",
+	Long: `Kompiliert die gegebene .ddp Datei zu llvm-ir und gibt nur die Funktion mit dem gegebenen Namen aus,
+zusammen mit den Funktionen, globalen Variablen und Typen, die sie (auch indirekt) benutzt.
+Funktionen aus eingebundenen Modulen und der Laufzeitbibliothek werden nur deklariert.
+Die Anweisungen außerhalb von Funktionen werden nicht kompiliert.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		if filepath.Ext(filePath) != ".ddp" {
+			return fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+		}
+
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+		}
+
+		ignoredWarnings := make([]ddperror.Code, 0, len(dumpFuncIgnoredWarnings))
+		for _, code := range dumpFuncIgnoredWarnings {
+			ignoredWarnings = append(ignoredWarnings, ddperror.Code(code))
+		}
+
+		if err := compiler.DumpFunction(compiler.Options{
+			FileName:          filePath,
+			Source:            src,
+			To:                os.Stdout,
+			ErrorHandler:      ddperror.MakeWarningFilter(makeErrorHandler(filePath, src), ignoredWarnings...),
+			OptimizationLevel: dumpFuncOptimizationLevel,
+		}, dumpFuncName); err != nil {
+			return fmt.Errorf("Fehler beim Kompilieren: %w", err)
+		}
+		return nil
+	},
+}
+
+var (
+	dumpFuncName              string // flag for dump-func
+	dumpFuncOptimizationLevel uint   // flag for dump-func
+	dumpFuncIgnoredWarnings   []uint // flag for dump-func
+)
+
+func init() {
+	dumpFuncCmd.Flags().StringVar(&dumpFuncName, "name", "", "Name der Funktion, die ausgegeben wird")
+	dumpFuncCmd.MarkFlagRequired("name")
+	dumpFuncCmd.Flags().UintVarP(&dumpFuncOptimizationLevel, "optimierungs-stufe", "O", 1, "Menge und Art der Optimierungen, die angewandt werden")
+	dumpFuncCmd.Flags().UintSliceVar(&dumpFuncIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+}
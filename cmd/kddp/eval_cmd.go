@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var evalCmd = &cobra.Command{
+	Use:     "eval [--ignoriere-warnungen Codes] <Ausdruck>",
+	Aliases: []string{"werte-aus"},
+	Short:   "Wertet einen einzelnen Ausdruck aus und gibt das Ergebnis aus",
+	Long: `Kompiliert den gegebenen Ausdruck und führt ihn aus, z.B. kddp eval "2 mal 3 plus 4".
+Das Ergebnis wird mit Schreibe aus Duden/Ausgabe ausgegeben, also muss der Ausdruck eine Zahl, Kommazahl, ein Wahrheitswert, Buchstabe, Text oder eine Liste davon sein.
+Im Ausdruck können nur die Funktionen aus Duden/Ausgabe und keine Variablen benutzt werden.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		expr := strings.TrimSuffix(strings.TrimSpace(args[0]), ".")
+		if expr == "" {
+			return fmt.Errorf("Es wurde kein Ausdruck angegeben")
+		}
+
+		// helper function to print verbose output if the flag was set
+		print := func(format string, args ...any) {
+			if verbose {
+				fmt.Printf(format+"\n", args...)
+			}
+		}
+
+		outDir, err := os.MkdirTemp("", "KDDP_EVAL")
+		if err != nil {
+			return fmt.Errorf("Fehler beim Erstellen des temporären Ordners: %w", err)
+		}
+		defer os.RemoveAll(outDir)
+
+		// the expression is kept on the first line, so that error messages
+		// point to the same line the user wrote
+		filePath := filepath.Join(outDir, "Ausdruck.ddp")
+		src := fmt.Sprintf("Binde \"Duden/Ausgabe\" ein. Schreibe (%s) auf eine Zeile.\n", expr)
+		if err := os.WriteFile(filePath, []byte(src), 0o644); err != nil {
+			return fmt.Errorf("Fehler beim Schreiben von %s: %w", filePath, err)
+		}
+
+		exePath := filepath.Join(outDir, "Ausdruck.exe")
+		buildOutputPath = exePath
+		buildIgnoredWarnings = evalIgnoredWarnings
+
+		print("Kompiliere den Ausdruck")
+		if err = buildCmd.RunE(buildCmd, []string{filePath}); err != nil {
+			return fmt.Errorf("Fehler beim Auswerten des Ausdrucks: %w", err)
+		}
+
+		print("Starte das Programm\n")
+		ddpExe := exec.Command(exePath)
+		ddpExe.Stdin = os.Stdin
+		ddpExe.Stdout = os.Stdout
+		ddpExe.Stderr = os.Stderr
+
+		return ddpExe.Run()
+	},
+}
+
+var evalIgnoredWarnings []uint // flag for eval
+
+func init() {
+	evalCmd.Flags().UintSliceVar(&evalIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/linter"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [--disable Regeln] [--regeln] <Datei>",
+	Short: "Prüft eine .ddp Datei auf Fehler und gibt die Warnungen der aktivierten Regeln aus",
+	Long: `Parst die gegebene .ddp Datei und alle eingebundenen Module und gibt alle Fehler und die Warnungen der aktivierten Regeln aus.
+Alle Regeln sind aktiviert, mit --disable können einzelne Regeln ausgeschaltet werden (z.B. --disable unused-var,todo).
+Ungenutzte Variablen und Funktionen, unerreichbarer Code und Großschreibung werden nur in der gegebenen Datei geprüft und nur, wenn keine Fehler gefunden wurden.
+Mit --regeln werden alle Regeln aufgelistet.
+Wurden Fehler gefunden, wird der Befehl je nach Art des ersten Fehlers mit einem Exit Code ungleich 0 beendet (siehe README), Warnungen ändern den Exit Code nicht.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if lintListRules {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if lintListRules {
+			writeLintRules(os.Stdout)
+			return nil
+		}
+
+		filePath := args[0]
+		if filepath.Ext(filePath) != ".ddp" {
+			return fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+		}
+
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+		}
+
+		errorCount := 0
+		errorHandler := makeErrorHandler(filePath, src)
+		if _, err := linter.Lint(linter.Options{
+			FileName: filePath,
+			Source:   src,
+			ErrorHandler: func(err ddperror.Error) {
+				if err.Level == ddperror.LEVEL_ERROR {
+					errorCount++
+				}
+				errorHandler(err)
+			},
+			Disabled: lintDisabledRules,
+		}); err != nil {
+			return err
+		}
+
+		if errorCount > 0 {
+			return fmt.Errorf("Es wurden %d Fehler gefunden", errorCount)
+		}
+		return nil
+	},
+}
+
+// writes the id, code and description of every lint rule as a table to w
+func writeLintRules(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, rule := range linter.Rules {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", rule.ID, rule.Code, rule.Desc)
+	}
+	tw.Flush()
+}
+
+var (
+	lintDisabledRules []string // flag for lint
+	lintListRules     bool     // flag for lint
+)
+
+func init() {
+	lintCmd.Flags().StringSliceVar(&lintDisabledRules, "disable", nil, "IDs der Regeln, die nicht geprüft werden (z.B. unused-var)")
+	lintCmd.Flags().BoolVar(&lintListRules, "regeln", false, "Listet alle Regeln mit ihren IDs und Codes auf")
+}
@@ -2,8 +2,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+
+	"github.com/DDP-Projekt/Kompilierer/src/compiler"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
 )
 
 func main() {
@@ -12,8 +18,26 @@ func main() {
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// maps the error returned by a command to one of the exit codes in ddperror
+func exitCode(err error) int {
+	var (
+		compilerErr *compiler.CompilerError
+		parserErr   *parser.ParserError
+		pathErr     *fs.PathError
+	)
+	switch {
+	case errors.As(err, &compilerErr), errors.As(err, &parserErr):
+		return ddperror.EXIT_INTERNAL_ERROR
+	case firstReportedError != nil:
+		return firstReportedError.Code.ExitCode()
+	case errors.As(err, &pathErr):
+		return ddperror.EXIT_IO_ERROR
 	}
+	return ddperror.EXIT_FAILURE
 }
 
 func handle_panics() {
@@ -24,6 +48,6 @@ Dieser Fehler ist vermutlich ein Bug im DDP-Kompilierer.
 Bitte erstelle einen Issue unter https://github.com/DDP-Projekt/Kompilierer oder melde ihn anderweitig den Entwicklern.
 `,
 			err)
-		os.Exit(1)
+		os.Exit(ddperror.EXIT_INTERNAL_ERROR)
 	}
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ast/annotators"
-	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
 	"github.com/DDP-Projekt/Kompilierer/src/parser"
 	"github.com/spf13/cobra"
 )
@@ -34,7 +33,7 @@ var parseCmd = &cobra.Command{
 		module, err := parser.Parse(parser.Options{
 			FileName:     filePath,
 			Source:       src,
-			ErrorHandler: ddperror.MakeBasicHandler(os.Stderr),
+			ErrorHandler: makeErrorHandler(filePath, src),
 			Annotators: []ast.Annotator{
 				&annotators.ConstFuncParamAnnotator{},
 			},
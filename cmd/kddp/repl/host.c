@@ -0,0 +1,53 @@
+// the process in which the inputs of a kddp repl session are executed
+// linked with the runtime main.c, which calls ddp_ddpmain after initializing the runtime
+//
+// every input is compiled to a shared library whose path is read from the command file descriptor
+// the library is loaded with all its symbols visible to the following libraries
+// and its ddp_ddpmain is called
+// afterwards a line is written to the reply file descriptor:
+// "ok" or the error why the library could not be loaded
+//
+// if the ddp code ends the process (e.g. with a runtime error)
+// the repl notices it by the closed reply file descriptor
+#include <dlfcn.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+// the file descriptors inherited from kddp
+#define COMMAND_FD 3
+#define REPLY_FD 4
+
+int ddp_ddpmain(void) {
+	FILE *commands = fdopen(COMMAND_FD, "r");
+	FILE *replies = fdopen(REPLY_FD, "w");
+	if (commands == NULL || replies == NULL) {
+		fprintf(stderr, "Die Sitzung wurde nicht von kddp gestartet\n");
+		return 1;
+	}
+
+	char path[4096];
+	while (fgets(path, sizeof(path), commands) != NULL) {
+		path[strcspn(path, "\n")] = '\0';
+
+		void *library = dlopen(path, RTLD_NOW | RTLD_GLOBAL);
+		int (*input_main)(void) = library != NULL ? (int (*)(void))dlsym(library, "ddp_ddpmain") : NULL;
+		if (input_main == NULL) {
+			fprintf(replies, "%s\n", dlerror());
+			fflush(replies);
+			continue;
+		}
+
+		input_main();
+
+		// the output must be complete before kddp reads the next input
+		fflush(stdout);
+		fflush(stderr);
+		fprintf(replies, "ok\n");
+		fflush(replies);
+	}
+
+	fclose(commands);
+	fclose(replies);
+	return 0;
+}
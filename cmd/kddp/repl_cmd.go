@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/cmd/internal/linker"
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/compiler"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/spf13/cobra"
+)
+
+var replCmd = &cobra.Command{
+	Use:     "repl",
+	Aliases: []string{"sitzung"},
+	Short:   "Startet eine interaktive Sitzung, in der Anweisungen und Ausdrücke nacheinander ausgeführt werden",
+	Long: `Startet eine interaktive Sitzung, in der Anweisungen und Ausdrücke nacheinander ausgeführt werden.
+Deklarierte Variablen, Funktionen und Typen bleiben für die folgenden Eingaben erhalten.
+Ein einzelner Ausdruck (z.B. "x plus 1") wird ausgewertet und sein Ergebnis ausgegeben.
+Endet eine Zeile mit einem Doppelpunkt, wird bis zur nächsten leeren Zeile weitergelesen.
+
+Jede Eingabe wird als eigenes Modul gegen die Module der vorherigen Eingaben kompiliert
+und in einem Prozess ausgeführt, der für die ganze Sitzung bestehen bleibt.
+Vorherige Eingaben werden also nicht erneut ausgeführt und ihre Variablen behalten ihre Werte.
+Beendet eine Eingabe den Prozess (z.B. durch einen Laufzeitfehler), wird die Sitzung zurückgesetzt.
+Die Sitzung wird unter Windows nicht unterstützt.
+
+Befehle:
+	:beenden        beendet die Sitzung (wie das Ende der Eingabe)
+	:zurücksetzen   vergisst alle vorherigen Eingaben
+	:quelltext      zeigt den bisherigen Quelltext der Sitzung`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if runtime.GOOS == "windows" {
+			return fmt.Errorf("Die Sitzung wird unter Windows nicht unterstützt")
+		}
+
+		outDir, err := os.MkdirTemp("", "KDDP_REPL")
+		if err != nil {
+			return fmt.Errorf("Fehler beim Erstellen des temporären Ordners: %w", err)
+		}
+		defer os.RemoveAll(outDir)
+
+		session := newReplSession(outDir)
+		defer session.reset()
+		in := bufio.NewScanner(os.Stdin)
+		for {
+			input, ok := readReplInput(in)
+			if !ok {
+				return in.Err()
+			}
+
+			switch strings.TrimSpace(input) {
+			case "":
+			case ":beenden":
+				return nil
+			case ":zurücksetzen":
+				session.reset()
+			case ":quelltext":
+				fmt.Print(replPrelude + session.src)
+			default:
+				// errors are already reported so the session simply continues
+				if err := session.execute(input); err != nil {
+					fmt.Fprintln(os.Stderr, err)
+				}
+			}
+		}
+	},
+}
+
+// reads the next input from in
+// an input which ends with a ':' (e.g. a function declaration)
+// continues until the next empty line
+// returns false at the end of in
+func readReplInput(in *bufio.Scanner) (string, bool) {
+	fmt.Print("ddp> ")
+	if !in.Scan() {
+		fmt.Println()
+		return "", false
+	}
+
+	input := in.Text()
+	if !strings.HasSuffix(strings.TrimSpace(input), ":") {
+		return input, true
+	}
+	for fmt.Print("...> "); in.Scan() && strings.TrimSpace(in.Text()) != ""; fmt.Print("...> ") {
+		input += "\n" + in.Text()
+	}
+	return input, true
+}
+
+const replPrelude = "Binde \"Duden/Ausgabe\" ein.\n"
+
+// holds the state of a repl session
+type replSession struct {
+	dir      string                 // directory for the temporary files
+	src      string                 // all previous inputs that were kept
+	imports  string                 // imports the modules of the kept inputs and the modules they imported
+	modules  map[string]*ast.Module // all parsed modules, including the kept inputs (see parser.Options.Modules)
+	inputs   int                    // number of compiled inputs, used to give every input a unique module name
+	compiler *compiler.Session      // the modules that are loaded into the host process
+	hostExe  string                 // path to the host executable, empty until it is built
+	host     *replHost              // the process in which the inputs are executed, nil until it is started
+}
+
+func newReplSession(dir string) *replSession {
+	s := &replSession{dir: dir}
+	s.reset()
+	return s
+}
+
+// forgets all previous inputs and ends the host process
+func (s *replSession) reset() {
+	if s.host != nil {
+		s.host.stop()
+		s.host = nil
+	}
+	s.src, s.imports = "", ""
+	s.modules = make(map[string]*ast.Module)
+	s.compiler = compiler.NewSession()
+}
+
+// executes input in the environment of all previous inputs and prints its output
+// if input is a statement or declaration it is kept for the following inputs
+// if input is an expression its result is printed
+func (s *replSession) execute(input string) error {
+	name := fmt.Sprintf("Eingabe_%d", s.inputs+1)
+	filePath := filepath.Join(s.dir, name+".ddp")
+	// the prelude and imports are on the first line so the errors of the input start at line 2
+	const inputLine = 2
+	prefix := strings.TrimSuffix(replPrelude, "\n") + " " + s.imports + "\n"
+
+	src := prefix + input + "\n"
+	module, errs, err := s.check(filePath, src, inputLine)
+	if err != nil {
+		return err
+	}
+	isExpression := false
+	if containsErrors(errs) {
+		// maybe the input is an expression whose result should be printed
+		expr := strings.TrimSuffix(strings.TrimSpace(input), ".")
+		exprSrc := prefix + fmt.Sprintf("Schreibe (%s) auf eine Zeile.\n", expr)
+		exprModule, exprErrs, err := s.check(filePath, exprSrc, inputLine)
+		if err != nil {
+			return err
+		}
+		// statements end with a '.', so without it the errors of the expression are more helpful
+		if !containsErrors(exprErrs) || !strings.HasSuffix(strings.TrimSpace(input), ".") {
+			src, module, errs, isExpression = exprSrc, exprModule, exprErrs, true
+		}
+	}
+
+	errorHandler := makeErrorHandler(filePath, []byte(src))
+	for _, err := range errs {
+		errorHandler(err)
+	}
+	if containsErrors(errs) {
+		return nil
+	}
+
+	// the following inputs import everything declared by this one
+	exportDeclarations(module)
+	if err := s.run(module); err != nil {
+		return err
+	}
+	if !isExpression {
+		s.src += input + "\n"
+		s.imports += fmt.Sprintf("Binde %q ein. ", name)
+		s.imports += userImports(module, inputLine)
+		s.modules[filePath] = module
+	}
+	return nil
+}
+
+// parses src with the modules of the session and returns the module and all errors and warnings
+// that do not belong to the prefix of the input (which ends before inputLine)
+func (s *replSession) check(filePath, src string, inputLine uint) (*ast.Module, []ddperror.Error, error) {
+	var errs []ddperror.Error
+	module, err := parser.Parse(parser.Options{
+		FileName: filePath,
+		Source:   []byte(src),
+		Modules:  s.modules,
+		ErrorHandler: func(err ddperror.Error) {
+			if err.File != filePath || err.Range.Start.Line >= inputLine {
+				errs = append(errs, err)
+			}
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+	}
+	return module, errs, nil
+}
+
+func containsErrors(errs []ddperror.Error) bool {
+	for _, err := range errs {
+		if err.Level == ddperror.LEVEL_ERROR {
+			return true
+		}
+	}
+	return false
+}
+
+// makes all top-level declarations of module public
+// so that they are imported and linked by the following inputs
+func exportDeclarations(module *ast.Module) {
+	for _, stmt := range module.Ast.Statements {
+		declStmt, isDecl := stmt.(*ast.DeclStmt)
+		if !isDecl {
+			continue
+		}
+		switch decl := declStmt.Decl.(type) {
+		case *ast.VarDecl:
+			decl.IsPublic = true
+		case *ast.FuncDecl:
+			decl.IsPublic = true
+		case *ast.StructDecl:
+			decl.IsPublic = true
+			for _, field := range decl.Fields {
+				if field, isVar := field.(*ast.VarDecl); isVar {
+					field.IsPublic = true
+				}
+			}
+		case *ast.EnumDecl:
+			decl.IsPublic = true
+		case *ast.TypeAliasDecl:
+			decl.IsPublic = true
+		case *ast.TypeDefDecl:
+			decl.IsPublic = true
+		default:
+			continue
+		}
+		if _, exists := module.PublicDecls[declStmt.Decl.Name()]; !exists {
+			module.PublicDecls[declStmt.Decl.Name()] = declStmt.Decl
+		}
+	}
+}
+
+// returns the import statements of module that start at or after inputLine as source code
+// imports are not passed on, so the following inputs have to repeat them
+func userImports(module *ast.Module, inputLine uint) string {
+	var imports strings.Builder
+	for _, imprt := range module.Imports {
+		if imprt.Range.Start.Line < inputLine {
+			continue
+		}
+		imports.WriteString("Binde ")
+		for i, symbol := range imprt.ImportedSymbols {
+			switch i {
+			case 0:
+			case len(imprt.ImportedSymbols) - 1:
+				imports.WriteString(" und ")
+			default:
+				imports.WriteString(", ")
+			}
+			imports.WriteString(symbol.Literal)
+		}
+		if len(imprt.ImportedSymbols) > 0 {
+			imports.WriteString(" aus ")
+		}
+		imports.WriteString(imprt.FileName.Literal + " ein. ")
+	}
+	return imports.String()
+}
+
+// compiles module to a shared library and executes it in the host process
+// if the host process ends (e.g. because of a runtime error) the session is reset
+func (s *replSession) run(module *ast.Module) error {
+	s.inputs++ // even if the input fails, its name might already be used by loaded symbols
+	objPath, libPath := changeExtension(module.FileName, ".o"), changeExtension(module.FileName, ".so")
+	obj, err := os.OpenFile(objPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(objPath)
+
+	result, err := compiler.Compile(compiler.Options{
+		Module:                  module,
+		To:                      obj,
+		OutputType:              compiler.OutputObj,
+		ErrorHandler:            ddperror.EmptyHandler, // already reported in execute
+		DeleteIntermediateFiles: true,
+		LinkInModules:           true,
+		OptimizationLevel:       1,
+		Session:                 s.compiler,
+	})
+	obj.Close()
+	if err != nil {
+		return fmt.Errorf("Fehler beim Kompilieren: %w", err)
+	}
+
+	if output, err := linker.LinkDDPFiles(linker.Options{
+		InputFile:               objPath,
+		OutputFile:              libPath,
+		Dependencies:            result,
+		DeleteIntermediateFiles: true,
+		Shared:                  true,
+	}); err != nil {
+		// the session expects the compiled modules to be loaded
+		s.reset()
+		return fmt.Errorf("Fehler beim Linken, die Sitzung wurde zurückgesetzt: %w (%s)", err, string(output))
+	}
+
+	if err := s.startHost(); err != nil {
+		return err
+	}
+	if err := s.host.execute(libPath); err != nil {
+		if errors.Is(err, errReplHostEnded) {
+			s.host = nil
+		}
+		s.reset()
+		return fmt.Errorf("%w, die Sitzung wurde zurückgesetzt", err)
+	}
+	return nil
+}
+
+// starts the host process if it is not running
+func (s *replSession) startHost() (err error) {
+	if s.host != nil {
+		return nil
+	}
+	if s.hostExe == "" {
+		if s.hostExe, err = buildReplHost(s.dir); err != nil {
+			return err
+		}
+	}
+	s.host, err = startReplHost(s.hostExe)
+	return err
+}
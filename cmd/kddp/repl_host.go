@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/cmd/internal/gcc"
+	"github.com/DDP-Projekt/Kompilierer/cmd/internal/linker"
+)
+
+//go:embed repl/host.c
+var replHostSource []byte
+
+// the process in which the inputs of a repl session are executed
+// every input is a shared library which is loaded into it (see repl/host.c)
+type replHost struct {
+	cmd      *exec.Cmd
+	commands io.WriteCloser // the paths of the libraries to execute are written to it
+	replies  *bufio.Reader  // the host replies to every command with a line
+}
+
+// compiles the host executable into dir and returns its path
+func buildReplHost(dir string) (string, error) {
+	srcPath, objPath, exePath := filepath.Join(dir, "ddp_sitzung.c"), filepath.Join(dir, "ddp_sitzung.o"), filepath.Join(dir, "ddp_sitzung")
+	if err := os.WriteFile(srcPath, replHostSource, os.ModePerm); err != nil {
+		return "", err
+	}
+	defer os.Remove(srcPath)
+
+	if output, err := gcc.New("-O2", "-c", "-Wall", "-o", objPath, srcPath).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("Fehler beim Kompilieren der Sitzung: %w (%s)", err, string(output))
+	}
+	defer os.Remove(objPath)
+
+	if output, err := linker.LinkDDPFiles(linker.Options{
+		InputFile:               objPath,
+		OutputFile:              exePath,
+		DeleteIntermediateFiles: true,
+		LinkInListDefs:          true,
+		ExportRuntime:           true,
+	}); err != nil {
+		return "", fmt.Errorf("Fehler beim Linken der Sitzung: %w (%s)", err, string(output))
+	}
+	return exePath, nil
+}
+
+// starts the host executable at exePath
+// the ddp code uses the standard streams of kddp
+func startReplHost(exePath string) (*replHost, error) {
+	commandsR, commandsW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	repliesR, repliesW, err := os.Pipe()
+	if err != nil {
+		commandsR.Close()
+		commandsW.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(exePath)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = []*os.File{commandsR, repliesW} // file descriptors 3 and 4
+	err = cmd.Start()
+	// the child has its own copies
+	commandsR.Close()
+	repliesW.Close()
+	if err != nil {
+		commandsW.Close()
+		repliesR.Close()
+		return nil, fmt.Errorf("Fehler beim Starten der Sitzung: %w", err)
+	}
+	return &replHost{cmd: cmd, commands: commandsW, replies: bufio.NewReader(repliesR)}, nil
+}
+
+// signals that the host process ended while executing an input
+var errReplHostEnded = errors.New("Der Prozess der Sitzung wurde beendet")
+
+// loads the shared library at libPath into the host and executes it
+// returns errReplHostEnded if the ddp code ended the process (e.g. through a runtime error)
+func (h *replHost) execute(libPath string) error {
+	if _, err := fmt.Fprintln(h.commands, libPath); err != nil {
+		return h.ended()
+	}
+	reply, err := h.replies.ReadString('\n')
+	if err != nil {
+		return h.ended()
+	}
+	if reply = strings.TrimSuffix(reply, "\n"); reply != "ok" {
+		return fmt.Errorf("Fehler beim Laden der Eingabe: %s", reply)
+	}
+	return nil
+}
+
+// waits for the ended host process and wraps its exit status
+func (h *replHost) ended() error {
+	h.commands.Close()
+	if err := h.cmd.Wait(); err != nil {
+		return fmt.Errorf("%w: %w", errReplHostEnded, err)
+	}
+	return errReplHostEnded
+}
+
+// ends the host process after the current input
+func (h *replHost) stop() {
+	h.commands.Close() // the host returns when there are no more commands
+	h.cmd.Wait()
+}
@@ -22,9 +22,13 @@ var rootCmd = &cobra.Command{
 // global verbose flag for all commands
 var verbose bool
 
+// global flag to disable colored diagnostics
+var noColor bool
+
 func init() {
 	// Flags inherited by all sub commands
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "wortreich", "w", false, "Gibt wortreiche Informationen aus")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Gibt Fehler und Warnungen ohne Farben aus (wie die NO_COLOR Umgebungsvariable)")
 
 	// sub commands
 	rootCmd.AddCommand(
@@ -34,6 +38,15 @@ func init() {
 		updateCmd,
 		parseCmd,
 		dumpListDefsCommand,
+		builtinsCmd,
+		checkCmd,
+		depsCmd,
+		evalCmd,
+		replCmd,
+		statsCmd,
+		dumpFuncCmd,
+		lintCmd,
+		astDiffCmd,
 	)
 
 	setDefaultCommandOptions(rootCmd)
@@ -10,10 +10,12 @@ import (
 )
 
 var runCmd = &cobra.Command{
-	Use:   "starte [--gcc-optionen GCC-Optionen] [--externe-gcc-optionen Externe-GCC-Optionen] <Datei>",
+	Use:   "starte [--gcc-optionen GCC-Optionen] [--externe-gcc-optionen Externe-GCC-Optionen] [--ignoriere-warnungen Codes] [--memstats] <Datei>",
 	Short: "Kompiliert und führt die angegebene .ddp Datei aus",
-	Long:  `Kompiliert und führt die angegebene .ddp Datei aus.`,
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Kompiliert und führt die angegebene .ddp Datei aus.
+Mit --memstats gibt das Programm beim Beenden für jeden Text- und Listen-Typ auf stderr aus, wie viele Werte höchstens gleichzeitig existierten und wie viele nie freigegeben wurden:
+	Speicher-Statistik: <Typ>: höchstens <Anzahl> gleichzeitig, <Anzahl> nie freigegeben`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		filePath := ""
 		if len(args) > 0 {
@@ -44,6 +46,8 @@ var runCmd = &cobra.Command{
 		buildOutputPath = exePath
 		buildGCCFlags = runGCCFlags
 		buildExternGCCFlags = runExternGCCFlags
+		buildIgnoredWarnings = runIgnoredWarnings
+		buildMemoryProfiling = runMemStats
 
 		print("Kompiliere den Quellcode")
 		if err = buildCmd.RunE(buildCmd, []string{filePath}); err != nil {
@@ -58,17 +62,25 @@ var runCmd = &cobra.Command{
 		ddpExe.Stdin = os.Stdin
 		ddpExe.Stdout = os.Stdout
 		ddpExe.Stderr = os.Stderr
+		if runMemStats {
+			// the runtime reports the statistics instead of the memory profile
+			ddpExe.Env = append(os.Environ(), "DDP_SPEICHER_STATISTIK=1")
+		}
 
 		return ddpExe.Run()
 	},
 }
 
 var (
-	runGCCFlags       string // flag for starte
-	runExternGCCFlags string // flag for starte
+	runGCCFlags        string // flag for starte
+	runExternGCCFlags  string // flag for starte
+	runIgnoredWarnings []uint // flag for starte
+	runMemStats        bool   // flag for starte
 )
 
 func init() {
 	runCmd.Flags().StringVar(&runGCCFlags, "gcc-optionen", "", "Benutzerdefinierte Optionen, die gcc übergeben werden")
 	runCmd.Flags().StringVar(&runExternGCCFlags, "externe-gcc-optionen", "", "Benutzerdefinierte Optionen, die gcc für jede externe .c Datei übergeben werden")
+	runCmd.Flags().UintSliceVar(&runIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+	runCmd.Flags().BoolVar(&runMemStats, "memstats", false, "Das Programm gibt beim Beenden auf stderr aus, wie viele Texte und Listen jedes Typs höchstens gleichzeitig existierten und nie freigegeben wurden")
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/DDP-Projekt/Kompilierer/src/compiler"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:     "stats [-O Stufe] [--ignoriere-warnungen Codes] <Datei>",
+	Aliases: []string{"statistik"},
+	Short:   "Gibt aus, wie viel llvm-ir für eine .ddp Datei erzeugt wird",
+	Long: `Kompiliert die gegebene .ddp Datei zu llvm-ir und gibt aus, wie viele Funktionen, Blöcke, Instruktionen,
+konstante Texte und Aufrufe der Laufzeitbibliothek dabei erzeugt wurden.
+Gezählt wird nur das llvm-ir der gegebenen Datei, nicht das der eingebundenen Module.
+Die Zahlen beziehen sich auf das llvm-ir bevor llvm es optimiert, mit -O 2 werden aber die Optimierungen des Kompilierers angewandt.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filePath := args[0]
+		if filepath.Ext(filePath) != ".ddp" {
+			return fmt.Errorf("Die Eingabedatei '%s' ist keine .ddp Datei", filePath)
+		}
+
+		src, err := os.ReadFile(filePath)
+		if err != nil {
+			return fmt.Errorf("Fehler beim Lesen von %s: %w", filePath, err)
+		}
+
+		ignoredWarnings := make([]ddperror.Code, 0, len(statsIgnoredWarnings))
+		for _, code := range statsIgnoredWarnings {
+			ignoredWarnings = append(ignoredWarnings, ddperror.Code(code))
+		}
+
+		stats := &compiler.IRStats{}
+		if _, err := compiler.Compile(compiler.Options{
+			FileName:          filePath,
+			Source:            src,
+			To:                io.Discard,
+			OutputType:        compiler.OutputIR,
+			ErrorHandler:      ddperror.MakeWarningFilter(makeErrorHandler(filePath, src), ignoredWarnings...),
+			LinkInModules:     false,
+			LinkInListDefs:    false,
+			OptimizationLevel: statsOptimizationLevel,
+			Stats:             stats,
+		}); err != nil {
+			return fmt.Errorf("Fehler beim Kompilieren: %w", err)
+		}
+
+		writeStats(os.Stdout, stats)
+		return nil
+	},
+}
+
+// writes stats as a table to w
+func writeStats(w io.Writer, stats *compiler.IRStats) {
+	counts := []struct {
+		name  string
+		count int
+	}{
+		{"Funktionen", stats.Functions},
+		{"Deklarationen", stats.Declarations},
+		{"Blöcke", stats.BasicBlocks},
+		{"Instruktionen", stats.Instructions},
+		{"Konstante Texte", stats.StringConstants},
+		{"Laufzeit-Aufrufe", stats.RuntimeCalls},
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, count := range counts {
+		fmt.Fprintf(tw, "%s:\t%d\n", count.name, count.count)
+	}
+	tw.Flush()
+}
+
+var (
+	statsOptimizationLevel uint   // flag for stats
+	statsIgnoredWarnings   []uint // flag for stats
+)
+
+func init() {
+	statsCmd.Flags().UintVarP(&statsOptimizationLevel, "optimierungs-stufe", "O", 1, "Menge und Art der Optimierungen, die angewandt werden")
+	statsCmd.Flags().UintSliceVar(&statsIgnoredWarnings, "ignoriere-warnungen", nil, "Codes der Warnungen, die nicht ausgegeben werden (z.B. 3013)")
+}
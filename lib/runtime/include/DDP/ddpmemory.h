@@ -30,4 +30,17 @@ void *ddp_reallocate(void *pointer, size_t oldSize, size_t newSize);
 #define DDP_FREE_ARRAY(type, pointer, oldCount) \
 	ddp_reallocate(pointer, sizeof(type) * (oldCount), 0)
 
+// enables tracking of all allocations made through ddp_reallocate
+// and registers a handler that reports the still allocated memory at exit
+// if the DDP_SPEICHER_STATISTIK environment variable is set, the peak and never freed
+// allocations of each type are reported instead
+// called at the start of the program if it was compiled with memory profiling
+void ddp_enable_memory_profiling(void);
+
+// labels the memory owned by value with type for the memory profile
+// value must be a pointer to a ddpstring or list whose first field is the
+// pointer to its allocated memory
+// does nothing if memory profiling is not enabled
+void ddp_memory_profile_tag(void *value, const char *type);
+
 #endif // DDP_MEMORY_H
@@ -1,9 +1,24 @@
 #ifndef DDP_RUNTIME_H
 #define DDP_RUNTIME_H
 
+#include "DDP/ddptypes.h"
+
 void SignalHandler(int sig);
 
 void ddp_init_runtime(int argc, char **argv);
 void ddp_end_runtime(void);
 
+// returns the Text used for b when a Wahrheitswert is converted to a Text
+// ("wahr" and "falsch" by default)
+// they can be changed with the environment variables DDP_WAHR and DDP_FALSCH
+const char *ddp_bool_text(ddpbool b);
+
+// called if the condition of a 'Stelle sicher, dass' statement was falsch
+// reports file, line, column and the optional message msg (may be NULL) as runtime error
+void ddp_assert_failed(const char *file, ddpint line, ddpint column, ddpstring *msg);
+
+// prints str to stdout, followed by a newline if newline is true
+// used by the 'Gib ... aus' statement
+void ddp_print_string(ddpstring *str, ddpbool newline);
+
 #endif // DDP_RUNTIME_H
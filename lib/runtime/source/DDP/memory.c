@@ -1,6 +1,178 @@
 #include "DDP/ddpmemory.h"
 #include "DDP/debug.h"
+#include <stdbool.h>
+#include <stdint.h>
+#include <stdio.h>
 #include <stdlib.h>
+#include <string.h>
+
+// an allocation tracked by the memory profile
+typedef struct profiled_allocation {
+	void *pointer;
+	size_t size;
+	const char *type; // NULL if the allocation was never tagged
+	struct profiled_allocation *next;
+} profiled_allocation;
+
+#define PROFILE_BUCKET_COUNT 4096
+
+static bool profiling_enabled = false;
+// hash map of all live allocations (pointer -> allocation)
+// it uses malloc directly, so that it does not track itself
+static profiled_allocation *profiled_allocations[PROFILE_BUCKET_COUNT];
+
+static size_t profile_bucket(void *pointer) {
+	return ((uintptr_t)pointer >> 4) % PROFILE_BUCKET_COUNT;
+}
+
+// how many allocations of a type are live and how many were live at most
+typedef struct {
+	const char *type;
+	size_t live, peak;
+} type_statistic;
+
+#define STATISTIC_TYPE_COUNT 64
+
+// in the order in which the types were first tagged
+// the same type name may come from different modules, so strcmp is used
+static type_statistic type_statistics[STATISTIC_TYPE_COUNT];
+static size_t type_statistics_len = 0;
+
+// adds delta to the live allocations of type and updates its peak
+// types beyond STATISTIC_TYPE_COUNT are not counted
+static void count_allocation(const char *type, int delta) {
+	if (type == NULL) {
+		return;
+	}
+	size_t i = 0;
+	while (i < type_statistics_len && strcmp(type_statistics[i].type, type) != 0) {
+		i++;
+	}
+	if (i == STATISTIC_TYPE_COUNT) {
+		return;
+	} else if (i == type_statistics_len) {
+		type_statistics[type_statistics_len++] = (type_statistic){type, 0, 0};
+	}
+
+	type_statistics[i].live += delta;
+	if (type_statistics[i].live > type_statistics[i].peak) {
+		type_statistics[i].peak = type_statistics[i].live;
+	}
+}
+
+// removes the allocation of pointer from the profile
+// and returns its type, or NULL if it was not tracked or tagged
+static const char *profile_remove(void *pointer) {
+	profiled_allocation **entry = &profiled_allocations[profile_bucket(pointer)];
+	for (; *entry != NULL; entry = &(*entry)->next) {
+		if ((*entry)->pointer == pointer) {
+			profiled_allocation *removed = *entry;
+			const char *type = removed->type;
+			*entry = removed->next;
+			free(removed);
+			count_allocation(type, -1);
+			return type;
+		}
+	}
+	return NULL;
+}
+
+static void profile_insert(void *pointer, size_t size, const char *type) {
+	profiled_allocation *allocation = malloc(sizeof(profiled_allocation));
+	if (allocation == NULL) {
+		ddp_runtime_error(1, "out of memory\n");
+	}
+	size_t bucket = profile_bucket(pointer);
+	*allocation = (profiled_allocation){pointer, size, type, profiled_allocations[bucket]};
+	profiled_allocations[bucket] = allocation;
+	count_allocation(type, 1);
+}
+
+// prints how many allocations of each type were never freed
+static void report_memory_profile(void) {
+	// every distinct type is counted once
+	// the same type name may come from different modules, so strcmp is used
+	typedef struct {
+		const char *type;
+		size_t count;
+	} type_count;
+	type_count counts[64];
+	size_t type_count_len = 0, untagged = 0, untagged_bytes = 0;
+
+	for (size_t i = 0; i < PROFILE_BUCKET_COUNT; i++) {
+		for (profiled_allocation *allocation = profiled_allocations[i]; allocation != NULL; allocation = allocation->next) {
+			size_t j = 0;
+			while (allocation->type != NULL && j < type_count_len && strcmp(counts[j].type, allocation->type) != 0) {
+				j++;
+			}
+			if (allocation->type == NULL || j == sizeof(counts) / sizeof(counts[0])) {
+				untagged++;
+				untagged_bytes += allocation->size;
+			} else if (j == type_count_len) {
+				counts[type_count_len++] = (type_count){allocation->type, 1};
+			} else {
+				counts[j].count++;
+			}
+		}
+	}
+
+	if (type_count_len == 0 && untagged == 0) {
+		fprintf(stderr, "Speicher-Profil: Aller Speicher wurde freigegeben\n");
+		return;
+	}
+	for (size_t i = 0; i < type_count_len; i++) {
+		fprintf(stderr, "Speicher-Profil: %zu %s nie freigegeben\n", counts[i].count, counts[i].type);
+	}
+	if (untagged > 0) {
+		fprintf(stderr, "Speicher-Profil: %zu weitere Speicherbereiche (%zu Bytes) nie freigegeben\n", untagged, untagged_bytes);
+	}
+}
+
+// prints the peak and never freed allocations of every type in the format
+//
+//	Speicher-Statistik: <Typ>: höchstens <n> gleichzeitig, <n> nie freigegeben
+//
+// followed by the untagged allocations that were never freed, if there are any
+static void report_memory_statistics(void) {
+	size_t untagged = 0;
+	for (size_t i = 0; i < PROFILE_BUCKET_COUNT; i++) {
+		for (profiled_allocation *allocation = profiled_allocations[i]; allocation != NULL; allocation = allocation->next) {
+			if (allocation->type == NULL) {
+				untagged++;
+			}
+		}
+	}
+
+	for (size_t i = 0; i < type_statistics_len; i++) {
+		fprintf(stderr, "Speicher-Statistik: %s: höchstens %zu gleichzeitig, %zu nie freigegeben\n", type_statistics[i].type, type_statistics[i].peak, type_statistics[i].live);
+	}
+	if (untagged > 0) {
+		fprintf(stderr, "Speicher-Statistik: %zu weitere Speicherbereiche nie freigegeben\n", untagged);
+	}
+}
+
+void ddp_enable_memory_profiling(void) {
+	if (!profiling_enabled) {
+		profiling_enabled = true;
+		// set by kddp starte --memstats
+		atexit(getenv("DDP_SPEICHER_STATISTIK") != NULL ? report_memory_statistics : report_memory_profile);
+	}
+}
+
+void ddp_memory_profile_tag(void *value, const char *type) {
+	if (!profiling_enabled) {
+		return;
+	}
+	void *pointer = *(void **)value;
+	for (profiled_allocation *allocation = profiled_allocations[profile_bucket(pointer)]; allocation != NULL; allocation = allocation->next) {
+		if (allocation->pointer == pointer) {
+			count_allocation(allocation->type, -1);
+			allocation->type = type;
+			count_allocation(type, 1);
+			return;
+		}
+	}
+}
 
 // used for allocation/reallocation and freeing of memory
 // to allocate call reallocate(NULL, 0, size)
@@ -13,6 +185,9 @@ void *ddp_reallocate(void *pointer, size_t oldSize, size_t newSize) {
 
 	// newSize == 0 means free
 	if (newSize == 0) {
+		if (profiling_enabled && pointer != NULL) {
+			profile_remove(pointer);
+		}
 		free(pointer);
 #ifdef DDP_DEBUG
 		allocatedBytes -= oldSize;
@@ -26,6 +201,12 @@ void *ddp_reallocate(void *pointer, size_t oldSize, size_t newSize) {
 		return pointer;
 	}
 
+	// a reallocated pointer keeps its type
+	const char *type = NULL;
+	if (profiling_enabled && pointer != NULL) {
+		type = profile_remove(pointer);
+	}
+
 	// realloc can act as realloc or malloc
 	// if pointer is NULL it acts as malloc
 	// otherwise as realloc
@@ -38,5 +219,9 @@ void *ddp_reallocate(void *pointer, size_t oldSize, size_t newSize) {
 		ddp_runtime_error(1, "out of memory\n");
 	}
 
+	if (profiling_enabled) {
+		profile_insert(result, newSize, type);
+	}
+
 	return result;
 }
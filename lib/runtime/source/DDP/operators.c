@@ -3,12 +3,14 @@
 */
 #include "DDP/ddpmemory.h"
 #include "DDP/ddptypes.h"
+#include "DDP/runtime.h"
 #include "DDP/debug.h"
 #include "DDP/utf8/utf8.h"
 #include <float.h>
 #include <stdlib.h>
 #include <string.h>
 
+// returns the number of utf8 codepoints (not bytes) in str
 ddpint ddp_string_length(ddpstring *str) {
 	if (ddp_string_empty(str)) {
 		return 0;
@@ -16,7 +18,9 @@ ddpint ddp_string_length(ddpstring *str) {
 	return (ddpint)utf8_strlen(str->str);
 }
 
-ddpchar ddp_string_index(ddpstring *str, ddpint index) {
+// returns the byte offset of the codepoint at index (1-based) in str
+// errors if index is out of range
+static size_t string_index_offset(ddpstring *str, ddpint index) {
 	if (index < 1) {
 		ddp_runtime_error(1, "Texte fangen bei Index 1 an. Es wurde wurde versucht " DDP_INT_FMT " zu indizieren\n", index);
 	}
@@ -35,29 +39,22 @@ ddpchar ddp_string_index(ddpstring *str, ddpint index) {
 		ddp_runtime_error(1, "Index außerhalb der Text Länge (Index war " DDP_INT_FMT ", Text Länge war " DDP_INT_FMT ")\n", index, utf8_strlen(str->str));
 	}
 
+	return i;
+}
+
+// returns the char at index (1-based, in codepoints) in str
+ddpchar ddp_string_index(ddpstring *str, ddpint index) {
+	size_t i = string_index_offset(str, index);
+
 	uint32_t result;
 	utf8_string_to_char(str->str + i, &result);
 	return (ddpchar)result;
 }
 
+// replaces the char at index (1-based, in codepoints) in str with ch
+// str is modified in place and is expected to be owned by the caller
 void ddp_replace_char_in_string(ddpstring *str, ddpchar ch, ddpint index) {
-	if (index < 1) {
-		ddp_runtime_error(1, "Texte fangen bei Index 1 an. Es wurde wurde versucht " DDP_INT_FMT " zu indizieren\n", index);
-	}
-
-	if (index > str->cap || str->cap <= 1) {
-		ddp_runtime_error(1, "Index außerhalb der Text Länge (Index war " DDP_INT_FMT ", Text Länge war " DDP_INT_FMT ")\n", index, utf8_strlen(str->str));
-	}
-
-	size_t i = 0, len = index;
-	while (str->str[i] != 0 && len > 1) {
-		i += utf8_num_bytes(str->str + i);
-		len--;
-	}
-
-	if (str->str[i] == 0) {
-		ddp_runtime_error(1, "Index außerhalb der Text Länge (Index war " DDP_INT_FMT ", Text Länge war " DDP_INT_FMT ")\n", index, utf8_strlen(str->str));
-	}
+	size_t i = string_index_offset(str, index);
 
 	size_t oldCharLen = utf8_num_bytes(str->str + i);
 	char newChar[5];
@@ -197,6 +194,52 @@ void ddp_string_char_verkettet(ddpstring *ret, ddpstring *str, ddpchar c) {
 	*str = DDP_EMPTY_STRING;
 }
 
+// repeats the first num_bytes bytes of str n times into ret
+// n <= 0 results in an empty string
+static void repeat_bytes(ddpstring *ret, const char *str, size_t num_bytes, ddpint n) {
+	if (n <= 0 || num_bytes == 0) {
+		*ret = DDP_EMPTY_STRING;
+		return;
+	}
+
+	if ((size_t)n > (SIZE_MAX - 1) / num_bytes) {
+		ddp_runtime_error(1, "Ein Text mit %zu Bytes kann nicht " DDP_INT_FMT " mal wiederholt werden, da das Ergebnis zu lang wäre\n", num_bytes, n);
+	}
+
+	ret->cap = num_bytes * (size_t)n + 1;
+	ret->str = DDP_ALLOCATE(char, ret->cap);
+	for (ddpint i = 0; i < n; i++) {
+		memcpy(&ret->str[(size_t)i * num_bytes], str, num_bytes);
+	}
+	ret->str[ret->cap - 1] = '\0';
+}
+
+// repeats str n times
+// n <= 0 results in an empty string
+// str is not modified
+void ddp_string_mal(ddpstring *ret, ddpstring *str, ddpint n) {
+	DDP_DBGLOG("_ddp_string_mal: %p, ret: %p", str, ret);
+
+	if (ddp_string_empty(str)) {
+		*ret = DDP_EMPTY_STRING;
+		return;
+	}
+	repeat_bytes(ret, str->str, str->cap - 1, n);
+}
+
+// repeats c n times
+// n <= 0 results in an empty string
+void ddp_char_mal(ddpstring *ret, ddpchar c, ddpint n) {
+	DDP_DBGLOG("_ddp_char_mal: ret: %p", ret);
+
+	char temp[5];
+	size_t num_bytes = utf8_char_to_string(temp, c);
+	if (num_bytes == (size_t)-1) { // invalid utf8, string will be empty
+		num_bytes = 0;
+	}
+	repeat_bytes(ret, temp, num_bytes, n);
+}
+
 ddpint ddp_string_to_int(ddpstring *str) {
 	if (ddp_string_empty(str)) {
 		return 0; // empty string
@@ -205,6 +248,19 @@ ddpint ddp_string_to_int(ddpstring *str) {
 	return strtoll(str->str, NULL, 10); // cast the copy to int
 }
 
+// converts a Text with exactly one codepoint to a Buchstabe
+// errors if str is empty or contains more than one codepoint
+ddpchar ddp_string_to_char(ddpstring *str) {
+	size_t len = ddp_string_empty(str) ? 0 : utf8_strlen(str->str);
+	if (len != 1) {
+		ddp_runtime_error(1, "Ein Text mit " DDP_INT_FMT " Buchstaben kann nicht in einen Buchstaben umgewandelt werden\n", (ddpint)len);
+	}
+
+	uint32_t result;
+	utf8_string_to_char(str->str, &result);
+	return (ddpchar)result;
+}
+
 ddpfloat ddp_string_to_float(ddpstring *str) {
 	if (ddp_string_empty(str)) {
 		return 0; // empty string
@@ -246,18 +302,14 @@ void ddp_float_to_string(ddpstring *ret, ddpfloat f) {
 void ddp_bool_to_string(ddpstring *ret, ddpbool b) {
 	DDP_DBGLOG("_ddp_bool_to_string: %p", ret);
 
-	char *string;
+	const char *text = ddp_bool_text(b);
+	size_t len = strlen(text);
+
+	char *string = DDP_ALLOCATE(char, len + 1); // the char array of the string + null-terminator
+	memcpy(string, text, len + 1);
 
-	if (b) {
-		ret->cap = 5;
-		string = DDP_ALLOCATE(char, 5);
-		memcpy(string, "wahr", 5);
-	} else {
-		ret->cap = 7;
-		string = DDP_ALLOCATE(char, 7);
-		memcpy(string, "falsch", 7);
-	}
 	ret->str = string;
+	ret->cap = len + 1;
 }
 
 void ddp_char_to_string(ddpstring *ret, ddpchar c) {
@@ -286,3 +338,16 @@ ddpbool ddp_string_equal(ddpstring *str1, ddpstring *str2) {
 	}
 	return memcmp(str1->str, str2->str, str1->cap) == 0;
 }
+
+// compares str1 and str2 lexicographically by their codepoints
+// (for utf8 this is the same as comparing their bytes)
+// no locale specific collation is used, so e.g. 'Z' comes before 'a' and 'ä' after 'z'
+// returns a negative number if str1 comes before str2,
+// 0 if they are equal and a positive number if str1 comes after str2
+ddpint ddp_string_compare(ddpstring *str1, ddpstring *str2) {
+	if (str1 == str2) {
+		return 0;
+	}
+	// strcmp compares the bytes as unsigned char
+	return (ddpint)strcmp(ddp_string_empty(str1) ? "" : str1->str, ddp_string_empty(str2) ? "" : str2->str);
+}
@@ -5,6 +5,7 @@
 #include "DDP/runtime.h"
 #include <locale.h>
 #include <signal.h>
+#include <stdlib.h>
 
 #include "DDP/ddpwindows.h"
 
@@ -33,6 +34,37 @@ static void handle_args(int argc, char **argv) {
 	}
 }
 
+// the Texte of wahr and falsch (see ddp_bool_text)
+static const char *true_text = "wahr";
+static const char *false_text = "falsch";
+
+// reads the Texte of wahr and falsch from the environment
+static void handle_bool_texts(void) {
+	const char *text = getenv("DDP_WAHR");
+	if (text != NULL) {
+		true_text = text;
+	}
+	if ((text = getenv("DDP_FALSCH")) != NULL) {
+		false_text = text;
+	}
+}
+
+const char *ddp_bool_text(ddpbool b) {
+	return b ? true_text : false_text;
+}
+
+void ddp_assert_failed(const char *file, ddpint line, ddpint column, ddpstring *msg) {
+	if (msg == NULL || ddp_string_empty(msg)) {
+		ddp_runtime_error(1, "%s, Zeile " DDP_INT_FMT ", Spalte " DDP_INT_FMT ": Die Sicherstellung ist fehlgeschlagen\n", file, line, column);
+	}
+	ddp_runtime_error(1, "%s, Zeile " DDP_INT_FMT ", Spalte " DDP_INT_FMT ": Die Sicherstellung ist fehlgeschlagen: %s\n", file, line, column, msg->str);
+}
+
+void ddp_print_string(ddpstring *str, ddpbool newline) {
+	// {NULL, 0} is a valid string, so we need to check for NULL
+	printf(newline ? DDP_STRING_FMT "\n" : DDP_STRING_FMT, str->str ? str->str : "");
+}
+
 // initialize runtime stuff
 void ddp_init_runtime(int argc, char **argv) {
 	DDP_DBGLOG("init_runtime");
@@ -53,6 +85,7 @@ void ddp_init_runtime(int argc, char **argv) {
 	signal(SIGSEGV, SignalHandler); // "catch" segfaults
 
 	handle_args(argc, argv); // turn the commandline args into a ddpstringlist
+	handle_bool_texts();
 }
 
 // end the runtime
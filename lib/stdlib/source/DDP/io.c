@@ -8,6 +8,7 @@
 #include "DDP/ddpmemory.h"
 #include "DDP/ddptypes.h"
 #include "DDP/ddpwindows.h"
+#include "DDP/runtime.h"
 #include "DDP/debug.h"
 #include "DDP/utf8/utf8.h"
 #include <math.h>
@@ -38,7 +39,7 @@ void Schreibe_Kommazahl(ddpfloat p1) {
 }
 
 void Schreibe_Wahrheitswert(ddpbool p1) {
-	printf(p1 ? "wahr" : "falsch");
+	printf(DDP_STRING_FMT, ddp_bool_text(p1));
 }
 
 void Schreibe_Buchstabe(ddpchar p1) {
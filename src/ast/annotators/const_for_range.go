@@ -0,0 +1,168 @@
+package annotators
+
+import (
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+)
+
+const ConstForRangeMetaKind ast.MetadataKind = "ConstForRange"
+
+// attached to ForRangeStmts whose In is a local variable
+// that is never changed in the loop body
+// such loops can iterate over the variable itself instead of over a copy
+type ConstForRangeMeta struct{}
+
+var _ ast.MetadataAttachment = (*ConstForRangeMeta)(nil)
+
+func (m ConstForRangeMeta) String() string {
+	return "ConstForRangeMeta"
+}
+
+func (m ConstForRangeMeta) Kind() ast.MetadataKind {
+	return ConstForRangeMetaKind
+}
+
+// attaches ConstForRangeMeta to the ForRangeStmts that
+// iterate over a local variable which is only read in the loop body
+//
+// the variable may not be
+//   - global, as every called function might change it
+//   - a parameter, as it might reference a global or
+//     (for const parameters) the argument is not copied
+//
+// and in the loop body it may not be
+//   - assigned to (or one of its elements or fields)
+//   - passed to a reference parameter that is not const (see ConstFuncParamAnnotator),
+//     also of an operator overload
+//   - passed to a function value
+//
+// it has to run after the ConstFuncParamAnnotator
+type ConstForRangeAnnotator struct {
+	ast.BaseVisitor
+	params map[*ast.VarDecl]struct{} // parameters of the current function
+}
+
+var (
+	_ ast.Annotator           = (*ConstForRangeAnnotator)(nil)
+	_ ast.FuncDeclVisitor     = (*ConstForRangeAnnotator)(nil)
+	_ ast.ForRangeStmtVisitor = (*ConstForRangeAnnotator)(nil)
+)
+
+func (a *ConstForRangeAnnotator) VisitFuncDecl(decl *ast.FuncDecl) ast.VisitResult {
+	a.params = make(map[*ast.VarDecl]struct{}, len(decl.Parameters))
+	if decl.Body == nil {
+		return ast.VisitRecurse
+	}
+
+	for _, funcParam := range decl.Parameters {
+		if param, _, isVar := decl.Body.Symbols.LookupVar(funcParam.Name.Literal); isVar {
+			a.params[param] = struct{}{}
+		}
+	}
+	return ast.VisitRecurse
+}
+
+func (a *ConstForRangeAnnotator) VisitForRangeStmt(stmt *ast.ForRangeStmt) ast.VisitResult {
+	ident, isIdent := stmt.In.(*ast.Ident)
+	if !isIdent || ident.Declaration == nil {
+		return ast.VisitRecurse
+	}
+
+	decl := ident.Declaration
+	if decl.Mod != a.CurrentModule || a.CurrentModule.Ast.Symbols.Declarations[decl.Name()] == ast.Declaration(decl) {
+		return ast.VisitRecurse
+	}
+	if _, isParam := a.params[decl]; isParam {
+		return ast.VisitRecurse
+	}
+
+	finder := &mutationFinder{decls: []*ast.VarDecl{decl}}
+	ast.VisitNode(finder, stmt.Body, nil)
+	if !finder.found {
+		a.CurrentModule.Ast.AddAttachement(stmt, ConstForRangeMeta{})
+	}
+	return ast.VisitRecurse
+}
+
+// searches for statements and expressions that might change one of decls
+type mutationFinder struct {
+	ast.BaseVisitor
+	decls []*ast.VarDecl
+	found bool
+}
+
+var (
+	_ ast.AssignStmtVisitor    = (*mutationFinder)(nil)
+	_ ast.FuncCallVisitor      = (*mutationFinder)(nil)
+	_ ast.FuncValueCallVisitor = (*mutationFinder)(nil)
+	_ ast.UnaryExprVisitor     = (*mutationFinder)(nil)
+	_ ast.BinaryExprVisitor    = (*mutationFinder)(nil)
+	_ ast.TernaryExprVisitor   = (*mutationFinder)(nil)
+	_ ast.CastExprVisitor      = (*mutationFinder)(nil)
+)
+
+func (f *mutationFinder) VisitAssignStmt(stmt *ast.AssignStmt) ast.VisitResult {
+	return f.check(stmt.Var)
+}
+
+func (f *mutationFinder) VisitFuncCall(call *ast.FuncCall) ast.VisitResult {
+	return f.checkReferenceArgs(call.Func, call.Args)
+}
+
+// operator overloads may take reference parameters as well
+func (f *mutationFinder) VisitUnaryExpr(expr *ast.UnaryExpr) ast.VisitResult {
+	return f.checkOverload(expr.OverloadedBy)
+}
+
+func (f *mutationFinder) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
+	return f.checkOverload(expr.OverloadedBy)
+}
+
+func (f *mutationFinder) VisitTernaryExpr(expr *ast.TernaryExpr) ast.VisitResult {
+	return f.checkOverload(expr.OverloadedBy)
+}
+
+func (f *mutationFinder) VisitCastExpr(expr *ast.CastExpr) ast.VisitResult {
+	return f.checkOverload(expr.OverloadedBy)
+}
+
+func (f *mutationFinder) checkOverload(overload *ast.OperatorOverload) ast.VisitResult {
+	if overload == nil {
+		return ast.VisitRecurse
+	}
+	return f.checkReferenceArgs(overload.Decl, overload.Args)
+}
+
+// checks the arguments of fun that are passed to non-const reference parameters
+func (f *mutationFinder) checkReferenceArgs(fun *ast.FuncDecl, args map[string]ast.Expression) ast.VisitResult {
+	var isConst map[string]bool
+	if attachement, ok := fun.Module().Ast.GetMetadataByKind(fun, ConstFuncParamMetaKind); ok && attachement != nil {
+		isConst = attachement.(ConstFuncParamMeta).IsConst
+	}
+
+	for _, param := range fun.Parameters {
+		if !param.Type.IsReference || isConst[param.Name.Literal] {
+			continue
+		}
+		if f.check(args[param.Name.Literal]) == ast.VisitBreak {
+			return ast.VisitBreak
+		}
+	}
+	return ast.VisitRecurse
+}
+
+func (f *mutationFinder) VisitFuncValueCall(call *ast.FuncValueCall) ast.VisitResult {
+	for _, arg := range call.Args {
+		if f.check(arg) == ast.VisitBreak {
+			return ast.VisitBreak
+		}
+	}
+	return ast.VisitRecurse
+}
+
+func (f *mutationFinder) check(expr ast.Expression) ast.VisitResult {
+	if len(doesReferenceVarMutable(expr, f.decls)) > 0 {
+		f.found = true
+		return ast.VisitBreak
+	}
+	return ast.VisitRecurse
+}
@@ -0,0 +1,82 @@
+package annotators
+
+import (
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstForRange(t *testing.T) {
+	assert := assert.New(t)
+
+	src := `Die Zahlen Liste g ist eine Liste, die aus 1, 2 besteht.
+Die Funktion verändere mit dem Parameter l vom Typ Zahlen Listen Referenz, gibt nichts zurück, macht:
+	Speichere 1 in l an der Stelle 1.
+Und kann so benutzt werden:
+	"verändere <l>"
+Die Funktion lies mit dem Parameter l vom Typ Zahlen Listen Referenz, gibt eine Zahl zurück, macht:
+	Gib die Länge von l zurück.
+Und kann so benutzt werden:
+	"lies <l>"
+Die Funktion betrag mit dem Parameter a vom Typ Zahlen Listen Referenz, gibt eine Zahl zurück, macht:
+	Speichere eine Liste, die aus 1 besteht in a.
+	Gib 1 zurück.
+Und überlädt den "Betrag" Operator.
+Die Funktion f mit dem Parameter p vom Typ Zahlen Liste, gibt nichts zurück, macht:
+	Die Zahlen Liste l ist eine Liste, die aus 1, 2 besteht.
+	Die Zahl n ist 0.
+	Für jede Zahl z in l, mache:
+		Erhöhe n um z plus (die Länge von l) plus (l an der Stelle 1).
+	Für jede Zahl z in l, mache:
+		Speichere eine Liste, die aus 3 besteht in l.
+	Für jede Zahl z in l, mache:
+		Speichere 3 in l an der Stelle 1.
+	Für jede Zahl z in l, mache:
+		verändere l.
+	Für jede Zahl z in l, mache:
+		Erhöhe n um lies l.
+	Für jede Zahl z in g, mache:
+		Erhöhe n um z.
+	Für jede Zahl z in p, mache:
+		Erhöhe n um z.
+	Für jede Zahl z in l verkettet mit l, mache:
+		Erhöhe n um z.
+	Für jede Zahl z in l, mache:
+		Erhöhe n um (der Betrag von l).
+Und kann so benutzt werden:
+	"f <p>"
+`
+	module, err := parser.Parse(parser.Options{
+		FileName:     "test.ddp",
+		Source:       []byte(src),
+		ErrorHandler: ddperror.MakePanicHandler(),
+		Annotators:   []ast.Annotator{&ConstFuncParamAnnotator{}, &ConstForRangeAnnotator{}},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	var isConst []bool
+	body := module.Ast.Statements[4].(*ast.DeclStmt).Decl.(*ast.FuncDecl).Body
+	for _, stmt := range body.Statements {
+		if stmt, ok := stmt.(*ast.ForRangeStmt); ok {
+			meta, _ := module.Ast.GetMetadataByKind(stmt, ConstForRangeMetaKind)
+			isConst = append(isConst, meta != nil)
+		}
+	}
+
+	assert.Equal([]bool{
+		true,  // only read
+		false, // assigned
+		false, // element assigned
+		false, // passed to a reference parameter
+		true,  // passed to a const reference parameter
+		false, // global
+		false, // parameter
+		false, // no variable
+		false, // passed to a reference parameter of an operator overload
+	}, isConst)
+}
@@ -73,9 +73,8 @@ func (a *ConstFuncParamAnnotator) VisitFuncDecl(decl *ast.FuncDecl) ast.VisitRes
 		body = decl.Def.Body
 	}
 	for _, funcParam := range decl.Parameters {
-		param, exists, isVar := body.Symbols.LookupDecl(funcParam.Name.Literal)
-		if exists && isVar {
-			a.currentParams[param.(*ast.VarDecl)] = true
+		if param, _, isVar := body.Symbols.LookupVar(funcParam.Name.Literal); isVar {
+			a.currentParams[param] = true
 			attachement.IsConst[funcParam.Name.Literal] = true
 		}
 	}
@@ -0,0 +1,404 @@
+package annotators
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+)
+
+// replaces expressions that only consist of literals
+// (like 2 mal 3 plus 4) with a single literal
+// so that the compiler does not have to generate code for them
+//
+// this annotator does not attach metadata but changes the AST in-place
+type ConstantFoldingAnnotator struct {
+	ast.BaseVisitor
+}
+
+var (
+	_ ast.Annotator            = (*ConstantFoldingAnnotator)(nil)
+	_ ast.VarDeclVisitor       = (*ConstantFoldingAnnotator)(nil)
+	_ ast.IndexingVisitor      = (*ConstantFoldingAnnotator)(nil)
+	_ ast.ListLitVisitor       = (*ConstantFoldingAnnotator)(nil)
+	_ ast.UnaryExprVisitor     = (*ConstantFoldingAnnotator)(nil)
+	_ ast.BinaryExprVisitor    = (*ConstantFoldingAnnotator)(nil)
+	_ ast.TernaryExprVisitor   = (*ConstantFoldingAnnotator)(nil)
+	_ ast.CastExprVisitor      = (*ConstantFoldingAnnotator)(nil)
+	_ ast.TypeCheckVisitor     = (*ConstantFoldingAnnotator)(nil)
+	_ ast.GroupingVisitor      = (*ConstantFoldingAnnotator)(nil)
+	_ ast.FuncCallVisitor      = (*ConstantFoldingAnnotator)(nil)
+	_ ast.StructLiteralVisitor = (*ConstantFoldingAnnotator)(nil)
+	_ ast.FuncValueCallVisitor = (*ConstantFoldingAnnotator)(nil)
+	_ ast.ExprStmtVisitor      = (*ConstantFoldingAnnotator)(nil)
+	_ ast.AssignStmtVisitor    = (*ConstantFoldingAnnotator)(nil)
+	_ ast.IfStmtVisitor        = (*ConstantFoldingAnnotator)(nil)
+	_ ast.WhileStmtVisitor     = (*ConstantFoldingAnnotator)(nil)
+	_ ast.ForStmtVisitor       = (*ConstantFoldingAnnotator)(nil)
+	_ ast.ForRangeStmtVisitor  = (*ConstantFoldingAnnotator)(nil)
+	_ ast.ReturnStmtVisitor    = (*ConstantFoldingAnnotator)(nil)
+)
+
+// the visit functions replace the child expressions of every node
+// the children are visited afterwards, so nested expressions
+// that could not be folded as a whole are folded too
+
+func (a *ConstantFoldingAnnotator) VisitVarDecl(decl *ast.VarDecl) ast.VisitResult {
+	decl.InitVal = FoldConstant(decl.InitVal)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitIndexing(expr *ast.Indexing) ast.VisitResult {
+	expr.Index = FoldConstant(expr.Index)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitListLit(expr *ast.ListLit) ast.VisitResult {
+	for i := range expr.Values {
+		expr.Values[i] = FoldConstant(expr.Values[i])
+	}
+	expr.Count = FoldConstant(expr.Count)
+	expr.Value = FoldConstant(expr.Value)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitUnaryExpr(expr *ast.UnaryExpr) ast.VisitResult {
+	expr.Rhs = FoldConstant(expr.Rhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
+	expr.Lhs = FoldConstant(expr.Lhs)
+	expr.Rhs = FoldConstant(expr.Rhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitTernaryExpr(expr *ast.TernaryExpr) ast.VisitResult {
+	expr.Lhs = FoldConstant(expr.Lhs)
+	expr.Mid = FoldConstant(expr.Mid)
+	expr.Rhs = FoldConstant(expr.Rhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitCastExpr(expr *ast.CastExpr) ast.VisitResult {
+	expr.Lhs = FoldConstant(expr.Lhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitTypeCheck(expr *ast.TypeCheck) ast.VisitResult {
+	expr.Lhs = FoldConstant(expr.Lhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitGrouping(expr *ast.Grouping) ast.VisitResult {
+	expr.Expr = FoldConstant(expr.Expr)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitFuncCall(expr *ast.FuncCall) ast.VisitResult {
+	for name, arg := range expr.Args {
+		expr.Args[name] = FoldConstant(arg)
+	}
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResult {
+	for name, arg := range expr.Args {
+		expr.Args[name] = FoldConstant(arg)
+	}
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitFuncValueCall(expr *ast.FuncValueCall) ast.VisitResult {
+	for i := range expr.Args {
+		expr.Args[i] = FoldConstant(expr.Args[i])
+	}
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitExprStmt(stmt *ast.ExprStmt) ast.VisitResult {
+	stmt.Expr = FoldConstant(stmt.Expr)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitAssignStmt(stmt *ast.AssignStmt) ast.VisitResult {
+	stmt.Rhs = FoldConstant(stmt.Rhs)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitIfStmt(stmt *ast.IfStmt) ast.VisitResult {
+	stmt.Condition = FoldConstant(stmt.Condition)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitWhileStmt(stmt *ast.WhileStmt) ast.VisitResult {
+	stmt.Condition = FoldConstant(stmt.Condition)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitForStmt(stmt *ast.ForStmt) ast.VisitResult {
+	stmt.To = FoldConstant(stmt.To)
+	stmt.StepSize = FoldConstant(stmt.StepSize)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitForRangeStmt(stmt *ast.ForRangeStmt) ast.VisitResult {
+	stmt.In = FoldConstant(stmt.In)
+	return ast.VisitRecurse
+}
+
+func (a *ConstantFoldingAnnotator) VisitReturnStmt(stmt *ast.ReturnStmt) ast.VisitResult {
+	stmt.Value = FoldConstant(stmt.Value)
+	return ast.VisitRecurse
+}
+
+// returns expr with all sub-expressions that only consist of
+// Zahl, Kommazahl and Wahrheitswert literals replaced by a single literal
+// the returned literal spans the range of the replaced expression
+//
+// expressions are not folded if the result would differ from
+// what the compiled program computes (integer overflow, division by zero,
+// NaN or infinite results, calls to the C math library etc.)
+// or if the operator is overloaded
+// in that case expr itself is returned, but its operands may still be folded
+func FoldConstant(expr ast.Expression) ast.Expression {
+	switch e := expr.(type) {
+	case *ast.Grouping:
+		e.Expr = FoldConstant(e.Expr)
+		if isFoldedLiteral(e.Expr) {
+			return withRange(e.Expr, e.GetRange())
+		}
+	case *ast.UnaryExpr:
+		if e.OverloadedBy != nil {
+			return expr
+		}
+		e.Rhs = FoldConstant(e.Rhs)
+		if result := foldUnary(e.Operator, e.Rhs); result != nil {
+			return withRange(result, e.GetRange())
+		}
+	case *ast.BinaryExpr:
+		if e.OverloadedBy != nil {
+			return expr
+		}
+		e.Lhs = FoldConstant(e.Lhs)
+		e.Rhs = FoldConstant(e.Rhs)
+		if result := foldBinary(e.Operator, e.Lhs, e.Rhs); result != nil {
+			return withRange(result, e.GetRange())
+		}
+	}
+	return expr
+}
+
+// wether expr is one of the literals produced by FoldConstant
+func isFoldedLiteral(expr ast.Expression) bool {
+	switch expr.(type) {
+	case *ast.IntLit, *ast.FloatLit, *ast.BoolLit:
+		return true
+	}
+	return false
+}
+
+func foldUnary(op ast.UnaryOperator, rhs ast.Expression) ast.Expression {
+	switch rhs := rhs.(type) {
+	case *ast.IntLit:
+		switch op {
+		case ast.UN_NEGATE:
+			if rhs.Value != math.MinInt64 {
+				return newIntLit(-rhs.Value)
+			}
+		case ast.UN_ABS:
+			if rhs.Value != math.MinInt64 {
+				return newIntLit(max(rhs.Value, -rhs.Value))
+			}
+		case ast.UN_LOGIC_NOT:
+			return newIntLit(^rhs.Value)
+		}
+	case *ast.FloatLit:
+		switch op {
+		case ast.UN_NEGATE:
+			return newFloatLit(-rhs.Value)
+		case ast.UN_ABS:
+			// like the compiler, so that -0,0 stays -0,0 (which is not folded)
+			if rhs.Value < 0 {
+				return newFloatLit(-rhs.Value)
+			}
+			return newFloatLit(rhs.Value)
+		}
+	case *ast.BoolLit:
+		if op == ast.UN_NOT {
+			return newBoolLit(!rhs.Value)
+		}
+	}
+	return nil
+}
+
+func foldBinary(op ast.BinaryOperator, lhs, rhs ast.Expression) ast.Expression {
+	if lhs, ok := lhs.(*ast.BoolLit); ok {
+		if rhs, ok := rhs.(*ast.BoolLit); ok {
+			return foldBool(op, lhs.Value, rhs.Value)
+		}
+		return nil
+	}
+
+	lhsInt, lhsIsInt := lhs.(*ast.IntLit)
+	rhsInt, rhsIsInt := rhs.(*ast.IntLit)
+	// durch always returns a Kommazahl
+	if lhsIsInt && rhsIsInt && op != ast.BIN_DIV {
+		return foldInt(op, lhsInt.Value, rhsInt.Value)
+	}
+
+	// Zahlen are converted to Kommazahlen in mixed expressions
+	// and for operators that always return a Kommazahl
+	lhsFloat, lhsOk := asFloat(lhs)
+	rhsFloat, rhsOk := asFloat(rhs)
+	if !lhsOk || !rhsOk {
+		return nil
+	}
+	// the typechecker only allows gleich/ungleich for equal types
+	if (op == ast.BIN_EQUAL || op == ast.BIN_UNEQUAL) && lhsIsInt != rhsIsInt {
+		return nil
+	}
+	return foldFloat(op, lhsFloat, rhsFloat)
+}
+
+func foldBool(op ast.BinaryOperator, lhs, rhs bool) ast.Expression {
+	switch op {
+	case ast.BIN_AND:
+		return newBoolLit(lhs && rhs)
+	case ast.BIN_OR:
+		return newBoolLit(lhs || rhs)
+	case ast.BIN_XOR, ast.BIN_UNEQUAL:
+		return newBoolLit(lhs != rhs)
+	case ast.BIN_EQUAL:
+		return newBoolLit(lhs == rhs)
+	}
+	return nil
+}
+
+func foldInt(op ast.BinaryOperator, lhs, rhs int64) ast.Expression {
+	switch op {
+	case ast.BIN_PLUS:
+		if result := lhs + rhs; (result > lhs) == (rhs > 0) {
+			return newIntLit(result)
+		}
+	case ast.BIN_MINUS:
+		if result := lhs - rhs; (result < lhs) == (rhs > 0) {
+			return newIntLit(result)
+		}
+	case ast.BIN_MULT:
+		if result := lhs * rhs; lhs == 0 || (result/lhs == rhs && !(lhs == -1 && rhs == math.MinInt64)) {
+			return newIntLit(result)
+		}
+	case ast.BIN_MOD:
+		if rhs != 0 && !(lhs == math.MinInt64 && rhs == -1) {
+			return newIntLit(lhs % rhs)
+		}
+	case ast.BIN_LOGIC_AND:
+		return newIntLit(lhs & rhs)
+	case ast.BIN_LOGIC_OR:
+		return newIntLit(lhs | rhs)
+	case ast.BIN_LOGIC_XOR:
+		return newIntLit(lhs ^ rhs)
+	case ast.BIN_LEFT_SHIFT:
+		// shifting by the bit width or more is undefined in LLVM
+		if rhs >= 0 && rhs < 64 {
+			return newIntLit(lhs << rhs)
+		}
+	case ast.BIN_RIGHT_SHIFT:
+		if rhs >= 0 && rhs < 64 {
+			return newIntLit(int64(uint64(lhs) >> rhs))
+		}
+	case ast.BIN_EQUAL:
+		return newBoolLit(lhs == rhs)
+	case ast.BIN_UNEQUAL:
+		return newBoolLit(lhs != rhs)
+	case ast.BIN_LESS:
+		return newBoolLit(lhs < rhs)
+	case ast.BIN_LESS_EQ:
+		return newBoolLit(lhs <= rhs)
+	case ast.BIN_GREATER:
+		return newBoolLit(lhs > rhs)
+	case ast.BIN_GREATER_EQ:
+		return newBoolLit(lhs >= rhs)
+	}
+	return nil
+}
+
+// hoch and Logarithmus are not folded, because they call
+// the C math library whose results might differ from Go's
+func foldFloat(op ast.BinaryOperator, lhs, rhs float64) ast.Expression {
+	switch op {
+	case ast.BIN_PLUS:
+		return newFloatLit(lhs + rhs)
+	case ast.BIN_MINUS:
+		return newFloatLit(lhs - rhs)
+	case ast.BIN_MULT:
+		return newFloatLit(lhs * rhs)
+	case ast.BIN_DIV:
+		return newFloatLit(lhs / rhs)
+	case ast.BIN_EQUAL:
+		return newBoolLit(lhs == rhs)
+	case ast.BIN_UNEQUAL:
+		return newBoolLit(lhs != rhs)
+	case ast.BIN_LESS:
+		return newBoolLit(lhs < rhs)
+	case ast.BIN_LESS_EQ:
+		return newBoolLit(lhs <= rhs)
+	case ast.BIN_GREATER:
+		return newBoolLit(lhs > rhs)
+	case ast.BIN_GREATER_EQ:
+		return newBoolLit(lhs >= rhs)
+	}
+	return nil
+}
+
+func asFloat(expr ast.Expression) (float64, bool) {
+	switch expr := expr.(type) {
+	case *ast.IntLit:
+		return float64(expr.Value), true
+	case *ast.FloatLit:
+		return expr.Value, true
+	}
+	return 0, false
+}
+
+func newIntLit(value int64) ast.Expression {
+	return &ast.IntLit{
+		Literal: token.Token{Type: token.INT, Literal: strconv.FormatInt(value, 10)},
+		Value:   value,
+	}
+}
+
+// returns nil for infinite or NaN results
+// and for negative zero, whose sign might get lost
+func newFloatLit(value float64) ast.Expression {
+	if math.IsInf(value, 0) || math.IsNaN(value) || (value == 0 && math.Signbit(value)) {
+		return nil
+	}
+	return &ast.FloatLit{
+		Literal: token.Token{Type: token.FLOAT, Literal: strings.ReplaceAll(strconv.FormatFloat(value, 'f', -1, 64), ".", ",")},
+		Value:   value,
+	}
+}
+
+func newBoolLit(value bool) ast.Expression {
+	if value {
+		return &ast.BoolLit{Literal: token.Token{Type: token.TRUE, Literal: "wahr"}, Value: true}
+	}
+	return &ast.BoolLit{Literal: token.Token{Type: token.FALSE, Literal: "falsch"}, Value: false}
+}
+
+// sets the range of a literal created by FoldConstant
+func withRange(lit ast.Expression, rang token.Range) ast.Expression {
+	switch lit := lit.(type) {
+	case *ast.IntLit:
+		lit.Literal.Range = rang
+	case *ast.FloatLit:
+		lit.Literal.Range = rang
+	case *ast.BoolLit:
+		lit.Literal.Range = rang
+	}
+	return lit
+}
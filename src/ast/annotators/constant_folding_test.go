@@ -0,0 +1,220 @@
+package annotators
+
+import (
+	"math"
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func intLit(v int64) *ast.IntLit {
+	return &ast.IntLit{Value: v}
+}
+
+func floatLit(v float64) *ast.FloatLit {
+	return &ast.FloatLit{Value: v}
+}
+
+func boolLit(v bool) *ast.BoolLit {
+	return &ast.BoolLit{Value: v}
+}
+
+func unary(op ast.UnaryOperator, rhs ast.Expression) *ast.UnaryExpr {
+	return &ast.UnaryExpr{Operator: op, Rhs: rhs}
+}
+
+func binary(op ast.BinaryOperator, lhs, rhs ast.Expression) *ast.BinaryExpr {
+	return &ast.BinaryExpr{Operator: op, Lhs: lhs, Rhs: rhs}
+}
+
+func grouping(expr ast.Expression) *ast.Grouping {
+	return &ast.Grouping{Expr: expr}
+}
+
+func TestFoldConstant(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		name     string
+		expr     ast.Expression
+		expected any // int64, float64 or bool
+	}{
+		{"plus", binary(ast.BIN_PLUS, intLit(1), intLit(2)), int64(3)},
+		{"minus", binary(ast.BIN_MINUS, intLit(1), intLit(2)), int64(-1)},
+		{"mal plus", binary(ast.BIN_PLUS, binary(ast.BIN_MULT, intLit(2), intLit(3)), intLit(4)), int64(10)},
+		{"modulo", binary(ast.BIN_MOD, intLit(-7), intLit(3)), int64(-1)},
+		{"durch Zahlen", binary(ast.BIN_DIV, intLit(7), intLit(2)), 3.5},
+		{"plus gemischt", binary(ast.BIN_PLUS, intLit(1), floatLit(0.5)), 1.5},
+		{"mal Kommazahlen", binary(ast.BIN_MULT, floatLit(1.5), floatLit(2)), 3.0},
+		{"minus Kommazahl", binary(ast.BIN_MINUS, floatLit(1.5), intLit(1)), 0.5},
+		{"logisch und", binary(ast.BIN_LOGIC_AND, intLit(6), intLit(3)), int64(2)},
+		{"logisch oder", binary(ast.BIN_LOGIC_OR, intLit(6), intLit(3)), int64(7)},
+		{"logisch kontra", binary(ast.BIN_LOGIC_XOR, intLit(6), intLit(3)), int64(5)},
+		{"links verschoben", binary(ast.BIN_LEFT_SHIFT, intLit(1), intLit(4)), int64(16)},
+		{"rechts verschoben", binary(ast.BIN_RIGHT_SHIFT, intLit(-1), intLit(60)), int64(15)},
+		{"negieren", unary(ast.UN_NEGATE, intLit(5)), int64(-5)},
+		{"negieren Kommazahl", unary(ast.UN_NEGATE, floatLit(2.5)), -2.5},
+		{"Betrag", unary(ast.UN_ABS, intLit(-5)), int64(5)},
+		{"Betrag Kommazahl", unary(ast.UN_ABS, floatLit(-2.5)), 2.5},
+		{"logisch nicht", unary(ast.UN_LOGIC_NOT, intLit(0)), int64(-1)},
+		{"nicht", unary(ast.UN_NOT, boolLit(true)), false},
+		{"und", binary(ast.BIN_AND, boolLit(true), boolLit(false)), false},
+		{"oder", binary(ast.BIN_OR, boolLit(true), boolLit(false)), true},
+		{"entweder oder", binary(ast.BIN_XOR, boolLit(true), boolLit(true)), false},
+		{"gleich Wahrheitswerte", binary(ast.BIN_EQUAL, boolLit(false), boolLit(false)), true},
+		{"gleich", binary(ast.BIN_EQUAL, intLit(1), intLit(1)), true},
+		{"ungleich", binary(ast.BIN_UNEQUAL, floatLit(1), floatLit(1)), false},
+		{"kleiner", binary(ast.BIN_LESS, intLit(1), intLit(2)), true},
+		{"kleiner gemischt", binary(ast.BIN_LESS, intLit(1), floatLit(0.5)), false},
+		{"größer als, oder", binary(ast.BIN_GREATER_EQ, floatLit(2), intLit(2)), true},
+		{"kleiner als, oder", binary(ast.BIN_LESS_EQ, intLit(3), intLit(2)), false},
+		{"größer", binary(ast.BIN_GREATER, intLit(3), intLit(2)), true},
+		{"Klammern", grouping(binary(ast.BIN_PLUS, intLit(1), intLit(2))), int64(3)},
+		{"verschachtelt", binary(ast.BIN_MULT, grouping(binary(ast.BIN_PLUS, intLit(1), intLit(2))), unary(ast.UN_NEGATE, intLit(2))), int64(-6)},
+		{"Vergleich von Ergebnissen", binary(ast.BIN_AND, binary(ast.BIN_LESS, intLit(1), intLit(2)), unary(ast.UN_NOT, boolLit(false))), true},
+		{"größte Zahl", binary(ast.BIN_PLUS, intLit(math.MaxInt64-1), intLit(1)), int64(math.MaxInt64)},
+		{"kleinste Zahl", binary(ast.BIN_MINUS, intLit(math.MinInt64+1), intLit(1)), int64(math.MinInt64)},
+	}
+
+	for _, test := range tests {
+		switch expected := test.expected.(type) {
+		case int64:
+			if lit, ok := FoldConstant(test.expr).(*ast.IntLit); assert.True(ok, test.name) {
+				assert.Equal(expected, lit.Value, test.name)
+			}
+		case float64:
+			if lit, ok := FoldConstant(test.expr).(*ast.FloatLit); assert.True(ok, test.name) {
+				assert.Equal(expected, lit.Value, test.name)
+			}
+		case bool:
+			if lit, ok := FoldConstant(test.expr).(*ast.BoolLit); assert.True(ok, test.name) {
+				assert.Equal(expected, lit.Value, test.name)
+			}
+		}
+	}
+}
+
+func TestFoldConstantNotFolded(t *testing.T) {
+	assert := assert.New(t)
+
+	overloaded := binary(ast.BIN_PLUS, intLit(1), intLit(2))
+	overloaded.OverloadedBy = &ast.OperatorOverload{}
+
+	tests := []struct {
+		name string
+		expr ast.Expression
+	}{
+		{"Überlauf plus", binary(ast.BIN_PLUS, intLit(math.MaxInt64), intLit(1))},
+		{"Überlauf minus", binary(ast.BIN_MINUS, intLit(math.MinInt64), intLit(1))},
+		{"Überlauf mal", binary(ast.BIN_MULT, intLit(math.MaxInt64/2+1), intLit(2))},
+		{"Überlauf mal -1", binary(ast.BIN_MULT, intLit(-1), intLit(math.MinInt64))},
+		{"Überlauf negieren", unary(ast.UN_NEGATE, intLit(math.MinInt64))},
+		{"Überlauf Betrag", unary(ast.UN_ABS, intLit(math.MinInt64))},
+		{"modulo 0", binary(ast.BIN_MOD, intLit(1), intLit(0))},
+		{"Überlauf modulo", binary(ast.BIN_MOD, intLit(math.MinInt64), intLit(-1))},
+		{"durch 0", binary(ast.BIN_DIV, intLit(1), intLit(0))},
+		{"0 durch 0", binary(ast.BIN_DIV, floatLit(0), floatLit(0))},
+		{"unendlich", binary(ast.BIN_MULT, floatLit(math.MaxFloat64), floatLit(2))},
+		{"negative 0", unary(ast.UN_NEGATE, floatLit(0))},
+		{"negative 0 mal", binary(ast.BIN_MULT, floatLit(-1), intLit(0))},
+		{"zu weit verschoben", binary(ast.BIN_LEFT_SHIFT, intLit(1), intLit(64))},
+		{"negativ verschoben", binary(ast.BIN_RIGHT_SHIFT, intLit(1), intLit(-1))},
+		{"hoch", binary(ast.BIN_POW, intLit(2), intLit(3))},
+		{"Logarithmus", binary(ast.BIN_LOG, intLit(8), intLit(2))},
+		{"überladen", overloaded},
+		{"Variable", binary(ast.BIN_PLUS, intLit(1), &ast.Ident{})},
+		{"Kurzschluss", binary(ast.BIN_AND, boolLit(false), &ast.Ident{})},
+	}
+
+	for _, test := range tests {
+		assert.Same(test.expr, FoldConstant(test.expr), test.name)
+	}
+}
+
+func TestFoldConstantPartial(t *testing.T) {
+	assert := assert.New(t)
+
+	// (1 plus 2) plus x
+	expr := binary(ast.BIN_PLUS, grouping(binary(ast.BIN_PLUS, intLit(1), intLit(2))), &ast.Ident{})
+	assert.Same(expr, FoldConstant(expr))
+	if lit, ok := expr.Lhs.(*ast.IntLit); assert.True(ok) {
+		assert.Equal(int64(3), lit.Value)
+	}
+
+	// the operands of overloaded operators are kept
+	overloaded := unary(ast.UN_NEGATE, binary(ast.BIN_PLUS, intLit(1), intLit(2)))
+	overloaded.OverloadedBy = &ast.OperatorOverload{}
+	assert.Same(overloaded, FoldConstant(overloaded))
+	assert.IsType(&ast.BinaryExpr{}, overloaded.Rhs)
+}
+
+func TestFoldConstantRange(t *testing.T) {
+	assert := assert.New(t)
+
+	rang := token.NewRange(&token.Token{Range: token.Range{Start: token.Position{Line: 1, Column: 10}}}, &token.Token{Range: token.Range{End: token.Position{Line: 1, Column: 20}}})
+	expr := binary(ast.BIN_MULT, intLit(2), floatLit(1.25))
+	expr.Range = rang
+
+	lit, ok := FoldConstant(expr).(*ast.FloatLit)
+	if assert.True(ok) {
+		assert.Equal(rang, lit.GetRange())
+		assert.Equal(token.FLOAT, lit.Literal.Type)
+		assert.Equal("2,5", lit.Literal.Literal)
+	}
+}
+
+func TestConstantFoldingAnnotator(t *testing.T) {
+	assert := assert.New(t)
+
+	src := `Die Zahl z ist 2 mal 3 plus 4.
+Die Kommazahl k ist 1 durch 4.
+Der Wahrheitswert b ist 1 kleiner als 2 ist und wahr.
+Wenn 1 gleich 1 plus 0 ist, dann:
+	Speichere z plus (2 minus 1) in z.
+Die Zahlen Liste l ist eine Liste, die aus 1 plus 1, 2 mal 2 besteht.
+`
+	module, err := parser.Parse(parser.Options{
+		FileName:     "test.ddp",
+		Source:       []byte(src),
+		ErrorHandler: ddperror.MakePanicHandler(),
+		Annotators:   []ast.Annotator{&ConstantFoldingAnnotator{}},
+	})
+	if !assert.NoError(err) {
+		return
+	}
+
+	stmts := module.Ast.Statements
+	initVal := func(i int) ast.Expression {
+		return stmts[i].(*ast.DeclStmt).Decl.(*ast.VarDecl).InitVal
+	}
+
+	if lit, ok := initVal(0).(*ast.IntLit); assert.True(ok) {
+		assert.Equal(int64(10), lit.Value)
+	}
+	if lit, ok := initVal(1).(*ast.FloatLit); assert.True(ok) {
+		assert.Equal(0.25, lit.Value)
+	}
+	if lit, ok := initVal(2).(*ast.BoolLit); assert.True(ok) {
+		assert.True(lit.Value)
+	}
+
+	ifStmt := stmts[3].(*ast.IfStmt)
+	if lit, ok := ifStmt.Condition.(*ast.BoolLit); assert.True(ok) {
+		assert.True(lit.Value)
+	}
+	assign := ifStmt.Then.(*ast.BlockStmt).Statements[0].(*ast.AssignStmt)
+	if rhs, ok := assign.Rhs.(*ast.BinaryExpr); assert.True(ok) {
+		if lit, ok := rhs.Rhs.(*ast.IntLit); assert.True(ok) {
+			assert.Equal(int64(1), lit.Value)
+		}
+	}
+
+	if list, ok := initVal(4).(*ast.ListLit); assert.True(ok) && assert.Len(list.Values, 2) {
+		assert.Equal(int64(2), list.Values[0].(*ast.IntLit).Value)
+		assert.Equal(int64(4), list.Values[1].(*ast.IntLit).Value)
+	}
+}
@@ -25,6 +25,7 @@ type (
 		Mod             *Module       // the module in which the variable was declared
 		InitVal         Expression    // initial value
 		InitType        ddptypes.Type // type of InitVal, filled in by the typechecker, used to keep information about typedefs
+		TypeInferred    bool          // wether the declaration had no type ("Der Wert x ist ..."), then Type is filled in by the typechecker
 	}
 
 	FuncDecl struct {
@@ -66,6 +67,17 @@ type (
 		Aliases []*StructAlias       // the constructors of the struct
 	}
 
+	EnumDecl struct {
+		Range      token.Range
+		CommentTok *token.Token       // optional comment (also contained in ast.Comments)
+		Tok        token.Token        // Die
+		NameTok    token.Token        // token of the name
+		IsPublic   bool               // wether the enum decl is marked with öffentliche
+		Mod        *Module            // the module in which the enum was declared
+		Variants   []token.Token      // the names of the variants in order of declaration
+		Type       *ddptypes.EnumType // the type resulting from this decl
+	}
+
 	TypeAliasDecl struct {
 		Range           token.Range
 		CommentTok      *token.Token // optional comment
@@ -96,6 +108,7 @@ func (decl *VarDecl) node()       {}
 func (decl *FuncDecl) node()      {}
 func (decl *FuncDef) node()       {}
 func (decl *StructDecl) node()    {}
+func (decl *EnumDecl) node()      {}
 func (decl *TypeAliasDecl) node() {}
 func (decl *TypeDefDecl) node()   {}
 
@@ -104,6 +117,7 @@ func (decl *VarDecl) String() string       { return "VarDecl" }
 func (decl *FuncDecl) String() string      { return "FuncDecl" }
 func (decl *FuncDef) String() string       { return "FuncDef" }
 func (decl *StructDecl) String() string    { return "StructDecl" }
+func (decl *EnumDecl) String() string      { return "EnumDecl" }
 func (decl *TypeAliasDecl) String() string { return "TypeAliasDecl" }
 func (decl *TypeDefDecl) String() string   { return "TypeDefDecl" }
 
@@ -112,6 +126,7 @@ func (decl *VarDecl) Token() token.Token       { return decl.NameTok }
 func (decl *FuncDecl) Token() token.Token      { return decl.Tok }
 func (decl *FuncDef) Token() token.Token       { return decl.Tok }
 func (decl *StructDecl) Token() token.Token    { return decl.Tok }
+func (decl *EnumDecl) Token() token.Token      { return decl.Tok }
 func (decl *TypeAliasDecl) Token() token.Token { return decl.Tok }
 func (decl *TypeDefDecl) Token() token.Token   { return decl.Tok }
 
@@ -120,6 +135,7 @@ func (decl *VarDecl) GetRange() token.Range       { return decl.Range }
 func (decl *FuncDecl) GetRange() token.Range      { return decl.Range }
 func (decl *FuncDef) GetRange() token.Range       { return decl.Range }
 func (decl *StructDecl) GetRange() token.Range    { return decl.Range }
+func (decl *EnumDecl) GetRange() token.Range      { return decl.Range }
 func (decl *TypeAliasDecl) GetRange() token.Range { return decl.Range }
 func (decl *TypeDefDecl) GetRange() token.Range   { return decl.Range }
 
@@ -128,6 +144,7 @@ func (decl *VarDecl) Accept(visitor FullVisitor) VisitResult    { return visitor
 func (decl *FuncDecl) Accept(visitor FullVisitor) VisitResult   { return visitor.VisitFuncDecl(decl) }
 func (decl *FuncDef) Accept(visitor FullVisitor) VisitResult    { return visitor.VisitFuncDef(decl) }
 func (decl *StructDecl) Accept(visitor FullVisitor) VisitResult { return visitor.VisitStructDecl(decl) }
+func (decl *EnumDecl) Accept(visitor FullVisitor) VisitResult   { return visitor.VisitEnumDecl(decl) }
 func (decl *TypeAliasDecl) Accept(visitor FullVisitor) VisitResult {
 	return visitor.VisitTypeAliasDecl(decl)
 }
@@ -141,6 +158,7 @@ func (decl *VarDecl) declarationNode()       {}
 func (decl *FuncDecl) declarationNode()      {}
 func (decl *FuncDef) statementNode()         {}
 func (decl *StructDecl) declarationNode()    {}
+func (decl *EnumDecl) declarationNode()      {}
 func (decl *TypeAliasDecl) declarationNode() {}
 func (decl *TypeDefDecl) declarationNode()   {}
 
@@ -148,6 +166,7 @@ func (decl *BadDecl) Name() string       { return "" }
 func (decl *VarDecl) Name() string       { return decl.NameTok.Literal }
 func (decl *FuncDecl) Name() string      { return decl.NameTok.Literal }
 func (decl *StructDecl) Name() string    { return decl.NameTok.Literal }
+func (decl *EnumDecl) Name() string      { return decl.NameTok.Literal }
 func (decl *TypeAliasDecl) Name() string { return decl.NameTok.Literal }
 func (decl *TypeDefDecl) Name() string   { return decl.NameTok.Literal }
 
@@ -155,6 +174,7 @@ func (decl *BadDecl) Public() bool       { return false }
 func (decl *VarDecl) Public() bool       { return decl.IsPublic }
 func (decl *FuncDecl) Public() bool      { return decl.IsPublic }
 func (decl *StructDecl) Public() bool    { return decl.IsPublic }
+func (decl *EnumDecl) Public() bool      { return decl.IsPublic }
 func (decl *TypeAliasDecl) Public() bool { return decl.IsPublic }
 func (decl *TypeDefDecl) Public() bool   { return decl.IsPublic }
 
@@ -162,6 +182,7 @@ func (decl *BadDecl) Comment() *token.Token       { return nil }
 func (decl *VarDecl) Comment() *token.Token       { return decl.CommentTok }
 func (decl *FuncDecl) Comment() *token.Token      { return decl.CommentTok }
 func (decl *StructDecl) Comment() *token.Token    { return decl.CommentTok }
+func (decl *EnumDecl) Comment() *token.Token      { return decl.CommentTok }
 func (decl *TypeAliasDecl) Comment() *token.Token { return decl.CommentTok }
 func (decl *TypeDefDecl) Comment() *token.Token   { return decl.CommentTok }
 
@@ -169,5 +190,6 @@ func (decl *BadDecl) Module() *Module       { return decl.Mod }
 func (decl *VarDecl) Module() *Module       { return decl.Mod }
 func (decl *FuncDecl) Module() *Module      { return decl.Mod }
 func (decl *StructDecl) Module() *Module    { return decl.Mod }
+func (decl *EnumDecl) Module() *Module      { return decl.Mod }
 func (decl *TypeAliasDecl) Module() *Module { return decl.Mod }
 func (decl *TypeDefDecl) Module() *Module   { return decl.Mod }
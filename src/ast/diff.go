@@ -0,0 +1,400 @@
+package ast
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+)
+
+// kind of a Difference between two ASTs
+type DiffKind int
+
+const (
+	DIFF_ADDED   DiffKind = iota // the node only exists in the new AST
+	DIFF_REMOVED                 // the node only exists in the old AST
+	DIFF_CHANGED                 // the node exists in both ASTs but differs (e.g. in its operator, name or type)
+)
+
+func (kind DiffKind) String() string {
+	switch kind {
+	case DIFF_ADDED:
+		return "hinzugefügt"
+	case DIFF_REMOVED:
+		return "entfernt"
+	case DIFF_CHANGED:
+		return "geändert"
+	}
+	return "?"
+}
+
+// a single difference found by Diff
+type Difference struct {
+	Kind DiffKind
+	// the differing nodes
+	// Old is nil for DIFF_ADDED and New is nil for DIFF_REMOVED
+	Old, New Node
+	// short descriptions of Old and New without their children (e.g. "BinaryExpr[plus]")
+	OldDesc, NewDesc string
+}
+
+// compares the statements of old and new and returns their structural differences
+// in the order in which they appear in the source code
+// comments, positions and parentheses are ignored, as they do not change the meaning of the program
+// imported modules are not compared, only which symbols are imported from where
+func Diff(old, new *Ast) []Difference {
+	d := &differ{}
+	d.diffLists(newDiffTrees(old.Statements), newDiffTrees(new.Statements))
+	return d.differences
+}
+
+// a node reduced to what Diff compares
+type diffNode struct {
+	node     Node
+	desc     string
+	children []*diffNode
+	key      string // desc followed by the keys of all children, so equal keys mean equal subtrees
+}
+
+func newDiffTrees[T Node](nodes []T) []*diffNode {
+	trees := make([]*diffNode, 0, len(nodes))
+	for _, node := range nodes {
+		if !isNil(node) {
+			trees = append(trees, newDiffTree(node))
+		}
+	}
+	return trees
+}
+
+func newDiffTree(node Node) *diffNode {
+	// parentheses are already encoded in the structure of the AST
+	for grouping, ok := node.(*Grouping); ok; grouping, ok = node.(*Grouping) {
+		node = grouping.Expr
+	}
+
+	describer := &diffDescriber{}
+	node.Accept(describer)
+
+	tree := &diffNode{node: node, desc: describer.desc, children: newDiffTrees(describer.children)}
+	keys := make([]string, 0, len(tree.children))
+	for _, child := range tree.children {
+		keys = append(keys, child.key)
+	}
+	tree.key = tree.desc + "(" + strings.Join(keys, ",") + ")"
+	return tree
+}
+
+type differ struct {
+	differences []Difference
+}
+
+func (d *differ) report(kind DiffKind, old, new *diffNode) {
+	diff := Difference{Kind: kind}
+	if old != nil {
+		diff.Old, diff.OldDesc = old.node, old.desc
+	}
+	if new != nil {
+		diff.New, diff.NewDesc = new.node, new.desc
+	}
+	d.differences = append(d.differences, diff)
+}
+
+func (d *differ) diffNodes(old, new *diffNode) {
+	switch {
+	case old.key == new.key:
+	// nodes of different kinds are not compared further
+	case reflect.TypeOf(old.node) != reflect.TypeOf(new.node):
+		d.report(DIFF_CHANGED, old, new)
+	default:
+		if old.desc != new.desc {
+			d.report(DIFF_CHANGED, old, new)
+		}
+		d.diffLists(old.children, new.children)
+	}
+}
+
+// matches the equal subtrees of olds and news (longest common subsequence)
+// and compares the unmatched nodes in between pairwise
+func (d *differ) diffLists(olds, news []*diffNode) {
+	// lcs[i][j] is the length of the longest common subsequence of olds[i:] and news[j:]
+	lcs := make([][]int, len(olds)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(news)+1)
+	}
+	for i := len(olds) - 1; i >= 0; i-- {
+		for j := len(news) - 1; j >= 0; j-- {
+			if olds[i].key == news[j].key {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var removed, added []*diffNode
+	flush := func() {
+		for k := 0; k < min(len(removed), len(added)); k++ {
+			d.diffNodes(removed[k], added[k])
+		}
+		for _, old := range removed[min(len(removed), len(added)):] {
+			d.report(DIFF_REMOVED, old, nil)
+		}
+		for _, new := range added[min(len(removed), len(added)):] {
+			d.report(DIFF_ADDED, nil, new)
+		}
+		removed, added = removed[:0], added[:0]
+	}
+
+	i, j := 0, 0
+	for i < len(olds) || j < len(news) {
+		switch {
+		case i < len(olds) && j < len(news) && olds[i].key == news[j].key:
+			flush()
+			i, j = i+1, j+1
+		case j == len(news) || (i < len(olds) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, olds[i])
+			i++
+		default:
+			added = append(added, news[j])
+			j++
+		}
+	}
+	flush()
+}
+
+// sets the description and compared children of the visited node
+// the descriptions are like the ones of the printer, but without positions and comments
+type diffDescriber struct {
+	desc     string
+	children []Node
+}
+
+func (d *diffDescriber) describe(desc string, children ...Node) VisitResult {
+	d.desc, d.children = desc, children
+	return VisitRecurse
+}
+
+func (*diffDescriber) Visitor() {}
+
+func (d *diffDescriber) VisitBadDecl(decl *BadDecl) VisitResult {
+	return d.describe("BadDecl")
+}
+
+func (d *diffDescriber) VisitVarDecl(decl *VarDecl) VisitResult {
+	return d.describe(fmt.Sprintf("VarDecl[%s: %s, Public(%v), ExternVisible(%v)]", decl.Name(), decl.Type, decl.IsPublic, decl.IsExternVisible), decl.InitVal)
+}
+
+func (d *diffDescriber) VisitFuncDecl(decl *FuncDecl) VisitResult {
+	params := make([]string, 0, len(decl.Parameters))
+	for _, param := range decl.Parameters {
+		paramType := param.Type.Type.String()
+		if param.Type.IsReference {
+			paramType += " Referenz"
+		}
+		params = append(params, fmt.Sprintf("%s: %s", param.Name.Literal, paramType))
+	}
+	aliases := make([]string, 0, len(decl.Aliases))
+	for _, alias := range decl.Aliases {
+		aliases = append(aliases, alias.Original.Literal)
+	}
+
+	desc := fmt.Sprintf("FuncDecl[%s: [%s], %s, Public(%v), ExternVisible(%v), Aliases[%s]]", decl.Name(), strings.Join(params, ", "), decl.ReturnType, decl.IsPublic, decl.IsExternVisible, strings.Join(aliases, ", "))
+	if decl.Operator != nil {
+		desc += fmt.Sprintf(" [Operator %s]", decl.Operator)
+	}
+	if IsExternFunc(decl) {
+		return d.describe(desc + fmt.Sprintf(" [Extern %s]", decl.ExternFile.Literal))
+	}
+	if IsForwardDecl(decl) {
+		return d.describe(desc + " [Forward Decl]")
+	}
+	return d.describe(desc, decl.Body)
+}
+
+func (d *diffDescriber) VisitFuncDef(decl *FuncDef) VisitResult {
+	return d.describe(fmt.Sprintf("FuncDef[%s]", decl.Func.Name()), decl.Body)
+}
+
+func (d *diffDescriber) VisitStructDecl(decl *StructDecl) VisitResult {
+	aliases := make([]string, 0, len(decl.Aliases))
+	for _, alias := range decl.Aliases {
+		aliases = append(aliases, alias.Original.Literal)
+	}
+	return d.describe(fmt.Sprintf("StructDecl[%s: Public(%v), Aliases[%s]]", decl.Name(), decl.IsPublic, strings.Join(aliases, ", ")), toInterfaceSlice[Declaration, Node](decl.Fields)...)
+}
+
+func (d *diffDescriber) VisitEnumDecl(decl *EnumDecl) VisitResult {
+	return d.describe(fmt.Sprintf("EnumDecl[%s: Public(%v)] = %v", decl.Name(), decl.IsPublic, decl.Type.Variants))
+}
+
+func (d *diffDescriber) VisitTypeAliasDecl(decl *TypeAliasDecl) VisitResult {
+	return d.describe(fmt.Sprintf("TypeAliasDecl[%s: Public(%v)] = %s", decl.Name(), decl.IsPublic, decl.Underlying))
+}
+
+func (d *diffDescriber) VisitTypeDefDecl(decl *TypeDefDecl) VisitResult {
+	return d.describe(fmt.Sprintf("TypeDefDecl[%s: Public(%v)] = %s", decl.Name(), decl.IsPublic, decl.Underlying))
+}
+
+func (d *diffDescriber) VisitBadExpr(expr *BadExpr) VisitResult {
+	return d.describe("BadExpr")
+}
+
+func (d *diffDescriber) VisitIdent(expr *Ident) VisitResult {
+	return d.describe(fmt.Sprintf("Ident[%s]", expr.Literal.Literal))
+}
+
+func (d *diffDescriber) VisitIndexing(expr *Indexing) VisitResult {
+	return d.describe("Indexing", expr.Lhs, expr.Index)
+}
+
+func (d *diffDescriber) VisitFieldAccess(expr *FieldAccess) VisitResult {
+	return d.describe("FieldAccess", expr.Field, expr.Rhs)
+}
+
+func (d *diffDescriber) VisitIntLit(expr *IntLit) VisitResult {
+	return d.describe(fmt.Sprintf("IntLit(%d)", expr.Value))
+}
+
+func (d *diffDescriber) VisitFloatLit(expr *FloatLit) VisitResult {
+	return d.describe(fmt.Sprintf("FloatLit(%v)", expr.Value))
+}
+
+func (d *diffDescriber) VisitBoolLit(expr *BoolLit) VisitResult {
+	return d.describe(fmt.Sprintf("BoolLit(%v)", expr.Value))
+}
+
+func (d *diffDescriber) VisitCharLit(expr *CharLit) VisitResult {
+	return d.describe(fmt.Sprintf("CharLit(%q)", expr.Value))
+}
+
+func (d *diffDescriber) VisitStringLit(expr *StringLit) VisitResult {
+	return d.describe(fmt.Sprintf("StringLit(%q)", expr.Value))
+}
+
+func (d *diffDescriber) VisitNothingLit(expr *NothingLit) VisitResult {
+	return d.describe("NothingLit")
+}
+
+func (d *diffDescriber) VisitEnumLit(expr *EnumLit) VisitResult {
+	return d.describe(fmt.Sprintf("EnumLit[%s]", expr.Literal.Literal))
+}
+
+func (d *diffDescriber) VisitListLit(expr *ListLit) VisitResult {
+	switch {
+	case expr.Values != nil:
+		return d.describe("ListLit", toInterfaceSlice[Expression, Node](expr.Values)...)
+	case expr.Count != nil:
+		return d.describe("ListLit[Count]", expr.Count, expr.Value)
+	default:
+		return d.describe(fmt.Sprintf("ListLit[%s]", expr.Type))
+	}
+}
+
+func (d *diffDescriber) VisitUnaryExpr(expr *UnaryExpr) VisitResult {
+	return d.describe(fmt.Sprintf("UnaryExpr[%s]", expr.Operator), expr.Rhs)
+}
+
+func (d *diffDescriber) VisitBinaryExpr(expr *BinaryExpr) VisitResult {
+	return d.describe(fmt.Sprintf("BinaryExpr[%s]", expr.Operator), expr.Lhs, expr.Rhs)
+}
+
+func (d *diffDescriber) VisitTernaryExpr(expr *TernaryExpr) VisitResult {
+	return d.describe(fmt.Sprintf("TernaryExpr[%s]", expr.Operator), expr.Lhs, expr.Mid, expr.Rhs)
+}
+
+func (d *diffDescriber) VisitCastExpr(expr *CastExpr) VisitResult {
+	return d.describe(fmt.Sprintf("CastExpr[%s]", expr.TargetType), expr.Lhs)
+}
+
+func (d *diffDescriber) VisitTypeOpExpr(expr *TypeOpExpr) VisitResult {
+	return d.describe(fmt.Sprintf("TypeOpExpr[%s: %s]", expr.Operator, expr.Rhs))
+}
+
+func (d *diffDescriber) VisitTypeCheck(expr *TypeCheck) VisitResult {
+	return d.describe(fmt.Sprintf("TypeCheck[%s]", expr.CheckType), expr.Lhs)
+}
+
+func (d *diffDescriber) VisitGrouping(expr *Grouping) VisitResult {
+	return d.describe("Grouping", expr.Expr)
+}
+
+func (d *diffDescriber) VisitFuncCall(expr *FuncCall) VisitResult {
+	return d.describe(fmt.Sprintf("FuncCall[%s]", expr.Name), toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
+}
+
+func (d *diffDescriber) VisitStructLiteral(expr *StructLiteral) VisitResult {
+	return d.describe(fmt.Sprintf("StructLiteral[%s]", expr.Struct.Name()), toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
+}
+
+func (d *diffDescriber) VisitFuncRef(expr *FuncRef) VisitResult {
+	return d.describe(fmt.Sprintf("FuncRef[%s]", expr.Name.Literal))
+}
+
+func (d *diffDescriber) VisitFuncValueCall(expr *FuncValueCall) VisitResult {
+	return d.describe("FuncValueCall", append([]Node{expr.Callee}, toInterfaceSlice[Expression, Node](expr.Args)...)...)
+}
+
+func (d *diffDescriber) VisitBadStmt(stmt *BadStmt) VisitResult {
+	return d.describe("BadStmt")
+}
+
+func (d *diffDescriber) VisitDeclStmt(stmt *DeclStmt) VisitResult {
+	return d.describe("DeclStmt", stmt.Decl)
+}
+
+func (d *diffDescriber) VisitExprStmt(stmt *ExprStmt) VisitResult {
+	return d.describe("ExprStmt", stmt.Expr)
+}
+
+func (d *diffDescriber) VisitImportStmt(stmt *ImportStmt) VisitResult {
+	return d.describe(fmt.Sprintf("ImportStmt[%s: %v]", stmt.FileName.Literal, literals(stmt.ImportedSymbols)))
+}
+
+func (d *diffDescriber) VisitAssignStmt(stmt *AssignStmt) VisitResult {
+	return d.describe("AssignStmt", stmt.Var, stmt.Rhs)
+}
+
+func (d *diffDescriber) VisitBlockStmt(stmt *BlockStmt) VisitResult {
+	return d.describe("BlockStmt", toInterfaceSlice[Statement, Node](stmt.Statements)...)
+}
+
+func (d *diffDescriber) VisitIfStmt(stmt *IfStmt) VisitResult {
+	return d.describe("IfStmt", stmt.Condition, stmt.Then, stmt.Else)
+}
+
+func (d *diffDescriber) VisitWhileStmt(stmt *WhileStmt) VisitResult {
+	return d.describe(fmt.Sprintf("WhileStmt[%s]", stmt.While.Type), stmt.Condition, stmt.Body)
+}
+
+func (d *diffDescriber) VisitForStmt(stmt *ForStmt) VisitResult {
+	return d.describe("ForStmt", stmt.Initializer, stmt.To, stmt.StepSize, stmt.Body)
+}
+
+func (d *diffDescriber) VisitForRangeStmt(stmt *ForRangeStmt) VisitResult {
+	// Initializer.InitVal is In, so only the name and type of the Initializer are compared
+	return d.describe(fmt.Sprintf("ForRangeStmt[%s: %s]", stmt.Initializer.Name(), stmt.Initializer.Type), stmt.In, stmt.Body)
+}
+
+func (d *diffDescriber) VisitBreakContinueStmt(stmt *BreakContinueStmt) VisitResult {
+	if stmt.Tok.Type == token.VERLASSE {
+		return d.describe("BreakContinueStmt[break]")
+	}
+	return d.describe("BreakContinueStmt[continue]")
+}
+
+func (d *diffDescriber) VisitReturnStmt(stmt *ReturnStmt) VisitResult {
+	return d.describe("ReturnStmt", stmt.Value)
+}
+
+func (d *diffDescriber) VisitTodoStmt(stmt *TodoStmt) VisitResult {
+	return d.describe("TodoStmt")
+}
+
+func (d *diffDescriber) VisitAssertStmt(stmt *AssertStmt) VisitResult {
+	return d.describe("AssertStmt", stmt.Condition, stmt.Message)
+}
+
+func (d *diffDescriber) VisitPrintStmt(stmt *PrintStmt) VisitResult {
+	return d.describe(fmt.Sprintf("PrintStmt[NewLine: %v]", stmt.NewLine), stmt.Value)
+}
@@ -0,0 +1,106 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, src string) *ast.Ast {
+	t.Helper()
+	module, err := parser.Parse(parser.Options{
+		FileName:     "test.ddp",
+		Source:       []byte(src),
+		ErrorHandler: ddperror.MakePanicHandler(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return module.Ast
+}
+
+// a Difference reduced to what the tests compare
+type difference struct {
+	kind    ast.DiffKind
+	oldLine uint // 0 if there is no old node
+	newLine uint // 0 if there is no new node
+	oldDesc string
+	newDesc string
+}
+
+func diff(t *testing.T, old, new string) []difference {
+	t.Helper()
+	var result []difference
+	for _, d := range ast.Diff(parse(t, old), parse(t, new)) {
+		diff := difference{kind: d.Kind, oldDesc: d.OldDesc, newDesc: d.NewDesc}
+		if d.Old != nil {
+			diff.oldLine = d.Old.GetRange().Start.Line
+		}
+		if d.New != nil {
+			diff.newLine = d.New.GetRange().Start.Line
+		}
+		result = append(result, diff)
+	}
+	return result
+}
+
+const diffSrc = `Die Zahl x ist 1 plus 2 mal 3.
+Die Funktion f mit dem Parameter a vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib a plus 1 zurück.
+Und kann so benutzt werden:
+	"f von <a>"
+Speichere f von x in x.
+`
+
+func TestDiffIgnoresFormatting(t *testing.T) {
+	assert.Empty(t, diff(t, diffSrc, `[Kommentar]
+Die   Zahl x ist 1 plus (2 mal 3).
+Die Funktion f mit dem Parameter a vom Typ Zahl, gibt eine Zahl zurück, macht:
+	[noch ein Kommentar]
+	Gib (a plus 1) zurück.
+Und kann so benutzt werden:
+	"f von <a>"
+
+Speichere f von (x) in x.
+`))
+}
+
+func TestDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]difference{
+		{ast.DIFF_CHANGED, 3, 3, "BinaryExpr[plus]", "BinaryExpr[minus]"},
+	}, diff(t, diffSrc, `Die Zahl x ist 1 plus 2 mal 3.
+Die Funktion f mit dem Parameter a vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib a minus 1 zurück.
+Und kann so benutzt werden:
+	"f von <a>"
+Speichere f von x in x.
+`), "only the changed operator is reported")
+
+	assert.Equal([]difference{
+		{ast.DIFF_CHANGED, 1, 1, "BinaryExpr[plus]", "BinaryExpr[mal]"},
+		{ast.DIFF_CHANGED, 1, 1, "IntLit(1)", "BinaryExpr[plus]"},
+		{ast.DIFF_CHANGED, 1, 1, "BinaryExpr[mal]", "IntLit(3)"},
+	}, diff(t, diffSrc, `Die Zahl x ist (1 plus 2) mal 3.
+Die Funktion f mit dem Parameter a vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib a plus 1 zurück.
+Und kann so benutzt werden:
+	"f von <a>"
+Speichere f von x in x.
+`), "parentheses that change the precedence change the AST")
+
+	assert.Equal([]difference{
+		{ast.DIFF_ADDED, 0, 2, "", "DeclStmt"},
+		{ast.DIFF_REMOVED, 6, 0, "AssignStmt", ""},
+	}, diff(t, diffSrc, `Die Zahl x ist 1 plus 2 mal 3.
+Die Zahl y ist x.
+Die Funktion f mit dem Parameter a vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib a plus 1 zurück.
+Und kann so benutzt werden:
+	"f von <a>"
+`))
+}
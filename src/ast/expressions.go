@@ -1,6 +1,8 @@
 package ast
 
 import (
+	"sort"
+
 	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
 	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
 	"github.com/DDP-Projekt/Kompilierer/src/token"
@@ -29,6 +31,7 @@ type (
 	// this one can count as Reference, and may be used
 	// inplace of Ident (may be assigned to etc.)
 	Indexing struct {
+		Range token.Range // includes the surrounding parens if present
 		Lhs   Assigneable // variable Name or other indexing
 		Index Expression
 	}
@@ -37,6 +40,7 @@ type (
 	// this one can count as Reference, and my be used
 	// inplace of Ident (may be assigned to etc.)
 	FieldAccess struct {
+		Range token.Range // includes the surrounding parens if present
 		Rhs   Assigneable // variable Name or other indexing
 		Field *Ident      // the field name
 	}
@@ -66,6 +70,22 @@ type (
 		Value   string // the evaluated string
 	}
 
+	// the nichts literal of optional types
+	NothingLit struct {
+		Literal token.Token
+		// the optional type of the literal
+		// the typechecker fills this field from the context of the literal
+		Type ddptypes.Type
+	}
+
+	// a variant of an enum like rot in
+	// Die Aufzählung Farbe ist rot, grün, blau.
+	EnumLit struct {
+		Literal token.Token
+		Decl    *EnumDecl // the enum this variant belongs to
+		Index   int       // index of the variant in Decl.Type.Variants
+	}
+
 	ListLit struct {
 		Tok   token.Token
 		Range token.Range
@@ -146,6 +166,9 @@ type (
 		// is set by the parser, or nil if the name was not found
 		Func *FuncDecl
 		Args map[string]Expression
+		// names of the arguments in the order they appear in the source code
+		// is set by the parser, or nil for calls that were generated by the compiler
+		ArgOrder []string
 	}
 
 	StructLiteral struct {
@@ -158,6 +181,25 @@ type (
 		// this does not include all struct fields,
 		// only the ones needed by the alias used
 		Args map[string]Expression
+		// names of the arguments in the order they appear in the source code
+		ArgOrder []string
+	}
+
+	// a function used as value (die Funktion f)
+	FuncRef struct {
+		Range token.Range
+		Tok   token.Token // die
+		Name  token.Token // name of the function
+		// the function declaration this reference refers to
+		// is set by the parser, or nil if the name was not found
+		Func *FuncDecl
+	}
+
+	// call of a function value (f(1, 2))
+	FuncValueCall struct {
+		Range  token.Range
+		Callee Expression // expression of function type that is called
+		Args   []Expression
 	}
 )
 
@@ -170,6 +212,8 @@ func (expr *FloatLit) node()      {}
 func (expr *BoolLit) node()       {}
 func (expr *CharLit) node()       {}
 func (expr *StringLit) node()     {}
+func (expr *NothingLit) node()    {}
+func (expr *EnumLit) node()       {}
 func (expr *ListLit) node()       {}
 func (expr *UnaryExpr) node()     {}
 func (expr *BinaryExpr) node()    {}
@@ -180,6 +224,8 @@ func (expr *TypeCheck) node()     {}
 func (expr *Grouping) node()      {}
 func (expr *FuncCall) node()      {}
 func (expr *StructLiteral) node() {}
+func (expr *FuncRef) node()       {}
+func (expr *FuncValueCall) node() {}
 
 func (expr *BadExpr) String() string       { return "BadExpr" }
 func (expr *Ident) String() string         { return "Ident" }
@@ -190,6 +236,8 @@ func (expr *FloatLit) String() string      { return "FloatLit" }
 func (expr *BoolLit) String() string       { return "BoolLit" }
 func (expr *CharLit) String() string       { return "CharLit" }
 func (expr *StringLit) String() string     { return "StringLit" }
+func (expr *NothingLit) String() string    { return "NothingLit" }
+func (expr *EnumLit) String() string       { return "EnumLit" }
 func (expr *ListLit) String() string       { return "ListLit" }
 func (expr *UnaryExpr) String() string     { return "UnaryExpr" }
 func (expr *BinaryExpr) String() string    { return "BinaryExpr" }
@@ -200,6 +248,8 @@ func (expr *TypeCheck) String() string     { return "TypeCheck" }
 func (expr *Grouping) String() string      { return "Grouping" }
 func (expr *FuncCall) String() string      { return "FuncCall" }
 func (expr *StructLiteral) String() string { return "StructLiteral" }
+func (expr *FuncRef) String() string       { return "FuncRef" }
+func (expr *FuncValueCall) String() string { return "FuncValueCall" }
 
 func (expr *BadExpr) Token() token.Token       { return expr.Tok }
 func (expr *Ident) Token() token.Token         { return expr.Literal }
@@ -210,6 +260,8 @@ func (expr *FloatLit) Token() token.Token      { return expr.Literal }
 func (expr *BoolLit) Token() token.Token       { return expr.Literal }
 func (expr *CharLit) Token() token.Token       { return expr.Literal }
 func (expr *StringLit) Token() token.Token     { return expr.Literal }
+func (expr *NothingLit) Token() token.Token    { return expr.Literal }
+func (expr *EnumLit) Token() token.Token       { return expr.Literal }
 func (expr *ListLit) Token() token.Token       { return expr.Tok }
 func (expr *UnaryExpr) Token() token.Token     { return expr.Tok }
 func (expr *BinaryExpr) Token() token.Token    { return expr.Tok }
@@ -220,21 +272,20 @@ func (expr *TypeCheck) Token() token.Token     { return expr.Tok }
 func (expr *Grouping) Token() token.Token      { return expr.LParen }
 func (expr *FuncCall) Token() token.Token      { return expr.Tok }
 func (expr *StructLiteral) Token() token.Token { return expr.Tok }
+func (expr *FuncRef) Token() token.Token       { return expr.Tok }
+func (expr *FuncValueCall) Token() token.Token { return expr.Callee.Token() }
 
-func (expr *BadExpr) GetRange() token.Range { return expr.Err.Range }
-func (expr *Ident) GetRange() token.Range   { return token.NewRange(&expr.Literal, &expr.Literal) }
-func (expr *Indexing) GetRange() token.Range {
-	return token.Range{Start: expr.Lhs.GetRange().Start, End: expr.Index.GetRange().End}
-}
-
-func (expr *FieldAccess) GetRange() token.Range {
-	return token.Range{Start: expr.Field.GetRange().Start, End: expr.Rhs.GetRange().End}
-}
+func (expr *BadExpr) GetRange() token.Range       { return expr.Err.Range }
+func (expr *Ident) GetRange() token.Range         { return token.NewRange(&expr.Literal, &expr.Literal) }
+func (expr *Indexing) GetRange() token.Range      { return expr.Range }
+func (expr *FieldAccess) GetRange() token.Range   { return expr.Range }
 func (expr *IntLit) GetRange() token.Range        { return expr.Literal.Range }
 func (expr *FloatLit) GetRange() token.Range      { return expr.Literal.Range }
 func (expr *BoolLit) GetRange() token.Range       { return expr.Literal.Range }
 func (expr *CharLit) GetRange() token.Range       { return expr.Literal.Range }
 func (expr *StringLit) GetRange() token.Range     { return expr.Literal.Range }
+func (expr *NothingLit) GetRange() token.Range    { return expr.Literal.Range }
+func (expr *EnumLit) GetRange() token.Range       { return expr.Literal.Range }
 func (expr *ListLit) GetRange() token.Range       { return expr.Range }
 func (expr *UnaryExpr) GetRange() token.Range     { return expr.Range }
 func (expr *BinaryExpr) GetRange() token.Range    { return expr.Range }
@@ -245,6 +296,8 @@ func (expr *TypeCheck) GetRange() token.Range     { return expr.Range }
 func (expr *Grouping) GetRange() token.Range      { return expr.Range }
 func (expr *FuncCall) GetRange() token.Range      { return expr.Range }
 func (expr *StructLiteral) GetRange() token.Range { return expr.Range }
+func (expr *FuncRef) GetRange() token.Range       { return expr.Range }
+func (expr *FuncValueCall) GetRange() token.Range { return expr.Range }
 
 func (expr *BadExpr) Accept(v FullVisitor) VisitResult       { return v.VisitBadExpr(expr) }
 func (expr *Ident) Accept(v FullVisitor) VisitResult         { return v.VisitIdent(expr) }
@@ -255,6 +308,8 @@ func (expr *FloatLit) Accept(v FullVisitor) VisitResult      { return v.VisitFlo
 func (expr *BoolLit) Accept(v FullVisitor) VisitResult       { return v.VisitBoolLit(expr) }
 func (expr *CharLit) Accept(v FullVisitor) VisitResult       { return v.VisitCharLit(expr) }
 func (expr *StringLit) Accept(v FullVisitor) VisitResult     { return v.VisitStringLit(expr) }
+func (expr *NothingLit) Accept(v FullVisitor) VisitResult    { return v.VisitNothingLit(expr) }
+func (expr *EnumLit) Accept(v FullVisitor) VisitResult       { return v.VisitEnumLit(expr) }
 func (expr *ListLit) Accept(v FullVisitor) VisitResult       { return v.VisitListLit(expr) }
 func (expr *UnaryExpr) Accept(v FullVisitor) VisitResult     { return v.VisitUnaryExpr(expr) }
 func (expr *BinaryExpr) Accept(v FullVisitor) VisitResult    { return v.VisitBinaryExpr(expr) }
@@ -265,6 +320,8 @@ func (expr *TypeCheck) Accept(v FullVisitor) VisitResult     { return v.VisitTyp
 func (expr *Grouping) Accept(v FullVisitor) VisitResult      { return v.VisitGrouping(expr) }
 func (expr *FuncCall) Accept(v FullVisitor) VisitResult      { return v.VisitFuncCall(expr) }
 func (expr *StructLiteral) Accept(v FullVisitor) VisitResult { return v.VisitStructLiteral(expr) }
+func (expr *FuncRef) Accept(v FullVisitor) VisitResult       { return v.VisitFuncRef(expr) }
+func (expr *FuncValueCall) Accept(v FullVisitor) VisitResult { return v.VisitFuncValueCall(expr) }
 
 func (expr *BadExpr) expressionNode()       {}
 func (expr *Ident) expressionNode()         {}
@@ -275,6 +332,8 @@ func (expr *FloatLit) expressionNode()      {}
 func (expr *BoolLit) expressionNode()       {}
 func (expr *CharLit) expressionNode()       {}
 func (expr *StringLit) expressionNode()     {}
+func (expr *NothingLit) expressionNode()    {}
+func (expr *EnumLit) expressionNode()       {}
 func (expr *ListLit) expressionNode()       {}
 func (expr *UnaryExpr) expressionNode()     {}
 func (expr *BinaryExpr) expressionNode()    {}
@@ -285,7 +344,54 @@ func (expr *TypeCheck) expressionNode()     {}
 func (expr *Grouping) expressionNode()      {}
 func (expr *FuncCall) expressionNode()      {}
 func (expr *StructLiteral) expressionNode() {}
+func (expr *FuncRef) expressionNode()       {}
+func (expr *FuncValueCall) expressionNode() {}
 
 func (expr *Ident) assigneable()       {}
 func (expr *Indexing) assigneable()    {}
 func (expr *FieldAccess) assigneable() {}
+
+// returns the names of the arguments in the order they appear in the source code
+func (expr *FuncCall) ArgNames() []string {
+	return argNames(expr.Args, expr.ArgOrder)
+}
+
+// returns the arguments in the order they appear in the source code
+func (expr *FuncCall) OrderedArgs() []Expression {
+	return orderedArgs(expr.Args, expr.ArgOrder)
+}
+
+// returns the names of the arguments in the order they appear in the source code
+func (expr *StructLiteral) ArgNames() []string {
+	return argNames(expr.Args, expr.ArgOrder)
+}
+
+// returns the arguments in the order they appear in the source code
+func (expr *StructLiteral) OrderedArgs() []Expression {
+	return orderedArgs(expr.Args, expr.ArgOrder)
+}
+
+// returns order if it describes args
+// otherwise (e.g. for generated nodes) the names of args are sorted
+// so that the order is still deterministic
+func argNames(args map[string]Expression, order []string) []string {
+	if len(order) == len(args) {
+		return order
+	}
+
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func orderedArgs(args map[string]Expression, order []string) []Expression {
+	names := argNames(args, order)
+	result := make([]Expression, 0, len(names))
+	for _, name := range names {
+		result = append(result, args[name])
+	}
+	return result
+}
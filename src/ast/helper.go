@@ -4,6 +4,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
 	"github.com/DDP-Projekt/Kompilierer/src/token"
 )
 
@@ -29,6 +30,48 @@ func TrimStringLit(lit *token.Token) string {
 	return strings.Trim(lit.Literal, "\"")
 }
 
+// creates a note pointing to the token of decl that declares name
+// (the name token or, for enums, the token of the first variant called name)
+func DeclaredHereNote(decl Declaration, name string) ddperror.Note {
+	var nameRange token.Range
+	switch decl := decl.(type) {
+	case *BadDecl:
+		nameRange = decl.Tok.Range
+	case *VarDecl:
+		nameRange = decl.NameTok.Range
+	case *FuncDecl:
+		nameRange = decl.NameTok.Range
+	case *StructDecl:
+		nameRange = decl.NameTok.Range
+	case *EnumDecl:
+		nameRange = decl.NameTok.Range
+		for _, variant := range decl.Variants {
+			if variant.Literal == name {
+				nameRange = variant.Range
+				break
+			}
+		}
+	case *TypeAliasDecl:
+		nameRange = decl.NameTok.Range
+	case *TypeDefDecl:
+		nameRange = decl.NameTok.Range
+	}
+
+	file := ""
+	if decl.Module() != nil {
+		file = decl.Module().FileName
+	}
+	return ddperror.NewNote(nameRange, ddperror.MsgDeclaredHere(name), file)
+}
+
+// removes all groupings around expr
+func UnwrapGrouping(expr Expression) Expression {
+	for grouping, isGrouping := expr.(*Grouping); isGrouping; grouping, isGrouping = expr.(*Grouping) {
+		expr = grouping.Expr
+	}
+	return expr
+}
+
 // returns wether table is the global scope
 // table.Enclosing == nil
 func IsGlobalScope(table *SymbolTable) bool {
@@ -18,6 +18,10 @@ type helperVisitor struct {
 // visitor can implement any of the Visit<Node> interfaces, and
 // the corresponding method is called for each node
 //
+// nodes are visited before their children and children
+// in the order they appear in the source code
+// (the arguments of a FuncCall or StructLiteral in the order of ArgNames)
+//
 // if the given Visitor implements the ConditionalVisitor interface,
 // the ShouldVisit method is used to determine if a node should be visited
 //
@@ -59,6 +63,10 @@ func visitSingleModule(module *Module, v *helperVisitor) {
 // visitor can implement any of the Visit<Node> interfaces, and
 // the corresponding method is called for each node
 //
+// nodes are visited before their children and children
+// in the order they appear in the source code
+// (the arguments of a FuncCall or StructLiteral in the order of ArgNames)
+//
 // imported modules are visited in the order they are included
 //
 // if the given Visitor implements the ConditionalVisitor interface,
@@ -113,6 +121,10 @@ func visitModuleRec(module *Module, visitor *helperVisitor, visited map[*Module]
 // visitor can implement any of the Visit<Node> interfaces, and
 // the corresponding method is called for each node
 //
+// nodes are visited before their children and children
+// in the order they appear in the source code
+// (the arguments of a FuncCall or StructLiteral in the order of ArgNames)
+//
 // if the given Visitor implements the ConditionalVisitor interface,
 // the ShouldVisit method is used to determine if a node should be visited
 //
@@ -204,6 +216,13 @@ func (h *helperVisitor) VisitStructDecl(decl *StructDecl) VisitResult {
 	return h.visitChildren(result, sortedByRange(decl.Fields)...)
 }
 
+func (h *helperVisitor) VisitEnumDecl(decl *EnumDecl) VisitResult {
+	if vis, ok := h.actualVisitor.(EnumDeclVisitor); ok {
+		return vis.VisitEnumDecl(decl)
+	}
+	return VisitRecurse
+}
+
 func (h *helperVisitor) VisitTypeAliasDecl(decl *TypeAliasDecl) VisitResult {
 	if vis, ok := h.actualVisitor.(TypeAliasDeclVisitor); ok {
 		return vis.VisitTypeAliasDecl(decl)
@@ -285,6 +304,20 @@ func (h *helperVisitor) VisitStringLit(expr *StringLit) VisitResult {
 	return VisitRecurse
 }
 
+func (h *helperVisitor) VisitNothingLit(expr *NothingLit) VisitResult {
+	if vis, ok := h.actualVisitor.(NothingLitVisitor); ok {
+		return vis.VisitNothingLit(expr)
+	}
+	return VisitRecurse
+}
+
+func (h *helperVisitor) VisitEnumLit(expr *EnumLit) VisitResult {
+	if vis, ok := h.actualVisitor.(EnumLitVisitor); ok {
+		return vis.VisitEnumLit(expr)
+	}
+	return VisitRecurse
+}
+
 func (h *helperVisitor) VisitListLit(expr *ListLit) VisitResult {
 	result := VisitRecurse
 	if vis, ok := h.actualVisitor.(ListLitVisitor); ok {
@@ -366,7 +399,7 @@ func (h *helperVisitor) VisitFuncCall(expr *FuncCall) VisitResult {
 	if vis, ok := h.actualVisitor.(FuncCallVisitor); ok {
 		result = vis.VisitFuncCall(expr)
 	}
-	return h.visitChildren(result, h.sortArgs(expr.Args)...)
+	return h.visitChildren(result, toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
 }
 
 func (h *helperVisitor) VisitStructLiteral(expr *StructLiteral) VisitResult {
@@ -374,7 +407,22 @@ func (h *helperVisitor) VisitStructLiteral(expr *StructLiteral) VisitResult {
 	if vis, ok := h.actualVisitor.(StructLiteralVisitor); ok {
 		result = vis.VisitStructLiteral(expr)
 	}
-	return h.visitChildren(result, h.sortArgs(expr.Args)...)
+	return h.visitChildren(result, toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
+}
+
+func (h *helperVisitor) VisitFuncRef(expr *FuncRef) VisitResult {
+	if vis, ok := h.actualVisitor.(FuncRefVisitor); ok {
+		return vis.VisitFuncRef(expr)
+	}
+	return VisitRecurse
+}
+
+func (h *helperVisitor) VisitFuncValueCall(expr *FuncValueCall) VisitResult {
+	result := VisitRecurse
+	if vis, ok := h.actualVisitor.(FuncValueCallVisitor); ok {
+		result = vis.VisitFuncValueCall(expr)
+	}
+	return h.visitChildren(result, append([]Node{expr.Callee}, toInterfaceSlice[Expression, Node](expr.Args)...)...)
 }
 
 func (h *helperVisitor) VisitBadStmt(stmt *BadStmt) VisitResult {
@@ -496,18 +544,20 @@ func (h *helperVisitor) VisitTodoStmt(stmt *TodoStmt) VisitResult {
 	return VisitRecurse
 }
 
-// helper for visitFuncCall and visitStructLiteral
-// sorts the arguments by their order in the source code by using their ranges
-func (h *helperVisitor) sortArgs(Args map[string]Expression) []Node {
-	if len(Args) != 0 {
-		// sort the arguments to visit them in the order they appear
-		args := make([]Node, 0, len(Args))
-		for _, arg := range Args {
-			args = append(args, arg)
-		}
-		return args
+func (h *helperVisitor) VisitAssertStmt(stmt *AssertStmt) VisitResult {
+	result := VisitRecurse
+	if vis, ok := h.actualVisitor.(AssertStmtVisitor); ok {
+		result = vis.VisitAssertStmt(stmt)
 	}
-	return nil
+	return h.visitChildren(result, stmt.Condition, stmt.Message)
+}
+
+func (h *helperVisitor) VisitPrintStmt(stmt *PrintStmt) VisitResult {
+	result := VisitRecurse
+	if vis, ok := h.actualVisitor.(PrintStmtVisitor); ok {
+		result = vis.VisitPrintStmt(stmt)
+	}
+	return h.visitChildren(result, stmt.Value)
 }
 
 func sortedByRange[T Node](nodes []T) []Node {
@@ -34,10 +34,44 @@ func GetOperator(s string) (Operator, bool) {
 	return op, ok
 }
 
+// returns all unary operators in the order they are declared
+func UnaryOperators() []UnaryOperator {
+	ops := make([]UnaryOperator, 0, un_end-1)
+	for op := UN_INVALID + 1; op < un_end; op++ {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// returns all binary operators in the order they are declared
+func BinaryOperators() []BinaryOperator {
+	ops := make([]BinaryOperator, 0, bin_end-1)
+	for op := BIN_INVALID + 1; op < bin_end; op++ {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// returns all ternary operators in the order they are declared
+func TernaryOperators() []TernaryOperator {
+	ops := make([]TernaryOperator, 0, ter_end-1)
+	for op := TER_INVALID + 1; op < ter_end; op++ {
+		ops = append(ops, op)
+	}
+	return ops
+}
+
 type UnaryOperator int
 
 func (UnaryOperator) Operator() {}
 
+// there are three kinds of negation:
+//   - UN_NEGATE is the arithmetic negation of a Zahl or Kommazahl (or a list of them)
+//   - UN_NOT is the logical negation of a Wahrheitswert
+//   - UN_LOGIC_NOT is the bitwise negation of a Zahl or Buchstabe
+//
+// the "Negiere x." statement uses UN_NOT for a Wahrheitswert,
+// UN_LOGIC_NOT for a Buchstabe and UN_NEGATE for everything else
 const (
 	UN_INVALID   UnaryOperator = iota
 	UN_ABS                     // Betrag von
@@ -45,6 +79,7 @@ const (
 	UN_NEGATE                  // -
 	UN_NOT                     // nicht
 	UN_LOGIC_NOT               // logisch nicht
+	UN_PRESENT                 // vorhanden
 	un_end                     // unexported constant to enable looping over all values
 )
 
@@ -60,6 +95,8 @@ func (op UnaryOperator) String() string {
 		return "nicht"
 	case UN_LOGIC_NOT:
 		return "logisch nicht"
+	case UN_PRESENT:
+		return "vorhanden"
 	}
 	panic(fmt.Errorf("unbekannter unärer Operator %d", op))
 }
@@ -96,6 +133,8 @@ const (
 	BIN_FIELD_ACCESS                // von
 	BIN_SLICE_TO                    // bis zum
 	BIN_SLICE_FROM                  // ab dem
+	BIN_MAP                         // abgebildet mit
+	BIN_FILTER                      // gefiltert mit
 	bin_end                         // unexported constant to enable looping over all values
 )
 
@@ -153,6 +192,10 @@ func (op BinaryOperator) String() string {
 		return "bis zum"
 	case BIN_SLICE_FROM:
 		return "ab dem"
+	case BIN_MAP:
+		return "abgebildet mit"
+	case BIN_FILTER:
+		return "gefiltert mit"
 	}
 	panic(fmt.Errorf("unbekannter binärer Operator %d", op))
 }
@@ -166,6 +209,7 @@ const (
 	TER_SLICE                   // von bis
 	TER_BETWEEN                 // zwischen
 	TER_FALLS                   // <a>, falls <b>, ansonsten <c>
+	TER_PAD                     // <a> aufgefüllt bis zur Länge <b> mit <c>
 	ter_end                     // unexported constant to enable looping over all values
 )
 
@@ -177,6 +221,8 @@ func (op TernaryOperator) String() string {
 		return "zwischen"
 	case TER_FALLS:
 		return "falls"
+	case TER_PAD:
+		return "aufgefüllt"
 	}
 
 	panic(fmt.Errorf("unbekannter ternärer Operator %d", op))
@@ -122,6 +122,11 @@ func (pr *printer) VisitStructDecl(decl *StructDecl) VisitResult {
 	return VisitRecurse
 }
 
+func (pr *printer) VisitEnumDecl(decl *EnumDecl) VisitResult {
+	pr.parenthesizeNode(fmt.Sprintf("EnumDecl[%s: Public(%v)] = %v", decl.Name(), decl.IsPublic, decl.Type.Variants))
+	return VisitRecurse
+}
+
 func (pr *printer) VisitTypeAliasDecl(decl *TypeAliasDecl) VisitResult {
 	pr.parenthesizeNode(fmt.Sprintf("TypeAliasDecl[%s: Public(%v)] = %s", decl.Name(), decl.IsPublic, decl.Underlying))
 	return VisitRecurse
@@ -177,6 +182,16 @@ func (pr *printer) VisitStringLit(expr *StringLit) VisitResult {
 	return VisitRecurse
 }
 
+func (pr *printer) VisitNothingLit(expr *NothingLit) VisitResult {
+	pr.parenthesizeNode("NothingLit")
+	return VisitRecurse
+}
+
+func (pr *printer) VisitEnumLit(expr *EnumLit) VisitResult {
+	pr.parenthesizeNode(fmt.Sprintf("EnumLit[%s]", expr.Literal.Literal))
+	return VisitRecurse
+}
+
 func (pr *printer) VisitListLit(expr *ListLit) VisitResult {
 	if expr.Values == nil {
 		pr.parenthesizeNode(fmt.Sprintf("ListLit[%s]", expr.Type))
@@ -226,20 +241,22 @@ func (pr *printer) VisitGrouping(expr *Grouping) VisitResult {
 }
 
 func (pr *printer) VisitFuncCall(expr *FuncCall) VisitResult {
-	args := make([]Node, 0, len(expr.Args))
-	for _, v := range expr.Args {
-		args = append(args, v)
-	}
-	pr.parenthesizeNode(fmt.Sprintf("FuncCall[%s]", expr.Name), args...)
+	pr.parenthesizeNode(fmt.Sprintf("FuncCall[%s]", expr.Name), toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
 	return VisitRecurse
 }
 
 func (pr *printer) VisitStructLiteral(expr *StructLiteral) VisitResult {
-	args := make([]Node, 0, len(expr.Args))
-	for _, v := range expr.Args {
-		args = append(args, v)
-	}
-	pr.parenthesizeNode(fmt.Sprintf("StructLiteral[%s]", expr.Struct.Name()), args...)
+	pr.parenthesizeNode(fmt.Sprintf("StructLiteral[%s]", expr.Struct.Name()), toInterfaceSlice[Expression, Node](expr.OrderedArgs())...)
+	return VisitRecurse
+}
+
+func (pr *printer) VisitFuncRef(expr *FuncRef) VisitResult {
+	pr.parenthesizeNode(fmt.Sprintf("FuncRef[%s]", expr.Name.Literal))
+	return VisitRecurse
+}
+
+func (pr *printer) VisitFuncValueCall(expr *FuncValueCall) VisitResult {
+	pr.parenthesizeNode("FuncValueCall", append([]Node{expr.Callee}, toInterfaceSlice[Expression, Node](expr.Args)...)...)
 	return VisitRecurse
 }
 
@@ -337,6 +354,20 @@ func (pr *printer) VisitTodoStmt(stmt *TodoStmt) VisitResult {
 	return VisitRecurse
 }
 
+func (pr *printer) VisitAssertStmt(stmt *AssertStmt) VisitResult {
+	if stmt.Message == nil {
+		pr.parenthesizeNode("AssertStmt", stmt.Condition)
+	} else {
+		pr.parenthesizeNode("AssertStmt", stmt.Condition, stmt.Message)
+	}
+	return VisitRecurse
+}
+
+func (pr *printer) VisitPrintStmt(stmt *PrintStmt) VisitResult {
+	pr.parenthesizeNode(fmt.Sprintf("PrintStmt[NewLine: %v]", stmt.NewLine), stmt.Value)
+	return VisitRecurse
+}
+
 func literals(tokens []token.Token) []string {
 	result := make([]string, 0, len(tokens))
 	for _, v := range tokens {
@@ -29,6 +29,10 @@ type (
 		// the module that was imported because of this
 		// nil if it does not exist or a similar error occured while importing
 		Module *Module
+		// the absolute path of the imported file
+		// also set for circular imports (where Module is nil)
+		// empty if the file does not exist
+		Path string
 		// slice of identifiers which specify
 		// the individual symbols imported
 		// if nil, all symbols are imported
@@ -60,7 +64,7 @@ type (
 
 	WhileStmt struct {
 		Range     token.Range
-		While     token.Token // solange, mache, mal
+		While     token.Token // solange, mache, wiederhole (also for "<stmt> <n> Mal")
 		Condition Expression
 		Body      Statement
 	}
@@ -97,6 +101,22 @@ type (
 	TodoStmt struct {
 		Tok token.Token // ...
 	}
+
+	// Stelle sicher, dass <Condition>[, sonst <Message>].
+	AssertStmt struct {
+		Range     token.Range
+		Tok       token.Token // Stelle
+		Condition Expression
+		Message   Expression // optional Text printed if Condition is falsch, may be nil
+	}
+
+	// Gib <Value> [auf einer neuen Zeile] aus.
+	PrintStmt struct {
+		Range   token.Range
+		Tok     token.Token // Gib
+		Value   Expression  // after typechecking always of type Text (wrapped in a CastExpr if necessary)
+		NewLine bool        // wether a newline is printed after Value
+	}
 )
 
 func (stmt *BadStmt) node()           {}
@@ -112,6 +132,8 @@ func (stmt *ForRangeStmt) node()      {}
 func (stmt *BreakContinueStmt) node() {}
 func (stmt *ReturnStmt) node()        {}
 func (stmt *TodoStmt) node()          {}
+func (stmt *AssertStmt) node()        {}
+func (stmt *PrintStmt) node()         {}
 
 func (stmt *BadStmt) String() string           { return "BadStmt" }
 func (stmt *DeclStmt) String() string          { return "DeclStmt" }
@@ -126,6 +148,8 @@ func (stmt *ForRangeStmt) String() string      { return "ForRangeStmt" }
 func (stmt *BreakContinueStmt) String() string { return "BreakContinueStmt" }
 func (stmt *ReturnStmt) String() string        { return "ReturnStmt" }
 func (stmt *TodoStmt) String() string          { return "TodoStmt" }
+func (stmt *AssertStmt) String() string        { return "AssertStmt" }
+func (stmt *PrintStmt) String() string         { return "PrintStmt" }
 
 func (stmt *BadStmt) Token() token.Token           { return stmt.Tok }
 func (stmt *DeclStmt) Token() token.Token          { return stmt.Decl.Token() }
@@ -140,6 +164,8 @@ func (stmt *ForRangeStmt) Token() token.Token      { return stmt.For }
 func (stmt *BreakContinueStmt) Token() token.Token { return stmt.Tok }
 func (stmt *ReturnStmt) Token() token.Token        { return stmt.Return }
 func (stmt *TodoStmt) Token() token.Token          { return stmt.Tok }
+func (stmt *AssertStmt) Token() token.Token        { return stmt.Tok }
+func (stmt *PrintStmt) Token() token.Token         { return stmt.Tok }
 
 func (stmt *BadStmt) GetRange() token.Range           { return stmt.Err.Range }
 func (stmt *DeclStmt) GetRange() token.Range          { return stmt.Decl.GetRange() }
@@ -154,6 +180,8 @@ func (stmt *ForRangeStmt) GetRange() token.Range      { return stmt.Range }
 func (stmt *BreakContinueStmt) GetRange() token.Range { return stmt.Range }
 func (stmt *ReturnStmt) GetRange() token.Range        { return stmt.Range }
 func (stmt *TodoStmt) GetRange() token.Range          { return stmt.Tok.Range }
+func (stmt *AssertStmt) GetRange() token.Range        { return stmt.Range }
+func (stmt *PrintStmt) GetRange() token.Range         { return stmt.Range }
 
 func (stmt *BadStmt) Accept(v FullVisitor) VisitResult      { return v.VisitBadStmt(stmt) }
 func (stmt *DeclStmt) Accept(v FullVisitor) VisitResult     { return v.VisitDeclStmt(stmt) }
@@ -170,6 +198,8 @@ func (stmt *BreakContinueStmt) Accept(v FullVisitor) VisitResult {
 }
 func (stmt *ReturnStmt) Accept(v FullVisitor) VisitResult { return v.VisitReturnStmt(stmt) }
 func (stmt *TodoStmt) Accept(v FullVisitor) VisitResult   { return v.VisitTodoStmt(stmt) }
+func (stmt *AssertStmt) Accept(v FullVisitor) VisitResult { return v.VisitAssertStmt(stmt) }
+func (stmt *PrintStmt) Accept(v FullVisitor) VisitResult  { return v.VisitPrintStmt(stmt) }
 
 func (stmt *BadStmt) statementNode()           {}
 func (stmt *DeclStmt) statementNode()          {}
@@ -184,3 +214,5 @@ func (stmt *ForRangeStmt) statementNode()      {}
 func (stmt *BreakContinueStmt) statementNode() {}
 func (stmt *ReturnStmt) statementNode()        {}
 func (stmt *TodoStmt) statementNode()          {}
+func (stmt *AssertStmt) statementNode()        {}
+func (stmt *PrintStmt) statementNode()         {}
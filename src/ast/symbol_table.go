@@ -7,7 +7,7 @@ import (
 // stores symbols for one scope of an ast
 type SymbolTable struct {
 	Enclosing    *SymbolTable           // enclosing scope (nil in the global scope)
-	Declarations map[string]Declaration // map of all variables, functions and structs
+	Declarations map[string]Declaration // map of all variables, functions and structs (enum variants map to their *EnumDecl)
 }
 
 func NewSymbolTable(enclosing *SymbolTable) *SymbolTable {
@@ -32,6 +32,51 @@ func (scope *SymbolTable) LookupDecl(name string) (Declaration, bool, bool) {
 	}
 }
 
+// returns the declaration corresponding to name and the scope it was declared in
+// or nil, nil if it does not exist
+func (scope *SymbolTable) lookupWithScope(name string) (Declaration, *SymbolTable) {
+	for table := scope; table != nil; table = table.Enclosing {
+		if decl, ok := table.Declarations[name]; ok {
+			return decl, table
+		}
+	}
+	return nil, nil
+}
+
+// returns the nesting depth of the scope
+// the global scope has depth 0 and every enclosed scope adds 1
+func (scope *SymbolTable) depth() int {
+	depth := 0
+	for table := scope.Enclosing; table != nil; table = table.Enclosing {
+		depth++
+	}
+	return depth
+}
+
+// returns the variable declaration corresponding to name,
+// the depth of the scope it was declared in (0 for the global scope) and wether it exists
+// if name is shadowed by something that is not a variable, false is returned
+// call like this: decl, depth, isVar := LookupVar(name)
+func (scope *SymbolTable) LookupVar(name string) (*VarDecl, int, bool) {
+	decl, table := scope.lookupWithScope(name)
+	if varDecl, isVar := decl.(*VarDecl); isVar {
+		return varDecl, table.depth(), true
+	}
+	return nil, -1, false
+}
+
+// returns the function declaration corresponding to name,
+// the depth of the scope it was declared in (0 for the global scope) and wether it exists
+// if name is shadowed by something that is not a function, false is returned
+// call like this: decl, depth, isFunc := LookupFunc(name)
+func (scope *SymbolTable) LookupFunc(name string) (*FuncDecl, int, bool) {
+	decl, table := scope.lookupWithScope(name)
+	if funcDecl, isFunc := decl.(*FuncDecl); isFunc {
+		return funcDecl, table.depth(), true
+	}
+	return nil, -1, false
+}
+
 // inserts a declaration into the scope if it didn't exist yet
 // and returns wether it already existed
 // BadDecls are ignored
@@ -63,8 +108,28 @@ func (scope *SymbolTable) LookupType(name string) (ddptypes.Type, bool) {
 			return decl.Type, true
 		case *TypeDefDecl:
 			return decl.Type, true
+		case *EnumDecl:
+			// the variants of an enum are also stored under their own names
+			if decl.Name() == name {
+				return decl.Type, true
+			}
+			return nil, false
 		default:
 			return nil, false
 		}
 	}
 }
+
+// returns the enum declaration that has a variant with the given name, if present
+//
+//	enumDecl, ok := table.LookupEnumVariant(name)
+func (scope *SymbolTable) LookupEnumVariant(name string) (*EnumDecl, bool) {
+	decl, ok, _ := scope.LookupDecl(name)
+	if !ok {
+		return nil, false
+	}
+	if enumDecl, isEnum := decl.(*EnumDecl); isEnum && enumDecl.Name() != name {
+		return enumDecl, true
+	}
+	return nil, false
+}
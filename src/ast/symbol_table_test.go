@@ -0,0 +1,75 @@
+package ast
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupVar(t *testing.T) {
+	assert := assert.New(t)
+
+	global := NewSymbolTable(nil)
+	function := NewSymbolTable(global)
+	block := NewSymbolTable(function)
+
+	globalVar := &VarDecl{}
+	localVar := &VarDecl{}
+	global.Declarations["a"] = globalVar
+	global.Declarations["b"] = globalVar
+	block.Declarations["a"] = localVar
+	function.Declarations["f"] = &FuncDecl{}
+
+	decl, depth, ok := block.LookupVar("a")
+	assert.True(ok)
+	assert.Same(localVar, decl)
+	assert.Equal(2, depth)
+
+	decl, depth, ok = block.LookupVar("b")
+	assert.True(ok)
+	assert.Same(globalVar, decl)
+	assert.Equal(0, depth)
+
+	decl, depth, ok = function.LookupVar("a")
+	assert.True(ok)
+	assert.Same(globalVar, decl)
+	assert.Equal(0, depth)
+
+	decl, _, ok = block.LookupVar("f")
+	assert.False(ok)
+	assert.Nil(decl)
+
+	_, _, ok = block.LookupVar("c")
+	assert.False(ok)
+}
+
+func TestLookupFunc(t *testing.T) {
+	assert := assert.New(t)
+
+	global := NewSymbolTable(nil)
+	function := NewSymbolTable(global)
+	block := NewSymbolTable(function)
+
+	globalFunc := &FuncDecl{}
+	global.Declarations["f"] = globalFunc
+	global.Declarations["g"] = globalFunc
+	function.Declarations["g"] = &VarDecl{}
+
+	decl, depth, ok := block.LookupFunc("f")
+	assert.True(ok)
+	assert.Same(globalFunc, decl)
+	assert.Equal(0, depth)
+
+	// g is shadowed by a variable
+	decl, _, ok = block.LookupFunc("g")
+	assert.False(ok)
+	assert.Nil(decl)
+
+	decl, depth, ok = global.LookupFunc("g")
+	assert.True(ok)
+	assert.Same(globalFunc, decl)
+	assert.Equal(0, depth)
+
+	_, _, ok = block.LookupFunc("h")
+	assert.False(ok)
+}
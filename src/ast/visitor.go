@@ -29,6 +29,7 @@ type FullVisitor interface {
 	FuncDeclVisitor
 	FuncDefVisitor
 	StructDeclVisitor
+	EnumDeclVisitor
 	TypeAliasDeclVisitor
 	TypeDefDeclVisitor
 
@@ -45,6 +46,8 @@ type FullVisitor interface {
 	BoolLitVisitor
 	CharLitVisitor
 	StringLitVisitor
+	NothingLitVisitor
+	EnumLitVisitor
 	ListLitVisitor
 	UnaryExprVisitor
 	BinaryExprVisitor
@@ -55,6 +58,8 @@ type FullVisitor interface {
 	GroupingVisitor
 	FuncCallVisitor
 	StructLiteralVisitor
+	FuncRefVisitor
+	FuncValueCallVisitor
 
 	/*
 		Statements
@@ -73,6 +78,8 @@ type FullVisitor interface {
 	BreakContinueStmtVisitor
 	ReturnStmtVisitor
 	TodoStmtVisitor
+	AssertStmtVisitor
+	PrintStmtVisitor
 }
 
 type (
@@ -96,6 +103,10 @@ type (
 		Visitor
 		VisitStructDecl(*StructDecl) VisitResult
 	}
+	EnumDeclVisitor interface {
+		Visitor
+		VisitEnumDecl(*EnumDecl) VisitResult
+	}
 	TypeAliasDeclVisitor interface {
 		Visitor
 		VisitTypeAliasDecl(*TypeAliasDecl) VisitResult
@@ -141,6 +152,14 @@ type (
 		Visitor
 		VisitStringLit(*StringLit) VisitResult
 	}
+	NothingLitVisitor interface {
+		Visitor
+		VisitNothingLit(*NothingLit) VisitResult
+	}
+	EnumLitVisitor interface {
+		Visitor
+		VisitEnumLit(*EnumLit) VisitResult
+	}
 	ListLitVisitor interface {
 		Visitor
 		VisitListLit(*ListLit) VisitResult
@@ -181,6 +200,14 @@ type (
 		Visitor
 		VisitStructLiteral(*StructLiteral) VisitResult
 	}
+	FuncRefVisitor interface {
+		Visitor
+		VisitFuncRef(*FuncRef) VisitResult
+	}
+	FuncValueCallVisitor interface {
+		Visitor
+		VisitFuncValueCall(*FuncValueCall) VisitResult
+	}
 
 	BadStmtVisitor interface {
 		Visitor
@@ -234,6 +261,14 @@ type (
 		Visitor
 		VisitTodoStmt(*TodoStmt) VisitResult
 	}
+	AssertStmtVisitor interface {
+		Visitor
+		VisitAssertStmt(*AssertStmt) VisitResult
+	}
+	PrintStmtVisitor interface {
+		Visitor
+		VisitPrintStmt(*PrintStmt) VisitResult
+	}
 )
 
 // helper types to easily create small visitors
@@ -283,6 +318,15 @@ func (f StructDeclVisitorFunc) VisitStructDecl(stmt *StructDecl) VisitResult {
 	return f(stmt)
 }
 
+type EnumDeclVisitorFunc func(*EnumDecl) VisitResult
+
+var _ EnumDeclVisitor = (EnumDeclVisitorFunc)(nil)
+
+func (EnumDeclVisitorFunc) Visitor() {}
+func (f EnumDeclVisitorFunc) VisitEnumDecl(stmt *EnumDecl) VisitResult {
+	return f(stmt)
+}
+
 type TypeAliasDeclVisitorFunc func(*TypeAliasDecl) VisitResult
 
 var _ TypeAliasDeclVisitor = (TypeAliasDeclVisitorFunc)(nil)
@@ -383,6 +427,24 @@ func (f StringLitVisitorFunc) VisitStringLit(expr *StringLit) VisitResult {
 	return f(expr)
 }
 
+type NothingLitVisitorFunc func(*NothingLit) VisitResult
+
+var _ NothingLitVisitor = (NothingLitVisitorFunc)(nil)
+
+func (NothingLitVisitorFunc) Visitor() {}
+func (f NothingLitVisitorFunc) VisitNothingLit(expr *NothingLit) VisitResult {
+	return f(expr)
+}
+
+type EnumLitVisitorFunc func(*EnumLit) VisitResult
+
+var _ EnumLitVisitor = (EnumLitVisitorFunc)(nil)
+
+func (EnumLitVisitorFunc) Visitor() {}
+func (f EnumLitVisitorFunc) VisitEnumLit(expr *EnumLit) VisitResult {
+	return f(expr)
+}
+
 type ListLitVisitorFunc func(*ListLit) VisitResult
 
 var _ ListLitVisitor = (ListLitVisitorFunc)(nil)
@@ -473,6 +535,24 @@ func (f StructLiteralVisitorFunc) VisitStructLiteral(expr *StructLiteral) VisitR
 	return f(expr)
 }
 
+type FuncRefVisitorFunc func(*FuncRef) VisitResult
+
+var _ FuncRefVisitor = (FuncRefVisitorFunc)(nil)
+
+func (FuncRefVisitorFunc) Visitor() {}
+func (f FuncRefVisitorFunc) VisitFuncRef(expr *FuncRef) VisitResult {
+	return f(expr)
+}
+
+type FuncValueCallVisitorFunc func(*FuncValueCall) VisitResult
+
+var _ FuncValueCallVisitor = (FuncValueCallVisitorFunc)(nil)
+
+func (FuncValueCallVisitorFunc) Visitor() {}
+func (f FuncValueCallVisitorFunc) VisitFuncValueCall(expr *FuncValueCall) VisitResult {
+	return f(expr)
+}
+
 // Statements
 type BadStmtVisitorFunc func(*BadStmt) VisitResult
 
@@ -590,3 +670,21 @@ func (TodoStmtVisitorFunc) Visitor() {}
 func (f TodoStmtVisitorFunc) VisitTodoStmt(stmt *TodoStmt) VisitResult {
 	return f(stmt)
 }
+
+type AssertStmtVisitorFunc func(*AssertStmt) VisitResult
+
+var _ AssertStmtVisitor = (AssertStmtVisitorFunc)(nil)
+
+func (AssertStmtVisitorFunc) Visitor() {}
+func (f AssertStmtVisitorFunc) VisitAssertStmt(stmt *AssertStmt) VisitResult {
+	return f(stmt)
+}
+
+type PrintStmtVisitorFunc func(*PrintStmt) VisitResult
+
+var _ PrintStmtVisitor = (PrintStmtVisitorFunc)(nil)
+
+func (PrintStmtVisitorFunc) Visitor() {}
+func (f PrintStmtVisitorFunc) VisitPrintStmt(stmt *PrintStmt) VisitResult {
+	return f(stmt)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ast/annotators"
@@ -25,20 +27,28 @@ import (
 // compiles a mainModule and all it's imports
 // every module is written to a io.Writer created
 // by calling destCreator with the given module
+// opts are applied to the compiler of every module
+// stats are only collected for the main module
+// modules that session already compiled are skipped, session may be nil
 // returns:
 //   - a set of all external dependendcies
 //   - an error
 func compileWithImports(mod *ast.Module, destCreator func(*ast.Module) io.Writer,
-	errHndl ddperror.Handler, optimizationLevel uint,
+	errHndl ddperror.Handler, stats *IRStats, session *Session, opts ...compilerOption,
 ) (map[string]struct{}, error) {
 	compiledMods := map[string]*ast.Module{}
+	if session != nil {
+		for fileName := range session.compiled {
+			compiledMods[fileName] = nil
+		}
+	}
 	dependencies := map[string]struct{}{}
-	return compileWithImportsRec(mod, destCreator, compiledMods, dependencies, true, errHndl, optimizationLevel)
+	return compileWithImportsRec(mod, destCreator, compiledMods, dependencies, true, errHndl, stats, opts)
 }
 
 func compileWithImportsRec(mod *ast.Module, destCreator func(*ast.Module) io.Writer,
 	compiledMods map[string]*ast.Module, dependencies map[string]struct{},
-	isMainModule bool, errHndl ddperror.Handler, optimizationLevel uint,
+	isMainModule bool, errHndl ddperror.Handler, stats *IRStats, opts []compilerOption,
 ) (map[string]struct{}, error) {
 	// the ast must be valid (and should have been resolved and typechecked beforehand)
 	if mod.Ast.Faulty {
@@ -64,13 +74,14 @@ func compileWithImportsRec(mod *ast.Module, destCreator func(*ast.Module) io.Wri
 	}
 
 	// compile this module
-	if _, err := newCompiler(mod, errHndl, optimizationLevel).compile(destCreator(mod), isMainModule); err != nil {
+	comp := newCompiler(mod, errHndl, append(slices.Clip(opts), withStats(stats))...)
+	if _, err := comp.compile(destCreator(mod), isMainModule); err != nil {
 		return nil, fmt.Errorf("Fehler beim Kompilieren des Moduls '%s': %w", mod.GetIncludeFilename(), err)
 	}
 
 	// recursively compile the other dependencies
 	for _, imprt := range mod.Imports {
-		if _, err := compileWithImportsRec(imprt.Module, destCreator, compiledMods, dependencies, false, errHndl, optimizationLevel); err != nil {
+		if _, err := compileWithImportsRec(imprt.Module, destCreator, compiledMods, dependencies, false, errHndl, nil, opts); err != nil {
 			return nil, err
 		}
 	}
@@ -90,31 +101,58 @@ type compiler struct {
 	mod               *ir.Module       // the ir module (basically the ir file)
 	errorHandler      ddperror.Handler // errors are passed to this function
 	optimizationLevel uint             // level of optimization
+	memoryProfiling   bool             // wether allocations are tagged for the memory profile of the runtime
+	verify            bool             // wether the generated ir is checked by verifyModule before it is written
+	stats             *IRStats         // if non-nil, the IRStats of the module are written to it after compiling
+	comments          bool             // wether the llvm ir is commented with the ast nodes it was generated from
+	session           *Session         // if non-nil, the main module is compiled as part of this session
 	result            *Result          // result of the compilation
 	llTarget          llvmTarget       // information about the target machine
 
-	cbb              *ir.Block                                 // current basic block in the ir
-	cf               *ir.Func                                  // current function
-	scp              *scope                                    // current scope in the ast (not in the ir)
-	cfscp            *scope                                    // out-most scope of the current function
-	functions        map[string]*funcWrapper                   // all the global functions
-	typeMap          map[ddptypes.Type]*ast.Module             // maps ddpTypes to the module they originate from
-	structTypes      map[*ddptypes.StructType]*ddpIrStructType // struct names mapped to their IR type
-	latestReturn     value.Value                               // return of the latest evaluated expression (in the ir)
-	latestReturnType ddpIrType                                 // the type of latestReturn
-	latestIsTemp     bool                                      // ewther the latestReturn is a temporary or not
-	importedModules  map[*ast.Module]struct{}                  // all the modules that have already been imported
-	currentNode      ast.Node                                  // used for error reporting
+	cbb              *ir.Block                                     // current basic block in the ir
+	cf               *ir.Func                                      // current function
+	scp              *scope                                        // current scope in the ast (not in the ir)
+	cfscp            *scope                                        // out-most scope of the current function
+	functions        map[string]*funcWrapper                       // all the global functions
+	typeMap          map[ddptypes.Type]*ast.Module                 // maps ddpTypes to the module they originate from
+	structTypes      map[*ddptypes.StructType]*ddpIrStructType     // struct names mapped to their IR type
+	optionalTypes    map[*ddpIrPrimitiveType]*ddpIrOptionalType    // the underlying types of optionals mapped to the optional IR type
+	enumTypes        map[*ddptypes.EnumType]*ddpIrEnumType         // enum types mapped to their IR type
+	functionTypes    map[*ddptypes.FunctionType]*ddpIrFunctionType // function types mapped to their IR type
+	functionValues   map[string]*ir.Func                           // the functions used as function values (see getFunctionValue)
+	latestReturn     value.Value                                   // return of the latest evaluated expression (in the ir)
+	latestReturnType ddpIrType                                     // the type of latestReturn
+	latestIsTemp     bool                                          // ewther the latestReturn is a temporary or not
+	importedModules  map[*ast.Module]struct{}                      // all the modules that have already been imported
+	currentNode      ast.Node                                      // used for error reporting
 	typeDefVTables   map[string]constant.Constant
-
-	moduleInitFunc             *ir.Func  // the module_init func of this module
-	moduleInitCbb              *ir.Block // cbb but for module_init
-	moduleDisposeFunc          *ir.Func
-	out_of_bounds_error_string *ir.Global
-	slice_error_string         *ir.Global
-	todo_error_string          *ir.Global
-	bad_cast_error_string      *ir.Global
-	invalid_utf8_error_string  *ir.Global
+	blockNames       map[string]int        // counts how often a block name was used to keep them unique (see newBlock)
+	constantGlobals  map[string]*ir.Global // constant globals by their content-derived name (see constantString)
+
+	moduleInitFunc                    *ir.Func  // the module_init func of this module
+	moduleInitCbb                     *ir.Block // cbb but for module_init
+	moduleDisposeFunc                 *ir.Func
+	out_of_bounds_error_string        *ir.Global
+	string_out_of_bounds_error_string *ir.Global
+	slice_error_string                *ir.Global
+	list_repeat_error_string          *ir.Global
+	todo_error_string                 *ir.Global
+	bad_cast_error_string             *ir.Global
+	invalid_utf8_error_string         *ir.Global
+	zero_step_error_string            *ir.Global
+	nothing_cast_error_string         *ir.Global
+
+	// external functions of the ddp-runtime and libc (see initRuntimeFunctions)
+	ddp_reallocate_irfun      *ir.Func
+	ddp_runtime_error_irfun   *ir.Func
+	utf8_string_to_char_irfun *ir.Func
+	_libc_memcpy_irfun        *ir.Func
+	_libc_memcmp_irfun        *ir.Func
+	_libc_memmove_irfun       *ir.Func
+
+	// only declared if the module is compiled with memory profiling
+	ddp_enable_memory_profiling_irfun *ir.Func
+	ddp_memory_profile_tag_irfun      *ir.Func
 
 	curLeaveBlock    *ir.Block // leave block of the current loop
 	curContinueBlock *ir.Block // block where a continue should jump to
@@ -128,16 +166,63 @@ type compiler struct {
 	ddpintlist, ddpfloatlist, ddpboollist, ddpcharlist, ddpstringlist, ddpanylist *ddpIrListType
 }
 
+// configures a compiler created by newCompiler
+type compilerOption func(*compiler)
+
+// sets the level of optimization (see Options.OptimizationLevel), 0 by default
+func withOptimizationLevel(level uint) compilerOption {
+	return func(c *compiler) {
+		c.optimizationLevel = level
+	}
+}
+
+// sets wether allocations are tagged for the memory profile, disabled by default
+func withMemoryProfiling(enabled bool) compilerOption {
+	return func(c *compiler) {
+		c.memoryProfiling = enabled
+	}
+}
+
+// sets wether the generated ir is checked by verifyModule, disabled by default
+func withVerify(enabled bool) compilerOption {
+	return func(c *compiler) {
+		c.verify = enabled
+	}
+}
+
+// sets the IRStats the stats of the module are written to, nil by default
+func withStats(stats *IRStats) compilerOption {
+	return func(c *compiler) {
+		c.stats = stats
+	}
+}
+
+// sets wether the llvm ir is commented, enabled by default
+// comments increase the size of the intermediate files
+func withComments(enabled bool) compilerOption {
+	return func(c *compiler) {
+		c.comments = enabled
+	}
+}
+
+// sets the Session in which the main module is compiled, nil by default
+func withSession(session *Session) compilerOption {
+	return func(c *compiler) {
+		c.session = session
+	}
+}
+
 // create a new Compiler to compile the passed AST
-func newCompiler(module *ast.Module, errorHandler ddperror.Handler, optimizationLevel uint) *compiler {
+// without any opts the default configuration is used
+func newCompiler(module *ast.Module, errorHandler ddperror.Handler, opts ...compilerOption) *compiler {
 	if errorHandler == nil { // default error handler does nothing
 		errorHandler = ddperror.EmptyHandler
 	}
-	return &compiler{
-		ddpModule:         module,
-		mod:               ir.NewModule(),
-		errorHandler:      errorHandler,
-		optimizationLevel: optimizationLevel,
+	c := &compiler{
+		ddpModule:    module,
+		mod:          ir.NewModule(),
+		errorHandler: errorHandler,
+		comments:     true,
 		result: &Result{
 			Dependencies: make(map[string]struct{}),
 		},
@@ -148,15 +233,25 @@ func newCompiler(module *ast.Module, errorHandler ddperror.Handler, optimization
 		functions:        make(map[string]*funcWrapper),
 		typeMap:          createTypeMap(module),
 		structTypes:      make(map[*ddptypes.StructType]*ddpIrStructType),
+		optionalTypes:    make(map[*ddpIrPrimitiveType]*ddpIrOptionalType),
+		enumTypes:        make(map[*ddptypes.EnumType]*ddpIrEnumType),
+		functionTypes:    make(map[*ddptypes.FunctionType]*ddpIrFunctionType),
+		functionValues:   make(map[string]*ir.Func),
 		latestReturn:     nil,
 		latestReturnType: nil,
 		latestIsTemp:     false,
 		importedModules:  make(map[*ast.Module]struct{}),
 		typeDefVTables:   make(map[string]constant.Constant),
+		blockNames:       make(map[string]int),
+		constantGlobals:  make(map[string]*ir.Global),
 		curLeaveBlock:    nil,
 		curContinueBlock: nil,
 		curLoopScope:     nil,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // compile the AST contained in c
@@ -166,7 +261,7 @@ func newCompiler(module *ast.Module, errorHandler ddperror.Handler, optimization
 func (c *compiler) compile(w io.Writer, isMainModule bool) (result *Result, rerr error) {
 	defer compiler_panic_wrapper(c)
 
-	llTarget, err := newllvmTarget()
+	llTarget, err := newllvmTarget(llvm.RelocDynamicNoPic) // only used for the data layout
 	if err != nil {
 		return nil, err
 	}
@@ -186,9 +281,15 @@ func (c *compiler) compile(w io.Writer, isMainModule bool) (result *Result, rerr
 		)
 		c.cf = ddpmain               // first function is ddpmain
 		c.cbb = ddpmain.NewBlock("") // first block
+		if c.memoryProfiling {
+			c.cbb.NewCall(c.ddp_enable_memory_profiling_irfun)
+		}
 	}
 
 	// visit every statement in the modules AST and compile it
+	// top-level statements, including the initializers of global variables,
+	// are executed in source order, which is why a global can only be used after its declaration
+	// initializing all globals beforehand would reorder side effects like in- and output
 	for _, stmt := range c.ddpModule.Ast.Statements {
 		if isMainModule {
 			c.visitNode(stmt)
@@ -202,20 +303,33 @@ func (c *compiler) compile(w io.Writer, isMainModule bool) (result *Result, rerr
 		}
 	}
 
-	if isMainModule {
+	// in a session the globals and imported modules are still used by the following main modules
+	if isMainModule && c.session == nil {
 		c.scp = c.exitScope(c.scp) // exit the main scope
 		// call all the module_dispose functions
-		for mod := range c.importedModules {
+		for _, mod := range c.sortedImportedModules() {
 			_, dispose_name := getModuleInitDisposeName(mod)
 			dispose_fun := c.functions[dispose_name]
 			c.cbb.NewCall(dispose_fun.irFunc)
 		}
+	}
+	if isMainModule {
 		// on success ddpmain returns 0
 		c.cbb.NewRet(zero)
 	}
 
 	c.moduleInitCbb.NewRet(nil) // terminate the module_init func
 
+	if c.verify {
+		if err := verifyModule(c.mod); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.stats != nil {
+		*c.stats = c.collectStats()
+	}
+
 	_, err = c.mod.WriteTo(w)
 	return c.result, err
 }
@@ -238,6 +352,12 @@ func (c *compiler) dumpListDefinitions(w io.Writer) error {
 	c.ddpany = c.defineAnyType()
 	c.setupListTypes(false) // we want definitions
 
+	if c.verify {
+		if err := verifyModule(c.mod); err != nil {
+			return err
+		}
+	}
+
 	_, err := c.mod.WriteTo(w)
 	return err
 }
@@ -255,12 +375,8 @@ func (c *compiler) addExternalDependencies() {
 	}
 }
 
-// if the llvm-ir should be commented
-// increases the intermediate file size
-var Comments_Enabled = true
-
 func (c *compiler) commentNode(block *ir.Block, node ast.Node, details string) {
-	if Comments_Enabled {
+	if c.comments {
 		comment := fmt.Sprintf("F %s, %d:%d: %s", c.ddpModule.FileName, node.Token().Range.Start.Line, node.Token().Range.Start.Column, node)
 		if details != "" {
 			comment += " (" + details + ")"
@@ -270,7 +386,7 @@ func (c *compiler) commentNode(block *ir.Block, node ast.Node, details string) {
 }
 
 func (c *compiler) comment(comment string, block *ir.Block) {
-	if Comments_Enabled {
+	if c.comments {
 		block.Insts = append(block.Insts, irutil.NewComment(comment))
 	}
 }
@@ -284,6 +400,11 @@ func (c *compiler) visitNode(node ast.Node) {
 // helper to evaluate an expression and return its ir value and type
 // the  bool signals wether the returned value is a temporary value that can be claimed
 // or if it is a 'reference' to a variable that must be copied
+// variables, list elements and struct fields are returned as references without copying them,
+// which is safe because consumers of a reference
+//   - only read from it (e.g. die Länge von, an der Stelle, Für jede, comparisons, casts)
+//   - or copy it before retaining it (see claimOrCopy)
+//   - and never read from it after the referenced value was freed or changed (see VisitAssignStmt)
 func (c *compiler) evaluate(expr ast.Expression) (value.Value, ddpIrType, bool) {
 	c.visitNode(expr)
 	return c.latestReturn, c.latestReturnType, c.latestIsTemp
@@ -319,19 +440,17 @@ func (c *compiler) setup() {
 
 // used in setup()
 func (c *compiler) setupErrorStrings() {
-	createErrorString := func(msg string) *ir.Global {
-		error_string := c.mod.NewGlobalDef("", constant.NewCharArrayFromString(msg))
-		error_string.Linkage = enum.LinkageInternal
-		error_string.Visibility = enum.VisibilityDefault
-		error_string.Immutable = true
-		return error_string
-	}
+	createErrorString := c.constantString
 
 	c.out_of_bounds_error_string = createErrorString("Zeile %lld, Spalte %lld: Index außerhalb der Listen Länge (Index war %ld, Listen Länge war %ld)\n")
+	c.string_out_of_bounds_error_string = createErrorString("Zeile %lld, Spalte %lld: Index außerhalb der Text Länge (Index war %ld, Text Länge war %ld)\n")
 	c.slice_error_string = createErrorString("Invalide Indexe (Index 1 war %ld, Index 2 war %ld)\n")
+	c.list_repeat_error_string = createErrorString("Zeile %lld, Spalte %lld: Eine Liste mit %ld Elementen kann nicht %ld mal wiederholt werden, da das Ergebnis zu lang wäre\n")
 	c.todo_error_string = createErrorString("Zeile %lld, Spalte %lld: Dieser Teil des Programms wurde noch nicht implementiert\n")
 	c.bad_cast_error_string = createErrorString("Zeile %lld, Spalte %lld: Falsche Typumwandlung")
 	c.invalid_utf8_error_string = createErrorString("Zeile %lld, Spalte %lld: Invalider UTF8 Wert im Text")
+	c.zero_step_error_string = createErrorString("Zeile %lld, Spalte %lld: Die Schrittgröße einer Zählschleife darf nicht 0 sein\n")
+	c.nothing_cast_error_string = createErrorString("Zeile %lld, Spalte %lld: Der Wert war nichts und kann nicht umgewandelt werden\n")
 }
 
 // used in setup()
@@ -378,12 +497,14 @@ func (c *compiler) setupOperators() {
 	// ddpstring to type cast
 	c.declareExternalRuntimeFunction("ddp_string_to_int", ddpint, ir.NewParam("str", c.ddpstring.ptr))
 	c.declareExternalRuntimeFunction("ddp_string_to_float", ddpfloat, ir.NewParam("str", c.ddpstring.ptr))
+	c.declareExternalRuntimeFunction("ddp_string_to_char", ddpchar, ir.NewParam("str", c.ddpstring.ptr))
 }
 
 // deep copies the value pointed to by src into dest
 // and returns dest
 func (c *compiler) deepCopyInto(dest, src value.Value, typ ddpIrType) value.Value {
 	c.cbb.NewCall(typ.DeepCopyFunc(), dest, src)
+	c.tagAllocation(dest, typ)
 	return dest
 }
 
@@ -422,8 +543,8 @@ func (c *compiler) freeTemporaries(scp *scope, force bool) {
 // frees all local variables
 // returns the enclosing scope
 func (c *compiler) exitScope(scp *scope) *scope {
-	for _, v := range scp.variables {
-		if !v.isRef && !v.protected {
+	for _, name := range scp.variableNames() {
+		if v := scp.variables[name]; !v.isRef && !v.protected {
 			c.freeNonPrimitive(v.val, v.typ)
 		}
 	}
@@ -437,8 +558,8 @@ func (c *compiler) exitFuncScope(fun *ast.FuncDecl) *scope {
 		meta = attachement.(annotators.ConstFuncParamMeta)
 	}
 
-	for paramName, v := range c.cfscp.variables {
-		if !v.isRef && (!meta.IsConst[paramName] || c.optimizationLevel < 2) {
+	for _, paramName := range c.cfscp.variableNames() {
+		if v := c.cfscp.variables[paramName]; !v.isRef && (!meta.IsConst[paramName] || c.optimizationLevel < 2) {
 			c.freeNonPrimitive(v.val, v.typ)
 		}
 	}
@@ -597,34 +718,19 @@ func (c *compiler) defineFuncBody(irFunc *ir.Func, hasReturnParam bool, params [
 		}
 	}
 
-	// modified VisitBlockStmt
-	c.scp = newScope(c.scp) // a block gets its own scope
-	toplevelReturn := false
 	body := decl.Body
 	if ast.IsForwardDecl(decl) {
 		body = decl.Def.Body
 	}
-	for _, stmt := range body.Statements {
-		c.visitNode(stmt)
-		// on toplevel return statements, ignore anything that follows
-		if _, ok := stmt.(*ast.ReturnStmt); ok {
-			toplevelReturn = true
-			break
-		}
-	}
-	// free the local variables of the function
-	if toplevelReturn {
-		c.scp = c.scp.enclosing
-	} else {
-		c.scp = c.exitScope(c.scp)
-	}
+	// the return statement already freed the local variables and parameters
+	wasReturn := c.compileBlock(body)
 
 	if c.cbb.Term == nil {
 		c.cbb.NewRet(nil) // every block needs a terminator, and every function a return
 	}
 
 	// free the parameters of the function
-	if toplevelReturn {
+	if wasReturn {
 		c.scp = c.scp.enclosing
 	} else {
 		c.scp = c.exitFuncScope(decl)
@@ -637,6 +743,10 @@ func (c *compiler) VisitStructDecl(decl *ast.StructDecl) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (c *compiler) VisitEnumDecl(decl *ast.EnumDecl) ast.VisitResult {
+	return ast.VisitRecurse
+}
+
 func (c *compiler) VisitTypeAliasDecl(decl *ast.TypeAliasDecl) ast.VisitResult {
 	return ast.VisitRecurse
 }
@@ -672,6 +782,7 @@ func (c *compiler) VisitIndexing(e *ast.Indexing) ast.VisitResult {
 	if stringIndexing != nil {
 		lhs, _, _ := c.evaluate(stringIndexing.Lhs)
 		index, _, _ := c.evaluate(stringIndexing.Index)
+		c.checkStringIndex(lhs, index, stringIndexing.Token())
 		c.latestReturn = c.cbb.NewCall(c.ddpstring.indexIrFun, lhs, index)
 		c.latestReturnType = c.ddpchartyp
 		// c.latestIsTemp = false // it is a primitive typ, so we don't care
@@ -693,11 +804,9 @@ func (c *compiler) VisitFieldAccess(expr *ast.FieldAccess) ast.VisitResult {
 
 	if fieldType.IsPrimitive() {
 		c.latestReturn = c.cbb.NewLoad(fieldType.IrType(), fieldPtr)
-	} else {
-		dest := c.NewAlloca(fieldType.IrType())
-		c.deepCopyInto(dest, fieldPtr, fieldType)
-		c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, fieldType)
-		c.latestIsTemp = true
+	} else { // like variables, fields are used by pointer and only copied by the consumers that claim them
+		c.latestReturn = fieldPtr
+		c.latestIsTemp = false
 	}
 	c.latestReturnType = fieldType
 	return ast.VisitRecurse
@@ -734,8 +843,10 @@ func (c *compiler) VisitCharLit(e *ast.CharLit) ast.VisitResult {
 
 // string literals are created by the runtime
 // so we need to do some work here
+// the created ddpstring is a temporary of the current scope (see scope)
+// it is either claimed (e.g. by a variable or list element) or freed with the scope
 func (c *compiler) VisitStringLit(e *ast.StringLit) ast.VisitResult {
-	constStr := c.mod.NewGlobalDef("", irutil.NewCString(e.Value))
+	constStr := c.constantString(e.Value)
 	// call the ddp-runtime function to create the ddpstring
 	c.commentNode(c.cbb, e, constStr.Name())
 	dest := c.NewAlloca(c.ddpstring.typ)
@@ -743,12 +854,33 @@ func (c *compiler) VisitStringLit(e *ast.StringLit) ast.VisitResult {
 		c.cbb.NewStore(c.ddpstring.DefaultValue(), dest)
 	} else {
 		c.cbb.NewCall(c.ddpstring.fromConstantsIrFun, dest, c.cbb.NewBitCast(constStr, i8ptr))
+		c.tagAllocation(dest, c.ddpstring)
 	}
 	c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, c.ddpstring) // so that it is freed later
 	c.latestIsTemp = true
 	return ast.VisitRecurse
 }
 
+func (c *compiler) VisitNothingLit(e *ast.NothingLit) ast.VisitResult {
+	c.latestIsTemp = false
+	// literals without a type are only valid in expression statements
+	if e.Type == nil {
+		c.latestReturn, c.latestReturnType = nil, c.void
+		return ast.VisitRecurse
+	}
+
+	typ := c.toIrType(e.Type)
+	c.latestReturn, c.latestReturnType = typ.DefaultValue(), typ
+	return ast.VisitRecurse
+}
+
+func (c *compiler) VisitEnumLit(e *ast.EnumLit) ast.VisitResult {
+	c.latestReturn, c.latestReturnType, c.latestIsTemp = newInt(int64(e.Index)), c.getEnumType(e.Decl.Type), false
+	return ast.VisitRecurse
+}
+
+// like a string literal the list is a temporary of the current scope
+// its elements are claimed or copied into it (see claimOrCopy), so they are freed with the list
 func (c *compiler) VisitListLit(e *ast.ListLit) ast.VisitResult {
 	listType := c.toIrType(e.Type).(*ddpIrListType)
 	list := c.NewAlloca(listType.IrType())
@@ -768,6 +900,7 @@ func (c *compiler) VisitListLit(e *ast.ListLit) ast.VisitResult {
 
 	// create a empty list of the correct length
 	c.cbb.NewCall(listType.fromConstantsIrFun, list, listLen)
+	c.tagAllocation(list, listType)
 
 	listArr := c.loadStructField(list, list_arr_field_index) // load the array
 
@@ -779,8 +912,10 @@ func (c *compiler) VisitListLit(e *ast.ListLit) ast.VisitResult {
 			c.claimOrCopy(elementPtr, val, valTyp, isTemp)
 		}
 	} else if e.Count != nil && e.Value != nil { // single Value multiple times
-		val, _, _ := c.evaluate(e.Value) // if val is a temporary, it is freed automatically
+		// if val is a temporary, it is freed automatically (also if listLen is 0)
+		val, _, _ := c.evaluate(e.Value)
 
+		// every element gets its own copy which is owned by the list
 		c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
 			elementPtr := c.indexArray(listArr, index)
 			if listType.elementType.IsPrimitive() {
@@ -806,47 +941,87 @@ func (c *compiler) VisitUnaryExpr(e *ast.UnaryExpr) ast.VisitResult {
 		})
 	}
 
+	// the length of literals is known at compile time
+	// so we don't need to create (and free) them
+	if e.Operator == ast.UN_LEN {
+		if length, ok := constantLength(e.Rhs); ok {
+			c.commentNode(c.cbb, e, e.Operator.String())
+			c.latestReturn, c.latestReturnType = newInt(length), c.ddpinttyp
+			c.latestIsTemp = false
+			return ast.VisitRecurse
+		}
+	}
+
 	rhs, typ, _ := c.evaluate(e.Rhs) // compile the expression onto which the operator is applied
 
 	// big switches for the different type combinations
 	c.commentNode(c.cbb, e, e.Operator.String())
 	switch e.Operator {
 	case ast.UN_ABS:
-		switch typ {
-		case c.ddpfloattyp:
-			// c.latestReturn = rhs < 0 ? 0 - rhs : rhs;
-			c.latestReturn = c.createTernary(c.cbb.NewFCmp(enum.FPredOLT, rhs, zerof),
-				func() value.Value { return c.cbb.NewFSub(zerof, rhs) },
-				func() value.Value { return rhs },
-			)
-			c.latestReturnType = c.ddpfloattyp
-		case c.ddpinttyp:
-			// c.latestReturn = rhs < 0 ? 0 - rhs : rhs;
-			c.latestReturn = c.createTernary(c.cbb.NewICmp(enum.IPredSLT, rhs, zero),
-				func() value.Value { return c.cbb.NewSub(zero, rhs) },
-				func() value.Value { return rhs },
-			)
-			c.latestReturnType = c.ddpinttyp
-		default:
-			c.err("invalid Parameter Type for BETRAG: %s", typ.Name())
+		abs := func(val value.Value, typ ddpIrType) value.Value {
+			switch typ {
+			case c.ddpfloattyp:
+				// val < 0 ? 0 - val : val;
+				return c.createTernary(c.cbb.NewFCmp(enum.FPredOLT, val, zerof),
+					func() value.Value { return c.cbb.NewFSub(zerof, val) },
+					func() value.Value { return val },
+				)
+			case c.ddpinttyp:
+				// val < 0 ? 0 - val : val;
+				return c.createTernary(c.cbb.NewICmp(enum.IPredSLT, val, zero),
+					func() value.Value { return c.cbb.NewSub(zero, val) },
+					func() value.Value { return val },
+				)
+			default:
+				c.err("invalid Parameter Type for BETRAG: %s", typ.Name())
+			}
+			return nil // unreachable
+		}
+		if listType, isList := typ.(*ddpIrListType); isList {
+			c.latestReturn, c.latestReturnType = c.mapNumericList(rhs, listType, abs)
+			c.latestIsTemp = true
+		} else {
+			c.latestReturn, c.latestReturnType = abs(rhs, typ), typ
 		}
 	case ast.UN_NEGATE:
+		negate := func(val value.Value, typ ddpIrType) value.Value {
+			switch typ {
+			case c.ddpfloattyp:
+				// fneg only flips the sign bit, so -(0,0) is -0,0, -(-0,0) is 0,0
+				// and NaN stays NaN (with its payload)
+				// fsub 0,0, x would instead give 0,0 for both zeros
+				// see tests/testdata/kddp/negate_float
+				return c.cbb.NewFNeg(val)
+			case c.ddpinttyp:
+				return c.cbb.NewSub(zero, val)
+			default:
+				c.err("invalid Parameter Type for NEGATE: %s", typ.Name())
+			}
+			return nil // unreachable
+		}
+		if listType, isList := typ.(*ddpIrListType); isList {
+			c.latestReturn, c.latestReturnType = c.mapNumericList(rhs, listType, negate)
+			c.latestIsTemp = true
+		} else {
+			c.latestReturn, c.latestReturnType = negate(rhs, typ), typ
+		}
+	case ast.UN_NOT:
+		if typ != c.ddpbooltyp {
+			c.err("invalid Parameter Type for NICHT: %s", typ.Name())
+		}
+		c.latestReturn = c.cbb.NewXor(rhs, constant.True)
+		c.latestReturnType = c.ddpbooltyp
+	case ast.UN_LOGIC_NOT:
 		switch typ {
-		case c.ddpfloattyp:
-			c.latestReturn = c.cbb.NewFNeg(rhs)
-			c.latestReturnType = c.ddpfloattyp
 		case c.ddpinttyp:
-			c.latestReturn = c.cbb.NewSub(zero, rhs)
+			c.latestReturn = c.cbb.NewXor(rhs, all_ones)
 			c.latestReturnType = c.ddpinttyp
+		case c.ddpchartyp:
+			c.latestReturn = c.cbb.NewXor(rhs, all_ones_char)
+			c.latestReturnType = c.ddpchartyp
 		default:
-			c.err("invalid Parameter Type for NEGATE: %s", typ.Name())
+			c.err("invalid Parameter Type for LOGISCH NICHT: %s", typ.Name())
 		}
-	case ast.UN_NOT:
-		c.latestReturn = c.cbb.NewXor(rhs, newInt(1))
-		c.latestReturnType = c.ddpbooltyp
-	case ast.UN_LOGIC_NOT:
-		c.latestReturn = c.cbb.NewXor(rhs, all_ones)
-		c.latestReturnType = c.ddpinttyp
 	case ast.UN_LEN:
 		switch typ {
 		case c.ddpstring:
@@ -859,12 +1034,329 @@ func (c *compiler) VisitUnaryExpr(e *ast.UnaryExpr) ast.VisitResult {
 			}
 		}
 		c.latestReturnType = c.ddpinttyp
+	case ast.UN_PRESENT:
+		c.latestReturn = c.isOptionalPresent(rhs)
+		c.latestReturnType = c.ddpbooltyp
 	default:
 		c.err("Unbekannter Operator '%s'", e.Operator)
 	}
 	return ast.VisitRecurse
 }
 
+// returns the runtime function that converts a value of the primitive typ to a Text
+func (c *compiler) toStringFunc(typ ddpIrType) *ir.Func {
+	switch typ {
+	case c.ddpinttyp:
+		return c.ddpstring.int_to_string_IrFun
+	case c.ddpfloattyp:
+		return c.ddpstring.float_to_string_IrFun
+	case c.ddpbooltyp:
+		return c.ddpstring.bool_to_string_IrFun
+	case c.ddpchartyp:
+		return c.ddpstring.char_to_string_IrFun
+	default:
+		c.err("invalid Parameter Type for TEXT: %s", typ.Name())
+	}
+	return nil // unreachable
+}
+
+// converts a list of primitives to a new Text of the form "1, 2, 3"
+// the returned Text has to be freed by the caller
+func (c *compiler) listToString(list value.Value, listType *ddpIrListType) value.Value {
+	result := c.NewAlloca(c.ddpstring.typ)
+	c.cbb.NewStore(c.ddpstring.DefaultValue(), result)
+
+	separator := c.NewAlloca(c.ddpstring.typ)
+	c.cbb.NewCall(c.ddpstring.fromConstantsIrFun, separator, c.cbb.NewBitCast(c.constantString(", "), i8ptr))
+	c.tagAllocation(separator, c.ddpstring)
+
+	// appends str to result without claiming str
+	appendToResult := func(str value.Value) {
+		concatenated := c.NewAlloca(c.ddpstring.typ)
+		c.cbb.NewCall(c.ddpstring.str_str_concat_IrFunc, concatenated, result, str)
+		c.cbb.NewStore(c.cbb.NewLoad(c.ddpstring.typ, concatenated), result)
+	}
+
+	listLen := c.loadStructField(list, list_len_field_index)
+	listArr := c.loadStructField(list, list_arr_field_index)
+	c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
+		c.createIfElse(c.cbb.NewICmp(enum.IPredSGT, index, zero), func() {
+			appendToResult(separator)
+		}, nil)
+
+		elementPtr := c.indexArray(listArr, index)
+		if listType.elementType == c.ddpstring {
+			appendToResult(elementPtr)
+		} else {
+			elementStr := c.NewAlloca(c.ddpstring.typ)
+			c.cbb.NewCall(c.toStringFunc(listType.elementType), elementStr, c.cbb.NewLoad(listType.elementType.IrType(), elementPtr))
+			appendToResult(elementStr)
+			c.freeNonPrimitive(elementStr, c.ddpstring)
+		}
+	})
+
+	c.freeNonPrimitive(separator, c.ddpstring)
+	return result
+}
+
+// emits a runtime error at the position of tok
+// if the 1-based index is out of the bounds of str
+func (c *compiler) checkStringIndex(str, index value.Value, tok token.Token) {
+	strLen := c.cbb.NewCall(c.ddpstring.lengthIrFun, str)
+	outOfBounds := c.cbb.NewOr(c.cbb.NewICmp(enum.IPredSLT, index, newInt(1)), c.cbb.NewICmp(enum.IPredSGT, index, strLen))
+	c.createIfElse(outOfBounds, func() {
+		line, column := int64(tok.Range.Start.Line), int64(tok.Range.Start.Column)
+		c.string_out_of_bounds_error(newInt(line), newInt(column), index, strLen)
+	}, nil)
+}
+
+// converts the primitive or string val to the primitive type targetType
+// Text as targetType is not handled here, see toStringFunc
+func (c *compiler) castPrimitive(val value.Value, valTyp ddpIrType, targetType ddptypes.Type) value.Value {
+	switch targetType {
+	case ddptypes.ZAHL:
+		switch valTyp {
+		case c.ddpinttyp:
+			return val
+		case c.ddpfloattyp:
+			return c.cbb.NewFPToSI(val, ddpint)
+		case c.ddpbooltyp:
+			return c.cbb.NewZExt(val, ddpint)
+		case c.ddpchartyp:
+			return c.cbb.NewSExt(val, ddpint)
+		case c.ddpstring:
+			return c.cbb.NewCall(c.functions["ddp_string_to_int"].irFunc, val)
+		default:
+			c.err("invalid Parameter Type for ZAHL: %s", valTyp.Name())
+		}
+	case ddptypes.KOMMAZAHL:
+		switch valTyp {
+		case c.ddpinttyp:
+			return c.cbb.NewSIToFP(val, ddpfloat)
+		case c.ddpfloattyp:
+			return val
+		case c.ddpstring:
+			return c.cbb.NewCall(c.functions["ddp_string_to_float"].irFunc, val)
+		default:
+			c.err("invalid Parameter Type for KOMMAZAHL: %s", valTyp.Name())
+		}
+	case ddptypes.WAHRHEITSWERT:
+		switch valTyp {
+		case c.ddpinttyp:
+			return c.cbb.NewICmp(enum.IPredNE, val, zero)
+		case c.ddpbooltyp:
+			return val
+		default:
+			c.err("invalid Parameter Type for WAHRHEITSWERT: %s", valTyp.Name())
+		}
+	case ddptypes.BUCHSTABE:
+		switch valTyp {
+		case c.ddpinttyp:
+			return c.cbb.NewTrunc(val, ddpchar)
+		case c.ddpchartyp:
+			return val
+		case c.ddpstring:
+			return c.cbb.NewCall(c.functions["ddp_string_to_char"].irFunc, val)
+		default:
+			c.err("invalid Parameter Type for BUCHSTABE: %s", valTyp.Name())
+		}
+	default:
+		c.err("invalid primitive cast target: %s", targetType)
+	}
+	return nil // unreachable
+}
+
+// converts every element of list to the element type of targetType
+// and returns a pointer to the new list which has to be freed by the caller
+func (c *compiler) castListElements(list value.Value, listType, targetType *ddpIrListType, targetElementType ddptypes.Type) value.Value {
+	listLen := c.loadStructField(list, list_len_field_index)
+	result := c.NewAlloca(targetType.typ)
+	c.cbb.NewCall(targetType.fromConstantsIrFun, result, listLen)
+	c.tagAllocation(result, targetType)
+
+	listArr, resultArr := c.loadStructField(list, list_arr_field_index), c.loadStructField(result, list_arr_field_index)
+	c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
+		var element value.Value = c.indexArray(listArr, index)
+		if listType.elementType.IsPrimitive() {
+			element = c.cbb.NewLoad(listType.elementType.IrType(), element)
+		}
+
+		resultElementPtr := c.indexArray(resultArr, index)
+		if targetType.elementType == c.ddpstring {
+			c.cbb.NewCall(c.toStringFunc(listType.elementType), resultElementPtr, element)
+		} else {
+			c.cbb.NewStore(c.castPrimitive(element, listType.elementType, targetElementType), resultElementPtr)
+		}
+	})
+	return result
+}
+
+// decomposes the Text text into a new Buchstaben Liste with one element per Buchstabe
+// the Buchstaben are decoded forward like in a for-range loop over a Text
+// tok is the position reported if text is not valid utf8
+func (c *compiler) textToCharList(text value.Value, tok token.Token) value.Value {
+	length := c.cbb.NewCall(c.ddpstring.lengthIrFun, text)
+	result := c.NewAlloca(c.ddpcharlist.typ)
+	c.cbb.NewCall(c.ddpcharlist.fromConstantsIrFun, result, length)
+	c.tagAllocation(result, c.ddpcharlist)
+
+	iter_ptr := c.NewAlloca(i8ptr)
+	c.cbb.NewStore(c.loadStructField(text, string_str_field_index), iter_ptr)
+	resultArr := c.loadStructField(result, list_arr_field_index)
+	c.createFor(zero, c.forDefaultCond(length), func(index value.Value) {
+		iter := c.cbb.NewLoad(i8ptr, iter_ptr)
+		num_bytes := c.cbb.NewCall(c.utf8_string_to_char_irfun, iter, c.indexArray(resultArr, index))
+		c.createIfElse(c.cbb.NewICmp(enum.IPredEQ, num_bytes, all_ones), func() {
+			line, column := int64(tok.Range.Start.Line), int64(tok.Range.Start.Column)
+			c.runtime_error(1, c.invalid_utf8_error_string, newInt(line), newInt(column))
+		}, func() {})
+		c.cbb.NewStore(c.cbb.NewGetElementPtr(i8, iter, num_bytes), iter_ptr)
+	})
+	return result
+}
+
+// returns the length of expr if expr is a literal whose length is known at compile time
+// literals containing anything that might have side effects (like function calls) are not folded
+func constantLength(expr ast.Expression) (int64, bool) {
+	switch expr := expr.(type) {
+	case *ast.Grouping:
+		return constantLength(expr.Expr)
+	case *ast.StringLit:
+		return int64(utf8.RuneCountInString(expr.Value)), true
+	case *ast.ListLit:
+		switch {
+		case expr.Values != nil:
+			for _, v := range expr.Values {
+				if !isPureLiteral(v) {
+					return 0, false
+				}
+			}
+			return int64(len(expr.Values)), true
+		case expr.Count != nil:
+			count, isInt := expr.Count.(*ast.IntLit)
+			if !isInt || count.Value < 0 || !isPureLiteral(expr.Value) {
+				return 0, false
+			}
+			return count.Value, true
+		default: // empty list
+			return 0, true
+		}
+	}
+	return 0, false
+}
+
+// reports wether expr is a literal which is made up only of other literals
+// and can therefore be skipped without changing the behaviour of the program
+func isPureLiteral(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.IntLit, *ast.FloatLit, *ast.BoolLit, *ast.CharLit, *ast.StringLit:
+		return true
+	case *ast.Grouping:
+		return isPureLiteral(expr.Expr)
+	case *ast.ListLit:
+		for _, v := range expr.Values {
+			if !isPureLiteral(v) {
+				return false
+			}
+		}
+		if expr.Count != nil {
+			// negative counts might fail at runtime
+			if count, isInt := expr.Count.(*ast.IntLit); !isInt || count.Value < 0 {
+				return false
+			}
+		}
+		return expr.Value == nil || isPureLiteral(expr.Value)
+	}
+	return false
+}
+
+// creates a new temporary list containing mapper(element) for every element of list
+// used for the element-wise unary operators on Zahlen and Kommazahlen Listen
+func (c *compiler) mapNumericList(list value.Value, listType *ddpIrListType, mapper func(value.Value, ddpIrType) value.Value) (value.Value, ddpIrType) {
+	listLen := c.loadStructField(list, list_len_field_index)
+	listArr := c.loadStructField(list, list_arr_field_index)
+
+	result := c.NewAlloca(listType.typ)
+	c.cbb.NewCall(listType.fromConstantsIrFun, result, listLen)
+	c.tagAllocation(result, listType)
+	resultArr := c.loadStructField(result, list_arr_field_index)
+
+	c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
+		element := c.loadArrayElement(listArr, index)
+		c.cbb.NewStore(mapper(element, listType.elementType), c.indexArray(resultArr, index))
+	})
+	return c.scp.addTemporary(result, listType)
+}
+
+// returns the argument used to pass the element at index of arr to a function value
+// non-primitive elements are deep copied, because function values free their parameters
+func (c *compiler) elementAsFunctionArg(arr, index value.Value, elementType ddpIrType) value.Value {
+	elementPtr := c.indexArray(arr, index)
+	if elementType.IsPrimitive() {
+		return c.cbb.NewLoad(elementType.IrType(), elementPtr)
+	}
+	arg := c.NewAlloca(elementType.IrType())
+	c.deepCopyInto(arg, elementPtr, elementType)
+	return arg
+}
+
+// creates a new temporary list containing fun(element) for every element of list (ABGEBILDET)
+// every call gets its own copy of the element (see elementAsFunctionArg)
+// and non-primitive return values are written directly into the new list, which then owns them
+func (c *compiler) mapList(list value.Value, listType *ddpIrListType, fun value.Value, funTyp *ddpIrFunctionType) (value.Value, ddpIrType) {
+	resultType := c.toIrType(ddptypes.ListType{Underlying: funTyp.funcType.ReturnType}).(*ddpIrListType)
+	listLen := c.loadStructField(list, list_len_field_index)
+	listArr := c.loadStructField(list, list_arr_field_index)
+
+	result := c.NewAlloca(resultType.typ)
+	c.cbb.NewCall(resultType.fromConstantsIrFun, result, listLen)
+	c.tagAllocation(result, resultType)
+	resultArr := c.loadStructField(result, list_arr_field_index)
+
+	c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
+		arg := c.elementAsFunctionArg(listArr, index, listType.elementType)
+		resultElementPtr := c.indexArray(resultArr, index)
+		if resultType.elementType.IsPrimitive() {
+			c.cbb.NewStore(c.cbb.NewCall(fun, arg), resultElementPtr)
+		} else {
+			c.cbb.NewCall(fun, resultElementPtr, arg)
+		}
+	})
+	return c.scp.addTemporary(result, resultType)
+}
+
+// creates a new temporary list containing every element of list for which fun returns wahr (GEFILTERT)
+// every call gets its own copy of the element (see elementAsFunctionArg)
+// and the elements that are kept are copied into the new list
+func (c *compiler) filterList(list value.Value, listType *ddpIrListType, fun value.Value) (value.Value, ddpIrType) {
+	listLen := c.loadStructField(list, list_len_field_index)
+	listArr := c.loadStructField(list, list_arr_field_index)
+
+	// the result is allocated with the full length and shrunk afterwards
+	result := c.NewAlloca(listType.typ)
+	c.cbb.NewCall(listType.fromConstantsIrFun, result, listLen)
+	c.tagAllocation(result, listType)
+	resultArr := c.loadStructField(result, list_arr_field_index)
+	resultLen := c.NewAlloca(ddpint)
+	c.cbb.NewStore(zero, resultLen)
+
+	c.createFor(zero, c.forDefaultCond(listLen), func(index value.Value) {
+		keep := c.cbb.NewCall(fun, c.elementAsFunctionArg(listArr, index, listType.elementType))
+		c.createIfElse(keep, func() {
+			count := c.cbb.NewLoad(ddpint, resultLen)
+			elementPtr, dest := c.indexArray(listArr, index), c.indexArray(resultArr, count)
+			if listType.elementType.IsPrimitive() {
+				c.cbb.NewStore(c.cbb.NewLoad(listType.elementType.IrType(), elementPtr), dest)
+			} else {
+				c.deepCopyInto(dest, elementPtr, listType.elementType)
+			}
+			c.cbb.NewStore(c.cbb.NewAdd(count, newInt(1)), resultLen)
+		}, nil)
+	})
+	c.cbb.NewStore(c.cbb.NewLoad(ddpint, resultLen), c.indexStruct(result, list_len_field_index))
+	return c.scp.addTemporary(result, listType)
+}
+
 func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 	c.commentNode(c.cbb, e, e.Operator.String())
 
@@ -1108,8 +1600,30 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 				c.err("invalid Parameter Types for MAL (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 			}
 			c.latestReturnType = c.ddpfloattyp
+		case c.ddpstring, c.ddpchartyp:
+			if rhsTyp != c.ddpinttyp {
+				c.err("invalid Parameter Types for MAL (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			repeat_func := c.ddpstring.str_repeat_IrFunc
+			if lhsTyp == c.ddpchartyp {
+				repeat_func = c.ddpstring.char_repeat_IrFunc
+			}
+			// the text is only read, so lhs is freed like every other temporary
+			result := c.NewAlloca(c.ddpstring.IrType())
+			c.cbb.NewCall(repeat_func, result, lhs, rhs)
+			c.latestReturn, c.latestReturnType = c.scp.addTemporary(result, c.ddpstring)
+			c.latestIsTemp = true
 		default:
-			c.err("invalid Parameter Types for MAL (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			listTyp, isList := lhsTyp.(*ddpIrListType)
+			if !isList || rhsTyp != c.ddpinttyp {
+				c.err("invalid Parameter Types for MAL (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			// the list is only read, so lhs is freed like every other temporary
+			result := c.NewAlloca(listTyp.IrType())
+			line, column := int64(e.Token().Range.Start.Line), int64(e.Token().Range.Start.Column)
+			c.cbb.NewCall(listTyp.repeatIrFun, result, lhs, rhs, newInt(line), newInt(column))
+			c.latestReturn, c.latestReturnType = c.scp.addTemporary(result, listTyp)
+			c.latestIsTemp = true
 		}
 	case ast.BIN_DIV:
 		switch lhsTyp {
@@ -1142,12 +1656,13 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 	case ast.BIN_INDEX:
 		switch lhsTyp {
 		case c.ddpstring:
+			c.checkStringIndex(lhs, rhs, e.Token())
 			c.latestReturn = c.cbb.NewCall(c.ddpstring.indexIrFun, lhs, rhs)
 			c.latestReturnType = c.ddpchartyp
 		default:
 			if listType, isList := lhsTyp.(*ddpIrListType); isList {
 				listLen := c.loadStructField(lhs, list_len_field_index)
-				index := c.cbb.NewSub(rhs, newInt(1)) // ddp indices start at 1, so subtract 1
+				index := c.toZeroBasedIndex(rhs)
 				// index bounds check
 				cond := c.cbb.NewAnd(c.cbb.NewICmp(enum.IPredSLT, index, listLen), c.cbb.NewICmp(enum.IPredSGE, index, zero))
 				c.createIfElse(cond, func() {
@@ -1271,11 +1786,17 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 	case ast.BIN_RIGHT_SHIFT:
 		c.latestReturn = c.cbb.NewLShr(lhs, rhs)
 		c.latestReturnType = c.ddpinttyp
+	case ast.BIN_MAP:
+		c.latestReturn, c.latestReturnType = c.mapList(lhs, lhsTyp.(*ddpIrListType), rhs, rhsTyp.(*ddpIrFunctionType))
+		c.latestIsTemp = true
+	case ast.BIN_FILTER:
+		c.latestReturn, c.latestReturnType = c.filterList(lhs, lhsTyp.(*ddpIrListType), rhs)
+		c.latestIsTemp = true
 	case ast.BIN_EQUAL:
 		c.compare_values(lhs, rhs, lhsTyp)
 	case ast.BIN_UNEQUAL:
 		equal := c.compare_values(lhs, rhs, lhsTyp)
-		c.latestReturn = c.cbb.NewXor(equal, newInt(1))
+		c.latestReturn = c.cbb.NewXor(equal, constant.True)
 	case ast.BIN_LESS:
 		switch lhsTyp {
 		case c.ddpinttyp:
@@ -1298,6 +1819,13 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 			default:
 				c.err("invalid Parameter Types for KLEINER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 			}
+		case c.ddpstring, c.ddpchartyp:
+			if rhsTyp != lhsTyp {
+				c.err("invalid Parameter Types for KLEINER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			c.latestReturn = c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSLT)
+		default:
+			c.err("invalid Parameter Types for KLEINER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 		}
 		c.latestReturnType = c.ddpbooltyp
 	case ast.BIN_LESS_EQ:
@@ -1322,6 +1850,11 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 			default:
 				c.err("invalid Parameter Types for KLEINERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 			}
+		case c.ddpstring, c.ddpchartyp:
+			if rhsTyp != lhsTyp {
+				c.err("invalid Parameter Types for KLEINERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			c.latestReturn = c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSLE)
 		default:
 			c.err("invalid Parameter Types for KLEINERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 		}
@@ -1348,6 +1881,11 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 			default:
 				c.err("invalid Parameter Types for GRÖßER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 			}
+		case c.ddpstring, c.ddpchartyp:
+			if rhsTyp != lhsTyp {
+				c.err("invalid Parameter Types for GRÖßER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			c.latestReturn = c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSGT)
 		default:
 			c.err("invalid Parameter Types for GRÖßER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 		}
@@ -1374,6 +1912,11 @@ func (c *compiler) VisitBinaryExpr(e *ast.BinaryExpr) ast.VisitResult {
 			default:
 				c.err("invalid Parameter Types for GRÖßERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 			}
+		case c.ddpstring, c.ddpchartyp:
+			if rhsTyp != lhsTyp {
+				c.err("invalid Parameter Types for GRÖßERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
+			}
+			c.latestReturn = c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSGE)
 		default:
 			c.err("invalid Parameter Types for GRÖßERODER (%s, %s)", lhsTyp.Name(), rhsTyp.Name())
 		}
@@ -1479,6 +2022,16 @@ func (c *compiler) VisitTernaryExpr(e *ast.TernaryExpr) ast.VisitResult {
 		}
 		c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, lhsTyp)
 		c.latestIsTemp = true
+	case ast.TER_PAD:
+		listTyp, isList := lhsTyp.(*ddpIrListType)
+		if !isList || midTyp != c.ddpinttyp || rhsTyp != listTyp.elementType {
+			c.err("invalid Parameter Types for AUFGEFÜLLT (%s, %s, %s)", lhsTyp.Name(), midTyp.Name(), rhsTyp.Name())
+		}
+		// lhs and rhs are only read (and copied), so they are freed like every other temporary
+		dest := c.NewAlloca(listTyp.IrType())
+		c.cbb.NewCall(listTyp.padIrFun, dest, lhs, mid, rhs)
+		c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, listTyp)
+		c.latestIsTemp = true
 	case ast.TER_BETWEEN:
 		switch lhsTyp {
 		case c.ddpinttyp:
@@ -1561,6 +2114,14 @@ func (c *compiler) VisitTernaryExpr(e *ast.TernaryExpr) ast.VisitResult {
 			default:
 				c.err("invalid Parameter Types for ZWISCHEN (%s, %s, %s)", lhsTyp.Name(), midTyp.Name(), rhsTyp.Name())
 			}
+		case c.ddpstring, c.ddpchartyp:
+			if midTyp != lhsTyp || rhsTyp != lhsTyp {
+				c.err("invalid Parameter Types for ZWISCHEN (%s, %s, %s)", lhsTyp.Name(), midTyp.Name(), rhsTyp.Name())
+			}
+			c.latestReturn = c.cbb.NewOr(
+				c.cbb.NewAnd(c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSGT), c.compareOrdered(lhs, mid, lhsTyp, enum.IPredSLT)),
+				c.cbb.NewAnd(c.compareOrdered(lhs, mid, lhsTyp, enum.IPredSGT), c.compareOrdered(lhs, rhs, lhsTyp, enum.IPredSLT)),
+			)
 		default:
 			c.err("invalid Parameter Types for ZWISCHEN (%s, %s, %s)", lhsTyp.Name(), midTyp.Name(), rhsTyp.Name())
 		}
@@ -1585,6 +2146,40 @@ func (c *compiler) VisitCastExpr(e *ast.CastExpr) ast.VisitResult {
 	targetType := ddptypes.TrueUnderlying(e.TargetType)
 	lhs, lhsTyp, isTempLhs := c.evaluate(e.Lhs)
 
+	// values are wrapped in their optional type
+	if optionalTyp, isOptional := c.toIrType(targetType).(*ddpIrOptionalType); isOptional {
+		if lhsTyp != optionalTyp {
+			lhs = c.wrapOptional(lhs, optionalTyp)
+		}
+		c.latestReturn, c.latestReturnType, c.latestIsTemp = lhs, optionalTyp, false
+		return ast.VisitRecurse
+	}
+	// optionals are unwrapped or converted to Text
+	if optionalTyp, isOptional := lhsTyp.(*ddpIrOptionalType); isOptional {
+		c.castOptional(lhs, optionalTyp, targetType, e.Token())
+		return ast.VisitRecurse
+	}
+
+	// enums are converted to the position of the variant or its name
+	if enumTyp, isEnum := lhsTyp.(*ddpIrEnumType); isEnum {
+		switch targetType {
+		case ddptypes.ZAHL:
+			c.latestReturn, c.latestReturnType, c.latestIsTemp = c.cbb.NewAdd(lhs, newInt(1)), c.ddpinttyp, false
+		case ddptypes.TEXT:
+			c.latestReturn, c.latestReturnType = c.scp.addTemporary(c.enumToString(lhs, enumTyp), c.ddpstring)
+			c.latestIsTemp = true
+		default:
+			c.latestReturn, c.latestReturnType, c.latestIsTemp = lhs, enumTyp, false
+		}
+		return ast.VisitRecurse
+	}
+
+	// function values can only be casted to their own type
+	if funcTyp, isFunc := lhsTyp.(*ddpIrFunctionType); isFunc {
+		c.latestReturn, c.latestReturnType, c.latestIsTemp = lhs, funcTyp, false
+		return ast.VisitRecurse
+	}
+
 	vtable := c.toIrType(targetType).VTable()
 	if typeDef, isTypeDef := ddptypes.CastTypeDef(e.TargetType); isTypeDef {
 		vtable = c.typeDefVTables[c.mangledNameType(typeDef)]
@@ -1623,9 +2218,35 @@ func (c *compiler) VisitCastExpr(e *ast.CastExpr) ast.VisitResult {
 			return ast.VisitRecurse
 		}
 
+		targetListType := c.toIrType(targetType).(*ddpIrListType)
+		if lhsListType, isList := lhsTyp.(*ddpIrListType); isList && targetListType.elementType != lhsTyp {
+			// lists are converted element-wise
+			if lhsListType == targetListType {
+				c.latestReturn, c.latestReturnType, c.latestIsTemp = lhs, lhsTyp, isTempLhs
+				return ast.VisitRecurse // don't free lhs
+			}
+
+			c.latestReturn, c.latestReturnType = c.scp.addTemporary(c.castListElements(lhs, lhsListType, targetListType, ddptypes.TrueUnderlying(ddptypes.GetListUnderlying(targetType))), targetListType)
+			c.latestIsTemp = true
+			return ast.VisitRecurse
+		}
+
+		// Texte are decomposed into their Buchstaben
+		if lhsTyp == c.ddpstring && targetListType == c.ddpcharlist {
+			c.latestReturn, c.latestReturnType = c.scp.addTemporary(c.textToCharList(lhs, e.Token()), c.ddpcharlist)
+			c.latestIsTemp = true
+			return ast.VisitRecurse
+		}
+
+		// everything else is wrapped in a list with a single element
+		// which is only valid for the element type of the list, the typechecker should have caught everything else
+		if targetListType.elementType != lhsTyp {
+			c.err("invalid cast from %s to %s", lhsTyp.Name(), targetListType.Name())
+		}
 		listType := c.getListType(lhsTyp)
 		list := c.NewAlloca(listType.typ)
 		c.cbb.NewCall(listType.fromConstantsIrFun, list, newInt(1))
+		c.tagAllocation(list, listType)
 		elementPtr := c.indexArray(c.loadStructField(list, list_arr_field_index), zero)
 		c.claimOrCopy(elementPtr, lhs, lhsTyp, isTempLhs)
 		c.latestReturn, c.latestReturnType = c.scp.addTemporary(list, listType)
@@ -1643,58 +2264,11 @@ func (c *compiler) VisitCastExpr(e *ast.CastExpr) ast.VisitResult {
 		}
 
 		switch targetType {
-		case ddptypes.ZAHL:
-			switch lhsTyp {
-			case c.ddpinttyp:
-				c.latestReturn = lhs
-			case c.ddpfloattyp:
-				c.latestReturn = c.cbb.NewFPToSI(lhs, ddpint)
-			case c.ddpbooltyp:
-				cond := c.cbb.NewICmp(enum.IPredNE, lhs, zero)
-				c.latestReturn = c.cbb.NewZExt(cond, ddpint)
-			case c.ddpchartyp:
-				c.latestReturn = c.cbb.NewSExt(lhs, ddpint)
-			case c.ddpstring:
-				c.latestReturn = c.cbb.NewCall(c.functions["ddp_string_to_int"].irFunc, lhs)
-			case c.ddpany:
-				primitiveAnyCast(c.ddpinttyp)
-			default:
-				c.err("invalid Parameter Type for ZAHL: %s", lhsTyp.Name())
-			}
-		case ddptypes.KOMMAZAHL:
-			switch lhsTyp {
-			case c.ddpinttyp:
-				c.latestReturn = c.cbb.NewSIToFP(lhs, ddpfloat)
-			case c.ddpfloattyp:
-				c.latestReturn = lhs
-			case c.ddpstring:
-				c.latestReturn = c.cbb.NewCall(c.functions["ddp_string_to_float"].irFunc, lhs)
-			case c.ddpany:
-				primitiveAnyCast(c.ddpfloattyp)
-			default:
-				c.err("invalid Parameter Type for KOMMAZAHL: %s", lhsTyp.Name())
-			}
-		case ddptypes.WAHRHEITSWERT:
-			switch lhsTyp {
-			case c.ddpinttyp:
-				c.latestReturn = c.cbb.NewICmp(enum.IPredNE, lhs, zero)
-			case c.ddpbooltyp:
-				c.latestReturn = lhs
-			case c.ddpany:
-				primitiveAnyCast(c.ddpbooltyp)
-			default:
-				c.err("invalid Parameter Type for WAHRHEITSWERT: %s", lhsTyp.Name())
-			}
-		case ddptypes.BUCHSTABE:
-			switch lhsTyp {
-			case c.ddpinttyp:
-				c.latestReturn = c.cbb.NewTrunc(lhs, ddpchar)
-			case c.ddpchartyp:
-				c.latestReturn = lhs
-			case c.ddpany:
-				primitiveAnyCast(c.ddpchartyp)
-			default:
-				c.err("invalid Parameter Type for BUCHSTABE: %s", lhsTyp.Name())
+		case ddptypes.ZAHL, ddptypes.KOMMAZAHL, ddptypes.WAHRHEITSWERT, ddptypes.BUCHSTABE:
+			if lhsTyp == c.ddpany {
+				primitiveAnyCast(c.toIrType(targetType))
+			} else {
+				c.latestReturn = c.castPrimitive(lhs, lhsTyp, targetType)
 			}
 		case ddptypes.TEXT:
 			if lhsTyp == c.ddpany {
@@ -1709,21 +2283,14 @@ func (c *compiler) VisitCastExpr(e *ast.CastExpr) ast.VisitResult {
 				return ast.VisitRecurse // don't free lhs
 			}
 
-			var to_string_func *ir.Func
-			switch lhsTyp {
-			case c.ddpinttyp:
-				to_string_func = c.ddpstring.int_to_string_IrFun
-			case c.ddpfloattyp:
-				to_string_func = c.ddpstring.float_to_string_IrFun
-			case c.ddpbooltyp:
-				to_string_func = c.ddpstring.bool_to_string_IrFun
-			case c.ddpchartyp:
-				to_string_func = c.ddpstring.char_to_string_IrFun
-			default:
-				c.err("invalid Parameter Type for TEXT: %s", lhsTyp.Name())
+			if listType, isList := lhsTyp.(*ddpIrListType); isList {
+				c.latestReturn, c.latestReturnType = c.scp.addTemporary(c.listToString(lhs, listType), c.ddpstring)
+				c.latestIsTemp = true
+				return ast.VisitRecurse
 			}
+
 			dest := c.NewAlloca(c.ddpstring.typ)
-			c.cbb.NewCall(to_string_func, dest, lhs)
+			c.cbb.NewCall(c.toStringFunc(lhsTyp), dest, lhs)
 			c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, c.ddpstring)
 			c.latestIsTemp = true
 		case ddptypes.VARIABLE:
@@ -1745,9 +2312,36 @@ func (c *compiler) VisitCastExpr(e *ast.CastExpr) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+// converts the optional val to targetType
+// which is either Text or the underlying type of the optional
+// empty optionals become "nichts" as Text and are a runtime error otherwise
+func (c *compiler) castOptional(val value.Value, valTyp *ddpIrOptionalType, targetType ddptypes.Type, tok token.Token) {
+	present, underlying := c.isOptionalPresent(val), c.optionalValue(val)
+	if targetType != ddptypes.TEXT {
+		c.createIfElse(c.cbb.NewXor(present, constant.True), func() {
+			line, column := int64(tok.Range.Start.Line), int64(tok.Range.Start.Column)
+			c.runtime_error(1, c.nothing_cast_error_string, newInt(line), newInt(column))
+		}, nil)
+		c.latestReturn, c.latestReturnType, c.latestIsTemp = underlying, valTyp.underlying, false
+		return
+	}
+
+	dest := c.NewAlloca(c.ddpstring.typ)
+	c.createIfElse(present, func() {
+		c.cbb.NewCall(c.toStringFunc(valTyp.underlying), dest, underlying)
+	}, func() {
+		nothing := c.constantString("nichts")
+		c.cbb.NewCall(c.ddpstring.fromConstantsIrFun, dest, c.cbb.NewBitCast(nothing, i8ptr))
+		c.tagAllocation(dest, c.ddpstring)
+	})
+	c.latestReturn, c.latestReturnType = c.scp.addTemporary(dest, c.ddpstring)
+	c.latestIsTemp = true
+}
+
 func (c *compiler) VisitTypeOpExpr(e *ast.TypeOpExpr) ast.VisitResult {
 	switch e.Operator {
 	case ast.TYPE_SIZE:
+		// like sizeof in C the size of a struct includes the padding between its fields
 		c.latestReturn = c.sizeof(c.toIrType(e.Rhs).IrType())
 		c.latestReturnType = c.ddpinttyp
 	case ast.TYPE_DEFAULT:
@@ -1802,7 +2396,7 @@ func (c *compiler) evaluateAssignableOrReference(ass ast.Assigneable, as_ref boo
 		lhs, lhsTyp, _ := c.evaluateAssignableOrReference(assign.Lhs, as_ref) // get the (possibly nested) assignable
 		if listTyp, isList := lhsTyp.(*ddpIrListType); isList {
 			index, _, _ := c.evaluate(assign.Index)
-			index = c.cbb.NewSub(index, newInt(1)) // ddpindices start at 1
+			index = c.toZeroBasedIndex(index)
 			listLen := c.loadStructField(lhs, list_len_field_index)
 			var elementPtr value.Value
 
@@ -1815,7 +2409,11 @@ func (c *compiler) evaluateAssignableOrReference(ass ast.Assigneable, as_ref boo
 				c.out_of_bounds_error(newInt(line), newInt(column), c.cbb.NewAdd(index, newInt(1)), listLen)
 			})
 			return elementPtr, listTyp.elementType, nil
-		} else if !as_ref && lhsTyp == c.ddpstring {
+		} else if lhsTyp == c.ddpstring {
+			if as_ref {
+				// reaching this is a bug in the typechecker (see Typechecker.isCharInText)
+				c.err("a Buchstabe in a Text was passed as reference")
+			}
 			return lhs, lhsTyp, assign
 		} else {
 			c.err("non-list/string/struct type passed as assignable/reference")
@@ -1857,7 +2455,20 @@ func (c *compiler) VisitFuncCall(e *ast.FuncCall) ast.VisitResult {
 		args = append(args, ret)
 	}
 
-	for _, param := range fun.funcDecl.Parameters {
+	// the arguments are evaluated in the order in which they appear in the source code
+	// (not in the order of the parameters), so that their side effects happen in that order
+	// generated calls (e.g. for overloaded operators) have no ArgOrder and use the parameter order
+	params := fun.funcDecl.Parameters
+	if len(e.ArgOrder) == len(e.Args) {
+		params = make([]ast.ParameterInfo, 0, len(e.ArgOrder))
+		for _, name := range e.ArgOrder {
+			i := slices.IndexFunc(fun.funcDecl.Parameters, func(param ast.ParameterInfo) bool { return param.Name.Literal == name })
+			params = append(params, fun.funcDecl.Parameters[i])
+		}
+	}
+
+	argValues := make(map[string]value.Value, len(params))
+	for _, param := range params {
 		var val value.Value
 
 		// differentiate between references and normal parameters
@@ -1879,7 +2490,11 @@ func (c *compiler) VisitFuncCall(e *ast.FuncCall) ast.VisitResult {
 			}
 		}
 
-		args = append(args, val) // add the value to the arguments
+		argValues[param.Name.Literal] = val
+	}
+	// the arguments are passed in the order of the parameters
+	for _, param := range fun.funcDecl.Parameters {
+		args = append(args, argValues[param.Name.Literal])
 	}
 
 	c.commentNode(c.cbb, e, "")
@@ -1909,6 +2524,50 @@ func (c *compiler) VisitFuncCall(e *ast.FuncCall) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (c *compiler) VisitFuncRef(e *ast.FuncRef) ast.VisitResult {
+	c.latestReturn = c.getFunctionValue(e.Func)
+	c.latestReturnType = c.toIrType(ddptypes.NewFunctionType(mapSlice(e.Func.Parameters, func(param ast.ParameterInfo) ddptypes.Type {
+		return param.Type.Type
+	}), e.Func.ReturnType))
+	c.latestIsTemp = false
+	return ast.VisitRecurse
+}
+
+func (c *compiler) VisitFuncValueCall(e *ast.FuncValueCall) ast.VisitResult {
+	callee, calleeTyp, _ := c.evaluate(e.Callee)
+	funcTyp := calleeTyp.(*ddpIrFunctionType)
+	args := make([]value.Value, 0, len(e.Args)+1)
+
+	irReturnType := c.toIrType(funcTyp.funcType.ReturnType)
+	var ret value.Value
+	if !irReturnType.IsPrimitive() {
+		ret = c.NewAlloca(irReturnType.IrType())
+		args = append(args, ret)
+	}
+
+	for _, arg := range e.Args {
+		val, valTyp, isTemp := c.evaluate(arg)
+		if !valTyp.IsPrimitive() { // function values free their parameters, so we pass a copy
+			dest := c.NewAlloca(valTyp.IrType())
+			c.claimOrCopy(dest, val, valTyp, isTemp)
+			val = dest
+		}
+		args = append(args, val)
+	}
+
+	c.commentNode(c.cbb, e, "")
+	if irReturnType.IsPrimitive() {
+		c.latestReturn = c.cbb.NewCall(callee, args...)
+		c.latestIsTemp = false
+	} else {
+		c.cbb.NewCall(callee, args...)
+		c.latestReturn, _ = c.scp.addTemporary(ret, irReturnType)
+		c.latestIsTemp = true
+	}
+	c.latestReturnType = irReturnType
+	return ast.VisitRecurse
+}
+
 func (c *compiler) evaluateStructLiteral(structType *ddptypes.StructType, args map[string]ast.Expression) (value.Value, ddpIrType) {
 	structDecl := c.ddpModule.Ast.Symbols.Declarations[structType.Name].(*ast.StructDecl)
 	resultType := c.toIrType(structType)
@@ -1944,8 +2603,16 @@ func (c *compiler) VisitDeclStmt(s *ast.DeclStmt) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+// the value of the expression is discarded, so the temporaries created while
+// evaluating it (e.g. a string literal) are freed right away instead of at the end of the scope
+// otherwise discarded values in the global scope would only be freed when the program exits
 func (c *compiler) VisitExprStmt(s *ast.ExprStmt) ast.VisitResult {
+	current_temporaries_end := len(c.scp.temporaries)
 	c.visitNode(s.Expr)
+	for _, v := range c.scp.temporaries[current_temporaries_end:] {
+		c.freeNonPrimitive(v.val, v.typ)
+	}
+	c.scp.temporaries = c.scp.temporaries[:current_temporaries_end]
 	return ast.VisitRecurse
 }
 
@@ -2018,6 +2685,8 @@ func (c *compiler) VisitImportStmt(s *ast.ImportStmt) ast.VisitResult {
 			c.addTypdefVTable(decl, true)
 		case *ast.StructDecl:
 			c.defineOrDeclareStructType(decl.Type)
+		case *ast.EnumDecl:
+			// enum types are created on first use
 		case *ast.BadDecl:
 			c.err("BadDecl in import")
 		default:
@@ -2034,8 +2703,9 @@ func (c *compiler) VisitImportStmt(s *ast.ImportStmt) ast.VisitResult {
 			module_init.Linkage = enum.LinkageExternal
 			module_init.Visibility = enum.VisibilityDefault
 			c.insertFunction(init_name, nil, module_init)
-			if c.cf != nil && c.cbb != nil { // ddp_main
-				c.cbb.NewCall(module_init) // only call this in main modules
+			// only call this in main modules and only once per session
+			if c.cf != nil && c.cbb != nil && !c.session.isCompiled(module) { // ddp_main
+				c.cbb.NewCall(module_init)
 			}
 
 			module_dispose := c.mod.NewFunc(dispose_name, c.void.IrType())
@@ -2054,10 +2724,21 @@ func (c *compiler) VisitAssignStmt(s *ast.AssignStmt) ast.VisitResult {
 
 	lhs, lhsTyp, lhsStringIndexing := c.evaluateAssignableOrReference(s.Var, false)
 
+	// Texte have value semantics: every variable, list element and by-value parameter
+	// owns its own copy, so replacing the char in place only affects the assigned Text
+	// (or the variable behind a reference parameter)
 	if lhsStringIndexing != nil {
 		index, _, _ := c.evaluate(lhsStringIndexing.Index)
+		c.checkStringIndex(lhs, index, lhsStringIndexing.Token())
 		c.cbb.NewCall(c.ddpstring.replaceCharIrFun, lhs, rhs, index)
 	} else {
+		// rhs might reference the old value (e.g. Speichere x in x) so it is copied before that is freed
+		if !isTempRhs && !rhsTyp.IsPrimitive() {
+			dest := c.NewAlloca(rhsTyp.IrType())
+			c.deepCopyInto(dest, rhs, rhsTyp)
+			rhs, rhsTyp = c.scp.addTemporary(dest, rhsTyp)
+			isTempRhs = true
+		}
 		c.freeNonPrimitive(lhs, lhsTyp) // free the old value in the variable/list
 
 		// implicit cast to any if required
@@ -2075,10 +2756,19 @@ func (c *compiler) VisitAssignStmt(s *ast.AssignStmt) ast.VisitResult {
 }
 
 func (c *compiler) VisitBlockStmt(s *ast.BlockStmt) ast.VisitResult {
+	c.compileBlock(s)
+	return ast.VisitRecurse
+}
+
+// compiles the statements of s in a new scope
+// used for block statements and function bodies
+// returns wether s ended with a return statement
+// in which case the local variables were already freed by the return
+func (c *compiler) compileBlock(s *ast.BlockStmt) (wasReturn bool) {
 	c.scp = newScope(c.scp) // a block gets its own scope
-	wasReturn := false
 	for _, stmt := range s.Statements {
 		c.visitNode(stmt)
+		// on return statements, ignore anything that follows
 		if _, ok := stmt.(*ast.ReturnStmt); ok {
 			wasReturn = true
 			break
@@ -2089,42 +2779,62 @@ func (c *compiler) VisitBlockStmt(s *ast.BlockStmt) ast.VisitResult {
 	} else {
 		c.scp = c.exitScope(c.scp) // free local variables and return to the previous scope
 	}
-	return ast.VisitRecurse
+	return wasReturn
 }
 
 // for info on how the generated ir works you might want to see https://llir.github.io/document/user-guide/control/#If
+// a chain of wenn aber branches (which the parser nests as else-ifs)
+// is compiled as a flat sequence of conditions that share the same leaveBlock
 func (c *compiler) VisitIfStmt(s *ast.IfStmt) ast.VisitResult {
-	cond, _, _ := c.evaluate(s.Condition)
-	thenBlock, elseBlock, leaveBlock := c.cf.NewBlock(""), c.cf.NewBlock(""), c.cf.NewBlock("")
-	c.commentNode(c.cbb, s, "")
-	if s.Else != nil {
-		c.cbb.NewCondBr(cond, thenBlock, elseBlock)
-	} else {
-		c.cbb.NewCondBr(cond, thenBlock, leaveBlock)
-	}
+	leaveBlock := c.newBlock("if.end")
+	for {
+		// the condition gets its own scope, because it is only evaluated
+		// if all previous conditions of the chain were false
+		c.scp = newScope(c.scp)
+		cond, _, _ := c.evaluate(s.Condition)
+		c.scp = c.exitScope(c.scp)
 
-	c.cbb, c.scp = thenBlock, newScope(c.scp)
-	c.visitNode(s.Then)
-	if c.cbb.Term == nil {
+		thenBlock := c.newBlock("if.then")
+		elseIf, isElseIf := s.Else.(*ast.IfStmt)
+		// the else block is only needed if there is an else branch
+		elseBlock := leaveBlock
+		if isElseIf {
+			elseBlock = c.newBlock("if.elif")
+		} else if s.Else != nil {
+			elseBlock = c.newBlock("if.else")
+		}
 		c.commentNode(c.cbb, s, "")
-		c.cbb.NewBr(leaveBlock)
-	}
-	c.scp = c.exitScope(c.scp)
+		c.cbb.NewCondBr(cond, thenBlock, elseBlock)
 
-	if s.Else != nil {
-		c.cbb, c.scp = elseBlock, newScope(c.scp)
-		c.visitNode(s.Else)
-		if c.cbb.Term == nil {
-			c.commentNode(c.cbb, s, "")
-			c.cbb.NewBr(leaveBlock)
+		c.cbb = thenBlock
+		c.compileIfBranch(s, s.Then, leaveBlock)
+
+		c.cbb = elseBlock
+		if isElseIf {
+			s = elseIf
+			continue
 		}
-		c.scp = c.exitScope(c.scp)
-	} else {
-		elseBlock.NewUnreachable()
+		if s.Else != nil {
+			c.compileIfBranch(s, s.Else, leaveBlock)
+			c.cbb = leaveBlock
+		}
+		return ast.VisitRecurse
 	}
+}
 
-	c.cbb = leaveBlock
-	return ast.VisitRecurse
+// compiles a then or else branch of s in its own scope
+// and jumps to leaveBlock if the branch did not return or jump somewhere else
+func (c *compiler) compileIfBranch(s *ast.IfStmt, branch ast.Statement, leaveBlock *ir.Block) {
+	c.scp = newScope(c.scp)
+	c.visitNode(branch)
+	if c.cbb.Term != nil {
+		// the return already freed everything in this scope
+		c.scp = c.scp.enclosing
+		return
+	}
+	c.scp = c.exitScope(c.scp)
+	c.commentNode(c.cbb, s, "")
+	c.cbb.NewBr(leaveBlock)
 }
 
 // for info on how the generated ir works you might want to see https://llir.github.io/document/user-guide/control/#Loop
@@ -2132,8 +2842,8 @@ func (c *compiler) VisitWhileStmt(s *ast.WhileStmt) ast.VisitResult {
 	loopScopeBack, leaveBlockBack, continueBlockBack := c.curLoopScope, c.curLeaveBlock, c.curContinueBlock
 	switch op := s.While.Type; op {
 	case token.SOLANGE, token.MACHE:
-		condBlock, body, bodyScope := c.cf.NewBlock(""), c.cf.NewBlock(""), newScope(c.scp)
-		breakLeave := c.cf.NewBlock("")
+		condBlock, body, bodyScope := c.newBlock("while.cond"), c.newBlock("while.body"), newScope(c.scp)
+		breakLeave := c.newBlock("while.break")
 		c.curLoopScope, c.curLeaveBlock, c.curContinueBlock = bodyScope, breakLeave, condBlock
 
 		c.commentNode(c.cbb, s, "")
@@ -2150,12 +2860,15 @@ func (c *compiler) VisitWhileStmt(s *ast.WhileStmt) ast.VisitResult {
 		}
 
 		c.cbb, c.scp = condBlock, c.exitScope(c.scp) // the condition is not in scope
+		// the condition gets its own scope so that its temporaries are freed on every iteration
+		c.scp = newScope(c.scp)
 		cond, _, _ := c.evaluate(s.Condition)
-		leaveBlock := c.cf.NewBlock("")
+		c.scp = c.exitScope(c.scp)
+		leaveBlock := c.newBlock("while.leave")
 		c.commentNode(c.cbb, s, "")
 		c.cbb.NewCondBr(cond, body, leaveBlock)
 
-		trueLeave := c.cf.NewBlock("")
+		trueLeave := c.newBlock("while.end")
 		leaveBlock.NewBr(trueLeave)
 		breakLeave.NewBr(trueLeave)
 		c.cbb = trueLeave
@@ -2163,8 +2876,8 @@ func (c *compiler) VisitWhileStmt(s *ast.WhileStmt) ast.VisitResult {
 		counter := c.NewAlloca(ddpint)
 		cond, _, _ := c.evaluate(s.Condition)
 		c.cbb.NewStore(cond, counter)
-		condBlock, body, bodyScope := c.cf.NewBlock(""), c.cf.NewBlock(""), newScope(c.scp)
-		breakLeave := c.cf.NewBlock("")
+		condBlock, body, bodyScope := c.newBlock("repeat.cond"), c.newBlock("repeat.body"), newScope(c.scp)
+		breakLeave := c.newBlock("repeat.break")
 		c.curLoopScope, c.curLeaveBlock, c.curContinueBlock = bodyScope, breakLeave, condBlock
 
 		c.commentNode(c.cbb, s, "")
@@ -2178,16 +2891,16 @@ func (c *compiler) VisitWhileStmt(s *ast.WhileStmt) ast.VisitResult {
 			c.cbb.NewBr(condBlock)
 		}
 
-		leaveBlock := c.cf.NewBlock("")
+		leaveBlock := c.newBlock("repeat.leave")
 		c.cbb, c.scp = condBlock, c.exitScope(c.scp) // the condition is not in scope
 		c.commentNode(c.cbb, s, "")
-		c.cbb.NewCondBr( // while counter != 0, execute body
-			c.cbb.NewICmp(enum.IPredNE, c.cbb.NewLoad(ddpint, counter), zero),
+		c.cbb.NewCondBr( // while counter > 0, execute body
+			c.cbb.NewICmp(enum.IPredSGT, c.cbb.NewLoad(ddpint, counter), zero),
 			body,
 			leaveBlock,
 		)
 
-		trueLeave := c.cf.NewBlock("")
+		trueLeave := c.newBlock("repeat.end")
 		leaveBlock.NewBr(trueLeave)
 		breakLeave.NewBr(trueLeave)
 		c.cbb = trueLeave
@@ -2218,15 +2931,24 @@ func (c *compiler) VisitForStmt(s *ast.ForStmt) ast.VisitResult {
 		} else {
 			incrementer = constant.NewFloat(ddpfloat, 1.0)
 		}
-	} else { // stepsize was present, so compile it
+	} else { // stepsize was present, so compile it once before the loop
 		incrementer, _, _ = c.evaluate(s.StepSize)
+		// a stepsize of 0 would loop forever, so we report an error at runtime
+		// constant stepsizes that are not 0 don't need the check
+		if isZero, isConst := isZeroConstant(incrementer); isZero || !isConst {
+			isZeroStep := new_IorF_comp(enum.IPredEQ, enum.FPredOEQ, incrementer, newInt(0), constant.NewFloat(ddpfloat, 0.0))
+			c.createIfElse(isZeroStep, func() {
+				line, column := int64(s.StepSize.Token().Range.Start.Line), int64(s.StepSize.Token().Range.Start.Column)
+				c.runtime_error(1, c.zero_step_error_string, newInt(line), newInt(column))
+			}, nil)
+		}
 	}
 
-	condBlock := c.cf.NewBlock("")
-	incrementBlock := c.cf.NewBlock("")
-	forBody := c.cf.NewBlock("")
+	condBlock := c.newBlock("for.cond")
+	incrementBlock := c.newBlock("for.inc")
+	forBody := c.newBlock("for.body")
 
-	breakLeave := c.cf.NewBlock("")
+	breakLeave := c.newBlock("for.break")
 	c.curLoopScope, c.curLeaveBlock, c.curContinueBlock = c.scp, breakLeave, incrementBlock
 
 	c.commentNode(c.cbb, s, "")
@@ -2245,6 +2967,8 @@ func (c *compiler) VisitForStmt(s *ast.ForStmt) ast.VisitResult {
 	indexVar := c.cbb.NewLoad(Var.typ.IrType(), Var.val)
 
 	// add the incrementer to the counter variable
+	// for Kommazahl counters the rounding errors of inexact step sizes (like 0,1) accumulate,
+	// the typechecker warns about that (TYP_INEXACT_STEP_SIZE)
 	var add value.Value
 	if ddptypes.DeepEqual(s.Initializer.Type, ddptypes.ZAHL) {
 		add = c.cbb.NewAdd(indexVar, incrementer)
@@ -2256,9 +2980,9 @@ func (c *compiler) VisitForStmt(s *ast.ForStmt) ast.VisitResult {
 	c.cbb.NewBr(condBlock) // check the condition (loop)
 
 	// finally compile the condition block(s)
-	loopDown := c.cf.NewBlock("")
-	loopUp := c.cf.NewBlock("")
-	leaveBlock := c.cf.NewBlock("") // after the condition is false we jump to the leaveBlock
+	loopDown := c.newBlock("for.cond.down")
+	loopUp := c.newBlock("for.cond.up")
+	leaveBlock := c.newBlock("for.leave") // after the condition is false we jump to the leaveBlock
 
 	c.cbb = condBlock
 	// we check the counter differently depending on wether or not we are looping up or down (positive vs negative stepsize)
@@ -2283,7 +3007,7 @@ func (c *compiler) VisitForStmt(s *ast.ForStmt) ast.VisitResult {
 	c.cbb = leaveBlock
 	c.scp = c.exitScope(c.scp) // leave the scope
 
-	trueLeave := c.cf.NewBlock("")
+	trueLeave := c.newBlock("for.end")
 	leaveBlock.NewBr(trueLeave)
 	breakLeave.NewBr(trueLeave)
 	c.cbb = trueLeave
@@ -2298,10 +3022,16 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 	c.scp = newScope(c.scp)
 	in, inTyp, isTempIn := c.evaluate(s.In)
 
-	temp := c.NewAlloca(inTyp.IrType())
-	c.claimOrCopy(temp, in, inTyp, isTempIn)
-	in, _ = c.scp.addTemporary(temp, inTyp)
-	c.scp.protectTemporary(in)
+	// the loop iterates over a copy, so that changes to s.In in the body don't affect it
+	// unless the ConstForRangeAnnotator made sure that s.In is not changed
+	constMeta, _ := c.ddpModule.Ast.GetMetadataByKind(s, annotators.ConstForRangeMetaKind)
+	copyIn := constMeta == nil || isTempIn
+	if copyIn {
+		temp := c.NewAlloca(inTyp.IrType())
+		c.claimOrCopy(temp, in, inTyp, isTempIn)
+		in, _ = c.scp.addTemporary(temp, inTyp)
+		c.scp.protectTemporary(in)
+	}
 
 	var (
 		iter_ptr      value.Value // pointer used for iteration
@@ -2309,6 +3039,8 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 		end_ptr       value.Value // points to the one-after-last element
 		length        value.Value
 	)
+	// Texte are not indexed per Buchstabe but decoded forward from iter_ptr
+	// which is advanced by the byte-size of each Buchstabe, so a loop over a Text is linear in its length
 	if inTyp == c.ddpstring {
 		iter_ptr_type = i8ptr
 		iter_ptr = c.NewAlloca(iter_ptr_type)
@@ -2325,7 +3057,7 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 		end_ptr = c.indexArray(iter_ptr_val, length)
 	}
 
-	loopStart, condBlock, bodyBlock, incrementBlock, leaveBlock := c.cf.NewBlock(""), c.cf.NewBlock(""), c.cf.NewBlock(""), c.cf.NewBlock(""), c.cf.NewBlock("")
+	loopStart, condBlock, bodyBlock, incrementBlock, leaveBlock := c.newBlock("forrange.start"), c.newBlock("forrange.cond"), c.newBlock("forrange.body"), c.newBlock("forrange.inc"), c.newBlock("forrange.leave")
 	c.cbb.NewCondBr(c.cbb.NewICmp(enum.IPredEQ, length, zero), leaveBlock, loopStart)
 
 	c.cbb = loopStart
@@ -2338,7 +3070,7 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 
 	loopVar := c.scp.lookupVar(s.Initializer.Name())
 
-	continueBlock := c.cf.NewBlock("")
+	continueBlock := c.newBlock("forrange.continue")
 	c.cbb = continueBlock
 	c.freeNonPrimitive(loopVar.val, loopVar.typ)
 	c.cbb.NewBr(incrementBlock)
@@ -2346,7 +3078,7 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 	c.cbb = bodyBlock
 	var num_bytes value.Value
 	if inTyp == c.ddpstring {
-		num_bytes = c.cbb.NewCall(utf8_string_to_char_irfun,
+		num_bytes = c.cbb.NewCall(c.utf8_string_to_char_irfun,
 			c.cbb.NewLoad(iter_ptr_type, iter_ptr),
 			loopVar.val,
 		)
@@ -2361,15 +3093,18 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 			element := c.cbb.NewLoad(inListTyp.elementType.IrType(), elementPtr)
 			c.cbb.NewStore(element, loopVar.val)
 		} else {
+			// every non-primitive element (strings, lists, structs, ...) is deep copied
+			// so that the loop variable is independent of the list
 			c.deepCopyInto(loopVar.val, elementPtr, inListTyp.elementType)
 		}
 	}
-	breakLeave := c.cf.NewBlock("")
+	breakLeave := c.newBlock("forrange.break")
 	breakLeave.NewBr(leaveBlock)
 	c.curLoopScope, c.curLeaveBlock, c.curContinueBlock = c.scp, breakLeave, continueBlock
 	c.visitNode(s.Body)
-	c.freeNonPrimitive(loopVar.val, loopVar.typ)
+	// if the body ended with a return, the loop variable was already freed
 	if c.cbb.Term == nil {
+		c.freeNonPrimitive(loopVar.val, loopVar.typ)
 		c.cbb.NewBr(incrementBlock)
 	}
 
@@ -2401,15 +3136,19 @@ func (c *compiler) VisitForRangeStmt(s *ast.ForRangeStmt) ast.VisitResult {
 	c.cbb.NewBr(condBlock)
 
 	c.cbb = leaveBlock
-	c.scp.unprotectTemporary(in)
+	if copyIn {
+		c.scp.unprotectTemporary(in)
+	}
 	// delete(c.scp.variables, s.Initializer.Name()) // the loopvar was already freed
 	c.scp = c.exitScope(c.scp)
 
 	c.cbb = breakLeave
-	c.freeNonPrimitive(in, inTyp)
+	if copyIn {
+		c.freeNonPrimitive(in, inTyp)
+	}
 	c.freeNonPrimitive(loopVar.val, loopVar.typ)
 
-	trueLeave := c.cf.NewBlock("")
+	trueLeave := c.newBlock("forrange.end")
 	leaveBlock.NewBr(trueLeave)
 	breakLeave.NewBr(trueLeave)
 	c.cbb = trueLeave
@@ -2434,8 +3173,8 @@ func (c *compiler) VisitBreakContinueStmt(s *ast.BreakContinueStmt) ast.VisitRes
 func (c *compiler) VisitReturnStmt(s *ast.ReturnStmt) ast.VisitResult {
 	exitScopeReturn := func() {
 		for scp := c.scp; scp != c.cfscp; scp = scp.enclosing {
-			for _, Var := range scp.variables {
-				if !Var.isRef {
+			for _, name := range scp.variableNames() {
+				if Var := scp.variables[name]; !Var.isRef {
 					c.freeNonPrimitive(Var.val, Var.typ)
 				}
 			}
@@ -2488,6 +3227,42 @@ func (c *compiler) VisitTodoStmt(stmt *ast.TodoStmt) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (c *compiler) VisitAssertStmt(s *ast.AssertStmt) ast.VisitResult {
+	// the temporaries of the condition are freed before the branch
+	c.scp = newScope(c.scp)
+	cond, _, _ := c.evaluate(s.Condition)
+	c.scp = c.exitScope(c.scp)
+
+	failBlock, leaveBlock := c.newBlock("assert.fail"), c.newBlock("assert.end")
+	c.commentNode(c.cbb, s, "")
+	c.cbb.NewCondBr(cond, leaveBlock, failBlock)
+
+	c.cbb = failBlock
+	var msg value.Value
+	if s.Message != nil {
+		// the message is only evaluated if the assertion failed
+		// and never freed, because the program exits anyways
+		c.scp = newScope(c.scp)
+		msg, _, _ = c.evaluate(s.Message)
+		c.scp = c.scp.enclosing
+	}
+	line, column := int64(s.Token().Range.Start.Line), int64(s.Token().Range.Start.Column)
+	c.assert_failed(c.constantString(c.ddpModule.GetIncludeFilename()), newInt(line), newInt(column), msg)
+
+	c.cbb = leaveBlock
+	return ast.VisitRecurse
+}
+
+func (c *compiler) VisitPrintStmt(s *ast.PrintStmt) ast.VisitResult {
+	// s.Value is always a Text, the typechecker converts other values
+	c.scp = newScope(c.scp)
+	str, _, _ := c.evaluate(s.Value)
+	c.commentNode(c.cbb, s, "")
+	c.print_string(str, s.NewLine)
+	c.scp = c.exitScope(c.scp)
+	return ast.VisitRecurse
+}
+
 // exits all scopes until the current function scope
 // frees all scp.non_primitives
 func (c *compiler) exitNestedScopes(targetScope *scope) {
@@ -0,0 +1,190 @@
+package compiler
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/llir/irutil"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// compiles the source code from options and writes only the llvm ir
+// of the function called name (declared in the main module) to options.To
+// together with the functions, globals and types it (transitively) references
+// functions defined in other modules or the runtime are only declared
+// the top-level statements of the main module are not compiled
+// options.OutputType, LinkInModules and LinkInListDefs are ignored
+func DumpFunction(options Options, name string) (err error) {
+	defer panic_wrapper(&err)
+
+	if err := validateOptions(&options); err != nil {
+		return fmt.Errorf("Ungültige Compiler Optionen: %w", err)
+	}
+
+	if options.Source == nil && options.From != nil {
+		options.Source, err = io.ReadAll(options.From)
+		if err != nil {
+			return err
+		}
+	}
+
+	ddp_main_module, err := parser.Parse(options.ToParserOptions())
+	if err != nil {
+		return fmt.Errorf("Fehler beim Parsen: %w", err)
+	}
+	if ddp_main_module.Ast.Faulty {
+		return fmt.Errorf("Fehlerhafter Quellcode im Modul '%s', Kompilierung abgebrochen", ddp_main_module.GetIncludeFilename())
+	}
+
+	comp := newCompiler(ddp_main_module, options.ErrorHandler, options.compilerOptions()...)
+	if _, err := comp.compile(io.Discard, false); err != nil {
+		return err
+	}
+
+	mod, err := comp.extractFunction(name)
+	if err != nil {
+		return err
+	}
+	_, err = mod.WriteTo(options.To)
+	return err
+}
+
+// returns a module with only the ir function of the ddp function called name
+// and everything it references from c.mod
+func (c *compiler) extractFunction(name string) (*ir.Module, error) {
+	var fun *ir.Func
+	for _, f := range c.mod.Funcs {
+		if wrapper, ok := c.functions[f.Name()]; ok && wrapper.funcDecl != nil &&
+			wrapper.funcDecl.Name() == name && wrapper.funcDecl.Module() == c.ddpModule {
+			fun = f
+			break
+		}
+	}
+	if fun == nil {
+		return nil, fmt.Errorf("Die Funktion '%s' wurde im Modul '%s' nicht gefunden", name, c.ddpModule.GetIncludeFilename())
+	}
+
+	deps := funcDeps{values: make(map[value.Value]struct{}), types: make(map[types.Type]struct{})}
+	deps.addValue(fun)
+
+	// keep the order of c.mod so that the output is deterministic
+	mod := ir.NewModule()
+	mod.SourceFilename = c.mod.SourceFilename
+	for _, typ := range c.mod.TypeDefs {
+		if _, ok := deps.types[typ]; ok {
+			mod.TypeDefs = append(mod.TypeDefs, typ)
+		}
+	}
+	for _, global := range c.mod.Globals {
+		if _, ok := deps.values[global]; ok {
+			mod.Globals = append(mod.Globals, global)
+		}
+	}
+	for _, f := range c.mod.Funcs {
+		if _, ok := deps.values[f]; ok {
+			mod.Funcs = append(mod.Funcs, f)
+		}
+	}
+	return mod, nil
+}
+
+// the functions, globals and named types a function references
+type funcDeps struct {
+	values map[value.Value]struct{} // *ir.Func and *ir.Global
+	types  map[types.Type]struct{}  // only named struct types
+}
+
+func (deps *funcDeps) addValue(v value.Value) {
+	deps.addType(v.Type())
+
+	switch v := v.(type) {
+	case *ir.Func:
+		if _, ok := deps.values[v]; ok {
+			return
+		}
+		deps.values[v] = struct{}{}
+		for _, block := range v.Blocks {
+			for _, inst := range block.Insts {
+				deps.addOperands(inst)
+			}
+			if block.Term != nil {
+				deps.addOperands(block.Term)
+			}
+		}
+	case *ir.Global:
+		if _, ok := deps.values[v]; ok {
+			return
+		}
+		deps.values[v] = struct{}{}
+		deps.addType(v.ContentType)
+		if v.Init != nil {
+			deps.addValue(v.Init)
+		}
+	case *constant.ExprGetElementPtr:
+		deps.addValue(v.Src)
+	case *constant.ExprBitCast:
+		deps.addValue(v.From)
+	case *constant.Struct:
+		for _, field := range v.Fields {
+			deps.addValue(field)
+		}
+	case *constant.Array:
+		for _, elem := range v.Elems {
+			deps.addValue(elem)
+		}
+	}
+}
+
+// adds the operands of an instruction or terminator
+func (deps *funcDeps) addOperands(user any) {
+	if _, ok := user.(*irutil.Comment); ok {
+		return // comments have no operands
+	}
+	if inst, ok := user.(value.Value); ok {
+		deps.addType(inst.Type())
+	}
+	// the element types of loads, allocas and geps are not part of the operands
+	switch inst := user.(type) {
+	case *ir.InstAlloca:
+		deps.addType(inst.ElemType)
+	case *ir.InstLoad:
+		deps.addType(inst.ElemType)
+	case *ir.InstGetElementPtr:
+		deps.addType(inst.ElemType)
+	}
+	if user, ok := user.(value.User); ok {
+		for _, op := range user.Operands() {
+			if *op != nil {
+				deps.addValue(*op)
+			}
+		}
+	}
+}
+
+func (deps *funcDeps) addType(typ types.Type) {
+	switch typ := typ.(type) {
+	case *types.PointerType:
+		deps.addType(typ.ElemType)
+	case *types.ArrayType:
+		deps.addType(typ.ElemType)
+	case *types.FuncType:
+		deps.addType(typ.RetType)
+		for _, param := range typ.Params {
+			deps.addType(param)
+		}
+	case *types.StructType:
+		if typ.TypeName != "" {
+			if _, ok := deps.types[typ]; ok {
+				return
+			}
+			deps.types[typ] = struct{}{}
+		}
+		for _, field := range typ.Fields {
+			deps.addType(field)
+		}
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
 
+	"github.com/llir/irutil"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
@@ -34,9 +36,10 @@ var (
 
 	i8ptr = ptr(i8)
 
-	zero     = newInt(0) // 0: i64
-	zerof    = constant.NewFloat(ddpfloat, 0)
-	all_ones = newInt(^0) // int with all bits set to 1
+	zero          = newInt(0) // 0: i64
+	zerof         = constant.NewFloat(ddpfloat, 0)
+	all_ones      = newInt(^0)           // int with all bits set to 1
+	all_ones_char = newIntT(ddpchar, ^0) // char with all bits set to 1
 )
 
 func newInt(value int64) *constant.Int {
@@ -56,6 +59,9 @@ func (c *compiler) NewAlloca(elemType types.Type) *ir.InstAlloca {
 // turn a ddptypes.Type into the corresponding llvm type
 func (c *compiler) toIrType(ddpType ddptypes.Type) ddpIrType {
 	ddpType = ddptypes.TrueUnderlying(ddpType)
+	if optionalType, isOptional := ddptypes.CastOptional(ddpType); isOptional {
+		return c.getOptionalType(c.toIrType(optionalType.Underlying).(*ddpIrPrimitiveType))
+	}
 	if listType, isList := ddptypes.CastList(ddpType); isList {
 		underlying := ddptypes.TrueUnderlying(listType.Underlying)
 		switch underlying {
@@ -74,6 +80,10 @@ func (c *compiler) toIrType(ddpType ddptypes.Type) ddpIrType {
 		default:
 			return c.structTypes[underlying.(*ddptypes.StructType)].listType
 		}
+	} else if enumType, isEnum := ddpType.(*ddptypes.EnumType); isEnum {
+		return c.getEnumType(enumType)
+	} else if funcType, isFunc := ddptypes.CastFunction(ddpType); isFunc {
+		return c.getFunctionType(funcType)
 	} else {
 		switch ddpType {
 		case ddptypes.ZAHL:
@@ -152,8 +162,8 @@ func getModuleInitDisposeName(mod *ast.Module) (string, string) {
 	return name + "_init", name + "_dispose"
 }
 
+// shared by all compilers, so they must be safe for concurrent use
 var (
-	hasher                = sha256.New()
 	mangledNamesCacheDecl = sync.Map{}
 	mangledNamesCacheType = sync.Map{}
 )
@@ -206,14 +216,28 @@ func (c *compiler) mangledNameType(t ddptypes.Type) string {
 // base function for mangledNameType and mangledNameDecl
 // should not be called directly
 func mangledNameBase(name string, module *ast.Module) string {
-	hasher.Reset()
-	hasher.Write([]byte(getHashableModuleName(module)))
-	mangledName := name + "_mod_" + hex.EncodeToString(hasher.Sum(nil))
-	return mangledName
+	hash := sha256.Sum256([]byte(getHashableModuleName(module)))
+	return name + "_mod_" + hex.EncodeToString(hash[:])
 }
 
 // compares two values of same type for equality
 func (c *compiler) compare_values(lhs, rhs value.Value, typ ddpIrType) value.Value {
+	if optionalTyp, isOptional := typ.(*ddpIrOptionalType); isOptional {
+		c.latestReturn, c.latestReturnType = c.compareOptionals(lhs, rhs, optionalTyp), c.ddpbooltyp
+		return c.latestReturn
+	}
+
+	if _, isEnum := typ.(*ddpIrEnumType); isEnum {
+		c.latestReturn, c.latestReturnType = c.cbb.NewICmp(enum.IPredEQ, lhs, rhs), c.ddpbooltyp
+		return c.latestReturn
+	}
+
+	// function values are equal if they point to the same function
+	if _, isFunc := typ.(*ddpIrFunctionType); isFunc {
+		c.latestReturn, c.latestReturnType = c.cbb.NewICmp(enum.IPredEQ, lhs, rhs), c.ddpbooltyp
+		return c.latestReturn
+	}
+
 	switch typ {
 	case c.ddpinttyp, c.ddpbooltyp, c.ddpchartyp:
 		c.latestReturn = c.cbb.NewICmp(enum.IPredEQ, lhs, rhs)
@@ -225,3 +249,55 @@ func (c *compiler) compare_values(lhs, rhs value.Value, typ ddpIrType) value.Val
 	c.latestReturnType = c.ddpbooltyp
 	return c.latestReturn
 }
+
+// compares two Texte or two Buchstaben with pred (see ddptypes.IsOrdered)
+// Texte are compared lexicographically by their codepoints (see ddp_string_compare)
+// and Buchstaben by their codepoint
+func (c *compiler) compareOrdered(lhs, rhs value.Value, typ ddpIrType, pred enum.IPred) value.Value {
+	if typ == c.ddpstring {
+		return c.cbb.NewICmp(pred, c.cbb.NewCall(c.ddpstring.compareIrFun, lhs, rhs), zero)
+	}
+	return c.cbb.NewICmp(pred, lhs, rhs)
+}
+
+// returns a constant global containing the null-terminated string s
+// the name of the global is derived from s, so that compiling the same source
+// always results in the same ir and equal strings share the same global
+func (c *compiler) constantString(s string) *ir.Global {
+	return c.constantGlobal(".str."+contentHash(s), irutil.NewCString(s))
+}
+
+// returns the internal constant global called name
+// and defines it with init if it does not exist yet
+// name must be derived from init (see contentHash)
+func (c *compiler) constantGlobal(name string, init constant.Constant) *ir.Global {
+	if global, ok := c.constantGlobals[name]; ok {
+		return global
+	}
+	global := c.mod.NewGlobalDef(name, init)
+	global.Linkage = enum.LinkageInternal
+	global.Visibility = enum.VisibilityDefault
+	global.Immutable = true
+	c.constantGlobals[name] = global
+	return global
+}
+
+// returns a short hex encoded hash of s
+// used to give constant globals a name derived from their content
+func contentHash(s string) string {
+	hash := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(hash[:8])
+}
+
+// returns the imported modules sorted by their FileName
+// so that the generated ir does not depend on the map iteration order
+func (c *compiler) sortedImportedModules() []*ast.Module {
+	modules := make([]*ast.Module, 0, len(c.importedModules))
+	for mod := range c.importedModules {
+		modules = append(modules, mod)
+	}
+	slices.SortFunc(modules, func(a, b *ast.Module) int {
+		return strings.Compare(a.FileName, b.FileName)
+	})
+	return modules
+}
@@ -2,10 +2,13 @@ package compiler
 
 import (
 	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"io"
 	"runtime/debug"
+	"slices"
+	"time"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ast/annotators"
@@ -36,9 +39,16 @@ type Options struct {
 	// Optional reader to read the source code from
 	// if Source is nil
 	From io.Reader
+	// Optional already parsed module
+	// if non-nil, it is compiled instead of FileName, Source or From
+	Module *ast.Module
 	// writer where the result is written to
 	// must be non-nil
 	To io.Writer
+	// optional writer to which the textual llvm ir
+	// of the (linked) main module is written in addition to To
+	// the ir is written before any llvm optimizations are applied
+	IROut io.Writer
 	// type of the output
 	OutputType OutputType
 	// ErrorHandler used for the scanner, parser, ...
@@ -59,12 +69,54 @@ type Options struct {
 	//	-  1: only LLVM optimizations
 	//	- >2: all optimizations
 	OptimizationLevel uint
+	// wether the program reports the memory it never freed at exit
+	// strings and lists allocated in the compiled modules are reported per type
+	MemoryProfiling bool
+	// wether the generated llvm ir is not commented
+	// the comments only increase the size of intermediate files
+	// that are deleted anyways
+	DisableComments bool
+	// wether the generated llvm ir is checked for inconsistencies
+	// (e.g. a basic block without terminator) before it is passed to llvm
+	// the errors found this way are compiler bugs and returned as *VerifyError
+	// always enabled when kddp is built with the debug build tag
+	Verify bool
+	// directories that are searched for included modules
+	// see parser.Options.IncludePaths
+	IncludePaths []string
+	// Optional, the time spent in each phase is added to it
+	Timings *Timings
+	// Optional, the IRStats of the main module (without the imported modules)
+	// are written to it
+	Stats *IRStats
+	// Optional, the main module is compiled as the next main module of the Session
+	// the output is position independent code to be linked into a shared library
+	// requires LinkInModules
+	Session *Session
+}
+
+// the time spent in the different phases of a compilation
+type Timings struct {
+	parser.Timings
+	// generating the llvm ir from the Ast
+	Compiling time.Duration
+	// parsing, linking, optimizing and emitting the llvm ir
+	LLVM time.Duration
+}
+
+// the sum of all phases
+func (t *Timings) Total() time.Duration {
+	return t.Timings.Total() + t.Compiling + t.LLVM
 }
 
 func (options *Options) ToParserOptions() parser.Options {
+	var timings *parser.Timings
+	if options.Timings != nil {
+		timings = &options.Timings.Timings
+	}
 	var annos []ast.Annotator
 	if options.OptimizationLevel >= 2 {
-		annos = append(annos, &annotators.ConstFuncParamAnnotator{})
+		annos = append(annos, &annotators.ConstFuncParamAnnotator{}, &annotators.ConstForRangeAnnotator{}, &annotators.ConstantFoldingAnnotator{})
 	}
 	return parser.Options{
 		FileName:     options.FileName,
@@ -73,6 +125,19 @@ func (options *Options) ToParserOptions() parser.Options {
 		Modules:      nil,
 		ErrorHandler: options.ErrorHandler,
 		Annotators:   annos,
+		IncludePaths: options.IncludePaths,
+		Timings:      timings,
+	}
+}
+
+// the options that configure the compiler of every module
+func (options *Options) compilerOptions() []compilerOption {
+	return []compilerOption{
+		withOptimizationLevel(options.OptimizationLevel),
+		withMemoryProfiling(options.MemoryProfiling),
+		withVerify(options.Verify),
+		withComments(!options.DisableComments),
+		withSession(options.Session),
 	}
 }
 
@@ -84,8 +149,19 @@ type Result struct {
 	Dependencies map[string]struct{}
 }
 
+// returns the Dependencies sorted alphabetically
+// to get a deterministic order, e.g. for linking
+func (r *Result) SortedDependencies() []string {
+	dependencies := make([]string, 0, len(r.Dependencies))
+	for path := range r.Dependencies {
+		dependencies = append(dependencies, path)
+	}
+	slices.Sort(dependencies)
+	return dependencies
+}
+
 func validateOptions(options *Options) error {
-	if options.Source == nil && options.From == nil && options.FileName == "" {
+	if options.Module == nil && options.Source == nil && options.From == nil && options.FileName == "" {
 		return errors.New("Kein Quellcode gegeben")
 	}
 	if options.To == nil {
@@ -97,11 +173,19 @@ func validateOptions(options *Options) error {
 	if options.Log == nil {
 		options.Log = func(string, ...any) {}
 	}
+	if options.Timings == nil {
+		options.Timings = &Timings{}
+	}
+	if options.Session != nil && !options.LinkInModules {
+		return errors.New("Eine Sitzung benötigt LinkInModules")
+	}
+	options.Verify = options.Verify || verifyByDefault
 	return nil
 }
 
 // compile ddp-source-code from the given Options
 // if an error occured, the result is nil
+// Compile may be called concurrently, as every compilation uses its own compiler and llvm context
 func Compile(options Options) (result *Result, err error) {
 	defer panic_wrapper(&err)
 
@@ -111,38 +195,54 @@ func Compile(options Options) (result *Result, err error) {
 		return nil, fmt.Errorf("Ungültige Compiler Optionen: %w", err)
 	}
 
-	// compile the ddp-source into an Ast
-	options.Log("Parse DDP Quellcode")
-	if options.Source == nil && options.From != nil {
-		options.Source, err = io.ReadAll(options.From)
-		if err != nil {
-			return nil, err
+	ddp_main_module := options.Module
+	if ddp_main_module == nil {
+		// compile the ddp-source into an Ast
+		options.Log("Parse DDP Quellcode")
+		if options.Source == nil && options.From != nil {
+			options.Source, err = io.ReadAll(options.From)
+			if err != nil {
+				return nil, err
+			}
 		}
-	}
 
-	ddp_main_module, err := parser.Parse(options.ToParserOptions())
-	if err != nil {
-		return nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+		ddp_main_module, err = parser.Parse(options.ToParserOptions())
+		if err != nil {
+			return nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+		}
 	}
 
 	options.Log("Kompiliere den Abstrakten Syntaxbaum zu LLVM ir")
 
+	// everything from here on that is not generating the llvm ir is counted as LLVM
+	compileStart, compilingBefore := time.Now(), options.Timings.Compiling
+	defer func() {
+		options.Timings.LLVM += time.Since(compileStart) - (options.Timings.Compiling - compilingBefore)
+	}()
+
 	if !options.LinkInModules {
 		irBuff := &bytes.Buffer{}
-		comp_result, err := newCompiler(ddp_main_module, options.ErrorHandler, options.OptimizationLevel).compile(irBuff, true)
+		comp := newCompiler(ddp_main_module, options.ErrorHandler, append(options.compilerOptions(), withStats(options.Stats))...)
+		comp_result, err := comp.compile(irBuff, true)
+		options.Timings.Compiling += time.Since(compileStart)
 		if err != nil {
 			return nil, err
 		}
 
 		// early return
 		if !options.LinkInListDefs && options.OutputType == OutputIR {
+			if options.IROut != nil {
+				if _, err := options.IROut.Write(irBuff.Bytes()); err != nil {
+					return nil, err
+				}
+			}
 			options.To.Write(irBuff.Bytes())
 			return comp_result, nil
 		}
 
 		// if we did not return, we need it as a llvm.Module
 		options.Log("Erstelle llvm Context")
-		llctx, err := newllvmContext()
+		llctx, err := newllvmContext(llvm.RelocDynamicNoPic)
 		if err != nil {
 			return nil, fmt.Errorf("Fehler beim Erstellen des llvm Context: %w", err)
 		}
@@ -170,6 +270,12 @@ func Compile(options Options) (result *Result, err error) {
 			}
 		}
 
+		if options.IROut != nil {
+			if _, err := io.WriteString(options.IROut, mod.String()); err != nil {
+				return nil, err
+			}
+		}
+
 		switch options.OutputType {
 		case OutputIR:
 			_, err := io.WriteString(options.To, mod.String())
@@ -198,8 +304,13 @@ func Compile(options Options) (result *Result, err error) {
 	}
 	// options.LinkInModules == true
 
+	relocMode := llvm.RelocDynamicNoPic
+	if options.Session != nil {
+		relocMode = llvm.RelocPIC
+	}
+
 	options.Log("Erstelle llvm Context")
-	llctx, err := newllvmContext()
+	llctx, err := newllvmContext(relocMode)
 	if err != nil {
 		return nil, fmt.Errorf("Fehler beim Erstellen des llvm Context: %w", err)
 	}
@@ -208,10 +319,12 @@ func Compile(options Options) (result *Result, err error) {
 
 	ll_modules_ir := map[string]*bytes.Buffer{}
 
+	irStart := time.Now()
 	dependencies, err := compileWithImports(ddp_main_module, func(m *ast.Module) io.Writer {
 		ll_modules_ir[m.FileName] = &bytes.Buffer{}
 		return ll_modules_ir[m.FileName]
-	}, options.ErrorHandler, options.OptimizationLevel)
+	}, options.ErrorHandler, options.Stats, options.Session, options.compilerOptions()...)
+	options.Timings.Compiling += time.Since(irStart)
 	if err != nil {
 		return nil, err
 	}
@@ -228,7 +341,8 @@ func Compile(options Options) (result *Result, err error) {
 		ll_modules[ddppath.DDP_List_Types_Defs_LL] = list_defs
 	}
 
-	for name := range ll_modules_ir {
+	// parse the modules in a fixed order, because llvm renames equal types by the order in which they are parsed
+	for _, name := range sortedKeys(ll_modules_ir) {
 		options.Log("Parse '%s' zu llvm-Module", name)
 		// we do not need to defer llmod.Dispose here
 		// because the modules will be destroyed when linking them into the main module
@@ -244,16 +358,25 @@ func Compile(options Options) (result *Result, err error) {
 
 	defer ll_main_module.Dispose()
 	options.Log("Linke llvm Module")
-	if err := llvmLinkAllModules(ll_main_module, mapToSlice(ll_modules)); err != nil {
+	if err := llvmLinkAllModules(ll_main_module, sortedValues(ll_modules)); err != nil {
 		return nil, fmt.Errorf("Fehler beim Linken von llvm-Modulen: %w", err)
 	}
 
+	if options.IROut != nil {
+		if _, err := io.WriteString(options.IROut, ll_main_module.String()); err != nil {
+			return nil, err
+		}
+	}
+
 	// if we output llvm ir we are finished here
 	if options.OutputType == OutputIR {
 		if _, err := io.WriteString(options.To, ll_main_module.String()); err != nil {
 			return nil, err
 		}
 
+		if options.Session != nil {
+			options.Session.addCompiled(ddp_main_module)
+		}
 		return &Result{Dependencies: dependencies}, nil
 	}
 
@@ -271,6 +394,9 @@ func Compile(options Options) (result *Result, err error) {
 		return nil, err
 	}
 
+	if options.Session != nil {
+		options.Session.addCompiled(ddp_main_module)
+	}
 	return &Result{Dependencies: dependencies}, nil
 }
 
@@ -280,11 +406,12 @@ func DumpListDefinitions(w io.Writer, outputType OutputType, errorHandler ddperr
 	defer panic_wrapper(&err)
 
 	irBuff := bytes.Buffer{}
-	if err := newCompiler(nil, errorHandler, optimizationLevel).dumpListDefinitions(&irBuff); err != nil {
+	comp := newCompiler(nil, errorHandler, withOptimizationLevel(optimizationLevel), withVerify(verifyByDefault))
+	if err := comp.dumpListDefinitions(&irBuff); err != nil {
 		return err
 	}
 
-	llctx, err := newllvmContext()
+	llctx, err := newllvmContext(llvm.RelocDynamicNoPic)
 	if err != nil {
 		return fmt.Errorf("Fehler beim Erstellen des llvm Context: %w", err)
 	}
@@ -319,10 +446,22 @@ func DumpListDefinitions(w io.Writer, outputType OutputType, errorHandler ddperr
 	return errors.New("invalid compiler.OutputType")
 }
 
-func mapToSlice[T comparable, U any](m map[T]U) []U {
+// returns the keys of m in sorted order
+// so that the result does not depend on the map iteration order
+func sortedKeys[T cmp.Ordered, U any](m map[T]U) []T {
+	keys := make([]T, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// returns the values of m sorted by their keys
+func sortedValues[T cmp.Ordered, U any](m map[T]U) []U {
 	result := make([]U, 0, len(m))
-	for _, v := range m {
-		result = append(result, v)
+	for _, k := range sortedKeys(m) {
+		result = append(result, m[k])
 	}
 	return result
 }
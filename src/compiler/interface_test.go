@@ -0,0 +1,199 @@
+package compiler
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+)
+
+const concurrentSrc = `Die Zahlen Liste z ist eine Liste, die aus 1, 2, 3 besteht.
+Der Text t ist "a" verkettet mit "b".
+Die Buchstaben Liste b ist t als Buchstaben Liste.
+Die Funktion f mit dem Parameter x vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib x plus (die Länge von t) zurück.
+Und kann so benutzt werden:
+	"f von <x>"
+Speichere f von (z an der Stelle 1) in z an der Stelle 2.
+`
+
+// compiles src to llvm ir
+// errors reported to the ErrorHandler are returned as well, so that compileIR can be called
+// from other goroutines than the test goroutine
+func compileIR(src string, optimizationLevel uint) (string, error) {
+	var (
+		ir       bytes.Buffer
+		parseErr error
+	)
+	if _, err := Compile(Options{
+		FileName:          "main.ddp",
+		Source:            []byte(src),
+		To:                &ir,
+		OutputType:        OutputIR,
+		OptimizationLevel: optimizationLevel,
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Level == ddperror.LEVEL_ERROR && parseErr == nil {
+				parseErr = fmt.Errorf("unexpected error: %s", err.Msg)
+			}
+		},
+	}); err != nil {
+		return "", err
+	}
+	return ir.String(), parseErr
+}
+
+// like compileIR but fails the test on errors
+// must only be called from the test goroutine
+func mustCompileIR(t *testing.T, src string, optimizationLevel uint) string {
+	t.Helper()
+	ir, err := compileIR(src, optimizationLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ir
+}
+
+// compilers must not share mutable state, so that they can run concurrently
+// run with -race to detect data races
+func TestConcurrentCompile(t *testing.T) {
+	expected := mustCompileIR(t, concurrentSrc, 0)
+
+	var wg sync.WaitGroup
+	results := make([]string, 2)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = compileIR(concurrentSrc, 0)
+		}()
+	}
+	wg.Wait()
+
+	for i, ir := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		if ir != expected {
+			t.Error("concurrent compilation yielded different llvm ir")
+		}
+	}
+}
+
+// loops over local variables that are not changed in the loop body
+// iterate over the variable instead of a copy with -O 2
+func TestForRangeNotCopied(t *testing.T) {
+	src := `Die Funktion f gibt eine Zahl zurück, macht:
+	Die Zahlen Liste l ist eine Liste, die aus 4, 5 besteht.
+	Die Zahl n ist 0.
+	Für jede Zahl z in l, mache:
+		Erhöhe n um z plus (die Länge von l).
+	Gib n zurück.
+Und kann so benutzt werden:
+	"f"
+`
+
+	copyCall := "call void @ddp_deep_copy_ddpintlist("
+	copies, optimizedCopies := strings.Count(mustCompileIR(t, src, 1), copyCall), strings.Count(mustCompileIR(t, src, 2), copyCall)
+	if optimizedCopies != copies-1 {
+		t.Errorf("expected the copy of l to be removed with -O 2, but found %d copies instead of %d", optimizedCopies, copies)
+	}
+}
+
+// the operands of xor must have the same width as the compared values
+// which is only visible to the verifier, as llvm accepts the textual ir
+func TestVerifyUnequal(t *testing.T) {
+	src := `Wir nennen die Kombination aus
+	der Zahl x mit Standardwert 0,
+	dem Text name mit Standardwert "",
+einen Punkt, und erstellen sie so:
+	"ein Punkt mit <x> und <name>"
+Der Punkt p ist ein Punkt mit 1 und "a".
+Die vielleicht Zahl v ist 3.
+Der Wahrheitswert b ist p ungleich (ein Punkt mit 2 und "a") ist.
+Speichere v ungleich 4 ist in b.
+Die Zahl z ist (v als Zahl) plus (b als Zahl).
+`
+
+	if _, err := Compile(Options{
+		FileName:   "main.ddp",
+		Source:     []byte(src),
+		To:         &bytes.Buffer{},
+		OutputType: OutputIR,
+		Verify:     true,
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Level == ddperror.LEVEL_ERROR {
+				t.Errorf("unexpected error: %s", err.Msg)
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the equality functions of non-primitive lists are part of the list definitions
+	comp := newCompiler(nil, ddperror.EmptyHandler, withVerify(true))
+	if err := comp.dumpListDefinitions(&bytes.Buffer{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// every enum has its own named type in the ir, which llvm resolves to i64
+func TestNamedEnumType(t *testing.T) {
+	src := `Die Aufzählung Farbe ist rot, grün und blau.
+Die Farbe f ist grün.
+Der Text t ist f als Text.
+Die Zahl z ist 1 plus (f als Zahl).
+`
+
+	ir := mustCompileIR(t, src, 1)
+	if !regexp.MustCompile(`(?m)^%ddpenum\.Farbe_mod_[0-9a-f]+ = type i64$`).MatchString(ir) {
+		t.Errorf("expected a named type for the enum Farbe in the llvm ir:\n%s", ir)
+	}
+	if !strings.Contains(ir, "global %ddpenum.Farbe_mod_") {
+		t.Errorf("expected f to have the named type of Farbe in the llvm ir:\n%s", ir)
+	}
+
+	if _, err := Compile(Options{
+		FileName:   "main.ddp",
+		Source:     []byte(src),
+		To:         &bytes.Buffer{},
+		OutputType: OutputObj,
+		Verify:     true,
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Level == ddperror.LEVEL_ERROR {
+				t.Errorf("unexpected error: %s", err.Msg)
+			}
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSortedDependencies(t *testing.T) {
+	result := &Result{Dependencies: map[string]struct{}{
+		"lib/b.o":   {},
+		"lib/a.c":   {},
+		"c.lib":     {},
+		"lib/a.a":   {},
+		"main.o":    {},
+		"lib/A.c":   {},
+		"extern.so": {},
+	}}
+
+	expected := []string{"c.lib", "extern.so", "lib/A.c", "lib/a.a", "lib/a.c", "lib/b.o", "main.o"}
+	// map iteration order is random, so the result is checked multiple times
+	for range 10 {
+		if dependencies := result.SortedDependencies(); !slices.Equal(dependencies, expected) {
+			t.Fatalf("expected %v, got %v", expected, dependencies)
+		}
+	}
+
+	if dependencies := (&Result{}).SortedDependencies(); len(dependencies) != 0 {
+		t.Errorf("expected no dependencies, got %v", dependencies)
+	}
+}
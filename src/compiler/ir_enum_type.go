@@ -0,0 +1,110 @@
+package compiler
+
+import (
+	"strings"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+	"github.com/bafto/Go-LLVM-Bindings/llvm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// holds the type of a ddp enum (Aufzählung)
+// an enum value is the index of its variant as ddpint
+// and it is passed around by value like the primitive types
+//
+// in the IR every enum gets its own named type (%ddpenum.<mangled name> = type i64)
+// to keep the IR readable, llvm resolves it to i64 so enum values mix freely with ddpint constants
+type ddpIrEnumType struct {
+	typ          *types.IntType
+	ptr          *types.PointerType
+	name         string
+	llType       llvm.Type
+	variants     []string
+	variantNames *ir.Global // [n x i8*] array of the variant names, created on first use
+}
+
+var _ ddpIrType = (*ddpIrEnumType)(nil)
+
+func (t *ddpIrEnumType) IrType() types.Type {
+	return t.typ
+}
+
+func (t *ddpIrEnumType) PtrType() *types.PointerType {
+	return t.ptr
+}
+
+func (t *ddpIrEnumType) Name() string {
+	return t.name
+}
+
+func (*ddpIrEnumType) IsPrimitive() bool {
+	return true
+}
+
+// the default value is the first variant
+func (t *ddpIrEnumType) DefaultValue() constant.Constant {
+	return constant.NewInt(t.typ, 0)
+}
+
+// enums can not be stored in a Variable
+func (*ddpIrEnumType) VTable() constant.Constant {
+	return nil
+}
+
+func (t *ddpIrEnumType) LLVMType() llvm.Type {
+	return t.llType
+}
+
+func (*ddpIrEnumType) FreeFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrEnumType) DeepCopyFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrEnumType) EqualsFunc() *ir.Func {
+	return nil
+}
+
+// returns the IR type of the given enum type
+// and creates it if this is the first use in this module
+func (c *compiler) getEnumType(enumType *ddptypes.EnumType) *ddpIrEnumType {
+	if irType, exists := c.enumTypes[enumType]; exists {
+		return irType
+	}
+
+	typ := c.mod.NewTypeDef("ddpenum."+c.mangledNameType(enumType), types.NewInt(ddpint.BitSize)).(*types.IntType)
+	irType := &ddpIrEnumType{
+		typ:      typ,
+		ptr:      ptr(typ),
+		name:     enumType.Name,
+		llType:   c.ddpinttyp.LLVMType(),
+		variants: enumType.Variants,
+	}
+	c.enumTypes[enumType] = irType
+	return irType
+}
+
+// converts the enum value val to the name of its variant as ddpstring
+// and returns the temporary ddpstring
+func (c *compiler) enumToString(val value.Value, typ *ddpIrEnumType) value.Value {
+	arrType := types.NewArray(uint64(len(typ.variants)), i8ptr)
+	if typ.variantNames == nil {
+		names := make([]constant.Constant, 0, len(typ.variants))
+		for _, variant := range typ.variants {
+			names = append(names, constant.NewBitCast(c.constantString(variant), i8ptr))
+		}
+		// enums with the same variants share their names
+		typ.variantNames = c.constantGlobal(".enum."+contentHash(strings.Join(typ.variants, "\x00")), constant.NewArray(arrType, names...))
+	}
+
+	name := c.cbb.NewLoad(i8ptr, c.cbb.NewGetElementPtr(arrType, typ.variantNames, zero, val))
+	dest := c.NewAlloca(c.ddpstring.typ)
+	c.cbb.NewCall(c.ddpstring.fromConstantsIrFun, dest, name)
+	c.tagAllocation(dest, c.ddpstring)
+	return dest
+}
@@ -0,0 +1,166 @@
+package compiler
+
+import (
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ast/annotators"
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+	"github.com/bafto/Go-LLVM-Bindings/llvm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// holds the type of a ddp function value (Funktion(Zahl) gibt Zahl)
+// a function value is a pointer to a function with a uniform calling convention:
+// non-primitive return values are passed as pointer in the first parameter
+// and non-primitive parameters are passed as pointer to a copy that the callee frees
+type ddpIrFunctionType struct {
+	typ    *types.PointerType // pointer to funcTyp
+	ptr    *types.PointerType
+	name   string
+	llType llvm.Type
+	// the signature that funcTyp was created from
+	funcType *ddptypes.FunctionType
+}
+
+var _ ddpIrType = (*ddpIrFunctionType)(nil)
+
+func (t *ddpIrFunctionType) IrType() types.Type {
+	return t.typ
+}
+
+func (t *ddpIrFunctionType) PtrType() *types.PointerType {
+	return t.ptr
+}
+
+func (t *ddpIrFunctionType) Name() string {
+	return t.name
+}
+
+func (*ddpIrFunctionType) IsPrimitive() bool {
+	return true
+}
+
+func (t *ddpIrFunctionType) DefaultValue() constant.Constant {
+	return constant.NewNull(t.typ)
+}
+
+// function values can not be stored in a Variable
+func (*ddpIrFunctionType) VTable() constant.Constant {
+	return nil
+}
+
+func (t *ddpIrFunctionType) LLVMType() llvm.Type {
+	return t.llType
+}
+
+func (*ddpIrFunctionType) FreeFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrFunctionType) DeepCopyFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrFunctionType) EqualsFunc() *ir.Func {
+	return nil
+}
+
+// returns the IR type of the given function type
+// and creates it if this is the first use in this module
+func (c *compiler) getFunctionType(funcType *ddptypes.FunctionType) *ddpIrFunctionType {
+	if irType, exists := c.functionTypes[funcType]; exists {
+		return irType
+	}
+
+	retType := c.toIrType(funcType.ReturnType)
+	retTypeIr := retType.IrType()
+	params := make([]types.Type, 0, len(funcType.Params)+1)
+	// non-primitives are returned by passing a pointer to the struct as first parameter
+	if !retType.IsPrimitive() {
+		params = append(params, retType.PtrType())
+		retTypeIr = c.void.IrType()
+	}
+	for _, param := range funcType.Params {
+		params = append(params, c.toIrParamType(ddptypes.ParameterType{Type: param}))
+	}
+
+	typ := types.NewPointer(types.NewFunc(retTypeIr, params...))
+	irType := &ddpIrFunctionType{
+		typ:      typ,
+		ptr:      ptr(typ),
+		name:     funcType.String(),
+		llType:   llvm.PointerType(llvm.Int8Type(), 0),
+		funcType: funcType,
+	}
+	c.functionTypes[funcType] = irType
+	return irType
+}
+
+// returns the function that is used as value for decl
+// the function follows the calling convention described in ddpIrFunctionType
+// and calls decl, freeing the parameters if decl itself does not free them
+func (c *compiler) getFunctionValue(decl *ast.FuncDecl) value.Value {
+	// declare the function if it is imported
+	if decl.Module() != c.ddpModule {
+		c.declareImportedFuncDecl(decl)
+	}
+
+	fun := c.functions[c.mangledNameDecl(decl)]
+	name := "ddp_ref_" + fun.irFunc.Name()
+	if wrapper, exists := c.functionValues[name]; exists {
+		return wrapper
+	}
+
+	meta := annotators.ConstFuncParamMeta{}
+	if attachement, ok := decl.Module().Ast.GetMetadataByKind(decl, annotators.ConstFuncParamMetaKind); ok {
+		meta = attachement.(annotators.ConstFuncParamMeta)
+	}
+
+	params := make([]*ir.Param, 0, len(fun.irFunc.Params))
+	for _, param := range fun.irFunc.Params {
+		params = append(params, ir.NewParam(param.Name(), param.Typ))
+	}
+
+	wrapper := c.mod.NewFunc(name, fun.irFunc.Sig.RetType, params...)
+	wrapper.CallingConv = enum.CallingConvC
+	// every module that uses the function value gets its own copy
+	// which are merged by the linker, so that function values stay comparable
+	wrapper.Linkage = enum.LinkageLinkOnceODR
+	if !decl.IsPublic && !decl.IsExternVisible {
+		wrapper.Linkage = enum.LinkageInternal
+	}
+	c.functionValues[name] = wrapper
+
+	block := wrapper.NewBlock("")
+	args := make([]value.Value, 0, len(params))
+	for _, param := range params {
+		args = append(args, param)
+	}
+	ret := block.NewCall(fun.irFunc, args...)
+
+	// skip the possible return parameter
+	if len(params) > len(decl.Parameters) {
+		params = params[1:]
+	}
+	// extern functions and functions with const parameters
+	// do not free their parameters, so the wrapper does it for them
+	for i, param := range decl.Parameters {
+		irType := c.toIrType(param.Type.Type)
+		if irType.IsPrimitive() {
+			continue
+		}
+		if ast.IsExternFunc(decl) || (c.optimizationLevel >= 2 && meta.IsConst[param.Name.Literal]) {
+			block.NewCall(irType.FreeFunc(), params[i])
+		}
+	}
+
+	if types.Equal(wrapper.Sig.RetType, c.void.IrType()) {
+		block.NewRet(nil)
+	} else {
+		block.NewRet(ret)
+	}
+	return wrapper
+}
@@ -5,6 +5,8 @@ to generate ir
 package compiler
 
 import (
+	"fmt"
+
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
@@ -47,6 +49,13 @@ func (c *compiler) growCapacity(cap value.Value) value.Value {
 	return c.cbb.NewPhi(ir.NewIncoming(newInt(8), trueBlock), ir.NewIncoming(newCap, falseBlock))
 }
 
+// indices in DDP start at 1, but the ir uses 0-based indices
+// every index coming from DDP code must be converted using this function
+// before it is used to access an element
+func (c *compiler) toZeroBasedIndex(index value.Value) value.Value {
+	return c.cbb.NewSub(index, newInt(1))
+}
+
 // uses the GetElementPtr instruction to index a pointer
 // returns a pointer to the value
 func (c *compiler) indexArray(arr value.Value, index value.Value) value.Value {
@@ -74,6 +83,28 @@ func (c *compiler) loadStructField(structPtr value.Value, index int64) value.Val
 	return c.cbb.NewLoad(getPointeeType(fieldPtr), fieldPtr)
 }
 
+// reports wether v is a constant int or float and if so wether it is 0
+func isZeroConstant(v value.Value) (isZero, isConst bool) {
+	switch v := v.(type) {
+	case *constant.Int:
+		return v.X.Sign() == 0, true
+	case *constant.Float:
+		return v.X.Sign() == 0, true
+	}
+	return false, false
+}
+
+// creates a new block in c.cf named after name
+// a counter is appended to repeated names, as block names must be unique within a function
+func (c *compiler) newBlock(name string) *ir.Block {
+	count := c.blockNames[name]
+	c.blockNames[name]++
+	if count > 0 {
+		name = fmt.Sprintf("%s.%d", name, count)
+	}
+	return c.cf.NewBlock(name)
+}
+
 // generates a new if-else statement
 // cond is the condition
 // genTrueBody generates the then-body
@@ -0,0 +1,117 @@
+package compiler
+
+import (
+	"github.com/bafto/Go-LLVM-Bindings/llvm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// holds the type of an optional ddptype (vielleicht Zahl, ...)
+// an optional is a struct { ddpbool present; underlying value; }
+// that is passed around by value like the primitive types
+// an empty optional (nichts) always holds the zero value of the underlying type
+type ddpIrOptionalType struct {
+	typ        *types.StructType
+	ptr        *types.PointerType
+	underlying *ddpIrPrimitiveType
+	name       string
+	llType     llvm.Type
+}
+
+var _ ddpIrType = (*ddpIrOptionalType)(nil)
+
+func (t *ddpIrOptionalType) IrType() types.Type {
+	return t.typ
+}
+
+func (t *ddpIrOptionalType) PtrType() *types.PointerType {
+	return t.ptr
+}
+
+func (t *ddpIrOptionalType) Name() string {
+	return t.name
+}
+
+func (*ddpIrOptionalType) IsPrimitive() bool {
+	return true
+}
+
+// the default value is nichts
+func (t *ddpIrOptionalType) DefaultValue() constant.Constant {
+	return constant.NewZeroInitializer(t.typ)
+}
+
+// optionals can not be stored in a Variable
+func (*ddpIrOptionalType) VTable() constant.Constant {
+	return nil
+}
+
+func (t *ddpIrOptionalType) LLVMType() llvm.Type {
+	return t.llType
+}
+
+func (*ddpIrOptionalType) FreeFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrOptionalType) DeepCopyFunc() *ir.Func {
+	return nil
+}
+
+func (*ddpIrOptionalType) EqualsFunc() *ir.Func {
+	return nil
+}
+
+const (
+	optional_present_field_index = 0
+	optional_value_field_index   = 1
+)
+
+// returns the optional type of the given primitive type
+// and defines it if this is the first use in this module
+func (c *compiler) getOptionalType(underlying *ddpIrPrimitiveType) *ddpIrOptionalType {
+	if optionalType, exists := c.optionalTypes[underlying]; exists {
+		return optionalType
+	}
+
+	name := underlying.Name() + "optional"
+	optionalType := &ddpIrOptionalType{
+		typ:        c.mod.NewTypeDef(name, types.NewStruct(ddpbool, underlying.IrType())).(*types.StructType),
+		underlying: underlying,
+		name:       name,
+		llType:     llvm.StructType([]llvm.Type{c.ddpbooltyp.LLVMType(), underlying.LLVMType()}, false),
+	}
+	optionalType.ptr = ptr(optionalType.typ)
+	c.optionalTypes[underlying] = optionalType
+	return optionalType
+}
+
+// wraps the primitive val in the optional type typ
+func (c *compiler) wrapOptional(val value.Value, typ *ddpIrOptionalType) value.Value {
+	present := c.cbb.NewInsertValue(typ.DefaultValue(), constant.True, optional_present_field_index)
+	return c.cbb.NewInsertValue(present, val, optional_value_field_index)
+}
+
+// returns wether the optional val holds a value
+func (c *compiler) isOptionalPresent(val value.Value) value.Value {
+	return c.cbb.NewExtractValue(val, optional_present_field_index)
+}
+
+// returns the value of the optional val
+// the result is the zero value if val is empty
+func (c *compiler) optionalValue(val value.Value) value.Value {
+	return c.cbb.NewExtractValue(val, optional_value_field_index)
+}
+
+// compares two optionals of type typ for equality
+// they are equal if both are empty or both hold equal values
+func (c *compiler) compareOptionals(lhs, rhs value.Value, typ *ddpIrOptionalType) value.Value {
+	// empty optionals always hold the zero value
+	// so comparing both fields is enough
+	presentEqual := c.cbb.NewICmp(enum.IPredEQ, c.isOptionalPresent(lhs), c.isOptionalPresent(rhs))
+	valuesEqual := c.compare_values(c.optionalValue(lhs), c.optionalValue(rhs), typ.underlying)
+	return c.cbb.NewAnd(presentEqual, valuesEqual)
+}
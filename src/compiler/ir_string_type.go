@@ -25,6 +25,7 @@ type ddpIrStringType struct {
 	freeIrFun              *ir.Func // the free ir func
 	deepCopyIrFun          *ir.Func // the deepCopy ir func
 	equalsIrFun            *ir.Func // the equals ir func
+	compareIrFun           *ir.Func // the string_compare ir func
 	lengthIrFun            *ir.Func // the string_length ir func
 	indexIrFun             *ir.Func // the string_index ir func
 	replaceCharIrFun       *ir.Func // the replace_char_in_string ir func
@@ -32,6 +33,8 @@ type ddpIrStringType struct {
 	str_str_concat_IrFunc  *ir.Func // the str_str_verkettet ir func
 	str_char_concat_IrFunc *ir.Func // the str_char_verkettet ir func
 	char_str_concat_IrFunc *ir.Func // the char_str_verkettet ir func
+	str_repeat_IrFunc      *ir.Func // the string_mal ir func
+	char_repeat_IrFunc     *ir.Func // the char_mal ir func
 	int_to_string_IrFun    *ir.Func // the int_to_string ir func
 	float_to_string_IrFun  *ir.Func // the float_to_string ir func
 	bool_to_string_IrFun   *ir.Func // the bool_to_string ir func
@@ -115,6 +118,9 @@ func (c *compiler) defineStringType(declarationOnly bool) *ddpIrStringType {
 	// checks wether the two strings are equal
 	ddpstring.equalsIrFun = c.declareExternalRuntimeFunction("ddp_string_equal", ddpbool, ir.NewParam("str1", ddpstring.ptr), ir.NewParam("str2", ddpstring.ptr))
 
+	// compares the two strings lexicographically by their codepoints
+	ddpstring.compareIrFun = c.declareExternalRuntimeFunction("ddp_string_compare", ddpint, ir.NewParam("str1", ddpstring.ptr), ir.NewParam("str2", ddpstring.ptr))
+
 	// returns the number of utf8 runes in str
 	ddpstring.lengthIrFun = c.declareExternalRuntimeFunction("ddp_string_length", ddpint, ir.NewParam("str", ddpstring.ptr))
 
@@ -130,6 +136,9 @@ func (c *compiler) defineStringType(declarationOnly bool) *ddpIrStringType {
 	ddpstring.char_str_concat_IrFunc = c.declareExternalRuntimeFunction("ddp_char_string_verkettet", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("c", ddpchar), ir.NewParam("str", ddpstring.ptr))
 	ddpstring.str_char_concat_IrFunc = c.declareExternalRuntimeFunction("ddp_string_char_verkettet", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("str", ddpstring.ptr), ir.NewParam("c", ddpchar))
 
+	ddpstring.str_repeat_IrFunc = c.declareExternalRuntimeFunction("ddp_string_mal", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("str", ddpstring.ptr), ir.NewParam("n", ddpint))
+	ddpstring.char_repeat_IrFunc = c.declareExternalRuntimeFunction("ddp_char_mal", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("c", ddpchar), ir.NewParam("n", ddpint))
+
 	ddpstring.int_to_string_IrFun = c.declareExternalRuntimeFunction("ddp_int_to_string", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("i", ddpint))
 	ddpstring.float_to_string_IrFun = c.declareExternalRuntimeFunction("ddp_float_to_string", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("f", ddpfloat))
 	ddpstring.bool_to_string_IrFun = c.declareExternalRuntimeFunction("ddp_bool_to_string", c.void.IrType(), ir.NewParam("ret", ddpstring.ptr), ir.NewParam("b", ddpbool))
@@ -245,7 +245,7 @@ func (c *compiler) createStructEquals(structTyp *ddpIrStructType, declarationOnl
 			f1, f2 = c.indexStruct(struct1, int64(i)), c.indexStruct(struct2, int64(i))
 		}
 		equal := c.compare_values(f1, f2, field)
-		is_not_equal := c.cbb.NewXor(equal, newInt(1))
+		is_not_equal := c.cbb.NewXor(equal, constant.True)
 		c.createIfElse(is_not_equal, func() {
 			c.cbb.NewRet(constant.False)
 		}, nil)
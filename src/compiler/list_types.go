@@ -14,6 +14,7 @@ package compiler
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/bafto/Go-LLVM-Bindings/llvm"
 	"github.com/llir/llvm/ir"
@@ -38,6 +39,8 @@ type ddpIrListType struct {
 	deepCopyIrFun               *ir.Func // the deepCopy ir func
 	equalsIrFun                 *ir.Func // the equals ir func
 	sliceIrFun                  *ir.Func // the clice ir func
+	repeatIrFun                 *ir.Func // the mal ir func
+	padIrFun                    *ir.Func // the aufgefuellt ir func
 	list_list_concat_IrFunc     *ir.Func // the list_list_verkettet ir func
 	list_scalar_concat_IrFunc   *ir.Func // the list_scalar_verkettet ir func
 	scalar_scalar_concat_IrFunc *ir.Func // the scalar_scalar_verkettet ir func
@@ -123,6 +126,8 @@ func (c *compiler) createListType(name string, elementType ddpIrType, declaratio
 	list.deepCopyIrFun = c.createListDeepCopy(list, declarationOnly)
 	list.equalsIrFun = c.createListEquals(list, declarationOnly)
 	list.sliceIrFun = c.createListSlice(list, declarationOnly)
+	list.repeatIrFun = c.createListRepeat(list, declarationOnly)
+	list.padIrFun = c.createListPad(list, declarationOnly)
 
 	list.list_list_concat_IrFunc,
 		list.list_scalar_concat_IrFunc,
@@ -401,7 +406,7 @@ func (c *compiler) createListEquals(listType *ddpIrListType, declarationOnly boo
 		// return memcmp(list1->arr, list2->arr, sizeof(T) * list1->len) == 0;
 		size := c.cbb.NewMul(c.sizeof(listType.elementType.IrType()), list1_len)
 		memcmp := c.memcmp(c.loadStructField(list1, list_arr_field_index), c.loadStructField(list2, list_arr_field_index), size)
-		c.cbb.NewRet(c.cbb.NewICmp(enum.IPredEQ, memcmp, zero))
+		c.cbb.NewRet(c.cbb.NewICmp(enum.IPredEQ, memcmp, newIntT(i32, 0)))
 	} else { // non-primitive types need to be seperately compared
 		/*
 			for (int i = 0; i < list1->len; i++) {
@@ -413,7 +418,7 @@ func (c *compiler) createListEquals(listType *ddpIrListType, declarationOnly boo
 			func(index value.Value) {
 				list1_arr, list2_arr := c.loadStructField(list1, list_arr_field_index), c.loadStructField(list2, list_arr_field_index)
 				list1_at_count, list2_at_count := c.indexArray(list1_arr, index), c.indexArray(list2_arr, index)
-				elements_unequal := c.cbb.NewXor(c.cbb.NewCall(listType.elementType.EqualsFunc(), list1_at_count, list2_at_count), newInt(1))
+				elements_unequal := c.cbb.NewXor(c.cbb.NewCall(listType.elementType.EqualsFunc(), list1_at_count, list2_at_count), constant.True)
 
 				c.createIfElse(elements_unequal, func() {
 					c.cbb.NewRet(constant.False)
@@ -511,9 +516,7 @@ func (c *compiler) createListSlice(listType *ddpIrListType, declarationOnly bool
 		nil,
 	)
 
-	// sub 1 from the indices because ddp-indices start at 1 not 0
-	index1 = c.cbb.NewSub(index1, newInt(1))
-	index2 = c.cbb.NewSub(index2, newInt(1))
+	index1, index2 = c.toZeroBasedIndex(index1), c.toZeroBasedIndex(index2)
 
 	retArrPtr, retLenPtr, retCapPtr := c.indexStruct(ret, list_arr_field_index), c.indexStruct(ret, list_len_field_index), c.indexStruct(ret, list_cap_field_index)
 
@@ -568,6 +571,232 @@ func (c *compiler) createListSlice(listType *ddpIrListType, declarationOnly bool
 	return irFunc
 }
 
+/*
+defines the ddp_x_mal function for a listType
+
+ddp_x_mal stores n copies of list one after another in ret
+if n <= 0 or list is empty ret is an empty list
+list is not modified
+line and column are the position of the operator for the runtime error if the result would be too long
+
+signature:
+void ddp_x_mal(x* ret, x* list, ddpint n, ddpint line, ddpint column)
+*/
+func (c *compiler) createListRepeat(listType *ddpIrListType, declarationOnly bool) *ir.Func {
+	var (
+		ret    = ir.NewParam("ret", listType.ptr)
+		list   = ir.NewParam("list", listType.ptr)
+		n      = ir.NewParam("n", ddpint)
+		line   = ir.NewParam("line", ddpint)
+		column = ir.NewParam("column", ddpint)
+	)
+	irFunc := c.mod.NewFunc(
+		fmt.Sprintf("ddp_%s_mal", listType.typ.Name()),
+		c.void.IrType(),
+		ret,
+		list,
+		n,
+		line,
+		column,
+	)
+	irFunc.CallingConv = enum.CallingConvC
+
+	if declarationOnly {
+		irFunc.Linkage = enum.LinkageExternal
+		return irFunc
+	}
+
+	cbb, cf := c.cbb, c.cf // save the current basic block and ir function
+
+	c.cf = irFunc
+	c.cbb = c.cf.NewBlock("")
+
+	// empty the ret
+	c.cbb.NewStore(constant.NewNull(listType.elementType.PtrType()), c.indexStruct(ret, list_arr_field_index))
+	c.cbb.NewStore(zero, c.indexStruct(ret, list_len_field_index))
+	c.cbb.NewStore(zero, c.indexStruct(ret, list_cap_field_index))
+
+	listLen := c.loadStructField(list, list_len_field_index)
+
+	// if (list->len <= 0 || n <= 0) return;
+	is_empty := c.cbb.NewOr(c.cbb.NewICmp(enum.IPredSLE, listLen, zero), c.cbb.NewICmp(enum.IPredSLE, n, zero))
+	c.createIfElse(is_empty, func() {
+		c.cbb.NewRet(nil)
+	},
+		nil,
+	)
+
+	// if (n > INT64_MAX / list->len) runtime_error(...);
+	too_long := c.cbb.NewICmp(enum.IPredSGT, n, c.cbb.NewSDiv(newInt(math.MaxInt64), listLen))
+	c.createIfElse(too_long,
+		func() {
+			c.runtime_error(1, c.list_repeat_error_string, line, column, listLen, n)
+		},
+		nil,
+	)
+
+	/*
+		ret->len = list->len * n;
+		ret->cap = GROW_CAPACITY(ret->len);
+		ret->arr = ALLOCATE(elementType, ret->cap);
+	*/
+	new_len := c.cbb.NewMul(listLen, n)
+	c.cbb.NewStore(new_len, c.indexStruct(ret, list_len_field_index))
+	c.cbb.NewStore(c.growCapacity(new_len), c.indexStruct(ret, list_cap_field_index))
+	c.cbb.NewStore(c.allocateArr(listType.elementType.IrType(), c.loadStructField(ret, list_cap_field_index)), c.indexStruct(ret, list_arr_field_index))
+
+	retArr, listArr := c.loadStructField(ret, list_arr_field_index), c.loadStructField(list, list_arr_field_index)
+	if listType.elementType.IsPrimitive() {
+		/*
+			for (ddpint i = 0; i < n; i++) {
+				memcpy(&ret->arr[i * list->len], list->arr, sizeof(elementType) * list->len);
+			}
+		*/
+		c.createFor(zero, c.forDefaultCond(n),
+			func(index value.Value) {
+				c.memcpyArr(c.indexArray(retArr, c.cbb.NewMul(index, listLen)), listArr, listLen)
+			},
+		)
+	} else {
+		/*
+			for (ddpint i = 0; i < ret->len; i++) {
+				ddp_deep_copy_x(&ret->arr[i], &list->arr[i % list->len]);
+			}
+		*/
+		c.createFor(zero, c.forDefaultCond(new_len),
+			func(index value.Value) {
+				elementPtr := c.indexArray(retArr, index)
+				listElementPtr := c.indexArray(listArr, c.cbb.NewSRem(index, listLen))
+				c.cbb.NewCall(listType.elementType.DeepCopyFunc(), elementPtr, listElementPtr)
+			},
+		)
+	}
+
+	c.cbb.NewRet(nil)
+
+	c.cbb, c.cf = cbb, cf // restore the basic block and ir function
+
+	c.insertFunction(irFunc.Name(), nil, irFunc)
+	return irFunc
+}
+
+/*
+defines the ddp_x_aufgefuellt function for a listType
+
+ddp_x_aufgefuellt stores a list with exactly n elements in ret:
+if n < list->len only the first n elements of list are copied,
+if n > list->len the missing elements are copies of scal
+if n <= 0 ret is an empty list
+list and scal are not modified
+
+signature:
+void ddp_x_aufgefuellt(x* ret, x* list, ddpint n, elementType scal)
+*/
+func (c *compiler) createListPad(listType *ddpIrListType, declarationOnly bool) *ir.Func {
+	// non-primitive types are passed as pointers
+	// like in the concat functions
+	scal_param_type := listType.elementType.IrType()
+	if !listType.elementType.IsPrimitive() {
+		scal_param_type = ptr(scal_param_type)
+	}
+
+	var (
+		ret  = ir.NewParam("ret", listType.ptr)
+		list = ir.NewParam("list", listType.ptr)
+		n    = ir.NewParam("n", ddpint)
+		scal = ir.NewParam("scal", scal_param_type)
+	)
+	irFunc := c.mod.NewFunc(
+		fmt.Sprintf("ddp_%s_aufgefuellt", listType.typ.Name()),
+		c.void.IrType(),
+		ret,
+		list,
+		n,
+		scal,
+	)
+	irFunc.CallingConv = enum.CallingConvC
+
+	if declarationOnly {
+		irFunc.Linkage = enum.LinkageExternal
+		return irFunc
+	}
+
+	cbb, cf := c.cbb, c.cf // save the current basic block and ir function
+
+	c.cf = irFunc
+	c.cbb = c.cf.NewBlock("")
+
+	// empty the ret
+	c.cbb.NewStore(constant.NewNull(listType.elementType.PtrType()), c.indexStruct(ret, list_arr_field_index))
+	c.cbb.NewStore(zero, c.indexStruct(ret, list_len_field_index))
+	c.cbb.NewStore(zero, c.indexStruct(ret, list_cap_field_index))
+
+	// if (n <= 0) return;
+	c.createIfElse(c.cbb.NewICmp(enum.IPredSLE, n, zero), func() {
+		c.cbb.NewRet(nil)
+	},
+		nil,
+	)
+
+	/*
+		ret->len = n;
+		ret->cap = GROW_CAPACITY(ret->len);
+		ret->arr = ALLOCATE(elementType, ret->cap);
+	*/
+	c.cbb.NewStore(n, c.indexStruct(ret, list_len_field_index))
+	c.cbb.NewStore(c.growCapacity(n), c.indexStruct(ret, list_cap_field_index))
+	c.cbb.NewStore(c.allocateArr(listType.elementType.IrType(), c.loadStructField(ret, list_cap_field_index)), c.indexStruct(ret, list_arr_field_index))
+
+	// ddpint copied = list->len < n ? list->len : n;
+	listLen := c.loadStructField(list, list_len_field_index)
+	copied := c.createTernary(c.cbb.NewICmp(enum.IPredSLT, listLen, n),
+		func() value.Value { return listLen },
+		func() value.Value { return n },
+	)
+
+	retArr, listArr := c.loadStructField(ret, list_arr_field_index), c.loadStructField(list, list_arr_field_index)
+	if listType.elementType.IsPrimitive() {
+		/*
+			memcpy(ret->arr, list->arr, sizeof(elementType) * copied);
+			for (ddpint i = copied; i < n; i++) {
+				ret->arr[i] = scal;
+			}
+		*/
+		c.memcpyArr(retArr, listArr, copied)
+		c.createFor(copied, c.forDefaultCond(n),
+			func(index value.Value) {
+				c.cbb.NewStore(scal, c.indexArray(retArr, index))
+			},
+		)
+	} else {
+		/*
+			for (ddpint i = 0; i < copied; i++) {
+				ddp_deep_copy_x(&ret->arr[i], &list->arr[i]);
+			}
+			for (ddpint i = copied; i < n; i++) {
+				ddp_deep_copy_x(&ret->arr[i], scal);
+			}
+		*/
+		c.createFor(zero, c.forDefaultCond(copied),
+			func(index value.Value) {
+				c.cbb.NewCall(listType.elementType.DeepCopyFunc(), c.indexArray(retArr, index), c.indexArray(listArr, index))
+			},
+		)
+		c.createFor(copied, c.forDefaultCond(n),
+			func(index value.Value) {
+				c.cbb.NewCall(listType.elementType.DeepCopyFunc(), c.indexArray(retArr, index), scal)
+			},
+		)
+	}
+
+	c.cbb.NewRet(nil)
+
+	c.cbb, c.cf = cbb, cf // restore the basic block and ir function
+
+	c.insertFunction(irFunc.Name(), nil, irFunc)
+	return irFunc
+}
+
 /*
 defines the ddp_x_y_verkettet functions for a listType
 and returns them in the order:
@@ -755,14 +984,14 @@ func (c *compiler) createListConcats(listType *ddpIrListType, declarationOnly bo
 		retArr0Ptr, retArr1Ptr := c.indexArray(retArr, zero), c.indexArray(retArr, newInt(1))
 		if listType.elementType.IsPrimitive() {
 			// ret->arr[0] = scal1;
-			// ret->arr[1] = scal1;
+			// ret->arr[1] = scal2;
 			c.cbb.NewStore(scal1, retArr0Ptr)
 			c.cbb.NewStore(scal2, retArr1Ptr)
 		} else {
 			// ddp_deep_copy_scalar(&ret->arr[0], scal1);
-			// ddp_deep_copy_scalar(&ret->arr[0], scal1);
+			// ddp_deep_copy_scalar(&ret->arr[1], scal2);
 			c.cbb.NewCall(listType.elementType.DeepCopyFunc(), retArr0Ptr, scal1)
-			c.cbb.NewCall(listType.elementType.DeepCopyFunc(), retArr0Ptr, scal2)
+			c.cbb.NewCall(listType.elementType.DeepCopyFunc(), retArr1Ptr, scal2)
 		}
 
 		return finish(concScalScal)
@@ -22,7 +22,10 @@ type llvmTarget struct {
 	targetData    llvm.TargetData
 }
 
-func newllvmTarget() (*llvmTarget, error) {
+// creates the target for the current machine
+// relocMode is llvm.RelocPIC for code that is linked into a shared library (see Session)
+// and llvm.RelocDynamicNoPic otherwise
+func newllvmTarget(relocMode llvm.RelocMode) (*llvmTarget, error) {
 	target, err := llvm.GetTargetFromTriple(llvm.DefaultTargetTriple())
 	if err != nil {
 		return nil, fmt.Errorf("could not create llvm target: %w", err)
@@ -33,7 +36,7 @@ func newllvmTarget() (*llvmTarget, error) {
 		"generic",
 		"",
 		llvm.CodeGenOptLevel(llvm.CodeGenLevelDefault),
-		llvm.RelocMode(llvm.RelocDynamicNoPic), // TODO: find documentation about what this is
+		relocMode,
 		llvm.CodeModel(llvm.CodeModelDefault),
 	)
 
@@ -60,12 +63,13 @@ type llvmContext struct {
 	context     llvm.Context
 }
 
-func newllvmContext() (llctx *llvmContext, err error) {
+// relocMode is passed to newllvmTarget
+func newllvmContext(relocMode llvm.RelocMode) (llctx *llvmContext, err error) {
 	llctx = &llvmContext{}
 
 	llctx.context = llvm.NewContext()
 
-	target, err := newllvmTarget()
+	target, err := newllvmTarget(relocMode)
 	if err != nil {
 		return nil, err
 	}
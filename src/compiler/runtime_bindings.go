@@ -22,18 +22,9 @@ func (c *compiler) declareExternalRuntimeFunction(name string, returnType types.
 	return fun
 }
 
-var (
-	ddp_reallocate_irfun      *ir.Func
-	ddp_runtime_error_irfun   *ir.Func
-	utf8_string_to_char_irfun *ir.Func
-	_libc_memcpy_irfun        *ir.Func
-	_libc_memcmp_irfun        *ir.Func
-	_libc_memmove_irfun       *ir.Func
-)
-
 // initializes external functions defined in the ddp-runtime
 func (c *compiler) initRuntimeFunctions() {
-	ddp_reallocate_irfun = c.declareExternalRuntimeFunction(
+	c.ddp_reallocate_irfun = c.declareExternalRuntimeFunction(
 		"ddp_reallocate",
 		i8ptr,
 		ir.NewParam("pointer", i8ptr),
@@ -41,22 +32,22 @@ func (c *compiler) initRuntimeFunctions() {
 		ir.NewParam("newSize", i64),
 	)
 
-	ddp_runtime_error_irfun = c.declareExternalRuntimeFunction(
+	c.ddp_runtime_error_irfun = c.declareExternalRuntimeFunction(
 		"ddp_runtime_error",
 		c.void.IrType(),
 		ir.NewParam("exit_code", ddpint),
 		ir.NewParam("fmt", i8ptr),
 	)
-	ddp_runtime_error_irfun.Sig.Variadic = true
+	c.ddp_runtime_error_irfun.Sig.Variadic = true
 
-	utf8_string_to_char_irfun = c.declareExternalRuntimeFunction(
+	c.utf8_string_to_char_irfun = c.declareExternalRuntimeFunction(
 		"utf8_string_to_char",
 		i64,
 		ir.NewParam("str", i8ptr),
 		ir.NewParam("out", ptr(i32)),
 	)
 
-	_libc_memcpy_irfun = c.declareExternalRuntimeFunction(
+	c._libc_memcpy_irfun = c.declareExternalRuntimeFunction(
 		"memcpy",
 		i8ptr,
 		ir.NewParam("dest", i8ptr),
@@ -64,39 +55,96 @@ func (c *compiler) initRuntimeFunctions() {
 		ir.NewParam("n", i64),
 	)
 
-	_libc_memcmp_irfun = c.declareExternalRuntimeFunction(
+	c._libc_memcmp_irfun = c.declareExternalRuntimeFunction(
 		"memcmp",
-		ddpbool,
+		i32, // int in C
 		ir.NewParam("buf1", i8ptr),
 		ir.NewParam("buf2", i8ptr),
 		ir.NewParam("size", i64),
 	)
 
-	_libc_memmove_irfun = c.declareExternalRuntimeFunction(
+	c._libc_memmove_irfun = c.declareExternalRuntimeFunction(
 		"memmove",
 		i8ptr,
 		ir.NewParam("dest", i8ptr),
 		ir.NewParam("src", i8ptr),
 		ir.NewParam("n", i64),
 	)
+
+	if c.memoryProfiling {
+		c.ddp_enable_memory_profiling_irfun = c.declareExternalRuntimeFunction(
+			"ddp_enable_memory_profiling",
+			c.void.IrType(),
+		)
+
+		c.ddp_memory_profile_tag_irfun = c.declareExternalRuntimeFunction(
+			"ddp_memory_profile_tag",
+			c.void.IrType(),
+			ir.NewParam("value", i8ptr),
+			ir.NewParam("type", i8ptr),
+		)
+	}
 }
 
 // helper functions to use the runtime-bindings
 
 func (c *compiler) runtime_error(exit_code int, fmt value.Value, args ...value.Value) {
 	args = append([]value.Value{newInt(int64(exit_code)), c.cbb.NewBitCast(fmt, i8ptr)}, args...)
-	c.cbb.NewCall(ddp_runtime_error_irfun, args...)
+	c.cbb.NewCall(c.ddp_runtime_error_irfun, args...)
+	c.cbb.NewUnreachable()
+}
+
+// calls ddp_assert_failed from the runtime, which reports the failed assertion and does not return
+// it is declared on first use, because it needs the string type
+// msg may be nil if the assertion has no message
+func (c *compiler) assert_failed(file *ir.Global, line, column value.Value, msg value.Value) {
+	fun := c.functions["ddp_assert_failed"]
+	if fun == nil {
+		c.declareExternalRuntimeFunction(
+			"ddp_assert_failed",
+			c.void.IrType(),
+			ir.NewParam("file", i8ptr),
+			ir.NewParam("line", ddpint),
+			ir.NewParam("column", ddpint),
+			ir.NewParam("msg", c.ddpstring.ptr),
+		)
+		fun = c.functions["ddp_assert_failed"]
+	}
+	if msg == nil {
+		msg = constant.NewNull(c.ddpstring.ptr)
+	}
+	c.cbb.NewCall(fun.irFunc, c.cbb.NewBitCast(file, i8ptr), line, column, msg)
 	c.cbb.NewUnreachable()
 }
 
+// calls ddp_print_string from the runtime
+// it is declared on first use, because it needs the string type
+func (c *compiler) print_string(str value.Value, newline bool) {
+	fun := c.functions["ddp_print_string"]
+	if fun == nil {
+		c.declareExternalRuntimeFunction(
+			"ddp_print_string",
+			c.void.IrType(),
+			ir.NewParam("str", c.ddpstring.ptr),
+			ir.NewParam("newline", ddpbool),
+		)
+		fun = c.functions["ddp_print_string"]
+	}
+	c.cbb.NewCall(fun.irFunc, str, constant.NewBool(newline))
+}
+
 func (c *compiler) out_of_bounds_error(line, column, index, len value.Value) {
 	c.runtime_error(1, c.out_of_bounds_error_string, line, column, index, len)
 }
 
+func (c *compiler) string_out_of_bounds_error(line, column, index, len value.Value) {
+	c.runtime_error(1, c.string_out_of_bounds_error_string, line, column, index, len)
+}
+
 // calls ddp_reallocate from the runtime
 func (c *compiler) ddp_reallocate(pointer, oldSize, newSize value.Value) value.Value {
 	pointer_param := c.cbb.NewBitCast(pointer, i8ptr)
-	return c.cbb.NewBitCast(c.cbb.NewCall(ddp_reallocate_irfun, pointer_param, oldSize, newSize), pointer.Type())
+	return c.cbb.NewBitCast(c.cbb.NewCall(c.ddp_reallocate_irfun, pointer_param, oldSize, newSize), pointer.Type())
 }
 
 // dynamically allocates a single value of type typ
@@ -133,11 +181,27 @@ func (c *compiler) freeArr(ptr, n value.Value) {
 	c.ddp_reallocate(ptr, size, zero)
 }
 
+// labels the memory owned by val (a pointer to a string or list)
+// with the name of typ for the memory profile
+// does nothing if the module is not compiled with memory profiling
+func (c *compiler) tagAllocation(val value.Value, typ ddpIrType) {
+	if !c.memoryProfiling {
+		return
+	}
+	switch typ.(type) {
+	case *ddpIrStringType, *ddpIrListType:
+	default:
+		return
+	}
+
+	c.cbb.NewCall(c.ddp_memory_profile_tag_irfun, c.cbb.NewBitCast(val, i8ptr), c.cbb.NewBitCast(c.constantString(typ.Name()), i8ptr))
+}
+
 // wraps the memcpy function from libc
 // dest and src must be pointer types, n is the size to copy in bytes
 func (c *compiler) memcpy(dest, src, n value.Value) value.Value {
 	dest_param, src_param := c.cbb.NewBitCast(dest, i8ptr), c.cbb.NewBitCast(src, i8ptr)
-	return c.cbb.NewCall(_libc_memcpy_irfun, dest_param, src_param, n)
+	return c.cbb.NewCall(c._libc_memcpy_irfun, dest_param, src_param, n)
 }
 
 // wraps memcpy for a array, where n is the length of the array in src
@@ -151,7 +215,7 @@ func (c *compiler) memcpyArr(dest, src, n value.Value) value.Value {
 // dest and src must be pointer types, n is the size to copy in bytes
 func (c *compiler) memmove(dest, src, n value.Value) value.Value {
 	dest_param, src_param := c.cbb.NewBitCast(dest, i8ptr), c.cbb.NewBitCast(src, i8ptr)
-	return c.cbb.NewCall(_libc_memmove_irfun, dest_param, src_param, n)
+	return c.cbb.NewCall(c._libc_memmove_irfun, dest_param, src_param, n)
 }
 
 // wraps memmove for a array, where n is the length of the array in src
@@ -163,5 +227,5 @@ func (c *compiler) memmoveArr(dest, src, n value.Value) value.Value {
 
 func (c *compiler) memcmp(buf1, buf2, size value.Value) value.Value {
 	buf1_param, buf2_param := c.cbb.NewBitCast(buf1, i8ptr), c.cbb.NewBitCast(buf2, i8ptr)
-	return c.cbb.NewCall(_libc_memcmp_irfun, buf1_param, buf2_param, size)
+	return c.cbb.NewCall(c._libc_memcmp_irfun, buf1_param, buf2_param, size)
 }
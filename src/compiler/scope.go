@@ -1,6 +1,8 @@
 package compiler
 
 import (
+	"slices"
+
 	"github.com/llir/llvm/ir/value"
 )
 
@@ -13,6 +15,18 @@ type varwrapper struct {
 }
 
 // wraps local variables of a scope + the enclosing scope
+//
+// every non-primitive value in variables and temporaries (except references) is owned by the scope
+// and freed exactly once, depending on how the scope is left:
+//   - exitScope frees all variables and temporaries that are not protected
+//   - a return (see VisitReturnStmt) frees everything, including protected values,
+//     of all scopes up to the function scope
+//   - a break or continue (see exitNestedScopes) behaves like exitScope for all scopes up to the loop scope
+//   - an expression statement frees the temporaries it created right away (see VisitExprStmt)
+//
+// protected values (e.g. the list and loop variable of a für-jede loop)
+// are freed by hand by the code that protected them,
+// which must not free them again after a return
 type scope struct {
 	enclosing   *scope                // enclosing scope, nil if it is the global scope
 	variables   map[string]varwrapper // variables in this scope
@@ -41,17 +55,31 @@ func (s *scope) lookupVar(name string) varwrapper {
 	}
 }
 
+// returns the names of the variables in this scope in sorted order
+// so that the generated ir (e.g. the order in which variables are freed)
+// does not depend on the map iteration order
+func (s *scope) variableNames() []string {
+	names := make([]string, 0, len(s.variables))
+	for name := range s.variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 // add a variable to the scope
 func (scope *scope) addVar(name string, val value.Value, ty ddpIrType, isRef bool) value.Value {
 	scope.variables[name] = varwrapper{val: val, typ: ty, isRef: isRef, protected: false}
 	return val
 }
 
+// add a variable that is not freed by exitScope (see scope)
 func (scope *scope) addProtected(name string, val value.Value, ty ddpIrType, isRef bool) value.Value {
 	scope.variables[name] = varwrapper{val: val, typ: ty, isRef: isRef, protected: true}
 	return val
 }
 
+// marks the temporary val as protected, so that it is not freed by exitScope (see scope)
 func (scope *scope) protectTemporary(val value.Value) {
 	for i := len(scope.temporaries) - 1; i >= 0; i-- {
 		if scope.temporaries[i].val == val {
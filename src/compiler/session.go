@@ -0,0 +1,39 @@
+package compiler
+
+import (
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+)
+
+// A Session compiles main modules one after another
+// into shared libraries that are all loaded into the same process
+// and whose ddp_ddpmain functions are called in the same order (e.g. by the repl of kddp)
+//
+// every main module is compiled against the modules compiled before it:
+// those are neither compiled nor initialized again and
+// the globals of a main module are not freed at the end of its ddp_ddpmain,
+// so the following main modules can import and use them
+//
+// a Session must not be used by concurrent compilations
+type Session struct {
+	compiled map[string]struct{} // file names of all modules that were already compiled
+}
+
+func NewSession() *Session {
+	return &Session{compiled: make(map[string]struct{})}
+}
+
+// reports wether the module was compiled with a previous main module
+func (s *Session) isCompiled(module *ast.Module) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.compiled[module.FileName]
+	return ok
+}
+
+// adds the main module and all its imports to the compiled modules
+func (s *Session) addCompiled(mainModule *ast.Module) {
+	ast.IterateModuleImports(mainModule, func(module *ast.Module) {
+		s.compiled[module.FileName] = struct{}{}
+	})
+}
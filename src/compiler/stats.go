@@ -0,0 +1,57 @@
+package compiler
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir"
+)
+
+// counts of what the compiler generated for a module
+// to get an idea where the size of the llvm ir comes from
+type IRStats struct {
+	Functions       int // functions defined in the module
+	Declarations    int // functions only declared in the module (runtime functions, functions of other modules, ...)
+	BasicBlocks     int // basic blocks of all defined functions
+	Instructions    int // instructions of all defined functions, including terminators
+	StringConstants int // constant strings (literals, error messages, ...)
+	RuntimeCalls    int // calls of functions that are not DDP functions and not defined in the module
+}
+
+// collects the IRStats of c.mod
+func (c *compiler) collectStats() IRStats {
+	stats := IRStats{}
+	for _, f := range c.mod.Funcs {
+		if len(f.Blocks) == 0 {
+			stats.Declarations++
+			continue
+		}
+
+		stats.Functions++
+		stats.BasicBlocks += len(f.Blocks)
+		for _, block := range f.Blocks {
+			stats.Instructions += len(block.Insts) + 1 // +1 for the terminator
+			for _, inst := range block.Insts {
+				if call, ok := inst.(*ir.InstCall); ok && c.isRuntimeCall(call) {
+					stats.RuntimeCalls++
+				}
+			}
+		}
+	}
+
+	for name := range c.constantGlobals {
+		if strings.HasPrefix(name, ".str.") {
+			stats.StringConstants++
+		}
+	}
+	return stats
+}
+
+// wether call calls a function that is only declared in c.mod and does not belong to a DDP function
+func (c *compiler) isRuntimeCall(call *ir.InstCall) bool {
+	callee, ok := call.Callee.(*ir.Func)
+	if !ok || len(callee.Blocks) != 0 {
+		return false
+	}
+	fun, ok := c.functions[callee.Name()]
+	return !ok || fun.funcDecl == nil
+}
@@ -0,0 +1,183 @@
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// an inconsistency in the generated llvm ir found by verifyModule
+// like a CompilerError this is a compiler bug and not an error in the ddp-code
+type VerifyError struct {
+	ModulePath string // the module that was compiled
+	Func       string // the function containing the invalid ir
+	Block      string // the basic block containing the invalid ir, empty if the error is not inside a block
+	Inst       string // the invalid instruction or terminator, empty if the error is not about a single instruction
+	Msg        string // describes what is wrong
+}
+
+func (err *VerifyError) Error() string {
+	return fmt.Sprintf("VerifyError(Mod: %s, Func: %s, Block: %s, Inst: %s): %s", err.ModulePath, err.Func, err.Block, err.Inst, err.Msg)
+}
+
+// checks the ir of mod for simple inconsistencies
+// that would otherwise only be reported by llvm when parsing the ir:
+//   - every basic block must end with a terminator
+//   - the operands of binary instructions, comparisons, selects and phis must have the same type
+//   - loads, stores, calls and returns must match the types they use
+//
+// the first error found is returned as *VerifyError
+func verifyModule(mod *ir.Module) error {
+	for _, f := range mod.Funcs {
+		// assign the ids of unnamed values so that the ir in error messages is readable
+		// this is done again by mod.WriteTo anyways
+		if err := f.AssignIDs(); err != nil {
+			return &VerifyError{ModulePath: mod.SourceFilename, Func: f.Name(), Msg: err.Error()}
+		}
+
+		for _, block := range f.Blocks {
+			newErr := func(inst interface{ LLString() string }, format string, args ...any) *VerifyError {
+				return &VerifyError{
+					ModulePath: mod.SourceFilename,
+					Func:       f.Name(),
+					Block:      block.Ident(),
+					Inst:       inst.LLString(),
+					Msg:        fmt.Sprintf(format, args...),
+				}
+			}
+
+			for _, inst := range block.Insts {
+				if msg := verifyInst(inst); msg != "" {
+					return newErr(inst, "%s", msg)
+				}
+			}
+
+			if block.Term == nil {
+				return &VerifyError{ModulePath: mod.SourceFilename, Func: f.Name(), Block: block.Ident(), Msg: "block has no terminator"}
+			}
+			if msg := verifyTerm(block.Term, f.Sig.RetType); msg != "" {
+				return newErr(block.Term, "%s", msg)
+			}
+		}
+	}
+	return nil
+}
+
+// returns a message describing why inst is invalid or "" if it is valid
+func verifyInst(inst ir.Instruction) string {
+	switch inst := inst.(type) {
+	case *ir.InstAdd:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFAdd:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstSub:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFSub:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstMul:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFMul:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstUDiv:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstSDiv:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFDiv:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstURem:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstSRem:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFRem:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstShl:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstLShr:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstAShr:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstAnd:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstOr:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstXor:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstICmp:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstFCmp:
+		return sameType(inst.X, inst.Y)
+	case *ir.InstSelect:
+		if !types.Equal(inst.Cond.Type(), types.I1) {
+			return fmt.Sprintf("condition of type %s instead of i1", inst.Cond.Type())
+		}
+		return sameType(inst.ValueTrue, inst.ValueFalse)
+	case *ir.InstPhi:
+		for _, inc := range inst.Incs {
+			if msg := sameType(inst.Incs[0].X, inc.X); msg != "" {
+				return msg
+			}
+		}
+	case *ir.InstLoad:
+		if ptr, ok := inst.Src.Type().(*types.PointerType); !ok || !types.Equal(ptr.ElemType, inst.ElemType) {
+			return fmt.Sprintf("can not load %s from %s", inst.ElemType, inst.Src.Type())
+		}
+	case *ir.InstStore:
+		if ptr, ok := inst.Dst.Type().(*types.PointerType); !ok || !types.Equal(ptr.ElemType, inst.Src.Type()) {
+			return fmt.Sprintf("can not store %s in %s", inst.Src.Type(), inst.Dst.Type())
+		}
+	case *ir.InstCall:
+		return verifyCall(inst)
+	}
+	return ""
+}
+
+// returns a message describing why term is invalid or "" if it is valid
+// retType is the return type of the function term is in
+func verifyTerm(term ir.Terminator, retType types.Type) string {
+	switch term := term.(type) {
+	case *ir.TermRet:
+		if term.X == nil {
+			if !types.Equal(retType, types.Void) {
+				return fmt.Sprintf("missing return value of type %s", retType)
+			}
+		} else if !types.Equal(term.X.Type(), retType) {
+			return fmt.Sprintf("returned %s instead of %s", term.X.Type(), retType)
+		}
+	case *ir.TermCondBr:
+		if !types.Equal(term.Cond.Type(), types.I1) {
+			return fmt.Sprintf("condition of type %s instead of i1", term.Cond.Type())
+		}
+	}
+	return ""
+}
+
+func verifyCall(call *ir.InstCall) string {
+	ptr, ok := call.Callee.Type().(*types.PointerType)
+	if !ok {
+		return fmt.Sprintf("callee of type %s is not a function pointer", call.Callee.Type())
+	}
+	sig, ok := ptr.ElemType.(*types.FuncType)
+	if !ok {
+		return fmt.Sprintf("callee of type %s is not a function pointer", call.Callee.Type())
+	}
+
+	if len(call.Args) < len(sig.Params) || (!sig.Variadic && len(call.Args) != len(sig.Params)) {
+		return fmt.Sprintf("%d arguments instead of %d", len(call.Args), len(sig.Params))
+	}
+	for i, param := range sig.Params {
+		if !types.Equal(call.Args[i].Type(), param) {
+			return fmt.Sprintf("argument %d of type %s instead of %s", i+1, call.Args[i].Type(), param)
+		}
+	}
+	return ""
+}
+
+// returns a message if x and y do not have the same type
+func sameType(x, y value.Value) string {
+	if !types.Equal(x.Type(), y.Type()) {
+		return fmt.Sprintf("operands of different types %s and %s", x.Type(), y.Type())
+	}
+	return ""
+}
@@ -0,0 +1,6 @@
+//go:build debug
+
+package compiler
+
+// debug builds always verify the generated ir (see Options.Verify)
+const verifyByDefault = true
@@ -0,0 +1,6 @@
+//go:build !debug
+
+package compiler
+
+// see verify_debug.go
+const verifyByDefault = false
@@ -20,6 +20,10 @@ const (
 	SYN_INVALID_UTF8                              // text was not valid utf8
 	SYN_GENDER_MISMATCH                           // the expected and actual grammatical gender used mismatched
 	SYN_INVALID_OPERATOR                          // the given string is not a valid operator
+	SYN_INCLUDE_NOT_FOUND                         // the file of an include could not be found
+	SYN_EMPTY_GROUPING                            // parentheses without an expression inside ()
+	SYN_TOO_MANY_ERRORS                           // the scanner stopped because it reported too many errors
+	SYN_UNEXPECTED_CAPITAL                        // a keyword inside a sentence was capitalized (warning, only reported by the linter)
 )
 
 // semantic error codes
@@ -51,23 +55,31 @@ const (
 	SEM_FORWARD_DECL_WITHOUT_DEF                          // a function was declared as forward decl but never defined
 	SEM_WRONG_DECL_MODULE                                 // a definition was provided for a function from a different module
 	SEM_DEFINITION_ALREADY_DEFINED                        // a forward decl was already defined
+	SEM_EXPR_WITHOUT_EFFECT                               // an expression statement has no side effects (warning)
+	SEM_UNUSED_VARIABLE                                   // a variable is never used (warning, only reported by the linter)
+	SEM_UNUSED_FUNCTION                                   // a non-public function is never called (warning, only reported by the linter)
+	SEM_UNREACHABLE_CODE                                  // statements after a return, break or continue statement (warning, only reported by the linter)
 )
 
 // type error codes
 const (
-	TYP_TYPE_MISMATCH            Code = iota + 3000 // simple type mismatch in an operator
-	TYP_BAD_ASSIGNEMENT                             // invalid variable assignement
-	TYP_BAD_INDEXING                                // type error in index expression
-	TYP_BAD_LIST_LITERAL                            // wrong type in list literal
-	TYP_BAD_CAST                                    // invalid type conversion
-	TYP_EXPECTED_REFERENCE                          // a variable (reference parameter) was expected
-	TYP_INVALID_REFERENCE                           // a char in a string was tried to be passed as refernce
-	TYP_BAD_CONDITION                               // condition value was not of type boolean
-	TYP_BAD_FOR                                     // one of the expressions in a for loop was not of type int
-	TYP_WRONG_RETURN_TYPE                           // the return type did not match the function signature
-	TYP_BAD_FIELD_ACCESS                            // a non-struct type was accessed or similar
-	TYP_PRIVATE_FIELD_ACCESS                        // a non-public field was accessed from another module
-	TYP_BAD_OPERATOR_RETURN_TYPE                    // the return type of a operator overload is void
+	TYP_TYPE_MISMATCH             Code = iota + 3000 // simple type mismatch in an operator
+	TYP_BAD_ASSIGNEMENT                              // invalid variable assignement
+	TYP_BAD_INDEXING                                 // type error in index expression
+	TYP_BAD_LIST_LITERAL                             // wrong type in list literal
+	TYP_BAD_CAST                                     // invalid type conversion
+	TYP_EXPECTED_REFERENCE                           // a variable (reference parameter) was expected
+	TYP_INVALID_REFERENCE                            // a char in a string was tried to be passed as refernce
+	TYP_BAD_CONDITION                                // condition value was not of type boolean
+	TYP_BAD_FOR                                      // one of the expressions in a for loop was not of type int
+	TYP_WRONG_RETURN_TYPE                            // the return type did not match the function signature
+	TYP_BAD_FIELD_ACCESS                             // a non-struct type was accessed or similar
+	TYP_PRIVATE_FIELD_ACCESS                         // a non-public field was accessed from another module
+	TYP_BAD_OPERATOR_RETURN_TYPE                     // the return type of a operator overload is void
+	TYP_PRECISION_LOSS                               // a Zahl literal is too big to be converted to a Kommazahl without loss of precision (warning)
+	TYP_REDUNDANT_BOOL_COMPARISON                    // a Wahrheitswert is compared to wahr or falsch (warning)
+	TYP_BAD_FUNCTION_VALUE                           // a non-function was called, a function value was called with wrong arguments or similar
+	TYP_INEXACT_STEP_SIZE                            // the Kommazahl step size of a for loop can not be represented exactly, so rounding errors accumulate (warning)
 )
 
 func (code Code) IsMiscError() bool {
@@ -86,6 +98,28 @@ func (code Code) IsTypeError() bool {
 	return code >= 3000 && code < 4000
 }
 
+// exit codes of kddp, documented in cmd/kddp/README.md
+const (
+	EXIT_FAILURE        = 1 // any error that does not fit into the other categories (e.g. wrong arguments or linker errors)
+	EXIT_SYNTAX_ERROR   = 2 // a syntax error was reported
+	EXIT_SEMANTIC_ERROR = 3 // a semantic error was reported
+	EXIT_TYPE_ERROR     = 4 // a type error was reported
+	EXIT_IO_ERROR       = 5 // a file could not be read or written
+	EXIT_INTERNAL_ERROR = 6 // a bug in the compiler (a recovered panic)
+)
+
+// returns the exit code of kddp for an error with the given code
+func (code Code) ExitCode() int {
+	if code.IsSyntaxError() {
+		return EXIT_SYNTAX_ERROR
+	} else if code.IsSemanticError() {
+		return EXIT_SEMANTIC_ERROR
+	} else if code.IsTypeError() {
+		return EXIT_TYPE_ERROR
+	}
+	return EXIT_FAILURE
+}
+
 // returns the Prefix before "Fehler" of the given code
 // Prefixes are:
 // Syntax, Semantischer, Typ or nothing for MISC
@@ -21,6 +21,15 @@ type Error struct {
 	Msg   string      // the error message
 	File  string      // the filepath (or uri, url or whatever) in which the error occured
 	Level Level       // the level of the error
+	Notes []Note      // optional notes that point to related locations
+}
+
+// an additional hint attached to an Error
+// pointing to a location related to it (e.g. where a parameter was declared)
+type Note struct {
+	Range token.Range // the range the note refers to
+	Msg   string      // the message of the note
+	File  string      // the filepath in which Range lies
 }
 
 // simple string representation of the error
@@ -44,3 +53,12 @@ func New(code Code, level Level, Range token.Range, msg, file string) Error {
 		Level: level,
 	}
 }
+
+// create a new Note from the given parameters
+func NewNote(Range token.Range, msg, file string) Note {
+	return Note{
+		Range: Range,
+		Msg:   msg,
+		File:  file,
+	}
+}
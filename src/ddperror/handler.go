@@ -3,9 +3,11 @@ package ddperror
 import (
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
-	"strings"
-	"unicode/utf8"
+	"slices"
+
+	"github.com/DDP-Projekt/Kompilierer/src/token"
 )
 
 type Handler func(Error) // used by most ddp packages
@@ -13,92 +15,88 @@ type Handler func(Error) // used by most ddp packages
 // does nothing
 func EmptyHandler(Error) {}
 
+// wraps handler so that warnings with one of the given codes are not passed to it
+// errors are always passed to handler
+func MakeWarningFilter(handler Handler, ignored ...Code) Handler {
+	return func(err Error) {
+		if err.Level == LEVEL_WARN && slices.Contains(ignored, err.Code) {
+			return
+		}
+		handler(err)
+	}
+}
+
+// wraps handler so that every diagnostic is passed to it only once
+// diagnostics are the same if their code, file, range and message are equal
+// which happens for example if a module is reached through multiple includes
+func Deduplicate(handler Handler) Handler {
+	type key struct {
+		code Code
+		file string
+		rnge token.Range
+		msg  string
+	}
+	seen := make(map[key]struct{})
+	return func(err Error) {
+		k := key{code: err.Code, file: err.File, rnge: err.Range, msg: err.Msg}
+		if _, ok := seen[k]; ok {
+			return
+		}
+		seen[k] = struct{}{}
+		handler(err)
+	}
+}
+
 // creates a basic handler that prints the formatted error on a line
+// followed by one indented line per note
 func MakeBasicHandler(w io.Writer) Handler {
 	return func(err Error) {
 		fmt.Fprintf(w, "%s: %s\n", makeErrorHeader(err, ""), err.Msg)
+		for _, note := range err.Notes {
+			fmt.Fprintf(w, "\t%s: %s\n", makeNoteHeader(note, ""), note.Msg)
+		}
 	}
 }
 
 // creates a rust-like error handler writing to w
 // where src is the source-code from which the errors come
 // and file is the filename where src comes from
+// if src is nil or the error comes from another file (i.e. an included module)
+// the source is read from disk, if that fails they are
+// handled like in the basic error handler
 func MakeAdvancedHandler(file string, src []byte, w io.Writer) Handler {
-	lines := strings.Split(string(src), "\n")
+	return MakeColoredAdvancedHandler(file, src, w, false)
+}
+
+// like MakeAdvancedHandler but if colored is true
+// errors and warnings are colored using ANSI escape codes
+func MakeColoredAdvancedHandler(file string, src []byte, w io.Writer, colored bool) Handler {
 	file = filepath.Clean(file)
+	dir := filepath.Dir(file)
 	basicHandler := MakeBasicHandler(w)
+	sources := map[string][]string{}
+	if src != nil {
+		sources[file] = sourceLines(src)
+	}
 
-	return func(err Error) {
-		// we don't have the text of included files
-		// so we handle them with the basic error handler
-		if filepath.Clean(err.File) != file {
-			basicHandler(err)
-			return
-		}
-
-		// helper function to print s n-times
-		printN := func(n int, s string) {
-			for i := 0; i < n; i++ {
-				fmt.Fprint(w, s)
-			}
-		}
-
-		// helper to find the maximum of two uints
-		uMax := func(a, b uint) uint {
-			if a > b {
-				return a
+	getSource := func(file string) []string {
+		file = filepath.Clean(file)
+		lines, ok := sources[file]
+		if !ok {
+			if src, readErr := os.ReadFile(file); readErr == nil {
+				lines = sourceLines(src)
 			}
-			return b
+			sources[file] = lines
 		}
+		return lines
+	}
 
-		rnge := err.Range
-		maxLineCount, maxLineNumLen := 0, utf8.RuneCountInString(fmt.Sprintf("%d", uMax(rnge.Start.Line, rnge.End.Line)))
-		fmt.Fprintf(w, "%s\n\n", makeErrorHeader(err, filepath.Dir(file)))
-
-		for lineIndex := rnge.Start.Line - 1; lineIndex < rnge.End.Line; lineIndex++ {
-			replaceAndCount := func(slice []rune) int {
-				return utf8.RuneCountInString(strings.TrimRight(strings.ReplaceAll(string(slice), "\t", "    "), "\r"))
-			}
-
-			line := []rune(lines[lineIndex])
-			head := fmt.Sprintf("%*d |  ", maxLineNumLen, lineIndex+1)
-			printLine := strings.TrimRight(strings.ReplaceAll(string(line), "\t", "    "), "\r")
-			lineLen := utf8.RuneCountInString(printLine)
-			lineStart := utf8.RuneCountInString(printLine) - utf8.RuneCountInString(strings.TrimLeft(printLine, " "))
-			fmt.Fprintf(w, "%s%s\n", head, printLine)
-
-			if lineLen > maxLineCount {
-				maxLineCount = lineLen
-			}
-
-			fmt.Fprintf(w, "%*s |  ", maxLineNumLen, "")
-
-			if lineIndex == rnge.Start.Line-1 {
-				startLen := replaceAndCount(line[:rnge.Start.Column-1])
-				printN(startLen, " ")
-				restLen := replaceAndCount(line[rnge.Start.Column-1:])
-				if rnge.Start.Line == rnge.End.Line {
-					restLen = replaceAndCount(line[rnge.Start.Column-1 : rnge.End.Column-1])
-				}
-				printN(restLen, "^")
-			} else if lineIndex < rnge.End.Line-1 {
-				printN(lineStart, " ")
-				printN(lineLen-lineStart, "^")
-			} else {
-				restLen := replaceAndCount(line[:rnge.End.Column-1])
-				if lineStart < restLen {
-					printN(lineStart, " ")
-					printN(lineLen-lineStart, "^")
-				} else {
-					printN(restLen, "^")
-				}
-			}
-			fmt.Fprint(w, "\n")
+	return func(err Error) {
+		if getSource(err.File) == nil {
+			basicHandler(err)
+			return
 		}
-
-		fmt.Fprintf(w, "\n%s.\n\n", err.Msg)
-		printN(maxLineCount, "-")
-		fmt.Fprint(w, "\n\n")
+		fmt.Fprint(w, render(err, getSource, dir, colored))
 	}
 }
 
@@ -118,17 +116,29 @@ func makeErrorHeader(err Error, file string) string {
 		kind = "Warnung"
 		prefix = err.Code.WarningPrefix()
 	}
-	if path, e := filepath.Rel(file, err.File); e != nil {
-		file = err.File
-	} else {
-		file = path
-	}
 	return fmt.Sprintf("%s %s (%04d) in %s (Z: %d, S: %d)",
 		prefix,
 		kind,
 		err.Code,
-		file,
+		relativePath(file, err.File),
 		err.Range.Start.Line,
 		err.Range.Start.Column,
 	)
 }
+
+// like makeErrorHeader but for notes
+func makeNoteHeader(note Note, file string) string {
+	return fmt.Sprintf("Hinweis in %s (Z: %d, S: %d)",
+		relativePath(file, note.File),
+		note.Range.Start.Line,
+		note.Range.Start.Column,
+	)
+}
+
+// returns path relative to dir or path itself if that is not possible
+func relativePath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return rel
+	}
+	return path
+}
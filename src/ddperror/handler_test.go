@@ -0,0 +1,48 @@
+package ddperror
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeduplicate(t *testing.T) {
+	assert := assert.New(t)
+
+	var got []Error
+	handler := Deduplicate(func(err Error) {
+		got = append(got, err)
+	})
+
+	err := New(SYN_UNEXPECTED_TOKEN, LEVEL_ERROR, newRange(1, 1, 1, 4), "Fehler", "a.ddp")
+	handler(err)
+	handler(err)
+
+	otherFile := err
+	otherFile.File = "b.ddp"
+	handler(otherFile)
+
+	otherRange := err
+	otherRange.Range = newRange(2, 1, 2, 4)
+	handler(otherRange)
+
+	otherCode := err
+	otherCode.Code = SYN_EXPECTED_LITERAL
+	handler(otherCode)
+
+	otherMsg := err
+	otherMsg.Msg = "anderer Fehler"
+	handler(otherMsg)
+	handler(otherMsg)
+
+	assert.Equal([]Error{err, otherFile, otherRange, otherCode, otherMsg}, got)
+}
+
+func TestExitCode(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(EXIT_FAILURE, MISC_INCLUDE_ERROR.ExitCode())
+	assert.Equal(EXIT_SYNTAX_ERROR, SYN_UNEXPECTED_TOKEN.ExitCode())
+	assert.Equal(EXIT_SEMANTIC_ERROR, SEM_NAME_UNDEFINED.ExitCode())
+	assert.Equal(EXIT_TYPE_ERROR, TYP_TYPE_MISMATCH.ExitCode())
+}
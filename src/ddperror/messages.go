@@ -27,6 +27,10 @@ func MsgNameAlreadyExists(name string) string {
 	return fmt.Sprintf("Der Name %s steht bereits für eine Variable, Funktion oder Struktur", name)
 }
 
+func MsgDeclaredHere(name string) string {
+	return fmt.Sprintf("%s wurde hier deklariert", name)
+}
+
 func MsgAliasAlreadyExists(alias, name string, isFunc bool) string {
 	typ := "Struktur"
 	if isFunc {
@@ -41,4 +45,5 @@ const (
 	MSG_INVALID_UTF8           = "Der Quelltext entspricht nicht dem UTF-8 Standard"
 	MSG_INVALID_FILE_EXTENSION = "Ungültiger Datei Typ (nicht .ddp)"
 	MSG_GLOBAL_RETURN          = "Man kann nur aus Funktionen einen Wert zurückgeben"
+	MSG_NON_GLOBAL_FUNCTION    = "Funktionen können nur auf oberster Ebene deklariert werden"
 )
@@ -0,0 +1,194 @@
+package ddperror
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+)
+
+// renders err like the advanced handler does:
+// the error header, the lines of src covered by err.Range
+// with the range underlined like ^~~~, the error message
+// and the notes of err
+// notes that refer to another file than err are rendered without their source
+func Render(err Error, src []byte) string {
+	return render(err, singleSource(err.File, src), "", false)
+}
+
+// like Render but uses ANSI escape codes to color the header
+// and the underline red for errors and yellow for warnings
+// notes are colored cyan
+func RenderColored(err Error, src []byte) string {
+	return render(err, singleSource(err.File, src), "", true)
+}
+
+// ANSI escape codes used by render
+const (
+	ansiReset      = "\x1b[0m"
+	ansiBoldRed    = "\x1b[1;31m"
+	ansiBoldYellow = "\x1b[1;33m"
+	ansiBoldCyan   = "\x1b[1;36m"
+)
+
+// returns the source lines of file
+// or nil if they are not available
+type sourceFunc func(file string) []string
+
+// a sourceFunc that only knows the source of file
+func singleSource(file string, src []byte) sourceFunc {
+	lines := sourceLines(src)
+	return func(f string) []string {
+		if f == file {
+			return lines
+		}
+		return nil
+	}
+}
+
+// splits src into lines without the line endings
+func sourceLines(src []byte) []string {
+	lines := strings.Split(string(src), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return lines
+}
+
+// renders err with the source lines returned by sources
+// the source of err.File must be available
+// dir is the directory to which the files in the headers are made relative
+// if colored is true ANSI escape codes are used to color the headers and the underlines
+func render(err Error, sources sourceFunc, dir string, colored bool) string {
+	var b strings.Builder
+
+	// returns a function that wraps s in color
+	colorizer := func(color string) func(string) string {
+		return func(s string) string {
+			if !colored || s == "" {
+				return s
+			}
+			return color + s + ansiReset
+		}
+	}
+	colorize, colorizeNote := colorizer(ansiBoldRed), colorizer(ansiBoldCyan)
+	if err.Level == LEVEL_WARN {
+		colorize = colorizer(ansiBoldYellow)
+	}
+
+	fmt.Fprintf(&b, "%s\n\n", colorize(makeErrorHeader(err, dir)))
+	maxLineCount := renderSnippet(&b, err.Range, sources(err.File), colorize)
+	fmt.Fprintf(&b, "\n%s.\n\n", err.Msg)
+
+	for _, note := range err.Notes {
+		fmt.Fprintf(&b, "%s: %s\n\n", colorizeNote(makeNoteHeader(note, dir)), note.Msg)
+		if lines := sources(note.File); lines != nil {
+			maxLineCount = max(maxLineCount, renderSnippet(&b, note.Range, lines, colorizeNote))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString(strings.Repeat("-", maxLineCount))
+	b.WriteString("\n\n")
+	return b.String()
+}
+
+// writes the lines covered by rnge to b with the range underlined like ^~~~
+// the underline is wrapped by colorize
+// returns the display width of the longest written line
+func renderSnippet(b *strings.Builder, rnge token.Range, lines []string, colorize func(string) string) int {
+	startLine, endLine := max(rnge.Start.Line, 1), min(max(rnge.End.Line, rnge.Start.Line), uint(len(lines)))
+	maxLineCount, maxLineNumLen := 0, len(fmt.Sprintf("%d", endLine))
+
+	for lineNum := startLine; lineNum <= endLine; lineNum++ {
+		line := []rune(lines[lineNum-1])
+		// columns are 1-based and the end column is exclusive
+		clampColumn := func(column uint) int {
+			return min(max(int(column), 1), len(line)+1) - 1
+		}
+
+		printLine := strings.ReplaceAll(string(line), "\t", "    ")
+		lineLen := displayWidth(line)
+		lineStart := lineLen - displayWidth([]rune(strings.TrimLeft(string(line), " \t")))
+		fmt.Fprintf(b, "%*d |  %s\n", maxLineNumLen, lineNum, printLine)
+		maxLineCount = max(maxLineCount, lineLen)
+
+		fmt.Fprintf(b, "%*s |  ", maxLineNumLen, "")
+		switch {
+		case lineNum == startLine:
+			start, end := clampColumn(rnge.Start.Column), len(line)
+			if startLine == rnge.End.Line {
+				end = max(clampColumn(rnge.End.Column), start)
+			}
+			b.WriteString(strings.Repeat(" ", displayWidth(line[:start])))
+			b.WriteString(colorize(underline(displayWidth(line[start:end]), true)))
+		case lineNum < rnge.End.Line:
+			b.WriteString(strings.Repeat(" ", lineStart))
+			b.WriteString(colorize(underline(lineLen-lineStart, false)))
+		default:
+			endLen := displayWidth(line[:clampColumn(rnge.End.Column)])
+			if lineStart < endLen {
+				b.WriteString(strings.Repeat(" ", lineStart))
+				b.WriteString(colorize(underline(endLen-lineStart, false)))
+			} else {
+				b.WriteString(colorize(underline(endLen, false)))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return maxLineCount
+}
+
+// returns a ^~~~ underline of width n
+// only underlines which start the range begin with ^
+func underline(n int, isStart bool) string {
+	if n <= 0 {
+		if isStart {
+			return "^" // always mark where the range starts
+		}
+		return ""
+	}
+	if isStart {
+		return "^" + strings.Repeat("~", n-1)
+	}
+	return strings.Repeat("~", n)
+}
+
+// returns the number of terminal columns needed to print runes
+// tabs are printed as 4 spaces
+func displayWidth(runes []rune) int {
+	width := 0
+	for _, r := range runes {
+		width += runeWidth(r)
+	}
+	return width
+}
+
+// returns the number of terminal columns needed to print r
+func runeWidth(r rune) int {
+	switch {
+	case r == '\t':
+		return 4
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWideRune(r):
+		return 2
+	}
+	return 1
+}
+
+// reports wether r is displayed with double width in most terminals
+// (east asian wide and fullwidth characters and most emojis)
+func isWideRune(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) ||
+		(r >= 0x2E80 && r <= 0xA4CF && r != 0x303F) ||
+		(r >= 0xAC00 && r <= 0xD7A3) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) ||
+		(r >= 0xFFE0 && r <= 0xFFE6) ||
+		(r >= 0x1F300 && r <= 0x1F64F) ||
+		(r >= 0x1F900 && r <= 0x1F9FF) ||
+		(r >= 0x20000 && r <= 0x3FFFD)
+}
@@ -0,0 +1,140 @@
+package ddperror
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRange(startLine, startCol, endLine, endCol uint) token.Range {
+	return token.Range{
+		Start: token.Position{Line: startLine, Column: startCol},
+		End:   token.Position{Line: endLine, Column: endCol},
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		rnge     token.Range
+		expected string
+	}{
+		{
+			name: "single line",
+			src:  "Die Zahl x ist 1.",
+			rnge: newRange(1, 16, 1, 17),
+			expected: `1 |  Die Zahl x ist 1.
+  |                 ^
+`,
+		},
+		{
+			name: "multi-byte",
+			src:  `Der Text t ist "äöü" plus 1.`,
+			rnge: newRange(1, 16, 1, 21),
+			expected: `1 |  Der Text t ist "äöü" plus 1.
+  |                 ^~~~~
+`,
+		},
+		{
+			name: "wide runes",
+			src:  `Der Text t ist "日本" plus 1.`,
+			rnge: newRange(1, 16, 1, 20),
+			expected: `1 |  Der Text t ist "日本" plus 1.
+  |                 ^~~~~~
+`,
+		},
+		{
+			name: "tabs",
+			src:  "\tSchreibe x.",
+			rnge: newRange(1, 11, 1, 12),
+			expected: `1 |      Schreibe x.
+  |               ^
+`,
+		},
+		{
+			name: "multiple lines",
+			src:  "Die Zahl x ist 1 plus\n\t2 plus\n\t3.",
+			rnge: newRange(1, 16, 3, 3),
+			expected: `1 |  Die Zahl x ist 1 plus
+  |                 ^~~~~~
+2 |      2 plus
+  |      ~~~~~~
+3 |      3.
+  |      ~
+`,
+		},
+		{
+			name: "out of range",
+			src:  "Die Zahl x ist 1.",
+			rnge: newRange(1, 30, 2, 5),
+			expected: `1 |  Die Zahl x ist 1.
+  |                   ^
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := New(TYP_TYPE_MISMATCH, LEVEL_ERROR, test.rnge, "Fehler", "test.ddp")
+			expected := makeErrorHeader(err, "") + "\n\n" + test.expected + "\nFehler.\n\n"
+			assert.True(t, strings.HasPrefix(Render(err, []byte(test.src)), expected))
+		})
+	}
+}
+
+func TestRenderColored(t *testing.T) {
+	assert := assert.New(t)
+	src := []byte("Die Zahl x ist 1.")
+
+	err := New(TYP_TYPE_MISMATCH, LEVEL_ERROR, newRange(1, 16, 1, 17), "Fehler", "test.ddp")
+	rendered := RenderColored(err, src)
+	assert.Contains(rendered, ansiBoldRed+makeErrorHeader(err, "")+ansiReset)
+	assert.Contains(rendered, ansiBoldRed+"^"+ansiReset)
+	assert.Equal(Render(err, src), strings.NewReplacer(ansiBoldRed, "", ansiReset, "").Replace(rendered))
+
+	warn := New(TYP_PRECISION_LOSS, LEVEL_WARN, newRange(1, 16, 1, 17), "Warnung", "test.ddp")
+	assert.Contains(RenderColored(warn, src), ansiBoldYellow+"^"+ansiReset)
+}
+
+func TestRenderNotes(t *testing.T) {
+	assert := assert.New(t)
+	src := []byte("Die Zahl x ist 1.\nDer Text x ist \"a\".")
+
+	err := New(SEM_NAME_ALREADY_DEFINED, LEVEL_ERROR, newRange(2, 10, 2, 11), "Fehler", "test.ddp")
+	err.Notes = []Note{
+		NewNote(newRange(1, 10, 1, 11), "hier", "test.ddp"),
+		NewNote(newRange(3, 1, 3, 5), "woanders", "other.ddp"),
+	}
+
+	expected := makeErrorHeader(err, "") + `
+
+2 |  Der Text x ist "a".
+  |           ^
+
+Fehler.
+
+Hinweis in test.ddp (Z: 1, S: 10): hier
+
+1 |  Die Zahl x ist 1.
+  |           ^
+
+Hinweis in other.ddp (Z: 3, S: 1): woanders
+
+-------------------
+
+`
+	assert.Equal(expected, Render(err, src))
+	assert.Contains(RenderColored(err, src), ansiBoldCyan+"Hinweis in test.ddp (Z: 1, S: 10)"+ansiReset)
+}
+
+func TestBasicHandlerNotes(t *testing.T) {
+	var b strings.Builder
+	err := New(SEM_NAME_ALREADY_DEFINED, LEVEL_ERROR, newRange(2, 10, 2, 11), "Fehler", "test.ddp")
+	err.Notes = []Note{NewNote(newRange(1, 10, 1, 11), "hier", "test.ddp")}
+	MakeBasicHandler(&b)(err)
+
+	assert.Equal(t, makeErrorHeader(err, "")+": Fehler\n\tHinweis in test.ddp (Z: 1, S: 10): hier\n", b.String())
+}
@@ -0,0 +1,35 @@
+package ddptypes
+
+// represents the type of a ddp enum (Aufzählung)
+// the variants are numbered in order of declaration
+// and carry no explicit values
+type EnumType struct {
+	// name of the enum
+	Name string
+	// grammatical gender of the enum name
+	GramGender GrammaticalGender
+	// names of the variants
+	// in order of declaration
+	Variants []string
+}
+
+func (*EnumType) ddpType() {}
+
+func (t *EnumType) Gender() GrammaticalGender {
+	return t.GramGender
+}
+
+func (t *EnumType) String() string {
+	return t.Name
+}
+
+// returns the index of the variant with the given name
+// or -1 if the enum has no such variant
+func (t *EnumType) VariantIndex(name string) int {
+	for i, variant := range t.Variants {
+		if variant == name {
+			return i
+		}
+	}
+	return -1
+}
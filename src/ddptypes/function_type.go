@@ -0,0 +1,81 @@
+package ddptypes
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// the type of a function value like "Funktion(Zahl, Text) gibt Zahl"
+// function values take all parameters by value
+//
+// the underlying type of a function type (see GetUnderlying) is unique for
+// equal signatures (see NewFunctionType), so function types are compared using Equal like the other types
+type FunctionType struct {
+	// types of the parameters
+	// in order of declaration
+	Params []Type
+	// type of the return value (VoidType if nothing is returned)
+	ReturnType Type
+	// the unique function type with the underlying types of Params and ReturnType
+	// points to the function type itself if it is already unique
+	underlying *FunctionType
+}
+
+func (*FunctionType) ddpType() {}
+
+func (*FunctionType) Gender() GrammaticalGender {
+	return FEMININ
+}
+
+func (t *FunctionType) String() string {
+	params := mapSlice(t.Params, Type.String)
+	return "Funktion(" + strings.Join(params, ", ") + ") gibt " + t.ReturnType.String()
+}
+
+// a node in the trie of the unique function types
+// the path to a node is the return type followed by the parameter types
+type functionTypeNode struct {
+	typ      *FunctionType // the function type whose path ends at this node, may be nil
+	children map[Type]*functionTypeNode
+}
+
+var (
+	functionTypes      = functionTypeNode{children: make(map[Type]*functionTypeNode)} // all unique function types
+	functionTypesMutex sync.Mutex
+)
+
+// returns the function type with the given signature
+// the underlying types of the signature are unique,
+// so if no TypeAlias is used the same pointer is returned for equal signatures
+// otherwise the returned type keeps the spelling of the aliases
+func NewFunctionType(params []Type, returnType Type) *FunctionType {
+	underlyingParams, underlyingReturnType := mapSlice(params, GetUnderlying), GetUnderlying(returnType)
+	underlying := uniqueFunctionType(underlyingParams, underlyingReturnType)
+	if returnType == underlyingReturnType && slices.Equal(params, underlyingParams) {
+		return underlying
+	}
+	return &FunctionType{Params: params, ReturnType: returnType, underlying: underlying}
+}
+
+// returns the unique function type for the given underlying types
+func uniqueFunctionType(params []Type, returnType Type) *FunctionType {
+	functionTypesMutex.Lock()
+	defer functionTypesMutex.Unlock()
+
+	node := &functionTypes
+	for _, typ := range append([]Type{returnType}, params...) {
+		child, ok := node.children[typ]
+		if !ok {
+			child = &functionTypeNode{children: make(map[Type]*functionTypeNode)}
+			node.children[typ] = child
+		}
+		node = child
+	}
+
+	if node.typ == nil {
+		node.typ = &FunctionType{Params: params, ReturnType: returnType}
+		node.typ.underlying = node.typ
+	}
+	return node.typ
+}
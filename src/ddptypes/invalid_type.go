@@ -0,0 +1,16 @@
+package ddptypes
+
+// the type of an invalid expression (e.g. an undefined variable or a bad field access)
+// unlike VoidType it is not the type of any valid expression
+// the error was already reported, so errors about values of this type are follow-up errors
+type InvalidType struct{}
+
+func (InvalidType) ddpType() {}
+
+func (InvalidType) Gender() GrammaticalGender {
+	return INVALID_GENDER
+}
+
+func (InvalidType) String() string {
+	return "ungültig"
+}
@@ -11,8 +11,9 @@ func (ListType) Gender() GrammaticalGender {
 }
 
 func (listType ListType) String() string {
-	if _, ok := listType.Underlying.(PrimitiveType); ok {
-		switch listType.Underlying.(PrimitiveType) {
+	switch underlying := listType.Underlying.(type) {
+	case PrimitiveType:
+		switch underlying {
 		case ZAHL:
 			return "Zahlen Liste"
 		case KOMMAZAHL:
@@ -26,10 +27,12 @@ func (listType ListType) String() string {
 		default:
 			panic("invaid primitive type")
 		}
-	} else if _, isVoid := listType.Underlying.(VoidType); isVoid {
+	case Variable:
+		return "Variablen Liste"
+	case VoidType:
 		panic("void list type")
 	}
-	return listType.Underlying.String() + " Liste" // no 100% correct yet
+	return listType.Underlying.String() + " Liste" // nested lists become "Zahlen Liste Liste"
 }
 
 // gets the underlying type of a List
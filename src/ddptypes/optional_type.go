@@ -0,0 +1,27 @@
+package ddptypes
+
+// an optional type either holds a value of its underlying type or nichts
+// e.g. "vielleicht Zahl"
+type OptionalType struct {
+	Underlying Type
+}
+
+func (OptionalType) ddpType() {}
+
+func (optionalType OptionalType) Gender() GrammaticalGender {
+	return optionalType.Underlying.Gender()
+}
+
+func (optionalType OptionalType) String() string {
+	return "vielleicht " + optionalType.Underlying.String()
+}
+
+// reports wether typ can be the underlying type of an optional type
+// only Zahl, Kommazahl, Wahrheitswert and Buchstabe are supported for now
+func IsValidOptionalUnderlying(typ Type) bool {
+	switch GetUnderlying(typ) {
+	case ZAHL, KOMMAZAHL, WAHRHEITSWERT, BUCHSTABE:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package ddptypes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parses the string representation of a type
+// as returned by Type.String() back into a Type
+// lookup resolves all other type names (structs, type-aliases and typedefs)
+// and may be nil, in which case only builtin types can be parsed
+func ParseType(s string, lookup func(name string) (Type, bool)) (Type, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("leerer Typname")
+	}
+
+	// checked first, because "Funktion(Zahl) gibt Zahlen Liste" returns a list
+	if inner, isFunction := strings.CutPrefix(s, "Funktion("); isFunction {
+		return parseFunctionType(s, inner, lookup)
+	}
+
+	if inner, isList := strings.CutSuffix(s, " Liste"); isList {
+		switch inner {
+		case "Zahlen":
+			return ListType{Underlying: ZAHL}, nil
+		case "Kommazahlen":
+			return ListType{Underlying: KOMMAZAHL}, nil
+		case "Wahrheitswert":
+			return ListType{Underlying: WAHRHEITSWERT}, nil
+		case "Buchstaben":
+			return ListType{Underlying: BUCHSTABE}, nil
+		case "Text":
+			return ListType{Underlying: TEXT}, nil
+		case "Variablen":
+			return ListType{Underlying: VARIABLE}, nil
+		}
+
+		underlying, err := ParseType(inner, lookup)
+		if err != nil {
+			return nil, err
+		}
+		switch underlying.(type) {
+		// those have their own list names ("Zahlen Liste" etc.) and lists of optionals are not supported yet
+		case PrimitiveType, Variable, VoidType, OptionalType:
+			return nil, fmt.Errorf("'%s' ist kein gültiger Listen-Typ", s)
+		}
+		return ListType{Underlying: underlying}, nil
+	}
+
+	if inner, isOptional := strings.CutPrefix(s, "vielleicht "); isOptional {
+		underlying, err := ParseType(inner, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if !IsValidOptionalUnderlying(underlying) {
+			return nil, fmt.Errorf("'%s' ist kein gültiger optionaler Typ", s)
+		}
+		return OptionalType{Underlying: underlying}, nil
+	}
+
+	switch s {
+	case "Zahl":
+		return ZAHL, nil
+	case "Kommazahl":
+		return KOMMAZAHL, nil
+	case "Wahrheitswert":
+		return WAHRHEITSWERT, nil
+	case "Buchstabe":
+		return BUCHSTABE, nil
+	case "Text":
+		return TEXT, nil
+	case "Variable":
+		return VARIABLE, nil
+	case "nichts":
+		return VoidType{}, nil
+	}
+
+	if lookup != nil {
+		if typ, ok := lookup(s); ok {
+			return typ, nil
+		}
+	}
+	return nil, fmt.Errorf("unbekannter Typ '%s'", s)
+}
+
+// parses the rest of a function type after "Funktion("
+// s is the whole type for error messages
+func parseFunctionType(s, inner string, lookup func(name string) (Type, bool)) (Type, error) {
+	// find the ) that closes the parameter list and the top level commas
+	depth, end := 0, -1
+	paramStarts := []int{0}
+	for i, r := range inner {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				paramStarts = append(paramStarts, i+1)
+			}
+		}
+		if depth < 0 {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return nil, fmt.Errorf("'%s' ist kein gültiger Funktions-Typ", s)
+	}
+
+	returnStr, ok := strings.CutPrefix(inner[end+1:], " gibt ")
+	if !ok {
+		return nil, fmt.Errorf("'%s' ist kein gültiger Funktions-Typ", s)
+	}
+	returnType, err := ParseType(returnStr, lookup)
+	if err != nil {
+		return nil, err
+	}
+
+	var params []Type
+	if strings.TrimSpace(inner[:end]) != "" {
+		paramStarts = append(paramStarts, end+1)
+		for i := 0; i < len(paramStarts)-1; i++ {
+			param, err := ParseType(inner[paramStarts[i]:paramStarts[i+1]-1], lookup)
+			if err != nil {
+				return nil, err
+			}
+			if IsVoid(param) {
+				return nil, fmt.Errorf("'%s' ist kein gültiger Funktions-Typ", s)
+			}
+			params = append(params, param)
+		}
+	}
+	return NewFunctionType(params, returnType), nil
+}
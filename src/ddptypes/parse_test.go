@@ -0,0 +1,73 @@
+package ddptypes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTypeRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	vektor := &StructType{Name: "Vektor", GramGender: MASKULIN}
+	nummer := &TypeAlias{Name: "Nummer", Underlying: ZAHL, GramGender: FEMININ}
+	lookup := func(name string) (Type, bool) {
+		switch name {
+		case vektor.Name:
+			return vektor, true
+		case nummer.Name:
+			return nummer, true
+		}
+		return nil, false
+	}
+
+	testCases := []struct {
+		typ      Type
+		expected string
+	}{
+		{ZAHL, "Zahl"},
+		{KOMMAZAHL, "Kommazahl"},
+		{WAHRHEITSWERT, "Wahrheitswert"},
+		{BUCHSTABE, "Buchstabe"},
+		{TEXT, "Text"},
+		{VARIABLE, "Variable"},
+		{VoidType{}, "nichts"},
+		{ListType{Underlying: ZAHL}, "Zahlen Liste"},
+		{ListType{Underlying: KOMMAZAHL}, "Kommazahlen Liste"},
+		{ListType{Underlying: WAHRHEITSWERT}, "Wahrheitswert Liste"},
+		{ListType{Underlying: BUCHSTABE}, "Buchstaben Liste"},
+		{ListType{Underlying: TEXT}, "Text Liste"},
+		{ListType{Underlying: VARIABLE}, "Variablen Liste"},
+		{ListType{Underlying: ListType{Underlying: ZAHL}}, "Zahlen Liste Liste"},
+		{ListType{Underlying: ListType{Underlying: ListType{Underlying: TEXT}}}, "Text Liste Liste Liste"},
+		{vektor, "Vektor"},
+		{ListType{Underlying: vektor}, "Vektor Liste"},
+		{nummer, "Nummer"},
+		{ListType{Underlying: nummer}, "Nummer Liste"},
+		{OptionalType{Underlying: ZAHL}, "vielleicht Zahl"},
+		{OptionalType{Underlying: BUCHSTABE}, "vielleicht Buchstabe"},
+		{OptionalType{Underlying: nummer}, "vielleicht Nummer"},
+		{NewFunctionType(nil, VoidType{}), "Funktion() gibt nichts"},
+		{NewFunctionType([]Type{ZAHL, TEXT}, ZAHL), "Funktion(Zahl, Text) gibt Zahl"},
+		{NewFunctionType([]Type{vektor}, ListType{Underlying: vektor}), "Funktion(Vektor) gibt Vektor Liste"},
+		{NewFunctionType([]Type{ZAHL}, ListType{Underlying: ZAHL}), "Funktion(Zahl) gibt Zahlen Liste"},
+		{NewFunctionType([]Type{NewFunctionType([]Type{ZAHL}, ZAHL), ZAHL}, ZAHL), "Funktion(Funktion(Zahl) gibt Zahl, Zahl) gibt Zahl"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(testCase.expected, testCase.typ.String())
+
+		parsed, err := ParseType(testCase.typ.String(), lookup)
+		if assert.NoError(err, testCase.expected) {
+			assert.Equal(testCase.typ, parsed, testCase.expected)
+		}
+	}
+}
+
+func TestParseTypeError(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, s := range []string{"", "Vektor", "Zahl Liste", "nichts Liste", "Liste", "vielleicht Text", "vielleicht vielleicht Zahl", "vielleicht Zahl Liste", "Funktion(Zahl", "Funktion(Zahl)", "Funktion(nichts) gibt Zahl"} {
+		_, err := ParseType(s, nil)
+		assert.Error(err, s)
+	}
+}
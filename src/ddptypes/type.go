@@ -44,10 +44,24 @@ func GetUnderlying(t Type) Type {
 		return GetUnderlying(alias.Underlying)
 	} else if list, ok := t.(ListType); ok {
 		return ListType{Underlying: GetUnderlying(list.Underlying)}
+	} else if optional, ok := t.(OptionalType); ok {
+		return OptionalType{Underlying: GetUnderlying(optional.Underlying)}
+	} else if function, ok := t.(*FunctionType); ok {
+		return function.underlying
 	}
 	return t
 }
 
+// returns the name of t as used in error messages
+// if t is (or contains) a TypeAlias, the underlying type is appended
+// e.g. "Zeile (Kommazahlen Liste)"
+func Describe(t Type) string {
+	if underlying := GetUnderlying(t); underlying.String() != t.String() {
+		return t.String() + " (" + underlying.String() + ")"
+	}
+	return t.String()
+}
+
 func IsPrimitive(t Type) bool {
 	_, ok := GetUnderlying(t).(PrimitiveType)
 	return ok
@@ -65,6 +79,20 @@ func IsNumeric(t Type) bool {
 	return t == ZAHL || t == KOMMAZAHL
 }
 
+// checks wether two values of type t can be compared with GLEICH and UNGLEICH
+// every type that has values is comparable
+func IsComparable(t Type) bool {
+	return !IsVoid(t) && !IsInvalid(t)
+}
+
+// checks wether values of type t can be ordered with KLEINER, GRÖßER and ZWISCHEN
+// numeric types are ordered by their value,
+// Buchstaben by their codepoint and Texte lexicographically by their codepoints
+func IsOrdered(t Type) bool {
+	t = GetUnderlying(t)
+	return IsNumeric(t) || t == BUCHSTABE || t == TEXT
+}
+
 func IsList(t Type) bool {
 	_, ok := GetUnderlying(t).(ListType)
 	return ok
@@ -82,6 +110,12 @@ func IsVoid(t Type) bool {
 	return ok
 }
 
+// checks wether t is the type of an invalid expression (see InvalidType)
+func IsInvalid(t Type) bool {
+	_, ok := GetUnderlying(t).(InvalidType)
+	return ok
+}
+
 func IsPrimitiveOrVoid(t Type) bool {
 	return IsPrimitive(t) || IsVoid(t)
 }
@@ -98,6 +132,42 @@ func CastStruct(t Type) (*StructType, bool) {
 	return structType, ok
 }
 
+func IsEnum(t Type) bool {
+	_, ok := GetUnderlying(t).(*EnumType)
+	return ok
+}
+
+// acts like enumType, ok := t.(*EnumType)
+// but respects TypeAliases
+func CastEnum(t Type) (*EnumType, bool) {
+	enumType, ok := GetUnderlying(t).(*EnumType)
+	return enumType, ok
+}
+
+func IsFunction(t Type) bool {
+	_, ok := GetUnderlying(t).(*FunctionType)
+	return ok
+}
+
+// acts like functionType, ok := t.(*FunctionType)
+// but respects TypeAliases
+func CastFunction(t Type) (*FunctionType, bool) {
+	functionType, ok := GetUnderlying(t).(*FunctionType)
+	return functionType, ok
+}
+
+func IsOptional(t Type) bool {
+	_, ok := GetUnderlying(t).(OptionalType)
+	return ok
+}
+
+// acts like optionalType, ok := t.(OptionalType)
+// but respects TypeAliases
+func CastOptional(t Type) (OptionalType, bool) {
+	optionalType, ok := GetUnderlying(t).(OptionalType)
+	return optionalType, ok
+}
+
 func IsTypeAlias(t Type) bool {
 	_, ok := t.(*TypeAlias)
 	return ok
@@ -38,3 +38,106 @@ func TestStructurallyEqual(t *testing.T) {
 		}
 	}
 }
+
+func TestNewFunctionTypeUnique(t *testing.T) {
+	assert := assert.New(t)
+
+	f1 := NewFunctionType([]Type{ZAHL, TEXT}, ZAHL)
+	f2 := NewFunctionType([]Type{ZAHL, TEXT}, ZAHL)
+	assert.Same(f1, f2)
+	assert.True(Equal(f1, f2))
+
+	assert.NotSame(f1, NewFunctionType([]Type{ZAHL}, ZAHL))
+	assert.NotSame(f1, NewFunctionType([]Type{ZAHL, TEXT}, VoidType{}))
+	assert.NotSame(f1, NewFunctionType([]Type{TEXT, ZAHL}, ZAHL))
+	assert.Same(NewFunctionType(nil, VoidType{}), NewFunctionType([]Type{}, VoidType{}))
+}
+
+func TestNewFunctionTypeAlias(t *testing.T) {
+	assert := assert.New(t)
+	nummer := &TypeAlias{Name: "Nummer", Underlying: ZAHL, GramGender: FEMININ}
+
+	aliased := NewFunctionType([]Type{nummer}, ListType{Underlying: nummer})
+	plain := NewFunctionType([]Type{ZAHL}, ListType{Underlying: ZAHL})
+	assert.Equal("Funktion(Nummer) gibt Nummer Liste", aliased.String())
+	assert.Equal("Funktion(Nummer) gibt Zahlen Liste", NewFunctionType([]Type{nummer}, ListType{Underlying: ZAHL}).String())
+	assert.True(Equal(aliased, plain))
+	assert.True(DeepEqual(aliased, plain))
+	assert.Same(plain, GetUnderlying(aliased))
+	assert.Same(plain, GetUnderlying(plain))
+	assert.Equal("Funktion(Nummer) gibt Nummer Liste (Funktion(Zahl) gibt Zahlen Liste)", Describe(aliased))
+}
+
+func TestDescribe(t *testing.T) {
+	assert := assert.New(t)
+	zeile := &TypeAlias{Name: "Zeile", Underlying: ListType{Underlying: KOMMAZAHL}, GramGender: FEMININ}
+	nummer := &TypeAlias{Name: "Nummer", Underlying: ZAHL, GramGender: FEMININ}
+	hausnummer := &TypeAlias{Name: "Hausnummer", Underlying: nummer, GramGender: FEMININ}
+
+	assert.Equal("Zahl", Describe(ZAHL))
+	assert.Equal("Zahlen Liste", Describe(ListType{Underlying: ZAHL}))
+	assert.Equal("Zeile (Kommazahlen Liste)", Describe(zeile))
+	assert.Equal("Hausnummer (Zahl)", Describe(hausnummer))
+	assert.Equal("Nummer Liste (Zahlen Liste)", Describe(ListType{Underlying: nummer}))
+	assert.Equal("Nummer", Describe(&TypeDef{Name: "Nummer", Underlying: ZAHL, GramGender: FEMININ}))
+}
+
+func TestTypeCategories(t *testing.T) {
+	assert := assert.New(t)
+	testCases := []struct {
+		typ                          Type
+		numeric, comparable, ordered bool
+	}{
+		{ZAHL, true, true, true},
+		{KOMMAZAHL, true, true, true},
+		{WAHRHEITSWERT, false, true, false},
+		{BUCHSTABE, false, true, true},
+		{TEXT, false, true, true},
+		{ListType{Underlying: ZAHL}, false, true, false},
+		{OptionalType{Underlying: ZAHL}, false, true, false},
+		{fields(ZAHL), false, true, false},
+		{&EnumType{Name: "Farbe"}, false, true, false},
+		{NewFunctionType([]Type{ZAHL}, ZAHL), false, true, false},
+		{Variable{}, false, true, false},
+		{&TypeAlias{Underlying: ZAHL}, true, true, true},
+		{&TypeAlias{Underlying: TEXT}, false, true, true},
+		{&TypeAlias{Underlying: WAHRHEITSWERT}, false, true, false},
+		{&TypeDef{Underlying: KOMMAZAHL}, false, true, false},
+		{VoidType{}, false, false, false},
+		{InvalidType{}, false, false, false},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(testCase.numeric, IsNumeric(testCase.typ), "IsNumeric(%s)", testCase.typ)
+		assert.Equal(testCase.comparable, IsComparable(testCase.typ), "IsComparable(%s)", testCase.typ)
+		assert.Equal(testCase.ordered, IsOrdered(testCase.typ), "IsOrdered(%s)", testCase.typ)
+	}
+}
+
+func TestNestedListType(t *testing.T) {
+	assert := assert.New(t)
+	vektor := &StructType{Name: "Vektor", GramGender: MASKULIN}
+	nummer := &TypeAlias{Name: "Nummer", Underlying: ZAHL, GramGender: FEMININ}
+	hausnummer := &TypeDef{Name: "Hausnummer", Underlying: ZAHL, GramGender: FEMININ}
+
+	zahlenListeListe := ListType{Underlying: ListType{Underlying: ZAHL}}
+	assert.True(Equal(zahlenListeListe, ListType{Underlying: ListType{Underlying: ZAHL}}))
+	assert.False(Equal(zahlenListeListe, ListType{Underlying: ZAHL}))
+	assert.False(Equal(zahlenListeListe, ListType{Underlying: ListType{Underlying: KOMMAZAHL}}))
+	assert.False(Equal(zahlenListeListe, ListType{Underlying: ListType{Underlying: ListType{Underlying: ZAHL}}}))
+
+	// aliases are resolved in every level, typedefs only by DeepEqual
+	assert.True(Equal(zahlenListeListe, ListType{Underlying: ListType{Underlying: nummer}}))
+	assert.False(Equal(zahlenListeListe, ListType{Underlying: ListType{Underlying: hausnummer}}))
+	assert.True(DeepEqual(zahlenListeListe, ListType{Underlying: ListType{Underlying: hausnummer}}))
+
+	vektorListeListe := ListType{Underlying: ListType{Underlying: vektor}}
+	assert.True(Equal(vektorListeListe, ListType{Underlying: ListType{Underlying: vektor}}))
+	assert.False(Equal(vektorListeListe, ListType{Underlying: ListType{Underlying: &StructType{Name: "Vektor"}}}))
+	assert.Equal("Vektor Liste Liste", vektorListeListe.String())
+
+	assert.Equal(ListType{Underlying: ZAHL}, GetListUnderlying(zahlenListeListe))
+	assert.Equal(ZAHL, GetNestedListUnderlying(zahlenListeListe))
+	assert.Equal(vektor, GetNestedListUnderlying(vektorListeListe))
+	assert.Equal(ZAHL, ListTrueUnderlying(ListType{Underlying: ListType{Underlying: hausnummer}}))
+}
@@ -0,0 +1,211 @@
+package linter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+)
+
+// state of a single Lint call
+type linter struct {
+	module   *ast.Module
+	file     string // the file name used in the warnings
+	disabled []string
+	warnings []ddperror.Error // collected warnings, reported sorted by position
+	aliased  []token.Range    // ranges of function calls and struct literals, see checkCapitalization
+}
+
+func (l *linter) enabled(code ddperror.Code) bool {
+	rule, ok := ruleByCode(code)
+	return ok && !slices.Contains(l.disabled, rule.ID)
+}
+
+func (l *linter) warn(code ddperror.Code, Range token.Range, msg string) {
+	if l.enabled(code) {
+		l.warnings = append(l.warnings, ddperror.New(code, ddperror.LEVEL_WARN, Range, msg, l.file))
+	}
+}
+
+// checks the rules that need the AST of l.module
+func (l *linter) checkAst() {
+	collector := &usageCollector{
+		linter:   l,
+		ignored:  make(map[*ast.VarDecl]struct{}),
+		usedVars: make(map[*ast.VarDecl]struct{}),
+		usedFuns: make(map[*ast.FuncDecl]struct{}),
+	}
+	ast.VisitModule(l.module, collector)
+
+	for _, decl := range collector.vars {
+		if _, ok := collector.usedVars[decl]; !ok {
+			l.warn(ddperror.SEM_UNUSED_VARIABLE, decl.NameTok.Range, fmt.Sprintf("Die Variable '%s' wird nie benutzt", decl.Name()))
+		}
+	}
+	for _, decl := range collector.funs {
+		if _, ok := collector.usedFuns[decl]; !ok {
+			l.warn(ddperror.SEM_UNUSED_FUNCTION, decl.NameTok.Range, fmt.Sprintf("Die Funktion '%s' wird nie aufgerufen", decl.Name()))
+		}
+	}
+}
+
+// collects the declarations of a module and their uses
+// and reports unreachable code on the way
+type usageCollector struct {
+	ast.BaseVisitor
+	linter   *linter
+	vars     []*ast.VarDecl            // candidates for unused variables in order of declaration
+	funs     []*ast.FuncDecl           // candidates for unused functions in order of declaration
+	ignored  map[*ast.VarDecl]struct{} // struct fields and loop counters, which are not reported
+	usedVars map[*ast.VarDecl]struct{}
+	usedFuns map[*ast.FuncDecl]struct{}
+}
+
+var (
+	_ ast.VarDeclVisitor       = (*usageCollector)(nil)
+	_ ast.FuncDeclVisitor      = (*usageCollector)(nil)
+	_ ast.StructDeclVisitor    = (*usageCollector)(nil)
+	_ ast.IdentVisitor         = (*usageCollector)(nil)
+	_ ast.FuncCallVisitor      = (*usageCollector)(nil)
+	_ ast.StructLiteralVisitor = (*usageCollector)(nil)
+	_ ast.FuncRefVisitor       = (*usageCollector)(nil)
+	_ ast.BlockStmtVisitor     = (*usageCollector)(nil)
+	_ ast.ForStmtVisitor       = (*usageCollector)(nil)
+	_ ast.ForRangeStmtVisitor  = (*usageCollector)(nil)
+)
+
+func (c *usageCollector) VisitVarDecl(decl *ast.VarDecl) ast.VisitResult {
+	if _, ok := c.ignored[decl]; !ok && !decl.IsPublic && !decl.IsExternVisible {
+		c.vars = append(c.vars, decl)
+	}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitFuncDecl(decl *ast.FuncDecl) ast.VisitResult {
+	// operators are called implicitly and public or extern visible functions may be called from outside the module
+	if !decl.IsPublic && !decl.IsExternVisible && decl.Operator == nil {
+		c.funs = append(c.funs, decl)
+	}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitStructDecl(decl *ast.StructDecl) ast.VisitResult {
+	for _, field := range decl.Fields {
+		if field, ok := field.(*ast.VarDecl); ok {
+			c.ignored[field] = struct{}{}
+		}
+	}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitIdent(expr *ast.Ident) ast.VisitResult {
+	if expr.Declaration != nil {
+		c.usedVars[expr.Declaration] = struct{}{}
+	}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitFuncCall(expr *ast.FuncCall) ast.VisitResult {
+	if expr.Func != nil {
+		c.usedFuns[expr.Func] = struct{}{}
+	}
+	c.linter.aliased = append(c.linter.aliased, expr.Range)
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResult {
+	c.linter.aliased = append(c.linter.aliased, expr.Range)
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitFuncRef(expr *ast.FuncRef) ast.VisitResult {
+	if expr.Func != nil {
+		c.usedFuns[expr.Func] = struct{}{}
+	}
+	return ast.VisitRecurse
+}
+
+// statements after a return, break or continue statement are never executed
+func (c *usageCollector) VisitBlockStmt(stmt *ast.BlockStmt) ast.VisitResult {
+	for i, s := range stmt.Statements[:max(len(stmt.Statements)-1, 0)] {
+		switch s.(type) {
+		case *ast.ReturnStmt, *ast.BreakContinueStmt:
+			unreachable := token.Range{
+				Start: stmt.Statements[i+1].GetRange().Start,
+				End:   stmt.Statements[len(stmt.Statements)-1].GetRange().End,
+			}
+			c.linter.warn(ddperror.SEM_UNREACHABLE_CODE, unreachable, "Dieser Code wird nie ausgeführt")
+			return ast.VisitRecurse
+		}
+	}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitForStmt(stmt *ast.ForStmt) ast.VisitResult {
+	c.ignored[stmt.Initializer] = struct{}{}
+	return ast.VisitRecurse
+}
+
+func (c *usageCollector) VisitForRangeStmt(stmt *ast.ForRangeStmt) ast.VisitResult {
+	c.ignored[stmt.Initializer] = struct{}{}
+	return ast.VisitRecurse
+}
+
+// keywords inside a sentence are written in lowercase
+// the scanner already reports lowercase letters at the start of a sentence
+// tokens inside function calls and struct literals are not checked, as they are written like their alias
+func (l *linter) checkCapitalization(tokens []token.Token) {
+	if !l.enabled(ddperror.SYN_UNEXPECTED_CAPITAL) {
+		return
+	}
+
+	sentenceStart, line := true, uint(0)
+	for _, tok := range tokens {
+		if tok.Type == token.COMMENT {
+			continue
+		}
+
+		// the first token on a line may start a sentence too (e.g. after the aliases of a function)
+		if tok.Line() != line {
+			sentenceStart, line = true, tok.Line()
+		}
+
+		if !sentenceStart && isCapitalizedKeyword(tok) && !l.isAliased(tok.Range) {
+			l.warn(ddperror.SYN_UNEXPECTED_CAPITAL, tok.Range, fmt.Sprintf("'%s' wird innerhalb eines Satzes klein geschrieben", tok.Literal))
+		}
+
+		// after these tokens a new sentence may start
+		switch tok.Type {
+		case token.DOT, token.COLON, token.COMMA, token.DANN, token.SONST, token.MACHE:
+			sentenceStart = true
+		default:
+			sentenceStart = false
+		}
+	}
+}
+
+// wether tok is a keyword that is only a keyword in lowercase
+// keywords like Zahl or Text are always capitalized
+// "nach Links/Rechts verschoben" is written capitalized throughout the Duden, so it is allowed too
+func isCapitalizedKeyword(tok token.Token) bool {
+	if tok.Type == token.IDENTIFIER || tok.Type == token.LINKS || tok.Type == token.RECHTS || tok.Literal == "" || !unicode.IsUpper([]rune(tok.Literal)[0]) {
+		return false
+	}
+	if _, ok := token.KeywordMap[tok.Literal]; ok {
+		return false
+	}
+	return token.KeywordToTokenType(strings.ToLower(tok.Literal)) == tok.Type
+}
+
+func (l *linter) isAliased(Range token.Range) bool {
+	for _, aliased := range l.aliased {
+		if !Range.Start.IsBefore(aliased.Start) && !aliased.End.IsBefore(Range.End) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// Package linter runs the front-end passes on a module and reports
+// the optional warnings of the enabled lint rules
+package linter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/DDP-Projekt/Kompilierer/src/scanner"
+)
+
+type Options struct {
+	// Optional Filename to name the source
+	// this file is read if Source is nil
+	FileName string
+	// Optional ddp-source-code
+	// if nil, FileName is read
+	Source []byte
+	// receives the errors of all passes and the warnings of the enabled rules
+	// May be nil
+	ErrorHandler ddperror.Handler
+	// IDs of the rules whose warnings are not reported
+	Disabled []string
+}
+
+func validateOptions(options *Options) error {
+	if options.Source == nil && options.FileName == "" {
+		return errors.New("Kein Quellcode gegeben")
+	}
+	if options.ErrorHandler == nil {
+		options.ErrorHandler = ddperror.EmptyHandler
+	}
+	for _, id := range options.Disabled {
+		if _, ok := RuleByID(id); !ok {
+			return fmt.Errorf("Unbekannte Regel '%s'", id)
+		}
+	}
+	return nil
+}
+
+// scans and parses the source from options once and reports
+// the warnings of the enabled rules to options.ErrorHandler
+// the rules that need the AST are only checked on the given module
+// (not on the modules it includes) and only if no errors were found
+func Lint(options Options) (*ast.Module, error) {
+	if err := validateOptions(&options); err != nil {
+		return nil, fmt.Errorf("Ungültige Linter Optionen: %w", err)
+	}
+
+	errored := false
+	handler := MakeRuleFilter(options.ErrorHandler, options.Disabled...)
+	errorHandler := func(err ddperror.Error) {
+		if err.Level == ddperror.LEVEL_ERROR {
+			errored = true
+		}
+		handler(err)
+	}
+
+	tokens, err := scanner.Scan(scanner.Options{
+		FileName:     options.FileName,
+		Source:       options.Source,
+		ScannerMode:  scanner.ModeStrictCapitalization,
+		ErrorHandler: errorHandler,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Scannen: %w", err)
+	}
+
+	module, err := parser.Parse(parser.Options{
+		FileName:     options.FileName,
+		Source:       options.Source,
+		Tokens:       tokens,
+		ErrorHandler: errorHandler,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Fehler beim Parsen: %w", err)
+	}
+
+	if errored || module.Ast.Faulty {
+		return module, nil
+	}
+
+	l := &linter{module: module, file: options.FileName, disabled: options.Disabled}
+	l.checkAst()
+	l.checkCapitalization(tokens)
+
+	// the analyses report in different orders, so sort them by position
+	sort.SliceStable(l.warnings, func(i, j int) bool {
+		return l.warnings[i].Range.Start.IsBefore(l.warnings[j].Range.Start)
+	})
+	for _, warning := range l.warnings {
+		handler(warning)
+	}
+	return module, nil
+}
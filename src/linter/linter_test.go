@@ -0,0 +1,100 @@
+package linter
+
+import (
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/stretchr/testify/assert"
+)
+
+// a warning reduced to what the tests compare
+type warning struct {
+	code ddperror.Code
+	line uint
+}
+
+func lint(t *testing.T, src string, disabled ...string) []warning {
+	t.Helper()
+	var warnings []warning
+	_, err := Lint(Options{
+		FileName: "test.ddp",
+		Source:   []byte(src),
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Level != ddperror.LEVEL_WARN {
+				t.Errorf("unexpected error: %s", err.Msg)
+				return
+			}
+			warnings = append(warnings, warning{err.Code, err.Range.Start.Line})
+		},
+		Disabled: disabled,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return warnings
+}
+
+const lintSrc = `Die Zahl x ist 1.
+Die Zahl y ist 2.
+Die Funktion f gibt nichts zurück, macht:
+	Verlasse die Funktion.
+	Speichere x in y.
+Und kann so benutzt werden:
+	"f"
+Die Funktion g mit dem Parameter p vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib x Plus 1 zurück.
+Und kann so benutzt werden:
+	"g von <p>"
+Die Zahl z ist g von 2.
+Für jede Zahl i von 1 bis z, mache:
+	Verlasse die Schleife.
+	Erhöhe z um 1.
+Wenn (z gleich 1 ist) gleich wahr ist, dann:
+	Erhöhe z um 2.
+z.
+...
+`
+
+func TestLint(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]warning{
+		{ddperror.TYP_REDUNDANT_BOOL_COMPARISON, 16},
+		{ddperror.SEM_EXPR_WITHOUT_EFFECT, 18},
+		{ddperror.SEM_TODO_STMT_FOUND, 19},
+		{ddperror.SEM_UNUSED_FUNCTION, 3},
+		{ddperror.SEM_UNREACHABLE_CODE, 5},
+		{ddperror.SYN_UNEXPECTED_CAPITAL, 9},
+		{ddperror.SEM_UNREACHABLE_CODE, 15},
+	}, lint(t, lintSrc), "the parser warnings come first, then the lint warnings sorted by position")
+
+	assert.Equal([]warning{
+		{ddperror.SEM_UNUSED_FUNCTION, 3},
+		{ddperror.SYN_UNEXPECTED_CAPITAL, 9},
+	}, lint(t, lintSrc, "redundant-bool-comparison", "no-effect", "todo", "unreachable-code"))
+}
+
+func TestLintUnusedVar(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal([]warning{{ddperror.SEM_UNUSED_VARIABLE, 1}}, lint(t, "Die Zahl x ist 1.\n"))
+	assert.Empty(lint(t, "Die Zahl x ist 1.\n", "unused-var"))
+	assert.Empty(lint(t, "Die öffentliche Zahl x ist 1.\n"), "public variables may be used by other modules")
+	assert.Empty(lint(t, "Die Zahl x ist 1.\nErhöhe x um 1.\n"))
+	assert.Empty(lint(t, "Für jede Zahl i von 1 bis 2, mache:\n\t1 plus 1.\n", "no-effect"), "loop counters are not reported")
+	assert.Empty(lint(t, "Wir nennen die öffentliche Kombination aus\n\tder öffentlichen Zahl a mit Standardwert 1,\nEinen Punkt, und erstellen sie so:\n\t\"ein Punkt\"\n"), "struct fields are not reported")
+}
+
+func TestLintUnknownRule(t *testing.T) {
+	_, err := Lint(Options{Source: []byte("1.\n"), Disabled: []string{"unused-variable"}})
+	assert.Error(t, err)
+}
+
+func TestRulesDistinct(t *testing.T) {
+	ids, codes := map[string]struct{}{}, map[ddperror.Code]struct{}{}
+	for _, rule := range Rules {
+		ids[rule.ID], codes[rule.Code] = struct{}{}, struct{}{}
+	}
+	assert.Len(t, ids, len(Rules))
+	assert.Len(t, codes, len(Rules))
+}
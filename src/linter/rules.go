@@ -0,0 +1,62 @@
+package linter
+
+import (
+	"slices"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+)
+
+// a lint rule that can be disabled by its ID
+// every rule reports warnings with exactly one code
+type Rule struct {
+	ID   string        // stable name of the rule used on the command line (e.g. unused-var)
+	Code ddperror.Code // the code of the warnings reported for this rule
+	Desc string        // short german description of the rule
+}
+
+// all lint rules in the order they are listed by kddp lint
+// the IDs must not be changed, as they are used in scripts and build files
+var Rules = []Rule{
+	{ID: "unused-var", Code: ddperror.SEM_UNUSED_VARIABLE, Desc: "Variablen, die nie benutzt werden"},
+	{ID: "unused-func", Code: ddperror.SEM_UNUSED_FUNCTION, Desc: "Nicht öffentliche Funktionen, die nie aufgerufen werden"},
+	{ID: "redundant-bool-comparison", Code: ddperror.TYP_REDUNDANT_BOOL_COMPARISON, Desc: "Vergleiche von Wahrheitswerten mit wahr oder falsch"},
+	{ID: "unreachable-code", Code: ddperror.SEM_UNREACHABLE_CODE, Desc: "Anweisungen nach 'gib ... zurück', 'verlasse die Funktion', 'fahre mit der Schleife fort' oder 'verlasse die Schleife'"},
+	{ID: "no-effect", Code: ddperror.SEM_EXPR_WITHOUT_EFFECT, Desc: "Ausdrücke ohne Wirkung"},
+	{ID: "capitalization", Code: ddperror.SYN_UNEXPECTED_CAPITAL, Desc: "Großgeschriebene Schlüsselwörter innerhalb eines Satzes"},
+	{ID: "precision-loss", Code: ddperror.TYP_PRECISION_LOSS, Desc: "Zahlen, die nicht genau als Kommazahl dargestellt werden können"},
+	{ID: "inexact-step", Code: ddperror.TYP_INEXACT_STEP_SIZE, Desc: "Schrittgrößen von Kommazahl-Schleifen, die nicht genau dargestellt werden können"},
+	{ID: "todo", Code: ddperror.SEM_TODO_STMT_FOUND, Desc: "Platzhalter (...)"},
+}
+
+// returns the rule with the given ID
+func RuleByID(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// returns the rule that reports warnings with the given code
+func ruleByCode(code ddperror.Code) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.Code == code {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// wraps handler so that warnings of the disabled rules are not passed to it
+// warnings that do not belong to any rule and errors are always passed on
+func MakeRuleFilter(handler ddperror.Handler, disabled ...string) ddperror.Handler {
+	return func(err ddperror.Error) {
+		if err.Level == ddperror.LEVEL_WARN {
+			if rule, ok := ruleByCode(err.Code); ok && slices.Contains(disabled, rule.ID) {
+				return
+			}
+		}
+		handler(err)
+	}
+}
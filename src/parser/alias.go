@@ -43,7 +43,7 @@ func (p *parser) alias() ast.Expression {
 
 		if tok.Type == token.ALIAS_PARAMETER {
 			switch t := p.peek(); t.Type {
-			case token.INT, token.FLOAT, token.TRUE, token.FALSE, token.CHAR, token.STRING, token.IDENTIFIER, token.SYMBOL:
+			case token.INT, token.FLOAT, token.TRUE, token.FALSE, token.CHAR, token.STRING, token.IDENTIFIER, token.SYMBOL, token.NICHTS:
 				p.advance()
 				return tok, true
 			case token.NEGATE:
@@ -149,7 +149,7 @@ func (p *parser) alias() ast.Expression {
 					exprStart := p.cur
 					isGrouping := false
 					switch pType {
-					case token.INT, token.FLOAT, token.TRUE, token.FALSE, token.CHAR, token.STRING, token.IDENTIFIER, token.SYMBOL:
+					case token.INT, token.FLOAT, token.TRUE, token.FALSE, token.CHAR, token.STRING, token.IDENTIFIER, token.SYMBOL, token.NICHTS:
 						p.advance() // single-token argument
 					case token.NEGATE:
 						p.advance()
@@ -211,7 +211,7 @@ func (p *parser) alias() ast.Expression {
 					typ := p.typechecker.EvaluateSilent(cached_arg.Arg) // evaluate the argument
 
 					didMatch := true
-					if !ddptypes.Equal(typ, paramType.Type) {
+					if !ddptypes.Equal(typ, paramType.Type) && !isOptionalArgument(cached_arg.Arg, typ, paramType) {
 						didMatch = false
 					} else if ass, ok := cached_arg.Arg.(*ast.Indexing);                           // string-indexings may not be passed as char-reference
 					paramType.IsReference && ddptypes.Equal(paramType.Type, ddptypes.BUCHSTABE) && // if the parameter is a char-reference
@@ -236,13 +236,22 @@ func (p *parser) alias() ast.Expression {
 	}
 
 	callOrLiteralFromAlias := func(alias ast.Alias, args map[string]ast.Expression) ast.Expression {
+		// record the order in which the arguments appeared
+		argOrder := make([]string, 0, len(args))
+		for _, tok := range alias.GetTokens() {
+			if tok.Type == token.ALIAS_PARAMETER {
+				argOrder = append(argOrder, strings.Trim(tok.Literal, "<>"))
+			}
+		}
+
 		if fnalias, isFuncAlias := alias.(*ast.FuncAlias); isFuncAlias {
 			fnCall := &ast.FuncCall{
-				Range: token.NewRange(&p.tokens[start], p.previous()),
-				Tok:   p.tokens[start],
-				Name:  fnalias.Func.Name(),
-				Func:  fnalias.Func,
-				Args:  args,
+				Range:    token.NewRange(&p.tokens[start], p.previous()),
+				Tok:      p.tokens[start],
+				Name:     fnalias.Func.Name(),
+				Func:     fnalias.Func,
+				Args:     args,
+				ArgOrder: argOrder,
 			}
 
 			if fnalias.Negated {
@@ -259,10 +268,11 @@ func (p *parser) alias() ast.Expression {
 
 		stralias := alias.(*ast.StructAlias)
 		return &ast.StructLiteral{
-			Range:  token.NewRange(&p.tokens[start], p.previous()),
-			Tok:    p.tokens[start],
-			Struct: stralias.Struct,
-			Args:   args,
+			Range:    token.NewRange(&p.tokens[start], p.previous()),
+			Tok:      p.tokens[start],
+			Struct:   stralias.Struct,
+			Args:     args,
+			ArgOrder: argOrder,
 		}
 	}
 
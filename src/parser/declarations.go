@@ -41,10 +41,7 @@ func (p *parser) assignRhs() ast.Expression {
 			if tok.Type == token.FALSE {
 				rhs := p.expression() // the actual boolean expression after falsch wenn, which is negated
 				expr = &ast.UnaryExpr{
-					Range: token.Range{
-						Start: token.NewStartPos(tok),
-						End:   rhs.GetRange().End,
-					},
+					Range:    token.Merge(tok.Range, rhs.GetRange()),
 					Tok:      *tok,
 					Operator: ast.UN_NOT,
 					Rhs:      rhs,
@@ -82,9 +79,19 @@ func (p *parser) varDeclaration(startDepth int, isField bool) ast.Declaration {
 	}
 
 	type_start := p.peek()
-	typ := p.parseType()
+	var typ ddptypes.Type
+	typeInferred := !isField && p.isInferredVarDecl()
+	if typeInferred {
+		p.advance() // Wert
+	} else {
+		typ = p.parseType()
+	}
 	type_end := p.previous()
-	if typ == nil {
+	if typeInferred {
+		if begin.Type != token.DER {
+			p.err(ddperror.SYN_GENDER_MISMATCH, begin.Range, fmt.Sprintf("Falscher Artikel, meintest du %s?", token.DER))
+		}
+	} else if typ == nil {
 		p.err(ddperror.SYN_EXPECTED_TYPENAME, token.NewRange(type_start, p.previous()), fmt.Sprintf("Invalider Typname %s", p.previous()))
 	} else {
 		getArticle := func(gender ddptypes.GrammaticalGender) token.TokenType {
@@ -134,7 +141,7 @@ func (p *parser) varDeclaration(startDepth int, isField bool) ast.Declaration {
 	}
 	var expr ast.Expression
 
-	if !ddptypes.Equal(typ, ddptypes.WAHRHEITSWERT) && ddptypes.IsList(typ) { // TODO: fix this with function calls and groupings
+	if !typeInferred && !ddptypes.Equal(typ, ddptypes.WAHRHEITSWERT) && ddptypes.IsList(typ) { // TODO: fix this with function calls and groupings
 		expr = p.expression()
 		if p.matchAny(token.COUNT_MAL) {
 			value := p.expression()
@@ -170,9 +177,21 @@ func (p *parser) varDeclaration(startDepth int, isField bool) ast.Declaration {
 		IsExternVisible: isExternVisible,
 		Mod:             p.module,
 		InitVal:         expr,
+		TypeInferred:    typeInferred,
 	}
 }
 
+// wether the tokens after the article are "Wert <Name> ist"
+// which declares a variable whose type is inferred from its initial value
+// a type called Wert takes precedence
+func (p *parser) isInferredVarDecl() bool {
+	if p.peek().Type != token.IDENTIFIER || p.peek().Literal != "Wert" || p.peekN(1).Type != token.IDENTIFIER || p.peekN(2).Type != token.IST {
+		return false
+	}
+	_, isType := p.scope().LookupType("Wert")
+	return !isType
+}
+
 // helper for parsing function declarations
 // a paramName is allowed if it does not override a struct or function declaration
 // other variables are allowed to be overridden because of name-shadowing
@@ -212,7 +231,7 @@ func (p *parser) parseFunctionParameters(perr func(ddperror.Code, token.Range, s
 		// helper function to avoid too much repitition
 		addParamName := func(name *token.Token) {
 			if containsName(params, name.Literal) { // check that each parameter name is unique
-				perr(ddperror.SEM_NAME_ALREADY_DEFINED, name.Range, fmt.Sprintf("Ein Parameter mit dem Namen '%s' ist bereits vorhanden", name.Literal))
+				perr(ddperror.SEM_NAME_ALREADY_DEFINED, name.Range, fmt.Sprintf("Der Parameter '%s' wurde mehrfach angegeben", name.Literal))
 				return
 			}
 			if !p.paramNameAllowed(name) { // check that the parameter name is not already used
@@ -454,8 +473,8 @@ func (p *parser) funcDeclaration(startDepth int) ast.Statement {
 	}
 
 	// early error report if the name is already used
-	if _, existed, _ := p.scope().LookupDecl(funcName.Literal); existed {
-		p.err(ddperror.SEM_NAME_ALREADY_DEFINED, funcName.Range, ddperror.MsgNameAlreadyExists(funcName.Literal))
+	if existing, existed, _ := p.scope().LookupDecl(funcName.Literal); existed {
+		p.err(ddperror.SEM_NAME_ALREADY_DEFINED, funcName.Range, ddperror.MsgNameAlreadyExists(funcName.Literal), ast.DeclaredHereNote(existing, funcName.Literal))
 	}
 
 	// parse the parameter declaration
@@ -520,7 +539,7 @@ func (p *parser) funcDeclaration(startDepth int) ast.Statement {
 	aliasEnd := p.cur // save the end of the function declaration for later
 
 	if !ast.IsGlobalScope(p.scope()) {
-		perr(ddperror.SEM_NON_GLOBAL_FUNCTION, begin.Range, "Es können nur globale Funktionen deklariert werden")
+		perr(ddperror.SEM_NON_GLOBAL_FUNCTION, begin.Range, ddperror.MSG_NON_GLOBAL_FUNCTION)
 	}
 
 	if !valid {
@@ -637,7 +656,7 @@ func (p *parser) funcDefinition(begin, nameTok *token.Token) ast.Statement {
 	}
 
 	if !ast.IsGlobalScope(p.scope()) {
-		p.err(ddperror.SEM_NON_GLOBAL_FUNCTION, p.previous().Range, "Funktionen müssen global definiert werden")
+		p.err(ddperror.SEM_NON_GLOBAL_FUNCTION, p.previous().Range, ddperror.MSG_NON_GLOBAL_FUNCTION)
 		return nil
 	}
 
@@ -939,6 +958,84 @@ func (p *parser) structDeclaration() ast.Declaration {
 	return decl
 }
 
+// parses an enum declaration like
+// Die Aufzählung Farbe ist rot, grün und blau.
+// the gender of the enum is feminine unless an article is given before the name
+// (Die Aufzählung der Wochentag ist ...)
+// startDepth is the int passed to p.peekN(n) to get to the DIE token of the declaration
+func (p *parser) enumDeclaration(startDepth int) ast.Declaration {
+	begin := p.peekN(startDepth) // token.DIE
+	comment := p.parseDeclComment(begin.Range)
+
+	isPublic := p.peekN(startDepth+1).Type == token.OEFFENTLICHE
+	if begin.Type != token.DIE {
+		p.err(ddperror.SYN_GENDER_MISMATCH, begin.Range, fmt.Sprintf("Falscher Artikel, meintest du %s?", token.DIE))
+	}
+
+	var gender ddptypes.GrammaticalGender = ddptypes.FEMININ
+	if p.matchAny(token.DER, token.DIE, token.DAS) {
+		switch p.previous().Type {
+		case token.DER:
+			gender = ddptypes.MASKULIN
+		case token.DAS:
+			gender = ddptypes.NEUTRUM
+		}
+	}
+
+	if !p.consume(token.IDENTIFIER) {
+		return &ast.BadDecl{
+			Err: ddperror.New(ddperror.SYN_EXPECTED_IDENTIFIER, ddperror.LEVEL_ERROR, token.NewRange(begin, p.peek()), "Es wurde ein Aufzählungs Name erwartet", p.module.FileName),
+			Tok: *p.peek(),
+			Mod: p.module,
+		}
+	}
+	name := p.previous()
+
+	if _, exists := p.scope().LookupType(name.Literal); exists {
+		p.err(ddperror.SEM_NAME_ALREADY_DEFINED, name.Range, fmt.Sprintf("Ein Typ mit dem Namen '%s' existiert bereits", name.Literal))
+	}
+
+	p.consume(token.IST)
+
+	// parse the variants
+	var variants []token.Token
+	for {
+		if !p.consume(token.IDENTIFIER) {
+			break
+		}
+		variants = append(variants, *p.previous())
+		if p.matchAny(token.UND) {
+			p.consume(token.IDENTIFIER)
+			variants = append(variants, *p.previous())
+			break
+		}
+		if !p.matchAny(token.COMMA) {
+			break
+		}
+	}
+	p.consume(token.DOT)
+
+	variantNames := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		variantNames = append(variantNames, variant.Literal)
+	}
+
+	return &ast.EnumDecl{
+		Range:      token.NewRange(begin, p.previous()),
+		CommentTok: comment,
+		Tok:        *begin,
+		NameTok:    *name,
+		IsPublic:   isPublic,
+		Mod:        p.module,
+		Variants:   variants,
+		Type: &ddptypes.EnumType{
+			Name:       name.Literal,
+			GramGender: gender,
+			Variants:   variantNames,
+		},
+	}
+}
+
 func (p *parser) typeAliasDecl() ast.Declaration {
 	begin := p.previous() // Wir
 	comment := p.parseDeclComment(begin.Range)
@@ -1030,15 +1127,14 @@ func (p *parser) aliasDecl() ast.Statement {
 	p.consume(token.STEHT, token.FÜR, token.DIE, token.FUNKTION, token.IDENTIFIER)
 	fun := p.previous()
 
-	decl, ok, isVar := p.scope().LookupDecl(fun.Literal)
-	if !ok {
+	funDecl, _, isFunc := p.scope().LookupFunc(fun.Literal)
+	if _, exists, _ := p.scope().LookupDecl(fun.Literal); !exists {
 		p.err(ddperror.SEM_NAME_UNDEFINED, fun.Range, fmt.Sprintf("Der Name %s wurde noch nicht deklariert", fun.Literal))
 		return nil
-	} else if isVar {
-		p.err(ddperror.SEM_BAD_NAME_CONTEXT, fun.Range, fmt.Sprintf("Der Name %s steht für eine Variable und nicht für eine Funktion", fun.Literal))
+	} else if !isFunc {
+		p.err(ddperror.SEM_BAD_NAME_CONTEXT, fun.Range, fmt.Sprintf("Der Name %s steht für eine Variable oder Struktur und nicht für eine Funktion", fun.Literal))
 		return nil
 	}
-	funDecl := decl.(*ast.FuncDecl)
 
 	// map function parameters to their type (given to the alias if it is valid)
 	paramTypes := make(map[string]ddptypes.ParameterType, 4)
@@ -88,6 +88,37 @@ func createParser(test *testing.T, overrider parser) *parser {
 	return &parser
 }
 
+// parses src as the module main.ddp
+// and returns the module together with all errors (and warnings) passed to the ErrorHandler
+func parseSource(t *testing.T, src string) (*ast.Module, []ddperror.Error) {
+	t.Helper()
+	var errors []ddperror.Error
+	module, err := Parse(Options{
+		FileName: "main.ddp",
+		Source:   []byte(src),
+		ErrorHandler: func(err ddperror.Error) {
+			errors = append(errors, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return module, errors
+}
+
+// like parseSource but only returns errors of level LEVEL_ERROR
+func parseSourceErrors(t *testing.T, src string) (*ast.Module, []ddperror.Error) {
+	t.Helper()
+	module, all := parseSource(t, src)
+	var errors []ddperror.Error
+	for _, err := range all {
+		if err.Level == ddperror.LEVEL_ERROR {
+			errors = append(errors, err)
+		}
+	}
+	return module, errors
+}
+
 func createTokens(args ...any) (result []token.Token) {
 	range_index := uint(0)
 	for i, arg := range args {
@@ -142,6 +173,37 @@ func TestTypeAliasDecl(t *testing.T) {
 	assert.Equal(decl.Type, &ddptypes.TypeAlias{Name: "Nummer", Underlying: ddptypes.ZAHL, GramGender: ddptypes.FEMININ})
 }
 
+func TestEnumDecl(t *testing.T) {
+	assert := assert.New(t)
+	given := createParser(t,
+		parser{
+			tokens: createTokens(
+				token.DIE, token.AUFZÄHLUNG, token.DER,
+				token.IDENTIFIER, "Wochentag",
+				token.IST,
+				token.IDENTIFIER, "Montag", token.COMMA,
+				token.IDENTIFIER, "Dienstag", token.UND,
+				token.IDENTIFIER, "Mittwoch",
+				token.DOT,
+			),
+		},
+	)
+	given.cur = 2 // skip DIE and AUFZÄHLUNG
+
+	raw_decl := given.enumDeclaration(-2)
+	assert.NotNil(raw_decl)
+	decl := raw_decl.(*ast.EnumDecl)
+
+	assert.Equal(given.tokens[0], decl.Tok)
+	assert.Equal(given.tokens[3], decl.NameTok)
+	assert.False(decl.IsPublic)
+	assert.Equal(given.module, decl.Mod)
+	assert.Equal([]token.Token{given.tokens[5], given.tokens[7], given.tokens[9]}, decl.Variants)
+	assert.Equal(&ddptypes.EnumType{Name: "Wochentag", GramGender: ddptypes.MASKULIN, Variants: []string{"Montag", "Dienstag", "Mittwoch"}}, decl.Type)
+	assert.Equal(2, decl.Type.VariantIndex("Mittwoch"))
+	assert.Equal(-1, decl.Type.VariantIndex("Wochentag"))
+}
+
 func TestTypeAliasDeclError(t *testing.T) {
 	assert := assert.New(t)
 	panicMode := false
@@ -35,7 +35,7 @@ func (p *parser) expression() ast.Expression {
 	return p.ifExpression()
 }
 
-// <a> wenn <b>, sonst <c>
+// <a>, falls <b>, ansonsten <c>
 func (p *parser) ifExpression() ast.Expression {
 	expr := p.boolXOR()
 	for p.matchSeq(token.COMMA, token.FALLS) {
@@ -44,10 +44,7 @@ func (p *parser) ifExpression() ast.Expression {
 		p.consume(token.COMMA, token.ANSONSTEN)
 		other := p.ifExpression()
 		expr = &ast.TernaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   other.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), other.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Mid:      cond,
@@ -66,10 +63,7 @@ func (p *parser) boolXOR() ast.Expression {
 		p.consume(token.COMMA, token.ODER)
 		rhs := p.boolOR()
 		return &ast.BinaryExpr{
-			Range: token.Range{
-				Start: tok.Range.Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(tok.Range, rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      lhs,
 			Operator: ast.BIN_XOR,
@@ -85,10 +79,7 @@ func (p *parser) boolOR() ast.Expression {
 		tok := p.previous()
 		rhs := p.boolAND()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: ast.BIN_OR,
@@ -104,10 +95,7 @@ func (p *parser) boolAND() ast.Expression {
 		tok := p.previous()
 		rhs := p.bitwiseOR()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: ast.BIN_AND,
@@ -123,10 +111,7 @@ func (p *parser) bitwiseOR() ast.Expression {
 		tok := p.previous()
 		rhs := p.bitwiseXOR()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: ast.BIN_LOGIC_OR,
@@ -142,10 +127,7 @@ func (p *parser) bitwiseXOR() ast.Expression {
 		tok := p.previous()
 		rhs := p.bitwiseAND()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: ast.BIN_LOGIC_XOR,
@@ -161,10 +143,7 @@ func (p *parser) bitwiseAND() ast.Expression {
 		tok := p.previous()
 		rhs := p.equality()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: ast.BIN_LOGIC_AND,
@@ -176,7 +155,7 @@ func (p *parser) bitwiseAND() ast.Expression {
 
 func (p *parser) equality() ast.Expression {
 	expr := p.comparison()
-	for p.matchAny(token.GLEICH, token.UNGLEICH, token.EIN, token.EINE, token.KEIN, token.KEINE) {
+	for p.matchAny(token.GLEICH, token.UNGLEICH, token.EIN, token.EINE, token.KEIN, token.KEINE, token.VORHANDEN) || p.matchSeq(token.NICHT, token.VORHANDEN) {
 		tok := p.previous()
 
 		bin_operator := ast.BIN_EQUAL
@@ -187,10 +166,7 @@ func (p *parser) equality() ast.Expression {
 		case token.GLEICH:
 			rhs := p.comparison()
 			expr = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: expr.GetRange().Start,
-					End:   rhs.GetRange().End,
-				},
+				Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 				Tok:      *tok,
 				Lhs:      expr,
 				Operator: bin_operator,
@@ -199,10 +175,7 @@ func (p *parser) equality() ast.Expression {
 		case token.EIN, token.EINE, token.KEIN, token.KEINE:
 			checkType := p.parseType()
 			expr = &ast.TypeCheck{
-				Range: token.Range{
-					Start: expr.GetRange().Start,
-					End:   p.previous().Range.End,
-				},
+				Range:     token.Merge(expr.GetRange(), p.previous().Range),
 				Tok:       *tok,
 				CheckType: checkType,
 				Lhs:       expr,
@@ -224,6 +197,21 @@ func (p *parser) equality() ast.Expression {
 					p.err(ddperror.SYN_GENDER_MISMATCH, token.NewRange(tok, tok), "Meintest du 'eine'?")
 				}
 			}
+		case token.VORHANDEN: // x vorhanden ist, x nicht vorhanden ist
+			expr = &ast.UnaryExpr{
+				Range:    token.Merge(expr.GetRange(), tok.Range),
+				Tok:      *tok,
+				Operator: ast.UN_PRESENT,
+				Rhs:      expr,
+			}
+			if p.peekN(-2).Type == token.NICHT {
+				expr = &ast.UnaryExpr{
+					Range:    expr.GetRange(),
+					Tok:      *p.peekN(-2),
+					Operator: ast.UN_NOT,
+					Rhs:      expr,
+				}
+			}
 		}
 
 		if p.previous().Type != token.IST {
@@ -246,10 +234,7 @@ func (p *parser) comparison() ast.Expression {
 
 			// expr > mid && expr < rhs
 			expr = &ast.TernaryExpr{
-				Range: token.Range{
-					Start: expr.GetRange().Start,
-					End:   rhs.GetRange().End,
-				},
+				Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 				Lhs:      expr,
 				Mid:      mid,
 				Rhs:      rhs,
@@ -272,10 +257,7 @@ func (p *parser) comparison() ast.Expression {
 
 			rhs := p.bitShift()
 			expr = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: expr.GetRange().Start,
-					End:   rhs.GetRange().End,
-				},
+				Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 				Tok:      *tok,
 				Lhs:      expr,
 				Operator: operator,
@@ -309,10 +291,7 @@ func (p *parser) bitShift() ast.Expression {
 			operator = ast.BIN_RIGHT_SHIFT
 		}
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: operator,
@@ -325,21 +304,25 @@ func (p *parser) bitShift() ast.Expression {
 
 func (p *parser) term() ast.Expression {
 	expr := p.factor()
-	for p.matchAny(token.PLUS, token.MINUS, token.VERKETTET) {
+	for p.matchAny(token.PLUS, token.MINUS, token.VERKETTET, token.ABGEBILDET, token.GEFILTERT) {
 		tok := p.previous()
 		operator := ast.BIN_PLUS
-		if tok.Type == token.VERKETTET { // string concatenation
+		switch tok.Type {
+		case token.VERKETTET: // string concatenation
 			p.consume(token.MIT)
 			operator = ast.BIN_CONCAT
-		} else if tok.Type == token.MINUS {
+		case token.ABGEBILDET:
+			p.consume(token.MIT)
+			operator = ast.BIN_MAP
+		case token.GEFILTERT:
+			p.consume(token.MIT)
+			operator = ast.BIN_FILTER
+		case token.MINUS:
 			operator = ast.BIN_MINUS
 		}
 		rhs := p.factor()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: operator,
@@ -361,10 +344,7 @@ func (p *parser) factor() ast.Expression {
 		}
 		rhs := p.unary()
 		expr = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: expr.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(expr.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      expr,
 			Operator: operator,
@@ -458,10 +438,7 @@ func (p *parser) unary() ast.Expression {
 		}
 		rhs := p.unary()
 		return &ast.UnaryExpr{
-			Range: token.Range{
-				Start: token.NewStartPos(start),
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(start.Range, rhs.GetRange()),
 			Tok:      *tok,
 			Operator: operator,
 			Rhs:      rhs,
@@ -475,10 +452,7 @@ func (p *parser) negate() ast.Expression {
 		tok := p.previous()
 		rhs := p.negate()
 		return &ast.UnaryExpr{
-			Range: token.Range{
-				Start: token.NewStartPos(tok),
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(tok.Range, rhs.GetRange()),
 			Tok:      *tok,
 			Operator: ast.UN_NEGATE,
 			Rhs:      rhs,
@@ -491,7 +465,8 @@ func (p *parser) negate() ast.Expression {
 // TODO: check precedence
 func (p *parser) power(lhs ast.Expression) ast.Expression {
 	// TODO: grammar
-	if lhs == nil && p.matchAny(token.DIE, token.DER) {
+	// "die Funktion f" is a function value and parsed in primary
+	if lhs == nil && p.peekN(1).Type != token.FUNKTION && p.matchAny(token.DIE, token.DER) {
 		if p.matchAny(token.LOGARITHMUS) {
 			tok := p.previous()
 			p.consume(token.VON)
@@ -500,10 +475,7 @@ func (p *parser) power(lhs ast.Expression) ast.Expression {
 			rhs := p.unary()
 
 			lhs = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: numerus.GetRange().Start,
-					End:   rhs.GetRange().End,
-				},
+				Range:    token.Merge(numerus.GetRange(), rhs.GetRange()),
 				Tok:      *tok,
 				Lhs:      numerus,
 				Operator: ast.BIN_LOG,
@@ -518,10 +490,7 @@ func (p *parser) power(lhs ast.Expression) ast.Expression {
 			expr := p.unary()
 
 			lhs = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: expr.GetRange().Start,
-					End:   lhs.GetRange().End,
-				},
+				Range:    token.Merge(expr.GetRange(), lhs.GetRange()),
 				Tok:      *tok,
 				Lhs:      expr,
 				Operator: ast.BIN_POW,
@@ -544,10 +513,7 @@ func (p *parser) power(lhs ast.Expression) ast.Expression {
 		tok := p.previous()
 		rhs := p.unary()
 		lhs = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: lhs.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(lhs.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      lhs,
 			Operator: ast.BIN_POW,
@@ -559,7 +525,7 @@ func (p *parser) power(lhs ast.Expression) ast.Expression {
 
 func (p *parser) slicing(lhs ast.Expression) ast.Expression {
 	lhs = p.indexing(lhs)
-	for p.matchAny(token.IM, token.BIS, token.AB) {
+	for p.matchAny(token.IM, token.BIS, token.AB, token.AUFGEFÜLLT) {
 		switch p.previous().Type {
 		// im Bereich von ... bis ...
 		case token.IM:
@@ -569,10 +535,7 @@ func (p *parser) slicing(lhs ast.Expression) ast.Expression {
 			p.consume(token.BIS)
 			rhs := p.indexing(nil)
 			lhs = &ast.TernaryExpr{
-				Range: token.Range{
-					Start: lhs.GetRange().Start,
-					End:   rhs.GetRange().End,
-				},
+				Range:    token.Merge(lhs.GetRange(), rhs.GetRange()),
 				Tok:      *von,
 				Lhs:      lhs,
 				Mid:      mid,
@@ -587,10 +550,7 @@ func (p *parser) slicing(lhs ast.Expression) ast.Expression {
 			}
 			rhs := p.expression()
 			lhs = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: lhs.GetRange().Start,
-					End:   token.NewEndPos(p.previous()),
-				},
+				Range:    token.Merge(lhs.GetRange(), p.previous().Range),
 				Tok:      rhs.Token(),
 				Lhs:      lhs,
 				Rhs:      rhs,
@@ -602,16 +562,28 @@ func (p *parser) slicing(lhs ast.Expression) ast.Expression {
 			p.consume(token.DEM)
 			rhs := p.expression()
 			lhs = &ast.BinaryExpr{
-				Range: token.Range{
-					Start: lhs.GetRange().Start,
-					End:   token.NewEndPos(p.previous()),
-				},
+				Range:    token.Merge(lhs.GetRange(), p.previous().Range),
 				Tok:      rhs.Token(),
 				Lhs:      lhs,
 				Rhs:      rhs,
 				Operator: ast.BIN_SLICE_FROM,
 			}
 			p.consume(token.DOT, token.ELEMENT)
+			// l aufgefüllt bis zur Länge n mit w
+		case token.AUFGEFÜLLT:
+			tok := p.previous()
+			p.consume(token.BIS, token.ZUR, token.LÄNGE)
+			mid := p.expression()
+			p.consume(token.MIT)
+			rhs := p.indexing(nil)
+			lhs = &ast.TernaryExpr{
+				Range:    token.Merge(lhs.GetRange(), rhs.GetRange()),
+				Tok:      *tok,
+				Lhs:      lhs,
+				Mid:      mid,
+				Rhs:      rhs,
+				Operator: ast.TER_PAD,
+			}
 		}
 	}
 	return lhs
@@ -624,10 +596,7 @@ func (p *parser) indexing(lhs ast.Expression) ast.Expression {
 		tok := p.previous()
 		rhs := p.field_access(nil)
 		lhs = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: lhs.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(lhs.GetRange(), rhs.GetRange()),
 			Tok:      *tok,
 			Lhs:      lhs,
 			Operator: ast.BIN_INDEX,
@@ -645,10 +614,7 @@ func (p *parser) field_access(lhs ast.Expression) ast.Expression {
 		von := p.previous()
 		rhs := p.field_access(nil) // recursive call to enable x von y von z (right-associative)
 		lhs = &ast.BinaryExpr{
-			Range: token.Range{
-				Start: lhs.GetRange().Start,
-				End:   rhs.GetRange().End,
-			},
+			Range:    token.Merge(lhs.GetRange(), rhs.GetRange()),
 			Tok:      *von,
 			Lhs:      lhs,
 			Operator: ast.BIN_FIELD_ACCESS,
@@ -663,10 +629,7 @@ func (p *parser) type_cast(lhs ast.Expression) ast.Expression {
 	for p.matchAny(token.ALS) {
 		Type := p.parseType()
 		lhs = &ast.CastExpr{
-			Range: token.Range{
-				Start: lhs.GetRange().Start,
-				End:   token.NewEndPos(p.previous()),
-			},
+			Range:      token.Merge(lhs.GetRange(), p.previous().Range),
 			TargetType: Type,
 			Lhs:        lhs,
 		}
@@ -687,6 +650,20 @@ func (p *parser) primary(lhs ast.Expression) ast.Expression {
 		return lhs
 	}
 
+	// function used as value
+	if p.matchSeq(token.DIE, token.FUNKTION) {
+		begin := p.peekN(-2)
+		p.consume(token.IDENTIFIER)
+		// errors for unknown names are reported by the resolver
+		funcDecl, _, _ := p.scope().LookupFunc(p.previous().Literal)
+		return &ast.FuncRef{
+			Range: token.NewRange(begin, p.previous()),
+			Tok:   *begin,
+			Name:  *p.previous(),
+			Func:  funcDecl,
+		}
+	}
+
 	switch tok := p.advance(); tok.Type {
 	case token.FALSE:
 		lhs = &ast.BoolLit{Literal: *p.previous(), Value: false}
@@ -706,13 +683,25 @@ func (p *parser) primary(lhs ast.Expression) ast.Expression {
 		lit := p.previous()
 		lhs = &ast.CharLit{Literal: *lit, Value: p.parseChar(lit.Literal)}
 	case token.STRING:
-		lit := p.previous()
-		lhs = &ast.StringLit{Literal: *lit, Value: p.parseString(lit.Literal)}
+		lhs = p.stringLiteral(p.previous())
+	case token.NICHTS:
+		lhs = &ast.NothingLit{Literal: *p.previous()}
 	case token.LPAREN:
 		lhs = p.grouping()
 	case token.IDENTIFIER:
-		lhs = &ast.Ident{
-			Literal: *p.previous(),
+		if enumDecl, isVariant := p.scope().LookupEnumVariant(p.previous().Literal); isVariant {
+			lhs = &ast.EnumLit{
+				Literal: *p.previous(),
+				Decl:    enumDecl,
+				Index:   enumDecl.Type.VariantIndex(p.previous().Literal),
+			}
+		} else {
+			lhs = &ast.Ident{
+				Literal: *p.previous(),
+			}
+			if p.peek().Type == token.LPAREN && p.isFunctionVar(p.previous().Literal) {
+				lhs = p.funcValueCall(lhs)
+			}
 		}
 	// TODO: grammar
 	case token.EINE, token.EINER: // list literals
@@ -759,8 +748,45 @@ func (p *parser) primary(lhs ast.Expression) ast.Expression {
 
 // alias
 
+// wether name is a variable of function type
+// in which case name followed by a ( is a call of that function
+func (p *parser) isFunctionVar(name string) bool {
+	if decl, _, isVar := p.scope().LookupVar(name); isVar {
+		return ddptypes.IsFunction(ddptypes.TrueUnderlying(decl.Type))
+	}
+	return false
+}
+
+// parses the arguments of a call to a function value like f(1, 2)
+// expects the next token to be the opening paren
+func (p *parser) funcValueCall(callee ast.Expression) ast.Expression {
+	p.consume(token.LPAREN)
+	var args []ast.Expression
+	if !p.matchAny(token.RPAREN) {
+		args = append(args, p.expression())
+		for p.matchAny(token.COMMA) {
+			args = append(args, p.expression())
+		}
+		p.consume(token.RPAREN)
+	}
+
+	return &ast.FuncValueCall{
+		Range:  token.Merge(callee.GetRange(), p.previous().Range),
+		Callee: callee,
+		Args:   args,
+	}
+}
+
 func (p *parser) grouping() ast.Expression {
 	lParen := p.previous()
+	// () is reported as a whole instead of complaining about the )
+	if p.matchAny(token.RPAREN) {
+		p.err(ddperror.SYN_EMPTY_GROUPING, token.NewRange(lParen, p.previous()), "Zwischen den Klammern fehlt ein Ausdruck")
+		return &ast.BadExpr{
+			Err: p.lastError,
+			Tok: *lParen,
+		}
+	}
 	innerExpr := p.expression()
 	p.consume(token.RPAREN)
 
@@ -777,7 +803,8 @@ func (p *parser) grouping() ast.Expression {
 func (p *parser) assigneable() ast.Assigneable {
 	var assigneable_impl func(bool) ast.Assigneable
 	assigneable_impl = func(isInFieldAcess bool) ast.Assigneable {
-		isParenthesized := p.previous().Type == token.LPAREN
+		lParen := p.previous()
+		isParenthesized := lParen.Type == token.LPAREN
 		if isParenthesized {
 			p.consume(token.IDENTIFIER)
 		}
@@ -790,6 +817,7 @@ func (p *parser) assigneable() ast.Assigneable {
 			if p.matchAny(token.IDENTIFIER) {
 				rhs := assigneable_impl(true)
 				ass = &ast.FieldAccess{
+					Range: token.Merge(ident.GetRange(), rhs.GetRange()),
 					Rhs:   rhs,
 					Field: ident,
 				}
@@ -797,6 +825,7 @@ func (p *parser) assigneable() ast.Assigneable {
 				p.consume(token.LPAREN)
 				rhs := assigneable_impl(false)
 				ass = &ast.FieldAccess{
+					Range: token.Merge(ident.GetRange(), p.previous().Range), // p.previous() is the closing paren
 					Rhs:   rhs,
 					Field: ident,
 				}
@@ -808,6 +837,7 @@ func (p *parser) assigneable() ast.Assigneable {
 				p.consume(token.DER, token.STELLE)
 				index := p.unary() // TODO: check if this can stay p.expression or if p.unary is better
 				ass = &ast.Indexing{
+					Range: token.Merge(ass.GetRange(), index.GetRange()),
 					Lhs:   ass,
 					Index: index,
 				}
@@ -819,6 +849,13 @@ func (p *parser) assigneable() ast.Assigneable {
 
 		if isParenthesized {
 			p.consume(token.RPAREN)
+			// underline the parens as well
+			switch ass := ass.(type) {
+			case *ast.Indexing:
+				ass.Range = token.NewRange(lParen, p.previous())
+			case *ast.FieldAccess:
+				ass.Range = token.NewRange(lParen, p.previous())
+			}
 		}
 		return ass
 	}
@@ -860,8 +897,11 @@ func (p *parser) parseChar(s string) (r rune) {
 
 // helper to parse ddp strings with escape sequences
 func (p *parser) parseString(s string) string {
-	str := strings.TrimPrefix(strings.TrimSuffix(s, "\""), "\"") // remove the ""
+	return p.unescapeString(strings.TrimPrefix(strings.TrimSuffix(s, "\""), "\"")) // remove the ""
+}
 
+// replaces the escape sequences in str
+func (p *parser) unescapeString(str string) string {
 	for i, w := 0, 0; i < len(str); i += w {
 		var r rune
 		r, w = utf8.DecodeRuneInString(str[i:])
@@ -878,7 +918,7 @@ func (p *parser) parseString(s string) string {
 				seq = '\r'
 			case 't':
 				seq = '\t'
-			case '"':
+			case '"', '{', '}':
 			case '\\':
 			default:
 				p.err(ddperror.SYN_MALFORMED_LITERAL, p.previous().Range, fmt.Sprintf("Ungültige Escape Sequenz '\\%s' im Text Literal", string(seq)))
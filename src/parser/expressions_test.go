@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/scanner"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func scanTokens(t *testing.T, src string) []token.Token {
+	tokens, err := scanner.Scan(scanner.Options{
+		FileName:     t.Name(),
+		Source:       []byte(src),
+		ScannerMode:  scanner.ModeNone,
+		ErrorHandler: testHandler(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens
+}
+
+func newRange(startLine, startCol, endLine, endCol uint) token.Range {
+	return token.Range{
+		Start: token.Position{Line: startLine, Column: startCol},
+		End:   token.Position{Line: endLine, Column: endCol},
+	}
+}
+
+func TestAssigneableRange(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected token.Range
+	}{
+		{"s an der Stelle 1", newRange(1, 1, 1, 18)},
+		{"(s an der Stelle 1)", newRange(1, 1, 1, 20)},
+		{"s an der Stelle 1, an der Stelle 2", newRange(1, 1, 1, 35)},
+		{"f von s", newRange(1, 1, 1, 8)},
+		{"(f von s)", newRange(1, 1, 1, 10)},
+		{"f von (s)", newRange(1, 1, 1, 10)},
+		{"f von g von s", newRange(1, 1, 1, 14)},
+		{"f von s an der Stelle 2", newRange(1, 1, 1, 24)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			assert := assert.New(t)
+			given := createParser(t, parser{
+				tokens: scanTokens(t, test.src),
+			})
+			given.cur = 1 // assigneable expects the first token to be consumed
+
+			ass := given.assigneable()
+			assert.Equal(test.expected, ass.GetRange())
+			assert.Equal(token.EOF, given.peek().Type)
+		})
+	}
+}
+
+func TestAssigneableRangeNested(t *testing.T) {
+	assert := assert.New(t)
+	given := createParser(t, parser{
+		tokens: scanTokens(t, "f von s an der Stelle 2"),
+	})
+	given.cur = 1
+
+	indexing, ok := given.assigneable().(*ast.Indexing)
+	assert.True(ok)
+	assert.Equal(newRange(1, 1, 1, 8), indexing.Lhs.GetRange())
+	assert.Equal(newRange(1, 23, 1, 24), indexing.Index.GetRange())
+
+	fieldAccess, ok := indexing.Lhs.(*ast.FieldAccess)
+	assert.True(ok)
+	assert.Equal(newRange(1, 7, 1, 8), fieldAccess.Rhs.GetRange())
+}
+
+func TestSplitInterpolatedString(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected []stringPart
+	}{
+		{`"Hallo"`, []stringPart{{text: "Hallo", start: token.Position{Line: 1, Column: 2}}}},
+		{`"a{b}c"`, []stringPart{
+			{text: "a", start: token.Position{Line: 1, Column: 2}},
+			{text: "b", isExpr: true, start: token.Position{Line: 1, Column: 4}},
+			{text: "c", start: token.Position{Line: 1, Column: 6}},
+		}},
+		{`"{"}" verkettet mit x}\{"`, []stringPart{
+			{text: `"}" verkettet mit x`, isExpr: true, start: token.Position{Line: 1, Column: 3}},
+			{text: `\{`, start: token.Position{Line: 1, Column: 23}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			tokens := scanTokens(t, test.src)
+			assert.Equal(t, test.expected, splitInterpolatedString(&tokens[0]))
+		})
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime/debug"
+	"time"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddppath"
+	"github.com/DDP-Projekt/Kompilierer/src/parser/resolver"
 	"github.com/DDP-Projekt/Kompilierer/src/scanner"
 	"github.com/DDP-Projekt/Kompilierer/src/token"
 )
@@ -34,6 +37,38 @@ type Options struct {
 	// Annotators that are used to annotate the AST with additional information
 	// They are called after the parsing is done
 	Annotators []ast.Annotator
+	// directories that are searched in order for included modules
+	// which are neither from the Duden nor relative to the including file
+	// if nil, only ddppath.Lib is searched
+	IncludePaths []string
+	// Optional, the time spent in each phase is added to it
+	// including the time spent on imported modules
+	Timings *Timings
+	// the scanner stops after this many errors (see scanner.Options.MaxErrors)
+	// the errors of all imported modules are counted together
+	// 0 means no limit
+	MaxScannerErrors uint
+	// Optional, the number of errors reported by the scanner
+	// including the errors of imported modules
+	ScannerErrors *uint
+	// Optional, filled with the declaration of every usage in the parsed module
+	// (see resolver.SymbolIndex), imported modules are not indexed
+	SymbolIndex resolver.SymbolIndex
+}
+
+// the time spent in the different phases of parsing
+// resolving and typechecking happen interleaved with parsing
+// and are therefore not included in Parsing
+type Timings struct {
+	Scanning     time.Duration
+	Parsing      time.Duration
+	Resolving    time.Duration
+	Typechecking time.Duration
+}
+
+// the sum of all phases
+func (t *Timings) Total() time.Duration {
+	return t.Scanning + t.Parsing + t.Resolving + t.Typechecking
 }
 
 func (options *Options) ToScannerOptions(scannerMode scanner.Mode) scanner.Options {
@@ -42,6 +77,8 @@ func (options *Options) ToScannerOptions(scannerMode scanner.Mode) scanner.Optio
 		Source:       options.Source,
 		ScannerMode:  scannerMode,
 		ErrorHandler: options.ErrorHandler,
+		MaxErrors:    options.MaxScannerErrors,
+		ErrorCount:   options.ScannerErrors,
 	}
 }
 
@@ -55,6 +92,15 @@ func validateOptions(options *Options) error {
 	if options.ErrorHandler == nil {
 		options.ErrorHandler = ddperror.EmptyHandler
 	}
+	if options.IncludePaths == nil {
+		options.IncludePaths = []string{ddppath.Lib}
+	}
+	if options.Timings == nil {
+		options.Timings = &Timings{}
+	}
+	if options.ScannerErrors == nil {
+		options.ScannerErrors = new(uint)
+	}
 	return nil
 }
 
@@ -69,14 +115,28 @@ func Parse(options Options) (module *ast.Module, err error) {
 		return nil, fmt.Errorf("Ungültige Parser Optionen: %w", err)
 	}
 
+	// everything that is not measured separately (including nested Parse calls for imports)
+	// is counted as parsing
+	start, measuredBefore := time.Now(), options.Timings.Total()
+	defer func() {
+		options.Timings.Parsing += time.Since(start) - (options.Timings.Total() - measuredBefore)
+	}()
+
 	if options.Tokens == nil {
+		scanStart := time.Now()
 		options.Tokens, err = scanner.Scan(options.ToScannerOptions(scanner.ModeStrictCapitalization))
+		options.Timings.Scanning += time.Since(scanStart)
 		if err != nil {
 			return nil, fmt.Errorf("Fehler beim Scannen: %w", err)
 		}
 	}
 
-	module = newParser(options.FileName, options.Tokens, options.Modules, options.ErrorHandler).parse()
+	parser := newParser(options.FileName, options.Tokens, options.Modules, options.ErrorHandler)
+	parser.includePaths = options.IncludePaths
+	parser.timings = options.Timings
+	parser.maxScannerErrors, parser.scannerErrors = options.MaxScannerErrors, options.ScannerErrors
+	parser.resolver.Index = options.SymbolIndex
+	module = parser.parse()
 	if options.FileName != "" {
 		path, err := filepath.Abs(options.FileName)
 		if err != nil {
@@ -0,0 +1,191 @@
+/*
+This file defines the functions used to parse string literals with interpolations like "Hallo {name}"
+*/
+package parser
+
+import (
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+	"github.com/DDP-Projekt/Kompilierer/src/scanner"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+)
+
+// a part of a string literal
+// either text (still containing escape sequences)
+// or the source code of an interpolated expression
+type stringPart struct {
+	text   string
+	isExpr bool
+	start  token.Position // position of the first character of text in the source file
+}
+
+// splits the literal of a STRING token into its text and {expr} parts
+// the scanner already made sure that every { is closed
+func splitInterpolatedString(lit *token.Token) []stringPart {
+	runes := []rune(lit.Literal)
+	if len(runes) >= 2 {
+		runes = runes[1 : len(runes)-1] // remove the ""
+	}
+
+	pos := token.Position{Line: lit.Range.Start.Line, Column: lit.Range.Start.Column + 1}
+	advance := func(r rune) {
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+
+	parts := make([]stringPart, 0, 1)
+	current := stringPart{start: pos}
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '\\':
+			current.text += string(r)
+			advance(r)
+			if i+1 < len(runes) {
+				i++
+				current.text += string(runes[i])
+				advance(runes[i])
+			}
+		case '{':
+			advance(r)
+			if current.text != "" {
+				parts = append(parts, current)
+			}
+
+			end := matchingBrace(runes, i+1)
+			current = stringPart{text: string(runes[i+1 : end]), isExpr: true, start: pos}
+			parts = append(parts, current)
+			for _, r := range runes[i+1 : min(end+1, len(runes))] {
+				advance(r)
+			}
+			i = end
+			current = stringPart{start: pos}
+		default:
+			current.text += string(r)
+			advance(r)
+		}
+	}
+	if current.text != "" || len(parts) == 0 {
+		parts = append(parts, current)
+	}
+	return parts
+}
+
+// returns the index of the } that closes the interpolation starting at runes[start]
+// skips strings and characters inside the interpolation like the scanner does
+func matchingBrace(runes []rune, start int) int {
+	depth := 1
+	for i := start; i < len(runes); i++ {
+		switch runes[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '"', '\'':
+			quote := runes[i]
+			for i++; i < len(runes) && runes[i] != quote; i++ {
+				if runes[i] == '\\' {
+					i++
+				} else if quote == '"' && runes[i] == '{' {
+					i = matchingBrace(runes, i+1)
+				}
+			}
+		}
+	}
+	return len(runes)
+}
+
+// parses a string literal
+// if it contains interpolations, the result is a concatenation of
+// the text parts and the interpolated expressions converted to Text
+func (p *parser) stringLiteral(lit *token.Token) ast.Expression {
+	parts := splitInterpolatedString(lit)
+	if len(parts) == 1 && !parts[0].isExpr {
+		return &ast.StringLit{Literal: *lit, Value: p.parseString(lit.Literal)}
+	}
+
+	var result ast.Expression
+	for _, part := range parts {
+		var expr ast.Expression
+		if part.isExpr {
+			expr = p.interpolatedExpression(lit, part)
+		} else {
+			expr = &ast.StringLit{Literal: *lit, Value: p.unescapeString(part.text)}
+		}
+
+		if result == nil {
+			result = expr
+			continue
+		}
+		result = &ast.BinaryExpr{
+			Range:    lit.Range,
+			Tok:      *lit,
+			Lhs:      result,
+			Operator: ast.BIN_CONCAT,
+			Rhs:      expr,
+		}
+	}
+	return result
+}
+
+// parses the expression of an interpolation and converts it to Text
+func (p *parser) interpolatedExpression(lit *token.Token, part stringPart) ast.Expression {
+	// moves positions from the interpolation source to the original source file
+	shift := func(pos token.Position) token.Position {
+		if pos.Line == 1 {
+			pos.Column += part.start.Column - 1
+		}
+		pos.Line += part.start.Line - 1
+		return pos
+	}
+
+	tokens, err := scanner.Scan(scanner.Options{
+		FileName:    p.module.FileName,
+		Source:      []byte(part.text),
+		ScannerMode: scanner.ModeNone,
+		ErrorHandler: func(err ddperror.Error) {
+			err.Range.Start, err.Range.End = shift(err.Range.Start), shift(err.Range.End)
+			p.errorHandler(err)
+		},
+	})
+	if err != nil {
+		p.err(ddperror.SYN_MALFORMED_LITERAL, lit.Range, err.Error())
+		return &ast.StringLit{Literal: *lit} // an empty Text prevents follow up errors
+	}
+
+	// filter the comments and move the tokens to their place in the source file
+	exprTokens := make([]token.Token, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.Type == token.COMMENT {
+			continue
+		}
+		tok.Range.Start, tok.Range.End = shift(tok.Range.Start), shift(tok.Range.End)
+		exprTokens = append(exprTokens, tok)
+	}
+
+	if len(exprTokens) == 1 { // only EOF
+		p.err(ddperror.SYN_MALFORMED_LITERAL, lit.Range, "Eine Interpolation in einem Text braucht einen Ausdruck")
+		return &ast.StringLit{Literal: *lit} // an empty Text prevents follow up errors
+	}
+
+	tokensBack, curBack := p.tokens, p.cur
+	p.tokens, p.cur = exprTokens, 0
+	expr := p.expression()
+	if !p.atEnd() {
+		p.err(ddperror.SYN_UNEXPECTED_TOKEN, p.peek().Range, ddperror.MsgGotExpected(p.peek().Literal, "das Ende der Interpolation"))
+	}
+	p.tokens, p.cur = tokensBack, curBack
+
+	return &ast.CastExpr{
+		Range:      expr.GetRange(),
+		TargetType: ddptypes.TEXT,
+		Lhs:        expr,
+	}
+}
@@ -5,9 +5,11 @@ package parser
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
@@ -34,6 +36,8 @@ type parser struct {
 	module *ast.Module
 	// modules that were passed as environment, might not all be used
 	predefinedModules map[string]*ast.Module
+	// directories that are searched for included modules (see Options.IncludePaths)
+	includePaths []string
 	// all found aliases (+ inbuild aliases)
 	aliases *at.Trie[*token.Token, ast.Alias]
 	// function which is currently being parsed
@@ -48,6 +52,12 @@ type parser struct {
 	resolver *resolver.Resolver
 	// used to typecheck every node directly after it has been parsed
 	typechecker *typechecker.Typechecker
+	// the time spent resolving and typechecking is added to it
+	timings *Timings
+	// passed to the Parse calls of imported modules
+	// so that the scanner error limit applies to all of them together
+	maxScannerErrors uint
+	scannerErrors    *uint
 }
 
 // returns a new parser, ready to parse the provided tokens
@@ -113,6 +123,9 @@ func newParser(name string, tokens []token.Token, modules map[string]*ast.Module
 		errored:               false,
 		resolver:              &resolver.Resolver{},
 		typechecker:           &typechecker.Typechecker{},
+		timings:               &Timings{},
+		maxScannerErrors:      0,
+		scannerErrors:         new(uint),
 	}
 
 	// wrap the errorHandler to set the parsers Errored variable
@@ -181,8 +194,15 @@ func (p *parser) declaration() ast.Statement {
 			p.advance()
 			return p.aliasDecl()
 		case token.FUNKTION:
+			// "Die Funktion(Zahl) gibt Zahl f ist ..." declares a variable of function type
+			if p.peekN(1).Type == token.LPAREN {
+				return &ast.DeclStmt{Decl: p.varDeclaration(n, false)}
+			}
 			p.advance()
 			return p.funcDeclaration(n - 1)
+		case token.AUFZÄHLUNG:
+			p.advance()
+			return &ast.DeclStmt{Decl: p.enumDeclaration(n - 1)}
 		default:
 			return &ast.DeclStmt{Decl: p.varDeclaration(n, false)}
 		}
@@ -199,8 +219,12 @@ func (p *parser) checkStatement(stmt ast.Statement) {
 	if importStmt, ok := stmt.(*ast.ImportStmt); ok {
 		p.resolveModuleImport(importStmt)
 	}
-	p.resolver.ResolveNode(stmt)      // resolve symbols in it (variables, functions, ...)
+	start := time.Now()
+	p.resolver.ResolveNode(stmt) // resolve symbols in it (variables, functions, ...)
+	resolved := time.Now()
 	p.typechecker.TypecheckNode(stmt) // typecheck the node
+	p.timings.Resolving += resolved.Sub(start)
+	p.timings.Typechecking += time.Since(resolved)
 }
 
 // fils out importStmt.Module and updates the parser state accordingly
@@ -221,32 +245,49 @@ func (p *parser) resolveModuleImport(importStmt *ast.ImportStmt) {
 		inclPath = filepath.Join(ddppath.InstallDir, rawPath) + ".ddp"
 	} else {
 		inclPath, err = filepath.Abs(filepath.Join(filepath.Dir(p.module.FileName), rawPath+".ddp"))
+		// if the file does not exist relative to the current module
+		// we look for it in the include paths
+		if _, statErr := os.Stat(inclPath); err == nil && statErr != nil {
+			if libPath, found := p.searchIncludePaths(rawPath + ".ddp"); found {
+				inclPath = libPath
+			}
+		}
 	}
 
 	if err != nil {
 		p.err(ddperror.SYN_MALFORMED_INCLUDE_PATH, importStmt.FileName.Range, fmt.Sprintf("Fehlerhafter Dateipfad '%s': \"%s\"", rawPath+".ddp", err.Error()))
 		return
 	} else if module, ok := p.predefinedModules[inclPath]; !ok { // the module is new
+		if _, err := os.Stat(inclPath); err != nil {
+			p.err(ddperror.SYN_INCLUDE_NOT_FOUND, importStmt.FileName.Range, fmt.Sprintf("Die Datei '%s' konnte nicht gefunden werden", rawPath+".ddp"))
+			return
+		}
+		importStmt.Path = inclPath
 		p.predefinedModules[inclPath] = nil // already add the name to the map to not import it infinetly
 		// parse the new module
 		importStmt.Module, err = Parse(Options{
-			FileName:     inclPath,
-			Source:       nil,
-			Tokens:       nil,
-			Modules:      p.predefinedModules,
-			ErrorHandler: p.errorHandler,
+			FileName:         inclPath,
+			Source:           nil,
+			Tokens:           nil,
+			Modules:          p.predefinedModules,
+			ErrorHandler:     p.errorHandler,
+			IncludePaths:     p.includePaths,
+			Timings:          p.timings,
+			MaxScannerErrors: p.maxScannerErrors,
+			ScannerErrors:    p.scannerErrors,
 		})
 
 		// add the module to the list and to the importStmt
 		// or report the error
 		if err != nil {
-			p.err(ddperror.MISC_INCLUDE_ERROR, importStmt.Range, fmt.Sprintf("Fehler beim einbinden von '%s': %s", rawPath+".ddp", err.Error()))
+			p.err(ddperror.MISC_INCLUDE_ERROR, importStmt.Range, fmt.Sprintf("Fehler beim Einbinden von '%s': %s", rawPath+".ddp", err.Error()))
 			return // return early on error
 		} else {
 			importStmt.Module.FileNameToken = &importStmt.FileName
 			p.predefinedModules[inclPath] = importStmt.Module
 		}
 	} else { // we already included the module
+		importStmt.Path = inclPath
 		// circular import error
 		if module == nil {
 			p.err(ddperror.MISC_INCLUDE_ERROR, importStmt.Range, fmt.Sprintf("Zwei Module dürfen sich nicht gegenseitig einbinden! Das Modul '%s' versuchte das Modul '%s' einzubinden, während es von diesem Module eingebunden wurde", p.module.GetIncludeFilename(), rawPath+".ddp"))
@@ -293,6 +334,20 @@ func (p *parser) resolveModuleImport(importStmt *ast.ImportStmt) {
 	})
 }
 
+// returns the absolute path of the first file named path in one of p.includePaths
+func (p *parser) searchIncludePaths(path string) (string, bool) {
+	for _, dir := range p.includePaths {
+		inclPath, err := filepath.Abs(filepath.Join(dir, path))
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(inclPath); err == nil {
+			return inclPath, true
+		}
+	}
+	return "", false
+}
+
 func (p *parser) validateForwardDecls() {
 	ast.VisitModule(p.module, ast.FuncDeclVisitorFunc(func(decl *ast.FuncDecl) ast.VisitResult {
 		if decl.Body == nil && decl.ExternFile.Type == token.ILLEGAL && decl.Def == nil {
@@ -371,8 +426,11 @@ func (p *parser) errVal(err ddperror.Error) {
 }
 
 // helper to report errors and enter panic mode
-func (p *parser) err(code ddperror.Code, Range token.Range, msg string) {
-	p.errVal(ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, p.module.FileName))
+// notes are attached to the error
+func (p *parser) err(code ddperror.Code, Range token.Range, msg string, notes ...ddperror.Note) {
+	err := ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, p.module.FileName)
+	err.Notes = notes
+	p.errVal(err)
 }
 
 // helper to report errors and enter panic mode
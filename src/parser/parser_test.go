@@ -0,0 +1,1069 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+	"github.com/DDP-Projekt/Kompilierer/src/parser/resolver"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludePaths(t *testing.T) {
+	assert := assert.New(t)
+
+	writeFile := func(path, content string) {
+		if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dir := t.TempDir()
+	libA, libB := filepath.Join(dir, "libA"), filepath.Join(dir, "libB")
+	writeFile(filepath.Join(libA, "Bibliothek.ddp"), "Die öffentliche Zahl a ist 1.\n")
+	writeFile(filepath.Join(libB, "Bibliothek.ddp"), "Die öffentliche Zahl b ist 2.\n")
+	writeFile(filepath.Join(libB, "Andere.ddp"), "Die öffentliche Zahl c ist 3.\n")
+	writeFile(filepath.Join(dir, "main", "Lokal.ddp"), "Die öffentliche Zahl d ist 4.\n")
+	writeFile(filepath.Join(libA, "Lokal.ddp"), "Die öffentliche Zahl e ist 5.\n")
+
+	parse := func(src string) []string {
+		module, err := Parse(Options{
+			FileName:     filepath.Join(dir, "main", "main.ddp"),
+			Source:       []byte(src),
+			ErrorHandler: testHandler(t),
+			IncludePaths: []string{libA, libB},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.False(module.Ast.Faulty)
+		var decls []string
+		for _, imprt := range module.Imports {
+			for name := range imprt.Module.PublicDecls {
+				decls = append(decls, name)
+			}
+		}
+		return decls
+	}
+
+	assert.Equal([]string{"a"}, parse(`Binde "Bibliothek" ein.`), "the first include path comes first")
+	assert.Equal([]string{"c"}, parse(`Binde "Andere" ein.`))
+	assert.Equal([]string{"d"}, parse(`Binde "Lokal" ein.`), "relative paths come before the include paths")
+}
+
+func TestMaxScannerErrorsAcrossIncludes(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	lib := "Die öffentliche Zahl a ist 1. die öffentliche Zahl b ist 2. die öffentliche Zahl c ist 3. die öffentliche Zahl d ist 4.\n"
+	if err := os.WriteFile(filepath.Join(dir, "Bibliothek.ddp"), []byte(lib), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	var scannerErrors []ddperror.Error
+	_, err := Parse(Options{
+		FileName: filepath.Join(dir, "main.ddp"),
+		Source:   []byte("Binde \"Bibliothek\" ein. die Zahl x ist 1.\n"),
+		ErrorHandler: func(err ddperror.Error) {
+			if err.Code == ddperror.SYN_EXPECTED_CAPITAL || err.Code == ddperror.SYN_TOO_MANY_ERRORS {
+				scannerErrors = append(scannerErrors, err)
+			}
+		},
+		MaxScannerErrors: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one error in main.ddp, the limit is reached after two errors in Bibliothek.ddp
+	if assert.Len(scannerErrors, 4) {
+		assert.Equal("main.ddp", filepath.Base(scannerErrors[0].File))
+		assert.Equal("Bibliothek.ddp", filepath.Base(scannerErrors[1].File))
+		assert.Equal("Bibliothek.ddp", filepath.Base(scannerErrors[2].File))
+		assert.Equal(ddperror.SYN_TOO_MANY_ERRORS, scannerErrors[3].Code)
+		assert.Equal("Bibliothek.ddp", filepath.Base(scannerErrors[3].File))
+	}
+}
+
+func TestDiamondIncludeDiagnostics(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"B.ddp": "Binde \"D\" ein.\nDie öffentliche Zahl b ist 2.\n",
+		"C.ddp": "Binde \"D\" ein.\nDie öffentliche Zahl c ist 3.\n",
+		// a warning and an error in D
+		"D.ddp": "Die öffentliche Zahl d ist 4.\nd.\nDie Zahl e ist \"Text\".\n",
+	}
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var errors []ddperror.Error
+	_, err := Parse(Options{
+		FileName: filepath.Join(dir, "A.ddp"),
+		Source:   []byte("Binde \"B\" ein.\nBinde \"C\" ein.\n"),
+		ErrorHandler: ddperror.Deduplicate(func(err ddperror.Error) {
+			errors = append(errors, err)
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fromD []ddperror.Error
+	for _, err := range errors {
+		if filepath.Base(err.File) == "D.ddp" {
+			fromD = append(fromD, err)
+		}
+	}
+	if assert.Len(fromD, 2, "%v", errors) {
+		assert.Equal(ddperror.LEVEL_WARN, fromD[0].Level)
+		assert.Equal(ddperror.LEVEL_ERROR, fromD[1].Level)
+	}
+}
+
+func TestIncludeNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	var errors []ddperror.Error
+	module, err := Parse(Options{
+		FileName: filepath.Join(t.TempDir(), "main.ddp"),
+		Source:   []byte(`Binde "gibtsnicht" ein.`),
+		ErrorHandler: func(err ddperror.Error) {
+			errors = append(errors, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(module.Ast.Faulty)
+	if assert.Len(errors, 1) {
+		assert.Equal(ddperror.SYN_INCLUDE_NOT_FOUND, errors[0].Code)
+		assert.Equal(ddperror.LEVEL_ERROR, errors[0].Level)
+		assert.Equal(newRange(1, 7, 1, 19), errors[0].Range)
+		assert.Equal(module.FileName, errors[0].File)
+		assert.Equal("Die Datei 'gibtsnicht.ddp' konnte nicht gefunden werden", errors[0].Msg)
+	}
+}
+
+func TestCircularImportPath(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.ddp"), []byte(`Binde "b" ein.`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.ddp"), []byte(`Binde "a" ein.`), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	module, err := Parse(Options{
+		FileName:     filepath.Join(dir, "main.ddp"),
+		Source:       []byte(`Binde "a" ein.`),
+		ErrorHandler: func(ddperror.Error) {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(module.Imports, 1) && assert.NotNil(module.Imports[0].Module) {
+		assert.Equal(filepath.Join(dir, "a.ddp"), module.Imports[0].Path)
+		b := module.Imports[0].Module.Imports
+		if assert.Len(b, 1) && assert.NotNil(b[0].Module) && assert.Len(b[0].Module.Imports, 1) {
+			circular := b[0].Module.Imports[0]
+			assert.Nil(circular.Module)
+			assert.Equal(filepath.Join(dir, "a.ddp"), circular.Path, "the path is set for circular imports")
+		}
+	}
+}
+
+func TestLoopCounterNotAccessibleAfterLoop(t *testing.T) {
+	tests := map[string]string{
+		"für": `Für jede Zahl i von 1 bis 3, mache:
+	Die Zahl y ist i.
+Die Zahl x ist i.`,
+		"für jeden": `Für jeden Buchstaben b in "ab", mache:
+	Der Buchstabe y ist b.
+Der Buchstabe x ist b.`,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.SEM_NAME_UNDEFINED, errors[0].Code)
+				assert.Equal(uint(3), errors[0].Range.Start.Line)
+			}
+		})
+	}
+}
+
+// globals are initialized in source order, so they can not be used before their declaration
+func TestGlobalUsedBeforeDeclaration(t *testing.T) {
+	tests := map[string]string{
+		"Anweisung": `Speichere 1 in x.
+Die Zahl x ist 2.`,
+		"Initialisierung": `Die Zahl y ist x.
+Die Zahl x ist 2.`,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.SEM_NAME_UNDEFINED, errors[0].Code)
+				assert.Equal(uint(1), errors[0].Range.Start.Line)
+			}
+		})
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	assert := assert.New(t)
+	src := `Die Zahl x ist 1 plus.
+Die Zahl y ist x plus 2.
+Wenn wahr, dann:
+	Die Zahl z ist (1 plus.
+	Die Zahl w ist z plus 1.
+Die Zahl q ist 2 plus.`
+
+	module, errors := parseSourceErrors(t, src)
+
+	assert.True(module.Ast.Faulty)
+	// one syntax error per faulty statement and no follow up errors
+	if assert.Len(errors, 3) {
+		for i, line := range []uint{1, 4, 6} {
+			assert.Equal(ddperror.SYN_UNEXPECTED_TOKEN, errors[i].Code)
+			assert.Equal(line, errors[i].Range.Start.Line)
+		}
+	}
+}
+
+func TestArgOrder(t *testing.T) {
+	assert := assert.New(t)
+	src := `Wir nennen die Kombination aus
+	der Zahl x mit Standardwert 0,
+	der Zahl y mit Standardwert 0,
+einen Punkt, und erstellen sie so:
+	"ein Punkt mit y <y> und x <x>"
+
+Die Funktion f mit den Parametern a, b und c vom Typ Zahl, Zahl und Zahl, gibt eine Zahl zurück, macht:
+	Gib a zurück.
+Und kann so benutzt werden:
+	"<c> <a> <b>"
+
+Die Zahl z ist 3 1 2.
+Der Punkt p ist ein Punkt mit y 2 und x 1.`
+
+	module, err := Parse(Options{
+		FileName:     "main.ddp",
+		Source:       []byte(src),
+		ErrorHandler: testHandler(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var call *ast.FuncCall
+	var literal *ast.StructLiteral
+	ast.VisitModule(module, ast.FuncCallVisitorFunc(func(expr *ast.FuncCall) ast.VisitResult {
+		call = expr
+		return ast.VisitRecurse
+	}))
+	ast.VisitModule(module, ast.StructLiteralVisitorFunc(func(expr *ast.StructLiteral) ast.VisitResult {
+		literal = expr
+		return ast.VisitRecurse
+	}))
+
+	if assert.NotNil(call) {
+		assert.Equal([]string{"c", "a", "b"}, call.ArgNames())
+		assert.Equal([]int64{3, 1, 2}, intValues(call.OrderedArgs()))
+	}
+	if assert.NotNil(literal) {
+		assert.Equal([]string{"y", "x"}, literal.ArgNames())
+		assert.Equal([]int64{2, 1}, intValues(literal.OrderedArgs()))
+	}
+
+	// generated calls without ArgOrder are sorted by name
+	generated := &ast.FuncCall{Args: map[string]ast.Expression{"b": &ast.IntLit{Value: 2}, "a": &ast.IntLit{Value: 1}}}
+	assert.Equal([]string{"a", "b"}, generated.ArgNames())
+}
+
+func intValues(exprs []ast.Expression) []int64 {
+	values := make([]int64, 0, len(exprs))
+	for _, expr := range exprs {
+		if lit, ok := expr.(*ast.IntLit); ok {
+			values = append(values, lit.Value)
+		}
+	}
+	return values
+}
+
+func TestDuplicateParameterName(t *testing.T) {
+	assert := assert.New(t)
+	src := `Die Funktion f mit den Parametern a, b und a vom Typ Zahl, Zahl und Zahl, gibt eine Zahl zurück, macht:
+	Gib a zurück.
+Und kann so benutzt werden:
+	"f <a> <b>"`
+
+	module, errors := parseSource(t, src)
+
+	assert.True(module.Ast.Faulty)
+	if assert.Len(errors, 1) {
+		assert.Equal(ddperror.SEM_NAME_ALREADY_DEFINED, errors[0].Code)
+		assert.Equal(newRange(1, 44, 1, 45), errors[0].Range)
+		assert.Equal("Der Parameter 'a' wurde mehrfach angegeben", errors[0].Msg)
+	}
+}
+
+func TestReturnValueMismatch(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		msg     string
+		errLine uint
+	}{
+		"void mit Wert": {
+			src: `Die Funktion f gibt nichts zurück, macht:
+	Gib 1 zurück.
+Und kann so benutzt werden:
+	"f"`,
+			msg:     "Eine Funktion ohne Rückgabewert kann keinen Wert zurückgeben",
+			errLine: 2,
+		},
+		"Wert ohne Wert": {
+			src: `Die Funktion f gibt eine Zahl zurück, macht:
+	Verlasse die Funktion.
+	Gib 1 zurück.
+Und kann so benutzt werden:
+	"f"`,
+			msg:     "Es muss ein Wert vom Typ Zahl zurückgegeben werden",
+			errLine: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.TYP_WRONG_RETURN_TYPE, errors[0].Code)
+				assert.Equal(test.errLine, errors[0].Range.Start.Line)
+				assert.Equal(test.msg, errors[0].Msg)
+			}
+		})
+	}
+}
+
+func TestCharInTextReference(t *testing.T) {
+	tests := map[string]struct {
+		src     string
+		code    ddperror.Code
+		errLine uint
+	}{
+		"Funktion": {
+			src: `Die Funktion f mit dem Parameter b vom Typ Buchstaben Referenz, gibt nichts zurück, macht:
+	Speichere 'a' in b.
+Und kann so benutzt werden:
+	"f <b>"
+
+Der Text t ist "abc".
+f (t an der Stelle 1).`,
+			code:    ddperror.TYP_INVALID_REFERENCE,
+			errLine: 7,
+		},
+		// the overload is not used, so the builtin operator reports the error
+		"Operator": {
+			src: `Die Funktion f mit dem Parameter b vom Typ Buchstaben Referenz, gibt eine Zahl zurück, macht:
+	Gib 1 zurück.
+Und überlädt den "Länge" Operator.
+
+Der Text t ist "abc".
+Die Zahl z ist die Länge von (t an der Stelle 1).`,
+			code:    ddperror.TYP_TYPE_MISMATCH,
+			errLine: 6,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(test.code, errors[0].Code)
+				assert.Equal(test.errLine, errors[0].Range.Start.Line)
+			}
+		})
+	}
+}
+
+func TestNegiere(t *testing.T) {
+	tests := map[string]struct {
+		decl     string
+		operator ast.UnaryOperator
+		errors   bool
+	}{
+		"Zahl":                {"Die Zahl x ist 1.", ast.UN_NEGATE, false},
+		"Kommazahl":           {"Die Kommazahl x ist 1,5.", ast.UN_NEGATE, false},
+		"Wahrheitswert":       {"Der Wahrheitswert x ist wahr.", ast.UN_NOT, false},
+		"Buchstabe":           {"Der Buchstabe x ist 'a'.", ast.UN_LOGIC_NOT, false},
+		"Zahlen Liste":        {"Die Zahlen Liste x ist eine leere Zahlen Liste.", ast.UN_NEGATE, false},
+		"Text":                {"Der Text x ist \"a\".", ast.UN_NEGATE, true},
+		"Wahrheitswert Liste": {"Die Wahrheitswert Liste x ist eine leere Wahrheitswert Liste.", ast.UN_NEGATE, true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, test.decl+"\nNegiere x.")
+
+			if test.errors {
+				if assert.Len(errors, 1) {
+					assert.Equal(ddperror.TYP_TYPE_MISMATCH, errors[0].Code)
+					assert.Contains(errors[0].Msg, "Negiere")
+				}
+				return
+			}
+
+			assert.Empty(errors)
+			if assign, ok := module.Ast.Statements[1].(*ast.AssignStmt); assert.True(ok) {
+				assert.Equal(test.operator, assign.Rhs.(*ast.UnaryExpr).Operator)
+			}
+		})
+	}
+}
+
+func TestFunctionValue(t *testing.T) {
+	assert := assert.New(t)
+
+	src := `Die Funktion Verdopple mit dem Parameter x vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib x mal 2 zurück.
+Und kann so benutzt werden:
+	"<x> verdoppelt"
+
+Die Funktion(Zahl) gibt Zahl f ist die Funktion Verdopple.
+Die Zahl z ist f(3).`
+
+	module, err := Parse(Options{
+		FileName:     "main.ddp",
+		Source:       []byte(src),
+		ErrorHandler: ddperror.EmptyHandler,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(module.Ast.Faulty)
+
+	decl, _, ok := module.Ast.Symbols.LookupVar("f")
+	if !assert.True(ok) {
+		return
+	}
+	assert.Equal("Funktion(Zahl) gibt Zahl", decl.Type.String())
+	if ref, ok := decl.InitVal.(*ast.FuncRef); assert.True(ok) {
+		assert.Equal("Verdopple", ref.Func.Name())
+	}
+
+	decl, _, ok = module.Ast.Symbols.LookupVar("z")
+	if !assert.True(ok) {
+		return
+	}
+	if call, ok := decl.InitVal.(*ast.FuncValueCall); assert.True(ok) {
+		assert.Len(call.Args, 1)
+		assert.IsType(&ast.Ident{}, call.Callee)
+	}
+}
+
+func TestEmptyGrouping(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected token.Range
+	}{
+		"leer":          {"Die Zahl x ist ().", newRange(1, 16, 1, 18)},
+		"verschachtelt": {"Die Zahl x ist (1 plus (())).", newRange(1, 25, 1, 27)},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSourceErrors(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.SYN_EMPTY_GROUPING, errors[0].Code)
+				assert.Equal(test.expected, errors[0].Range)
+			}
+		})
+	}
+}
+
+func TestLengthOfStruct(t *testing.T) {
+	tests := map[string]struct {
+		src string
+		msg string
+	}{
+		"maskulin": {
+			src: `Wir nennen die Kombination aus
+	der Zahl x mit Standardwert 0,
+einen Vektor, und erstellen sie so:
+	"ein Vektor"
+Die Zahl l ist die Länge von ein Vektor.`,
+			msg: "Der Länge Operator kann nicht auf die Kombination Vektor angewandt werden, ihre Größe in Bytes liefert 'die Größe von einem Vektor'",
+		},
+		"feminin": {
+			src: `Wir nennen die Kombination aus
+	der Zahl x mit Standardwert 0,
+eine Menge, und erstellen sie so:
+	"eine Menge"
+Die Zahl l ist die Länge von eine Menge.`,
+			msg: "Der Länge Operator kann nicht auf die Kombination Menge angewandt werden, ihre Größe in Bytes liefert 'die Größe von einer Menge'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSourceErrors(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.TYP_TYPE_MISMATCH, errors[0].Code)
+				assert.Equal(test.msg, errors[0].Msg)
+			}
+		})
+	}
+}
+
+func TestInferredVarDecl(t *testing.T) {
+	tests := map[string]struct {
+		src      string
+		expected ddptypes.Type
+	}{
+		"Zahl":          {"Der Wert x ist 1 plus 2.", ddptypes.ZAHL},
+		"Liste":         {"Der Wert x ist eine Liste, die aus \"a\", \"b\" besteht.", ddptypes.ListType{Underlying: ddptypes.TEXT}},
+		"leere Liste":   {"Der Wert x ist eine leere Kommazahlen Liste.", ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL}},
+		"Wahrheitswert": {"Der Wert x ist wahr, wenn 1 kleiner als 2 ist.", ddptypes.WAHRHEITSWERT},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, err := Parse(Options{
+				FileName:     "main.ddp",
+				Source:       []byte(test.src),
+				ErrorHandler: testHandler(t),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if assert.Len(module.Ast.Statements, 1) {
+				decl := module.Ast.Statements[0].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+				assert.True(decl.TypeInferred)
+				assert.Equal(test.expected, decl.Type)
+			}
+		})
+	}
+}
+
+func TestInferredVarDeclError(t *testing.T) {
+	tests := map[string]struct {
+		src  string
+		code ddperror.Code
+	}{
+		"nichts": {"Der Wert x ist nichts.", ddperror.TYP_BAD_ASSIGNEMENT},
+		"ohne Rückgabewert": {`Die Funktion f gibt nichts zurück, macht:
+	Verlasse die Funktion.
+Und kann so benutzt werden:
+	"f"
+Der Wert x ist f.`, ddperror.TYP_BAD_ASSIGNEMENT},
+		"Artikel": {"Die Wert x ist 1.", ddperror.SYN_GENDER_MISMATCH},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSourceErrors(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(test.code, errors[0].Code)
+			}
+		})
+	}
+}
+
+func TestInferredVarDeclTypeNamedWert(t *testing.T) {
+	assert := assert.New(t)
+
+	module, err := Parse(Options{
+		FileName: "main.ddp",
+		Source: []byte(`Wir nennen die Kombination aus
+	der Zahl z mit Standardwert 0,
+einen Wert, und erstellen sie so:
+	"ein Wert"
+Der Wert x ist ein Wert.`),
+		ErrorHandler: testHandler(t),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "Der Wert x" declares a variable of the struct type Wert
+	decl := module.Ast.Statements[1].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+	assert.False(decl.TypeInferred)
+	assert.Equal("Wert", decl.Type.String())
+}
+
+// typechecks every combination of the values below with VERKETTET
+// every combination that is not in valid must be rejected
+func TestConcatTypes(t *testing.T) {
+	decls := `Wir nennen die Kombination aus
+	der Zahl x mit Standardwert 0,
+einen Vektor, und erstellen sie so:
+	"ein Vektor"
+Die Aufzählung Farbe ist rot, grün und blau.
+Wir definieren eine Zeichenkette als einen Text.
+Wir definieren ein Zeichen als einen Buchstaben.
+Wir nennen einen Text auch eine Bezeichnung.
+Die Funktion verdopple mit dem Parameter x vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Gib x mal 2 zurück.
+Und kann so benutzt werden:
+	"verdoppelt <x>"
+Die Zahl z ist 1.
+Die Kommazahl k ist 1,5.
+Der Wahrheitswert w ist wahr.
+Der Buchstabe b ist 'a'.
+Der Text t ist "t".
+Die Zahlen Liste zl ist eine Liste, die aus 1, 2 besteht.
+Die Kommazahlen Liste kl ist eine Liste, die aus 1,5, 2,5 besteht.
+Die Wahrheitswert Liste wl ist eine Liste, die aus wahr, falsch besteht.
+Die Buchstaben Liste bl ist eine Liste, die aus 'a', 'b' besteht.
+Die Text Liste tl ist eine Liste, die aus "a", "b" besteht.
+Der Vektor v ist ein Vektor.
+Die Vektor Liste vl ist eine Liste, die aus ein Vektor, ein Vektor besteht.
+Die Variable a ist 5.
+Die Variablen Liste al ist eine Liste, die aus (1 als Variable), ("x" als Variable) besteht.
+Die Farbe f ist rot.
+Die vielleicht Zahl o ist 5.
+Die Funktion(Zahl) gibt Zahl fn ist die Funktion verdopple.
+Die Zeichenkette zk ist "ab" als Zeichenkette.
+Das Zeichen ze ist 'a' als Zeichen.
+Die Bezeichnung bz ist "b".
+`
+	values := []string{"z", "k", "w", "b", "t", "zl", "kl", "wl", "bl", "tl", "v", "vl", "a", "al", "f", "o", "fn", "zk", "ze", "bz"}
+	// maps "lhs rhs" to the type of the concatenation
+	valid := map[string]string{
+		"t t": "Text", "t b": "Text", "b t": "Text", "bz bz": "Text", "bz t": "Text", "t bz": "Text", "bz b": "Text", "b bz": "Text",
+		"tl tl": "Text Liste", "tl t": "Text Liste", "t tl": "Text Liste", "tl bz": "Text Liste", "bz tl": "Bezeichnung Liste",
+		"z z": "Zahlen Liste", "zl z": "Zahlen Liste", "z zl": "Zahlen Liste", "zl zl": "Zahlen Liste",
+		"k k": "Kommazahlen Liste", "kl k": "Kommazahlen Liste", "k kl": "Kommazahlen Liste", "kl kl": "Kommazahlen Liste",
+		"w w": "Wahrheitswert Liste", "wl w": "Wahrheitswert Liste", "w wl": "Wahrheitswert Liste", "wl wl": "Wahrheitswert Liste",
+		"b b": "Buchstaben Liste", "bl b": "Buchstaben Liste", "b bl": "Buchstaben Liste", "bl bl": "Buchstaben Liste",
+		"v v": "Vektor Liste", "vl v": "Vektor Liste", "v vl": "Vektor Liste", "vl vl": "Vektor Liste",
+		"a a": "Variablen Liste", "al a": "Variablen Liste", "a al": "Variablen Liste", "al al": "Variablen Liste",
+		"ze ze": "Zeichen Liste",
+	}
+
+	for _, lhs := range values {
+		for _, rhs := range values {
+			name := lhs + " " + rhs
+			t.Run(name, func(t *testing.T) {
+				assert := assert.New(t)
+
+				module, errors := parseSourceErrors(t, decls+"Der Wert r ist "+lhs+" verkettet mit "+rhs+".")
+
+				expected, ok := valid[name]
+				if !ok {
+					assert.True(module.Ast.Faulty)
+					assert.Len(errors, 1)
+					return
+				}
+				if assert.Empty(errors) {
+					decl := module.Ast.Statements[len(module.Ast.Statements)-1].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+					assert.Equal(expected, decl.Type.String())
+				}
+			})
+		}
+	}
+}
+
+func TestConcatMismatch(t *testing.T) {
+	tests := map[string]struct {
+		src string
+		msg string
+	}{
+		"Liste und Liste": {
+			src: `Die Zahlen Liste l ist eine Liste, die aus 1, 2 besteht.
+Der Wert x ist l verkettet mit eine Liste, die aus "a" besteht.`,
+			msg: "Eine Liste vom Typ Zahlen Liste kann nicht mit einer Liste vom Typ Text Liste verkettet werden, da ihre Elemente unterschiedliche Typen haben",
+		},
+		"Liste und Wert": {
+			src: `Die Zahlen Liste l ist eine Liste, die aus 1, 2 besteht.
+Der Wert x ist l verkettet mit 1,5.`,
+			msg: "Ein Wert vom Typ Kommazahl kann nicht an eine Liste vom Typ Zahlen Liste angehängt werden, es wird ein Wert vom Typ Zahl erwartet",
+		},
+		"Wert und Liste": {
+			src: `Die Zahlen Liste l ist eine Liste, die aus 1, 2 besteht.
+Der Wert x ist "a" verkettet mit l.`,
+			msg: "Ein Wert vom Typ Text kann nicht vor eine Liste vom Typ Zahlen Liste gehängt werden, es wird ein Wert vom Typ Zahl erwartet",
+		},
+		"Wert und Wert": {
+			src: `Der Wert x ist 1 verkettet mit wahr.`,
+			msg: "Werte vom Typ Zahl und Wahrheitswert können nicht zu einer Liste verkettet werden, da sie unterschiedliche Typen haben",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSourceErrors(t, test.src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.TYP_TYPE_MISMATCH, errors[0].Code)
+				assert.Equal(test.msg, errors[0].Msg)
+			}
+		})
+	}
+}
+
+func TestTimings(t *testing.T) {
+	assert := assert.New(t)
+
+	timings := &Timings{Parsing: time.Second} // already measured time is kept
+	_, err := Parse(Options{
+		FileName:     "main.ddp",
+		Source:       []byte("Die Zahl x ist 1 plus 2.\nDie Zahl y ist x mal 2."),
+		ErrorHandler: ddperror.EmptyHandler,
+		Timings:      timings,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Greater(timings.Scanning, time.Duration(0))
+	assert.Greater(timings.Parsing, time.Second)
+	assert.Greater(timings.Resolving, time.Duration(0))
+	assert.Greater(timings.Typechecking, time.Duration(0))
+	assert.Equal(timings.Scanning+timings.Parsing+timings.Resolving+timings.Typechecking, timings.Total())
+}
+
+func TestInexactStepSize(t *testing.T) {
+	tests := map[string]bool{
+		"Für jede Kommazahl x von 0,0 bis 1,0 mit Schrittgröße 0,1, x.":          true,
+		"Für jede Kommazahl x von 1,0 bis 0,0 mit Schrittgröße -(0,1), x.":       true,
+		"Für jede Kommazahl x von 0,0 bis 1,0 mit Schrittgröße 0,25, x.":         false,
+		"Für jede Kommazahl x von 1,0 bis 0,0 mit Schrittgröße -0,5, x.":         false,
+		"Für jede Kommazahl x von 0,0 bis 1,0, x.":                               false,
+		"Für jede Kommazahl x von 0,0 bis 1,0 mit Schrittgröße 1,0 durch 10, x.": false, // only literals are checked
+		"Für jede Zahl x von 0 bis 10 mit Schrittgröße 2, x.":                    false,
+	}
+
+	for src, warns := range tests {
+		t.Run(src, func(t *testing.T) {
+			assert := assert.New(t)
+
+			var warnings []ddperror.Error
+			module, err := Parse(Options{
+				FileName: "main.ddp",
+				Source:   []byte(src),
+				ErrorHandler: func(err ddperror.Error) {
+					if err.Code != ddperror.SEM_EXPR_WITHOUT_EFFECT {
+						warnings = append(warnings, err)
+					}
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.False(module.Ast.Faulty)
+
+			if warns {
+				if assert.Len(warnings, 1) {
+					assert.Equal(ddperror.TYP_INEXACT_STEP_SIZE, warnings[0].Code)
+					assert.Equal(ddperror.LEVEL_WARN, warnings[0].Level)
+				}
+			} else {
+				assert.Empty(warnings)
+			}
+		})
+	}
+}
+
+func TestSymbolIndex(t *testing.T) {
+	assert := assert.New(t)
+
+	src := `Die Zahl x ist 1.
+Die Funktion f gibt eine Zahl zurück, macht:
+	Die Zahl x ist 2.
+	Gib x zurück.
+Und kann so benutzt werden:
+	"f"
+Die Zahl y ist x plus f.
+Für jede Zahl x von 1 bis 2, mache:
+	Speichere x in y.
+Speichere x in y.
+Der Wert g ist die Funktion f.
+`
+	index := resolver.SymbolIndex{}
+	module, err := Parse(Options{
+		FileName:     "main.ddp",
+		Source:       []byte(src),
+		ErrorHandler: testHandler(t),
+		SymbolIndex:  index,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.False(module.Ast.Faulty)
+
+	globalX := module.Ast.Statements[0].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+	funcF := module.Ast.Statements[1].(*ast.DeclStmt).Decl.(*ast.FuncDecl)
+	localX := funcF.Body.Statements[0].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+	globalY := module.Ast.Statements[2].(*ast.DeclStmt).Decl.(*ast.VarDecl)
+	loopX := module.Ast.Statements[3].(*ast.ForStmt).Initializer
+
+	// returns the declaration of the usage at line:column with the given length
+	lookup := func(line, column, length uint) ast.Declaration {
+		return index[token.Range{
+			Start: token.Position{Line: line, Column: column},
+			End:   token.Position{Line: line, Column: column + length},
+		}]
+	}
+
+	assert.Same(localX, lookup(4, 6, 1), "x inside f refers to the local x")
+	assert.Same(globalX, lookup(7, 16, 1), "x after f refers to the global x again")
+	assert.Same(funcF, lookup(7, 23, 1), "the call of f")
+	assert.Same(loopX, lookup(9, 12, 1), "x inside the loop refers to the counter")
+	assert.Same(globalY, lookup(9, 17, 1))
+	assert.Same(globalX, lookup(10, 11, 1), "x after the loop refers to the global x again")
+	assert.Same(funcF, lookup(11, 29, 1), "the function reference")
+	assert.Nil(lookup(1, 10, 1), "declarations are not usages")
+}
+
+// functions can only be declared in the global scope until closures are supported
+func TestNonGlobalFunction(t *testing.T) {
+	tests := map[string]string{
+		"Schleife": `Für jede Zahl i von 1 bis 2, mache:
+	Die Funktion f gibt nichts zurück, macht:
+		Verlasse die Funktion.
+	Und kann so benutzt werden:
+		"f"`,
+		"Funktion": `Die Funktion g gibt nichts zurück, macht:
+	Die Funktion f gibt nichts zurück, macht:
+		Verlasse die Funktion.
+	Und kann so benutzt werden:
+		"f"
+Und kann so benutzt werden:
+	"g"`,
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.NotEmpty(errors) {
+				assert.Equal(ddperror.SEM_NON_GLOBAL_FUNCTION, errors[0].Code)
+				assert.Equal(ddperror.MSG_NON_GLOBAL_FUNCTION, errors[0].Msg)
+				assert.Equal(uint(2), errors[0].Range.Start.Line)
+			}
+		})
+	}
+}
+
+// comparisons are always Wahrheitswerte, so an invalid comparison
+// must not cause further errors in the surrounding expression
+func TestInvalidComparisonNested(t *testing.T) {
+	tests := []string{
+		`Der Wahrheitswert w ist (1 kleiner als "a" ist) und wahr.`,
+		`Der Wahrheitswert w ist nicht ("a" größer als 2 ist).`,
+		`Der Wahrheitswert w ist ("a" größer als, oder 2 ist) oder (1 kleiner als, oder 2 ist).`,
+		`Der Wahrheitswert w ist (1 zwischen "a" und 3 ist) und wahr.`,
+		`Der Text t ist ((1 kleiner als "a" ist) als Text) verkettet mit "b".`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, src)
+
+			assert.True(module.Ast.Faulty)
+			for _, err := range errors {
+				assert.Equal(ddperror.TYP_TYPE_MISMATCH, err.Code, err.Msg)
+			}
+			assert.Len(errors, 1, "only the invalid comparison is reported")
+		})
+	}
+}
+
+func TestInvalidValueNoFollowUpErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	module, errors := parseSource(t, `Der Wert x ist y.
+Die Zahl z ist x plus 1.
+Der Text t ist (x als Text) verkettet mit "a".
+Der Wahrheitswert w ist nicht (x gleich 1 ist).
+Die Zahlen Liste l ist eine Liste, die aus x, 2 besteht.`)
+
+	assert.True(module.Ast.Faulty)
+	if assert.Len(errors, 1, "only the undefined name is reported") {
+		assert.Equal(ddperror.SEM_NAME_UNDEFINED, errors[0].Code, errors[0].Msg)
+	}
+}
+
+func TestFieldAccessOnNonStruct(t *testing.T) {
+	assert := assert.New(t)
+
+	_, errors := parseSource(t, "Die Zahl x ist 1.\nDie Zahl z ist f von x.")
+
+	if assert.Len(errors, 1) {
+		assert.Equal(ddperror.TYP_BAD_FIELD_ACCESS, errors[0].Code, errors[0].Msg)
+	}
+}
+
+func TestVoidValue(t *testing.T) {
+	const decl = `Die Funktion f gibt nichts zurück, macht:
+	Verlasse die Funktion.
+Und kann so benutzt werden:
+	"f"
+`
+	tests := []string{
+		`Die Text Liste l ist eine Liste, die aus f, f besteht.`,
+		`Der Text t ist f verkettet mit f.`,
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			assert := assert.New(t)
+
+			module, errors := parseSource(t, decl+src)
+
+			assert.True(module.Ast.Faulty)
+			if assert.Len(errors, 1) {
+				assert.Equal(ddperror.TYP_TYPE_MISMATCH, errors[0].Code)
+				assert.Equal("Ein Ausdruck, der nichts zurückgibt, kann nicht als Wert benutzt werden", errors[0].Msg)
+			}
+		})
+	}
+}
+
+func TestRepeatCountType(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		src  string
+		code ddperror.Code // 0 if there is no error
+	}{
+		{"Wiederhole:\n\tDie Zahl x ist 1.\n3 Mal.", 0},
+		{"Die Funktion f gibt nichts zurück, macht:\n\tDie Zahl x ist 1.\nUnd kann so benutzt werden:\n\t\"f\"\nf 3 Mal.", 0},
+		{"Wiederhole:\n\tDie Zahl x ist 1.\n\"3\" Mal.", ddperror.TYP_TYPE_MISMATCH},
+		{"Die Funktion f gibt nichts zurück, macht:\n\tDie Zahl x ist 1.\nUnd kann so benutzt werden:\n\t\"f\"\nf 3,5 Mal.", ddperror.TYP_TYPE_MISMATCH},
+		{"Solange 1, mache:\n\tDie Zahl x ist 1.", ddperror.TYP_BAD_CONDITION},
+		{"Mache:\n\tDie Zahl x ist 1.\nSolange 1.", ddperror.TYP_BAD_CONDITION},
+	}
+
+	for _, test := range tests {
+		_, errors := parseSource(t, test.src)
+
+		if test.code == 0 {
+			assert.Empty(errors, test.src)
+		} else if assert.Len(errors, 1, test.src) {
+			assert.Equal(test.code, errors[0].Code, errors[0].Msg)
+		}
+	}
+}
+
+func TestErrorNotes(t *testing.T) {
+	assert := assert.New(t)
+
+	tests := []struct {
+		src      string
+		code     ddperror.Code
+		noteLine uint
+		noteCol  uint
+		noteMsg  string
+	}{
+		{"Die Funktion f mit dem Parameter a vom Typ Zahl, gibt nichts zurück, macht:\n\tDie Zahl x ist a.\nUnd kann so benutzt werden:\n\t\"f <a>\"\nf \"a\".",
+			ddperror.TYP_TYPE_MISMATCH, 1, 34, "Der Parameter a wurde hier deklariert"},
+		{"Die Zahl x ist 1.\nDer Text x ist \"a\".", ddperror.SEM_NAME_ALREADY_DEFINED, 1, 10, "x wurde hier deklariert"},
+		{"Die Zahl f ist 1.\nDie Funktion f gibt nichts zurück, macht:\n\tDie Zahl x ist 1.\nUnd kann so benutzt werden:\n\t\"f\"",
+			ddperror.SEM_NAME_ALREADY_DEFINED, 1, 10, "f wurde hier deklariert"},
+		{"Die Aufzählung Farbe ist rot, grün und blau.\nDie Zahl grün ist 1.", ddperror.SEM_NAME_ALREADY_DEFINED, 1, 31, "grün wurde hier deklariert"},
+	}
+
+	for _, test := range tests {
+		_, errors := parseSource(t, test.src)
+
+		if !assert.Len(errors, 1, test.src) {
+			continue
+		}
+		assert.Equal(test.code, errors[0].Code, errors[0].Msg)
+		if assert.Len(errors[0].Notes, 1, test.src) {
+			note := errors[0].Notes[0]
+			assert.Equal("main.ddp", note.File)
+			assert.Equal(test.noteLine, note.Range.Start.Line, test.src)
+			assert.Equal(test.noteCol, note.Range.Start.Column, test.src)
+			assert.Equal(test.noteMsg, note.Msg)
+		}
+	}
+}
+
+func TestAssignToNonVariable(t *testing.T) {
+	assert := assert.New(t)
+
+	const funcDecl = "Die Funktion f gibt eine Zahlen Liste zurück, macht:\n\tGib eine Liste, die aus 1, 2 besteht zurück.\nUnd kann so benutzt werden:\n\t\"f\"\n"
+	tests := []struct {
+		src  string
+		code ddperror.Code // the first reported error
+	}{
+		{"Speichere 5 in (eine Liste, die aus 1, 2 besteht) an der Stelle 1.", ddperror.SYN_UNEXPECTED_TOKEN},
+		{"Speichere 5 in 1 an der Stelle 1.", ddperror.SYN_UNEXPECTED_TOKEN},
+		{funcDecl + "Speichere 5 in f an der Stelle 1.", ddperror.SEM_BAD_NAME_CONTEXT},
+		{funcDecl + "Erhöhe f an der Stelle 1 um 1.", ddperror.SEM_BAD_NAME_CONTEXT},
+		{funcDecl + "Speichere eine Liste, die aus 3 besteht in f.", ddperror.SEM_BAD_NAME_CONTEXT},
+	}
+
+	for _, test := range tests {
+		module, errors := parseSource(t, test.src)
+
+		assert.True(module.Ast.Faulty, test.src)
+		if assert.NotEmpty(errors, test.src) {
+			assert.Equal(test.code, errors[0].Code, errors[0].Msg)
+		}
+	}
+}
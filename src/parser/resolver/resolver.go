@@ -28,9 +28,18 @@ type Resolver struct {
 	CurrentTable *ast.SymbolTable // needed state, public for the parser
 	Module       *ast.Module      // the module that is being resolved
 	LoopDepth    uint             // for break and continue statements
+	Index        SymbolIndex      // if non-nil, every resolved usage is recorded in it
 	panicMode    *bool            // panic mode synchronized with the parser and resolver
 }
 
+// maps the range of a usage in the source code to the declaration it refers to
+// so that tools like the language server can look up hover and go-to-definition information
+// without searching the AST
+//   - identifiers are mapped to their *ast.VarDecl by the range of the name
+//   - function calls are mapped to their *ast.FuncDecl by the range of the whole call
+//   - function references are mapped to their *ast.FuncDecl by the range of the function name
+type SymbolIndex map[token.Range]ast.Declaration
+
 // create a new resolver to resolve the passed AST
 func New(Mod *ast.Module, errorHandler ddperror.Handler, file string, panicMode *bool) *Resolver {
 	if errorHandler == nil {
@@ -57,6 +66,13 @@ func (r *Resolver) visit(node ast.Node) {
 	node.Accept(r)
 }
 
+// adds a usage to r.Index if it is enabled
+func (r *Resolver) index(Range token.Range, decl ast.Declaration) {
+	if r.Index != nil {
+		r.Index[Range] = decl
+	}
+}
+
 func (r *Resolver) setScope(symbols *ast.SymbolTable) {
 	r.CurrentTable = symbols
 }
@@ -66,14 +82,21 @@ func (r *Resolver) exitScope() {
 }
 
 // helper for errors
-func (r *Resolver) err(code ddperror.Code, Range token.Range, msg string) {
+func (r *Resolver) err(code ddperror.Code, Range token.Range, msg string, notes ...ddperror.Note) {
 	r.Module.Ast.Faulty = true
 	if !*r.panicMode {
 		*r.panicMode = true
-		r.ErrorHandler(ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, r.Module.FileName))
+		err := ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, r.Module.FileName)
+		err.Notes = notes
+		r.ErrorHandler(err)
 	}
 }
 
+// reports that name was already declared in the current scope
+func (r *Resolver) errAlreadyDeclared(Range token.Range, name string) {
+	r.err(ddperror.SEM_NAME_ALREADY_DEFINED, Range, ddperror.MsgNameAlreadyExists(name), ast.DeclaredHereNote(r.CurrentTable.Declarations[name], name))
+}
+
 func (*Resolver) Visitor() {}
 
 // if a BadDecl exists the AST is faulty
@@ -86,7 +109,7 @@ func (r *Resolver) VisitVarDecl(decl *ast.VarDecl) ast.VisitResult {
 	r.visit(decl.InitVal) // resolve the initial value
 	// insert the variable into the current scope (SymbolTable)
 	if existed := r.CurrentTable.InsertDecl(decl.Name(), decl); existed {
-		r.err(ddperror.SEM_NAME_ALREADY_DEFINED, decl.NameTok.Range, ddperror.MsgNameAlreadyExists(decl.Name())) // variables may only be declared once in the same scope
+		r.errAlreadyDeclared(decl.NameTok.Range, decl.Name()) // variables may only be declared once in the same scope
 	}
 
 	if decl.Public() && !ast.IsGlobalScope(r.CurrentTable) {
@@ -136,7 +159,7 @@ func (r *Resolver) VisitStructDecl(decl *ast.StructDecl) ast.VisitResult {
 	}
 	// insert the struct into the current scope (SymbolTable)
 	if existed := r.CurrentTable.InsertDecl(decl.Name(), decl); existed {
-		r.err(ddperror.SEM_NAME_ALREADY_DEFINED, decl.NameTok.Range, ddperror.MsgNameAlreadyExists(decl.Name())) // structs may only be declared once in the same module
+		r.errAlreadyDeclared(decl.NameTok.Range, decl.Name()) // structs may only be declared once in the same module
 	}
 	// insert the struct into the public module decls
 	if _, alreadyExists := r.Module.PublicDecls[decl.Name()]; decl.IsPublic && !alreadyExists {
@@ -145,13 +168,34 @@ func (r *Resolver) VisitStructDecl(decl *ast.StructDecl) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (r *Resolver) VisitEnumDecl(decl *ast.EnumDecl) ast.VisitResult {
+	if !ast.IsGlobalScope(r.CurrentTable) {
+		r.err(ddperror.SEM_NON_GLOBAL_TYPE_DECL, decl.NameTok.Range, "Es können nur globale Typen deklariert werden")
+	}
+
+	// insert the enum and its variants into the current scope (SymbolTable)
+	if existed := r.CurrentTable.InsertDecl(decl.Name(), decl); existed {
+		r.errAlreadyDeclared(decl.NameTok.Range, decl.Name()) // enums may only be declared once in the same module
+	}
+	for _, variant := range decl.Variants {
+		if existed := r.CurrentTable.InsertDecl(variant.Literal, decl); existed {
+			r.errAlreadyDeclared(variant.Range, variant.Literal)
+		}
+	}
+	// insert the enum into the public module decls
+	if _, alreadyExists := r.Module.PublicDecls[decl.Name()]; decl.IsPublic && !alreadyExists {
+		r.Module.PublicDecls[decl.Name()] = decl
+	}
+	return ast.VisitRecurse
+}
+
 func (r *Resolver) VisitTypeAliasDecl(decl *ast.TypeAliasDecl) ast.VisitResult {
 	if !ast.IsGlobalScope(r.CurrentTable) {
 		r.err(ddperror.SEM_NON_GLOBAL_TYPE_DECL, decl.NameTok.Range, "Es können nur globale Typen deklariert werden")
 	}
 
 	if existed := r.CurrentTable.InsertDecl(decl.Name(), decl); existed {
-		r.err(ddperror.SEM_NAME_ALREADY_DEFINED, decl.NameTok.Range, ddperror.MsgNameAlreadyExists(decl.Name())) // type aliases may only be declared once in the same module
+		r.errAlreadyDeclared(decl.NameTok.Range, decl.Name()) // type aliases may only be declared once in the same module
 	}
 	// insert the type decl into the public module decls
 	if _, alreadyExists := r.Module.PublicDecls[decl.Name()]; decl.IsPublic && !alreadyExists {
@@ -166,7 +210,7 @@ func (r *Resolver) VisitTypeDefDecl(decl *ast.TypeDefDecl) ast.VisitResult {
 	}
 
 	if existed := r.CurrentTable.InsertDecl(decl.Name(), decl); existed {
-		r.err(ddperror.SEM_NAME_ALREADY_DEFINED, decl.NameTok.Range, ddperror.MsgNameAlreadyExists(decl.Name())) // type defs may only be declared once in the same module
+		r.errAlreadyDeclared(decl.NameTok.Range, decl.Name()) // type defs may only be declared once in the same module
 	}
 	// insert the type decl into the public module decls
 	if _, alreadyExists := r.Module.PublicDecls[decl.Name()]; decl.IsPublic && !alreadyExists {
@@ -183,12 +227,13 @@ func (r *Resolver) VisitBadExpr(expr *ast.BadExpr) ast.VisitResult {
 
 func (r *Resolver) VisitIdent(expr *ast.Ident) ast.VisitResult {
 	// check if the variable exists
-	if decl, exists, isVar := r.CurrentTable.LookupDecl(expr.Literal.Literal); !exists {
+	if decl, _, isVar := r.CurrentTable.LookupVar(expr.Literal.Literal); isVar { // set the reference to the declaration
+		expr.Declaration = decl
+		r.index(expr.Literal.Range, expr.Declaration)
+	} else if _, exists, _ := r.CurrentTable.LookupDecl(expr.Literal.Literal); !exists {
 		r.err(ddperror.SEM_NAME_UNDEFINED, expr.Token().Range, fmt.Sprintf("Der Name '%s' wurde noch nicht als Variable deklariert", expr.Literal.Literal))
-	} else if !isVar {
+	} else {
 		r.err(ddperror.SEM_BAD_NAME_CONTEXT, expr.Token().Range, fmt.Sprintf("Der Name '%s' steht für eine Funktion oder Struktur und nicht für eine Variable", expr.Literal.Literal))
-	} else { // set the reference to the declaration
-		expr.Declaration = decl.(*ast.VarDecl)
 	}
 	return ast.VisitRecurse
 }
@@ -225,6 +270,14 @@ func (r *Resolver) VisitStringLit(expr *ast.StringLit) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (r *Resolver) VisitNothingLit(expr *ast.NothingLit) ast.VisitResult {
+	return ast.VisitRecurse
+}
+
+func (r *Resolver) VisitEnumLit(expr *ast.EnumLit) ast.VisitResult {
+	return ast.VisitRecurse
+}
+
 func (r *Resolver) VisitListLit(expr *ast.ListLit) ast.VisitResult {
 	if expr.Values != nil {
 		for _, v := range expr.Values {
@@ -280,14 +333,39 @@ func (r *Resolver) VisitGrouping(expr *ast.Grouping) ast.VisitResult {
 }
 
 func (r *Resolver) VisitFuncCall(expr *ast.FuncCall) ast.VisitResult {
+	// the function itself was already resolved by the parser when matching the alias
+	if expr.Func != nil {
+		r.index(expr.Range, expr.Func)
+	}
 	// visit the passed arguments
-	for _, v := range expr.Args {
+	for _, v := range expr.OrderedArgs() {
 		r.visit(v)
 	}
 	return ast.VisitRecurse
 }
 
 func (r *Resolver) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResult {
+	for _, arg := range expr.OrderedArgs() {
+		r.visit(arg)
+	}
+	return ast.VisitRecurse
+}
+
+func (r *Resolver) VisitFuncRef(expr *ast.FuncRef) ast.VisitResult {
+	// check if the function exists
+	if decl, _, isFunc := r.CurrentTable.LookupFunc(expr.Name.Literal); isFunc {
+		expr.Func = decl
+		r.index(expr.Name.Range, decl)
+	} else if _, exists, _ := r.CurrentTable.LookupDecl(expr.Name.Literal); !exists {
+		r.err(ddperror.SEM_NAME_UNDEFINED, expr.Name.Range, fmt.Sprintf("Der Name '%s' wurde noch nicht als Funktion deklariert", expr.Name.Literal))
+	} else {
+		r.err(ddperror.SEM_BAD_NAME_CONTEXT, expr.Name.Range, fmt.Sprintf("Der Name '%s' steht für eine Variable oder Struktur und nicht für eine Funktion", expr.Name.Literal))
+	}
+	return ast.VisitRecurse
+}
+
+func (r *Resolver) VisitFuncValueCall(expr *ast.FuncValueCall) ast.VisitResult {
+	r.visit(expr.Callee)
 	for _, arg := range expr.Args {
 		r.visit(arg)
 	}
@@ -346,6 +424,8 @@ func (r *Resolver) VisitImportStmt(stmt *ast.ImportStmt) ast.VisitResult {
 			checkSingleType(decl.Underlying)
 		case *ast.TypeDefDecl:
 			checkSingleType(decl.Underlying)
+		case *ast.EnumDecl:
+			// enums don't depend on other types
 		case *ast.BadDecl:
 			// error already reported while parsing the imported module
 		}
@@ -356,6 +436,14 @@ func (r *Resolver) VisitImportStmt(stmt *ast.ImportStmt) ast.VisitResult {
 			r.err(ddperror.SEM_NAME_ALREADY_DEFINED, stmt.FileName.Range, fmt.Sprintf("Der Name '%s' aus dem Modul '%s' existiert bereits in diesem Modul", decl.Name(), stmt.Module.GetIncludeFilename()))
 			return
 		}
+		// the variants of an enum are imported together with it
+		if enumDecl, isEnum := decl.(*ast.EnumDecl); isEnum {
+			for _, variant := range enumDecl.Variants {
+				if existed := r.CurrentTable.InsertDecl(variant.Literal, decl); existed {
+					r.err(ddperror.SEM_NAME_ALREADY_DEFINED, stmt.FileName.Range, fmt.Sprintf("Der Name '%s' aus dem Modul '%s' existiert bereits in diesem Modul", variant.Literal, stmt.Module.GetIncludeFilename()))
+				}
+			}
+		}
 
 		checkTypeDependency(decl)
 	}
@@ -376,12 +464,13 @@ func (r *Resolver) VisitAssignStmt(stmt *ast.AssignStmt) ast.VisitResult {
 	switch assign := stmt.Var.(type) {
 	case *ast.Ident:
 		// check if the variable exists
-		if varDecl, exists, isVar := r.CurrentTable.LookupDecl(assign.Literal.Literal); !exists {
+		if varDecl, _, isVar := r.CurrentTable.LookupVar(assign.Literal.Literal); isVar { // set the reference to the declaration
+			assign.Declaration = varDecl
+			r.index(assign.Literal.Range, assign.Declaration)
+		} else if _, exists, _ := r.CurrentTable.LookupDecl(assign.Literal.Literal); !exists {
 			r.err(ddperror.SEM_NAME_UNDEFINED, assign.Literal.Range, fmt.Sprintf("Der Name '%s' wurde in noch nicht als Variable deklariert", assign.Literal.Literal))
-		} else if !isVar {
+		} else {
 			r.err(ddperror.SEM_BAD_NAME_CONTEXT, assign.Token().Range, fmt.Sprintf("Der Name '%s' steht für eine Funktion oder Struktur und nicht für eine Variable", assign.Literal.Literal))
-		} else { // set the reference to the declaration
-			assign.Declaration = varDecl.(*ast.VarDecl)
 		}
 	case *ast.Indexing:
 		r.visit(assign.Lhs)
@@ -425,6 +514,8 @@ func (r *Resolver) VisitWhileStmt(stmt *ast.WhileStmt) ast.VisitResult {
 }
 
 func (r *Resolver) VisitForStmt(stmt *ast.ForStmt) ast.VisitResult {
+	// the counter is declared in the scope of the body
+	// so it is not accessible after the loop
 	r.setScope(stmt.Body.Symbols)
 	// only visit the InitVal because the variable is already in the scope
 	r.visit(stmt.Initializer.InitVal)
@@ -438,6 +529,8 @@ func (r *Resolver) VisitForStmt(stmt *ast.ForStmt) ast.VisitResult {
 }
 
 func (r *Resolver) VisitForRangeStmt(stmt *ast.ForRangeStmt) ast.VisitResult {
+	// the counter is declared in the scope of the body
+	// so it is not accessible after the loop
 	r.setScope(stmt.Body.Symbols)
 	// only visit the InitVal because the variable is already in the scope
 	r.visit(stmt.Initializer.InitVal)
@@ -465,3 +558,16 @@ func (r *Resolver) VisitReturnStmt(stmt *ast.ReturnStmt) ast.VisitResult {
 func (*Resolver) VisitTodoStmt(*ast.TodoStmt) ast.VisitResult {
 	return ast.VisitRecurse
 }
+
+func (r *Resolver) VisitAssertStmt(stmt *ast.AssertStmt) ast.VisitResult {
+	r.visit(stmt.Condition)
+	if stmt.Message != nil {
+		r.visit(stmt.Message)
+	}
+	return ast.VisitRecurse
+}
+
+func (r *Resolver) VisitPrintStmt(stmt *ast.PrintStmt) ast.VisitResult {
+	r.visit(stmt.Value)
+	return ast.VisitRecurse
+}
@@ -0,0 +1,100 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, src string) *ast.Module {
+	t.Helper()
+	module, err := parser.Parse(parser.Options{
+		FileName:     "test.ddp",
+		Source:       []byte(src),
+		ErrorHandler: ddperror.MakePanicHandler(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return module
+}
+
+// a resolved name and the line it is used in
+type resolved struct {
+	line uint
+	decl ast.Declaration
+}
+
+// collects the declarations of all Idents and FuncCalls in source order
+type resolvedCollector struct {
+	idents, calls []resolved
+}
+
+func (*resolvedCollector) Visitor() {}
+
+func (c *resolvedCollector) VisitIdent(expr *ast.Ident) ast.VisitResult {
+	c.idents = append(c.idents, resolved{expr.Literal.Range.Start.Line, expr.Declaration})
+	return ast.VisitRecurse
+}
+
+func (c *resolvedCollector) VisitFuncCall(expr *ast.FuncCall) ast.VisitResult {
+	c.calls = append(c.calls, resolved{expr.Range.Start.Line, expr.Func})
+	return ast.VisitRecurse
+}
+
+func TestResolveNestedScopes(t *testing.T) {
+	assert := assert.New(t)
+
+	module := parse(t, `Die Zahl x ist 1.
+Die Funktion f mit dem Parameter x vom Typ Zahl, gibt eine Zahl zurück, macht:
+	Wenn x gleich 1 ist, dann:
+		Die Zahl x ist 2.
+		Speichere x plus 1 in x.
+		Gib x zurück.
+	Gib x zurück.
+Und kann so benutzt werden:
+	"f von <x>"
+
+Die Funktion g gibt eine Zahl zurück, macht:
+	Die Zahl y ist x.
+	Wenn y gleich 1 ist, dann:
+		Gib f von y zurück.
+	Gib f von x zurück.
+Und kann so benutzt werden:
+	"g"
+
+Speichere f von x in x.
+`)
+
+	globals := module.Ast.Symbols.Declarations
+	global := globals["x"].(*ast.VarDecl)
+	f, g := globals["f"].(*ast.FuncDecl), globals["g"].(*ast.FuncDecl)
+	param := f.Body.Symbols.Declarations["x"].(*ast.VarDecl)
+	shadowing := f.Body.Statements[0].(*ast.IfStmt).Then.(*ast.BlockStmt).Symbols.Declarations["x"].(*ast.VarDecl)
+	y := g.Body.Symbols.Declarations["y"].(*ast.VarDecl)
+
+	collector := &resolvedCollector{}
+	ast.VisitModule(module, collector)
+
+	assert.Equal([]resolved{
+		{3, param},
+		{5, shadowing}, // the assigned variable
+		{5, shadowing},
+		{6, shadowing},
+		{7, param},
+		{12, global},
+		{13, y},
+		{14, y},
+		{15, global},
+		{19, global}, // the assigned variable
+		{19, global},
+	}, collector.idents)
+	assert.Equal([]resolved{
+		{14, f},
+		{15, f},
+		{19, f},
+	}, collector.calls)
+}
@@ -66,6 +66,12 @@ func (p *parser) statement() ast.Statement {
 	case token.ELIPSIS:
 		p.consume(token.ELIPSIS)
 		return p.todoStmt()
+	case token.STELLE:
+		// Stelle is also used in function aliases (e.g. "Stelle <c> vor <t>")
+		if p.peekN(1).Type == token.SICHER {
+			p.consume(token.STELLE)
+			return p.assertStatement()
+		}
 	}
 
 	// no other statement was found, so interpret it as expression statement, whose result will be discarded
@@ -154,10 +160,7 @@ func (p *parser) finishStatement(stmt ast.Statement) ast.Statement {
 	tok.Type = token.WIEDERHOLE
 	p.consume(token.DOT)
 	return &ast.WhileStmt{
-		Range: token.Range{
-			Start: stmt.GetRange().Start,
-			End:   token.NewEndPos(p.previous()),
-		},
+		Range:     token.Merge(stmt.GetRange(), p.previous().Range),
 		While:     *tok,
 		Condition: count,
 		Body:      stmt,
@@ -191,6 +194,8 @@ func (p *parser) compoundAssignement() ast.Statement {
 		operator := ast.UN_NEGATE
 		if ddptypes.Equal(typ, ddptypes.WAHRHEITSWERT) {
 			operator = ast.UN_NOT
+		} else if ddptypes.Equal(typ, ddptypes.BUCHSTABE) {
+			operator = ast.UN_LOGIC_NOT
 		}
 		return &ast.AssignStmt{
 			Range: token.NewRange(tok, p.previous()),
@@ -389,10 +394,7 @@ func (p *parser) whileStatement() ast.Statement {
 	}
 	p.resolver.LoopDepth--
 	return &ast.WhileStmt{
-		Range: token.Range{
-			Start: token.NewStartPos(While),
-			End:   Body.GetRange().End,
-		},
+		Range:     token.Merge(While.Range, Body.GetRange()),
 		While:     *While,
 		Condition: condition,
 		Body:      Body,
@@ -409,10 +411,7 @@ func (p *parser) doWhileStmt() ast.Statement {
 	condition := p.expression()
 	p.consume(token.DOT)
 	return &ast.WhileStmt{
-		Range: token.Range{
-			Start: token.NewStartPos(Do),
-			End:   token.NewEndPos(p.previous()),
-		},
+		Range:     token.Merge(Do.Range, p.previous().Range),
 		While:     *Do,
 		Condition: condition,
 		Body:      body,
@@ -428,10 +427,7 @@ func (p *parser) repeatStmt() ast.Statement {
 	count := p.expression()
 	p.consume(token.COUNT_MAL, token.DOT)
 	return &ast.WhileStmt{
-		Range: token.Range{
-			Start: token.NewStartPos(repeat),
-			End:   body.GetRange().End,
-		},
+		Range:     token.Merge(repeat.Range, body.GetRange()),
 		While:     *repeat,
 		Condition: count,
 		Body:      body,
@@ -468,10 +464,7 @@ func (p *parser) forStatement() ast.Statement {
 	if p.matchAny(token.VON) {
 		from := p.expression() // start of the counter
 		initializer := &ast.VarDecl{
-			Range: token.Range{
-				Start: token.NewStartPos(TypeTok),
-				End:   from.GetRange().End,
-			},
+			Range:      token.Merge(TypeTok.Range, from.GetRange()),
 			CommentTok: iteratorComment,
 			Type:       Typ,
 			NameTok:    *Ident,
@@ -504,10 +497,7 @@ func (p *parser) forStatement() ast.Statement {
 			p.exitScope()
 			// wrap the single statement in a block for variable-scoping of the counter variable in the resolver and typechecker
 			Body = &ast.BlockStmt{
-				Range: token.Range{
-					Start: token.NewStartPos(Colon),
-					End:   stmt.GetRange().End,
-				},
+				Range:      token.Merge(Colon.Range, stmt.GetRange()),
 				Colon:      *Colon,
 				Statements: []ast.Statement{stmt},
 				Symbols:    bodyTable,
@@ -515,10 +505,7 @@ func (p *parser) forStatement() ast.Statement {
 		}
 		p.resolver.LoopDepth--
 		return &ast.ForStmt{
-			Range: token.Range{
-				Start: token.NewStartPos(For),
-				End:   Body.GetRange().End,
-			},
+			Range:       token.Merge(For.Range, Body.GetRange()),
 			For:         *For,
 			Initializer: initializer,
 			To:          to,
@@ -528,10 +515,7 @@ func (p *parser) forStatement() ast.Statement {
 	} else if p.matchAny(token.IN) {
 		In := p.expression()
 		initializer := &ast.VarDecl{
-			Range: token.Range{
-				Start: token.NewStartPos(TypeTok),
-				End:   In.GetRange().End,
-			},
+			Range:    token.Merge(TypeTok.Range, In.GetRange()),
 			Type:     Typ,
 			NameTok:  *Ident,
 			IsPublic: false,
@@ -553,10 +537,7 @@ func (p *parser) forStatement() ast.Statement {
 			p.exitScope()
 			// wrap the single statement in a block for variable-scoping of the counter variable in the resolver and typechecker
 			Body = &ast.BlockStmt{
-				Range: token.Range{
-					Start: token.NewStartPos(Colon),
-					End:   stmt.GetRange().End,
-				},
+				Range:      token.Merge(Colon.Range, stmt.GetRange()),
 				Colon:      *Colon,
 				Statements: []ast.Statement{stmt},
 				Symbols:    bodyTable,
@@ -564,10 +545,7 @@ func (p *parser) forStatement() ast.Statement {
 		}
 		p.resolver.LoopDepth--
 		return &ast.ForRangeStmt{
-			Range: token.Range{
-				Start: token.NewStartPos(For),
-				End:   Body.GetRange().End,
-			},
+			Range:       token.Merge(For.Range, Body.GetRange()),
 			For:         *For,
 			Initializer: initializer,
 			In:          In,
@@ -590,6 +568,10 @@ func (p *parser) returnStatement() ast.Statement {
 		expr = p.expression()
 	}
 
+	if p.check(token.AUS) || p.check(token.IDENTIFIER) && p.peek().Literal == "auf" {
+		return p.printStatement(Return, expr)
+	}
+
 	p.consume(token.ZURÜCK, token.DOT)
 	rnge := token.NewRange(Return, p.previous())
 	if p.currentFunction == nil {
@@ -603,6 +585,19 @@ func (p *parser) returnStatement() ast.Statement {
 	}
 }
 
+// Gib <Ausdruck> [auf einer neuen Zeile] aus.
+// Gib and the expression were already parsed by returnStatement
+func (p *parser) printStatement(Gib *token.Token, value ast.Expression) ast.Statement {
+	newLine := p.matchLiterals("auf", "einer", "neuen", "Zeile")
+	p.consume(token.AUS, token.DOT)
+	return &ast.PrintStmt{
+		Range:   token.NewRange(Gib, p.previous()),
+		Tok:     *Gib,
+		Value:   value,
+		NewLine: newLine,
+	}
+}
+
 func (p *parser) voidReturnOrBreak() ast.Statement {
 	Leave := p.previous()
 	p.consume(token.DIE)
@@ -670,6 +665,25 @@ func (p *parser) todoStmt() ast.Statement {
 	}
 }
 
+// Stelle sicher, dass <Bedingung>[, sonst <Text>].
+func (p *parser) assertStatement() ast.Statement {
+	Stelle := p.previous()
+	p.consume(token.SICHER, token.COMMA, token.DASS)
+	condition := p.expression()
+	var message ast.Expression
+	if p.matchAny(token.COMMA) {
+		p.consume(token.SONST)
+		message = p.expression()
+	}
+	p.consume(token.DOT)
+	return &ast.AssertStmt{
+		Range:     token.NewRange(Stelle, p.previous()),
+		Tok:       *Stelle,
+		Condition: condition,
+		Message:   message,
+	}
+}
+
 func (p *parser) expressionStatement() ast.Statement {
 	return p.finishStatement(&ast.ExprStmt{Expr: p.expression()})
 }
@@ -36,6 +36,22 @@ func (p *parser) matchSeq(types ...token.TokenType) bool {
 	return true
 }
 
+// like matchSeq but compares the literals of the tokens
+// used for words that are not keywords
+func (p *parser) matchLiterals(literals ...string) bool {
+	for i, lit := range literals {
+		if p.peekN(i).Literal != lit {
+			return false
+		}
+	}
+
+	for range literals {
+		p.advance()
+	}
+
+	return true
+}
+
 // if the current token is of type t advance, otherwise error
 func (p *parser) consume1(t token.TokenType) bool {
 	if p.check(t) {
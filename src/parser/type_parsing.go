@@ -38,7 +38,7 @@ func (p *parser) tokenTypeToType(t token.TokenType) ddptypes.Type {
 // returns nil and errors if no typename was found
 func (p *parser) parseType() ddptypes.Type {
 	if !p.matchAny(token.ZAHL, token.KOMMAZAHL, token.WAHRHEITSWERT, token.BUCHSTABE,
-		token.TEXT, token.ZAHLEN, token.KOMMAZAHLEN, token.BUCHSTABEN, token.IDENTIFIER, token.VARIABLE, token.VARIABLEN) {
+		token.TEXT, token.ZAHLEN, token.KOMMAZAHLEN, token.BUCHSTABEN, token.IDENTIFIER, token.VARIABLE, token.VARIABLEN, token.VIELLEICHT, token.FUNKTION) {
 		p.err(ddperror.SYN_EXPECTED_TYPENAME, p.peek().Range, ddperror.MsgGotExpected(p.peek().Literal, "ein Typname"))
 		return nil
 	}
@@ -46,6 +46,10 @@ func (p *parser) parseType() ddptypes.Type {
 	switch p.previous().Type {
 	case token.ZAHL, token.KOMMAZAHL, token.BUCHSTABE, token.VARIABLE:
 		return p.tokenTypeToType(p.previous().Type)
+	case token.VIELLEICHT:
+		return p.parseOptionalType()
+	case token.FUNKTION:
+		return p.parseFunctionType()
 	case token.WAHRHEITSWERT, token.TEXT:
 		if !p.matchAny(token.LISTE) {
 			return p.tokenTypeToType(p.previous().Type)
@@ -69,7 +73,7 @@ func (p *parser) parseType() ddptypes.Type {
 	case token.IDENTIFIER:
 		if Type, exists := p.scope().LookupType(p.previous().Literal); exists {
 			if p.matchAny(token.LISTE) {
-				return ddptypes.ListType{Underlying: Type}
+				return p.listTypeOf(Type)
 			}
 			return Type
 		}
@@ -79,6 +83,67 @@ func (p *parser) parseType() ddptypes.Type {
 	return nil // unreachable
 }
 
+// parses the underlying type of an optional type like "vielleicht Zahl"
+// expects the VIELLEICHT token to be already consumed
+// returns nil and errors if the underlying type is not supported
+func (p *parser) parseOptionalType() ddptypes.Type {
+	if !p.matchAny(token.ZAHL, token.KOMMAZAHL, token.WAHRHEITSWERT, token.BUCHSTABE, token.BUCHSTABEN) {
+		p.err(ddperror.SYN_EXPECTED_TYPENAME, p.peek().Range, ddperror.MsgGotExpected(p.peek().Literal, "Zahl", "Kommazahl", "Wahrheitswert", "Buchstabe"))
+		return nil
+	}
+
+	if p.previous().Type == token.BUCHSTABEN {
+		// edge case in function return types like "einen vielleicht Buchstaben"
+		if p.peekN(-3).Type != token.EINEN {
+			p.err(ddperror.SYN_EXPECTED_TYPENAME, p.previous().Range, ddperror.MsgGotExpected(p.previous().Literal, "Buchstabe"))
+			return nil
+		}
+		return ddptypes.OptionalType{Underlying: ddptypes.BUCHSTABE}
+	}
+	return ddptypes.OptionalType{Underlying: p.tokenTypeToType(p.previous().Type)}
+}
+
+// parses the signature of a function type like "Funktion(Zahl, Text) gibt Zahl"
+// expects the FUNKTION token to be already consumed
+// returns nil and errors if one of the parameter or return types is invalid
+func (p *parser) parseFunctionType() ddptypes.Type {
+	if !p.consume(token.LPAREN) {
+		return nil
+	}
+
+	params := make([]ddptypes.Type, 0, 2)
+	valid := true
+	if !p.matchAny(token.RPAREN) {
+		for {
+			param := p.parseType()
+			if param == nil {
+				valid = false
+			}
+			params = append(params, param)
+			if !p.matchAny(token.COMMA) {
+				break
+			}
+		}
+		if !p.consume(token.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.consume(token.GIBT) {
+		return nil
+	}
+
+	var returnType ddptypes.Type = ddptypes.VoidType{}
+	if !p.matchAny(token.NICHTS) {
+		returnType = p.parseType()
+	}
+
+	if !valid || returnType == nil {
+		return nil
+	}
+	return ddptypes.NewFunctionType(params, returnType)
+}
+
 // parses tokens into a DDPType which must be a list type
 // expects the next token to be the start of the type
 // returns VoidList and errors if no typename was found
@@ -103,7 +168,7 @@ func (p *parser) parseListType() ddptypes.ListType {
 		result = ddptypes.ListType{Underlying: ddptypes.VARIABLE}
 	case token.IDENTIFIER:
 		if Type, exists := p.scope().LookupType(p.previous().Literal); exists {
-			result = ddptypes.ListType{Underlying: Type}
+			result = p.listTypeOf(Type)
 		} else {
 			p.err(ddperror.SYN_EXPECTED_TYPENAME, p.previous().Range, ddperror.MsgGotExpected(p.previous().Literal, "ein Listen-Typname"))
 		}
@@ -118,7 +183,7 @@ func (p *parser) parseListType() ddptypes.ListType {
 // returns nil and errors if no typename was found
 func (p *parser) parseReferenceType() (ddptypes.Type, bool) {
 	if !p.matchAny(token.ZAHL, token.KOMMAZAHL, token.WAHRHEITSWERT, token.BUCHSTABE,
-		token.TEXT, token.ZAHLEN, token.KOMMAZAHLEN, token.BUCHSTABEN, token.IDENTIFIER, token.VARIABLE, token.VARIABLEN) {
+		token.TEXT, token.ZAHLEN, token.KOMMAZAHLEN, token.BUCHSTABEN, token.IDENTIFIER, token.VARIABLE, token.VARIABLEN, token.VIELLEICHT, token.FUNKTION) {
 		p.err(ddperror.SYN_EXPECTED_TYPENAME, p.peek().Range, ddperror.MsgGotExpected(p.peek().Literal, "ein Typname"))
 		return nil, false // void indicates error
 	}
@@ -126,6 +191,16 @@ func (p *parser) parseReferenceType() (ddptypes.Type, bool) {
 	switch p.previous().Type {
 	case token.ZAHL, token.KOMMAZAHL, token.BUCHSTABE, token.VARIABLE:
 		return p.tokenTypeToType(p.previous().Type), false
+	case token.VIELLEICHT: // optionals can not be references (yet)
+		if typ := p.parseOptionalType(); typ != nil {
+			return typ, false
+		}
+		return nil, false
+	case token.FUNKTION: // function values can not be references
+		if typ := p.parseFunctionType(); typ != nil {
+			return typ, false
+		}
+		return nil, false
 	case token.WAHRHEITSWERT, token.TEXT:
 		if p.matchAny(token.LISTE) {
 			return ddptypes.ListType{Underlying: p.tokenTypeToType(p.peekN(-2).Type)}, false
@@ -179,14 +254,14 @@ func (p *parser) parseReferenceType() (ddptypes.Type, bool) {
 	case token.IDENTIFIER:
 		if Type, exists := p.scope().LookupType(p.previous().Literal); exists {
 			if p.matchAny(token.LISTE) {
-				return ddptypes.ListType{Underlying: Type}, false
+				return p.listTypeOf(Type), false
 			} else if p.matchAny(token.LISTEN) {
 				if !p.consume(token.REFERENZ) {
 					// report the error on the REFERENZ token, but still advance
 					// because there is a valid token afterwards
 					p.advance()
 				}
-				return ddptypes.ListType{Underlying: Type}, true
+				return p.listTypeOf(Type), true
 			} else if p.matchAny(token.REFERENZ) {
 				return Type, true
 			}
@@ -229,3 +304,12 @@ func (p *parser) parseReturnType() ddptypes.Type {
 	}
 	return typ
 }
+
+// returns the list type of the user defined type typ
+// lists of enums are not supported, so an error is reported for them
+func (p *parser) listTypeOf(typ ddptypes.Type) ddptypes.ListType {
+	if ddptypes.IsEnum(ddptypes.TrueUnderlying(typ)) {
+		p.err(ddperror.SYN_EXPECTED_TYPENAME, p.previous().Range, fmt.Sprintf("Es gibt keine Listen von Aufzählungen (%s)", typ))
+	}
+	return ddptypes.ListType{Underlying: typ}
+}
@@ -56,6 +56,7 @@ func (a *assignableCaster) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult
 		ast.VisitNode(a, expr.Rhs, nil)
 		if a.ass != nil {
 			a.ass = &ast.FieldAccess{
+				Range: expr.Range,
 				Field: ident,
 				Rhs:   a.ass,
 			}
@@ -64,6 +65,7 @@ func (a *assignableCaster) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult
 		ast.VisitNode(a, expr.Lhs, nil)
 		if a.ass != nil {
 			a.ass = &ast.Indexing{
+				Range: expr.Range,
 				Lhs:   a.ass,
 				Index: expr.Rhs,
 			}
@@ -0,0 +1,110 @@
+package typechecker
+
+import (
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+)
+
+// describes one valid combination of operand types for a builtin operator
+type OperatorSignature struct {
+	Operator ast.Operator
+	Operands []ddptypes.Type
+	Result   ddptypes.Type
+}
+
+// the types which are tried as operands by BuiltinOperators
+var builtinOperandTypes = []ddptypes.Type{
+	ddptypes.ZAHL,
+	ddptypes.KOMMAZAHL,
+	ddptypes.WAHRHEITSWERT,
+	ddptypes.BUCHSTABE,
+	ddptypes.TEXT,
+	ddptypes.ListType{Underlying: ddptypes.ZAHL},
+	ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL},
+	ddptypes.ListType{Underlying: ddptypes.WAHRHEITSWERT},
+	ddptypes.ListType{Underlying: ddptypes.BUCHSTABE},
+	ddptypes.ListType{Underlying: ddptypes.TEXT},
+	ddptypes.OptionalType{Underlying: ddptypes.ZAHL},
+	ddptypes.OptionalType{Underlying: ddptypes.KOMMAZAHL},
+	ddptypes.OptionalType{Underlying: ddptypes.WAHRHEITSWERT},
+	ddptypes.OptionalType{Underlying: ddptypes.BUCHSTABE},
+}
+
+// returns all combinations of primitive, primitive list and optional operand types
+// that are accepted by the builtin operators, in the order the operators are declared
+// the signatures are found by typechecking every combination
+// so they always match what the typechecker actually accepts
+func BuiltinOperators() []OperatorSignature {
+	hadError := false
+	panicMode := false
+	t := New(&ast.Module{
+		Ast: &ast.Ast{Symbols: ast.NewSymbolTable(nil)},
+	}, func(err ddperror.Error) {
+		if err.Level == ddperror.LEVEL_ERROR {
+			hadError = true
+		}
+	}, "", &panicMode)
+
+	var signatures []OperatorSignature
+	// typechecks expr and adds the signature if it is valid
+	check := func(operator ast.Operator, expr ast.Expression, operands ...ddptypes.Type) {
+		hadError, panicMode = false, false
+		result := t.Evaluate(expr)
+		if !hadError && !ddptypes.IsVoid(result) && !ddptypes.IsInvalid(result) {
+			signatures = append(signatures, OperatorSignature{Operator: operator, Operands: operands, Result: result})
+		}
+	}
+
+	for _, op := range ast.UnaryOperators() {
+		for _, rhs := range builtinOperandTypes {
+			check(op, &ast.UnaryExpr{Operator: op, Rhs: operandOfType(rhs)}, rhs)
+		}
+	}
+	for _, op := range ast.BinaryOperators() {
+		for _, lhs := range builtinOperandTypes {
+			for _, rhs := range builtinOperandTypes {
+				check(op, &ast.BinaryExpr{Operator: op, Lhs: operandOfType(lhs), Rhs: operandOfType(rhs)}, lhs, rhs)
+			}
+		}
+	}
+	for _, op := range ast.TernaryOperators() {
+		for _, lhs := range builtinOperandTypes {
+			for _, mid := range builtinOperandTypes {
+				for _, rhs := range builtinOperandTypes {
+					check(op, &ast.TernaryExpr{Operator: op, Lhs: operandOfType(lhs), Mid: operandOfType(mid), Rhs: operandOfType(rhs)}, lhs, mid, rhs)
+				}
+			}
+		}
+	}
+	for _, lhs := range builtinOperandTypes {
+		for _, target := range builtinOperandTypes {
+			check(ast.CAST_OP, &ast.CastExpr{TargetType: target, Lhs: operandOfType(lhs)}, lhs)
+		}
+	}
+	return signatures
+}
+
+// returns an expression of the given primitive, primitive list or optional type
+func operandOfType(typ ddptypes.Type) ast.Expression {
+	if listType, isList := ddptypes.CastList(typ); isList {
+		return &ast.ListLit{Type: listType}
+	}
+	if ddptypes.IsOptional(typ) {
+		return &ast.NothingLit{Type: typ}
+	}
+
+	switch typ {
+	case ddptypes.ZAHL:
+		// 0 would be rejected as literal index by checkLiteralIndex
+		return &ast.IntLit{Value: 1}
+	case ddptypes.KOMMAZAHL:
+		return &ast.FloatLit{}
+	case ddptypes.WAHRHEITSWERT:
+		return &ast.BoolLit{}
+	case ddptypes.BUCHSTABE:
+		return &ast.CharLit{}
+	default:
+		return &ast.StringLit{}
+	}
+}
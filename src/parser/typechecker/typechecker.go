@@ -2,6 +2,8 @@ package typechecker
 
 import (
 	"fmt"
+	"math/big"
+	"strings"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ast"
 	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
@@ -33,7 +35,7 @@ func New(Mod *ast.Module, errorHandler ddperror.Handler, file string, panicMode
 	return &Typechecker{
 		ErrorHandler:       errorHandler,
 		CurrentTable:       Mod.Ast.Symbols,
-		latestReturnedType: ddptypes.VoidType{}, // void signals invalid
+		latestReturnedType: ddptypes.InvalidType{},
 		Module:             Mod,
 		panicMode:          panicMode,
 	}
@@ -66,17 +68,60 @@ func (t *Typechecker) EvaluateSilent(expr ast.Expression) ddptypes.Type {
 }
 
 // helper for errors
-func (t *Typechecker) err(code ddperror.Code, Range token.Range, msg string) {
+func (t *Typechecker) err(code ddperror.Code, Range token.Range, msg string, notes ...ddperror.Note) {
 	t.Module.Ast.Faulty = true
 	if !*t.panicMode {
 		*t.panicMode = true
-		t.ErrorHandler(ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, t.Module.FileName))
+		err := ddperror.New(code, ddperror.LEVEL_ERROR, Range, msg, t.Module.FileName)
+		err.Notes = notes
+		t.ErrorHandler(err)
 	}
 }
 
+// helper for warnings
+// warnings don't make the Module faulty and ignore the panic mode
+func (t *Typechecker) warn(code ddperror.Code, Range token.Range, msg string) {
+	t.ErrorHandler(ddperror.New(code, ddperror.LEVEL_WARN, Range, msg, t.Module.FileName))
+}
+
 // helper to not always pass range and file
+// errors about expressions containing a BadExpr are not reported, because the parser
+// already reported the syntax error for them and they would only cascade
 func (t *Typechecker) errExpr(code ddperror.Code, expr ast.Expression, msgfmt string, fmtargs ...any) {
-	t.err(code, expr.GetRange(), fmt.Sprintf(msgfmt, fmtargs...))
+	t.errExprWithNotes(code, expr, nil, msgfmt, fmtargs...)
+}
+
+// like errExpr but attaches notes to the error
+func (t *Typechecker) errExprWithNotes(code ddperror.Code, expr ast.Expression, notes []ddperror.Note, msgfmt string, fmtargs ...any) {
+	if containsBadExpr(expr) || containsInvalidType(fmtargs) {
+		t.Module.Ast.Faulty = true
+		return
+	}
+	t.err(code, expr.GetRange(), fmt.Sprintf(msgfmt, describeTypes(fmtargs)...), notes...)
+}
+
+// errors about values of an InvalidType are caused by an error that was already reported
+func containsInvalidType(args []any) bool {
+	for _, arg := range args {
+		if typ, ok := arg.(ddptypes.Type); ok && ddptypes.IsInvalid(typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// replaces all types in args by their description (see ddptypes.Describe)
+// so that type aliases are shown together with their underlying type
+func describeTypes(args []any) []any {
+	described := make([]any, len(args))
+	for i, arg := range args {
+		if typ, ok := arg.(ddptypes.Type); ok {
+			described[i] = ddptypes.Describe(typ)
+		} else {
+			described[i] = arg
+		}
+	}
+	return described
 }
 
 // helper for commmon error message
@@ -99,19 +144,33 @@ func (t *Typechecker) errExpected(operator ast.Operator, expr ast.Expression, go
 func (*Typechecker) Visitor() {}
 
 func (t *Typechecker) VisitBadDecl(decl *ast.BadDecl) ast.VisitResult {
-	t.latestReturnedType = ddptypes.VoidType{}
+	t.latestReturnedType = ddptypes.InvalidType{}
 	return ast.VisitRecurse
 }
 
 func (t *Typechecker) VisitVarDecl(decl *ast.VarDecl) ast.VisitResult {
-	initialType := t.Evaluate(decl.InitVal)
-	decl.InitType = initialType
-	if !ddptypes.Equal(initialType, decl.Type) && (!ddptypes.Equal(decl.Type, ddptypes.VARIABLE) || ddptypes.Equal(initialType, ddptypes.VoidType{})) {
-		msg := fmt.Sprintf("Ein Wert vom Typ %s kann keiner Variable vom Typ %s zugewiesen werden", initialType, decl.Type)
-		t.errExpr(ddperror.TYP_BAD_ASSIGNEMENT,
-			decl.InitVal,
-			msg,
-		)
+	if decl.TypeInferred {
+		// the variable simply gets the type of its initial value
+		initialType := t.Evaluate(decl.InitVal)
+		decl.Type, decl.InitType = initialType, initialType
+		if ddptypes.IsVoid(initialType) {
+			t.errExpr(ddperror.TYP_BAD_ASSIGNEMENT,
+				decl.InitVal,
+				"Der Typ der Variable %s kann nicht aus ihrem Wert abgeleitet werden, da dieser keinen Typ hat, hier muss der Typ angegeben werden",
+				decl.Name(),
+			)
+		}
+	} else {
+		initialType := t.convertToOptional(&decl.InitVal, t.Evaluate(decl.InitVal), decl.Type)
+		decl.InitType = initialType
+		if !ddptypes.Equal(initialType, decl.Type) && (!ddptypes.Equal(decl.Type, ddptypes.VARIABLE) || !isValidAnyValue(initialType)) {
+			t.errExpr(ddperror.TYP_BAD_ASSIGNEMENT,
+				decl.InitVal,
+				"Ein Wert vom Typ %s kann keiner Variable vom Typ %s zugewiesen werden",
+				initialType,
+				decl.Type,
+			)
+		}
 	}
 
 	if decl.Public() && !IsPublicType(decl.Type, t.CurrentTable) {
@@ -158,6 +217,10 @@ func (t *Typechecker) VisitStructDecl(decl *ast.StructDecl) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (t *Typechecker) VisitEnumDecl(decl *ast.EnumDecl) ast.VisitResult {
+	return ast.VisitRecurse
+}
+
 func (t *Typechecker) VisitTypeAliasDecl(decl *ast.TypeAliasDecl) ast.VisitResult {
 	if decl.IsPublic && !IsPublicType(decl.Underlying, t.CurrentTable) {
 		t.err(ddperror.SEM_BAD_PUBLIC_MODIFIER, decl.NameTok.Range, "Der unterliegende Typ eines öffentlichen Typ-Aliases muss ebenfalls öffentlich sein")
@@ -175,16 +238,15 @@ func (t *Typechecker) VisitTypeDefDecl(decl *ast.TypeDefDecl) ast.VisitResult {
 }
 
 func (t *Typechecker) VisitBadExpr(expr *ast.BadExpr) ast.VisitResult {
-	t.latestReturnedType = ddptypes.VoidType{}
+	t.latestReturnedType = ddptypes.InvalidType{}
 	return ast.VisitRecurse
 }
 
 func (t *Typechecker) VisitIdent(expr *ast.Ident) ast.VisitResult {
-	decl, ok, isVar := t.CurrentTable.LookupDecl(expr.Literal.Literal)
-	if !ok || !isVar || decl == nil {
-		t.latestReturnedType = ddptypes.VoidType{}
+	if decl, _, ok := t.CurrentTable.LookupVar(expr.Literal.Literal); !ok {
+		t.latestReturnedType = ddptypes.InvalidType{}
 	} else {
-		t.latestReturnedType = decl.(*ast.VarDecl).Type
+		t.latestReturnedType = decl.Type
 	}
 	return ast.VisitRecurse
 }
@@ -193,6 +255,7 @@ func (t *Typechecker) VisitIndexing(expr *ast.Indexing) ast.VisitResult {
 	if typ := t.Evaluate(expr.Index); !ddptypes.Equal(typ, ddptypes.ZAHL) {
 		t.errExpr(ddperror.TYP_BAD_INDEXING, expr.Index, "Der STELLE Operator erwartet eine Zahl als zweiten Operanden, nicht %s", typ)
 	}
+	t.checkLiteralIndex(expr.Index)
 
 	lhs := t.Evaluate(expr.Lhs)
 	if !ddptypes.IsList(lhs) && !ddptypes.Equal(lhs, ddptypes.TEXT) {
@@ -211,7 +274,7 @@ func (t *Typechecker) VisitFieldAccess(expr *ast.FieldAccess) ast.VisitResult {
 	rhs := t.Evaluate(expr.Rhs)
 	if !ddptypes.IsStruct(rhs) {
 		t.errExpr(ddperror.TYP_BAD_FIELD_ACCESS, expr.Rhs, "Der VON Operator erwartet eine Struktur als rechten Operanden, nicht %s", rhs)
-		t.latestReturnedType = ddptypes.VoidType{}
+		t.latestReturnedType = ddptypes.InvalidType{}
 	} else {
 		t.latestReturnedType = t.checkFieldAccess(expr.Field, rhs)
 	}
@@ -243,16 +306,34 @@ func (t *Typechecker) VisitStringLit(expr *ast.StringLit) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (t *Typechecker) VisitNothingLit(expr *ast.NothingLit) ast.VisitResult {
+	// without a context the literal has no type
+	if expr.Type == nil {
+		t.latestReturnedType = ddptypes.VoidType{}
+	} else {
+		t.latestReturnedType = expr.Type
+	}
+	return ast.VisitRecurse
+}
+
+func (t *Typechecker) VisitEnumLit(expr *ast.EnumLit) ast.VisitResult {
+	t.latestReturnedType = expr.Decl.Type
+	return ast.VisitRecurse
+}
+
 func (t *Typechecker) VisitListLit(expr *ast.ListLit) ast.VisitResult {
 	if expr.Values != nil {
 		elementType := t.Evaluate(expr.Values[0])
 		for _, v := range expr.Values[1:] {
 			if ty := t.Evaluate(v); !ddptypes.Equal(elementType, ty) {
-				msg := fmt.Sprintf("Falscher Typ (%s) in Listen Literal vom Typ %s", ty, elementType)
-				t.errExpr(ddperror.TYP_BAD_LIST_LITERAL, v, msg)
+				t.errExpr(ddperror.TYP_BAD_LIST_LITERAL, v, "Falscher Typ (%s) in Listen Literal vom Typ %s", ty, elementType)
 			}
 		}
 		expr.Type = ddptypes.ListType{Underlying: elementType}
+		if !t.checkIsValue(expr.Values[0], elementType) {
+			t.latestReturnedType = ddptypes.InvalidType{}
+			return ast.VisitRecurse
+		}
 	} else if expr.Count != nil && expr.Value != nil {
 		if count := t.Evaluate(expr.Count); !ddptypes.Equal(count, ddptypes.ZAHL) {
 			t.errExpr(ddperror.TYP_BAD_LIST_LITERAL, expr, "Die Größe einer Liste muss als Zahl angegeben werden, nicht als %s", count)
@@ -265,10 +346,39 @@ func (t *Typechecker) VisitListLit(expr *ast.ListLit) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+// reports an error if expr, which is used as a value, is of type void
+// (e.g. the result of a function that returns nothing)
+// returns wether expr is a valid value
+func (t *Typechecker) checkIsValue(expr ast.Expression, typ ddptypes.Type) bool {
+	if ddptypes.IsVoid(typ) {
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Ein Ausdruck, der nichts zurückgibt, kann nicht als Wert benutzt werden")
+		return false
+	}
+	return !ddptypes.IsInvalid(typ)
+}
+
+// reports an error if two values of type elem
+// can not be concatenated into a new list
+func (t *Typechecker) checkConcatToList(expr *ast.BinaryExpr, elem ddptypes.Type) {
+	switch underlying := ddptypes.TrueUnderlying(elem); {
+	case ddptypes.IsEnum(underlying), ddptypes.IsOptional(underlying), ddptypes.IsFunction(underlying):
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Zwei Werte vom Typ %s können nicht verkettet werden, da es keine Listen von diesem Typ gibt", elem)
+	case ddptypes.Equal(underlying, ddptypes.TEXT):
+		// the Texte would be concatenated instead of creating a list
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Zwei Werte vom Typ %s können nicht verkettet werden, wandle sie vorher mit 'als Text' in Texte um", elem)
+	}
+}
+
 func (t *Typechecker) VisitUnaryExpr(expr *ast.UnaryExpr) ast.VisitResult {
 	// Evaluate the rhs expression and check if the operator fits it
 	rhs := t.Evaluate(expr.Rhs)
 
+	// the error was already reported
+	if ddptypes.IsInvalid(rhs) {
+		t.latestReturnedType = ddptypes.InvalidType{}
+		return ast.VisitRecurse
+	}
+
 	if overload := t.findOverload(expr.Operator, operand{rhs, expr.Rhs}); overload != nil {
 		expr.OverloadedBy = overload
 		t.latestReturnedType = overload.Decl.ReturnType
@@ -277,8 +387,14 @@ func (t *Typechecker) VisitUnaryExpr(expr *ast.UnaryExpr) ast.VisitResult {
 
 	switch expr.Operator {
 	case ast.UN_ABS, ast.UN_NEGATE:
-		if !ddptypes.IsNumeric(rhs) {
-			t.errExpected(expr.Operator, expr.Rhs, rhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		// numeric lists are mapped element-wise
+		if !ddptypes.IsNumeric(rhs) && !ddptypes.IsNumeric(ddptypes.GetListUnderlying(rhs)) {
+			// Negiere only falls back to UN_NEGATE, so the error should mention all types it accepts
+			if expr.Tok.Type == token.NEGIERE {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Negiere erwartet eine Variable vom Typ 'Zahl', 'Kommazahl', 'Wahrheitswert', 'Buchstabe', 'Zahlen Liste' oder 'Kommazahlen Liste' aber hat '%s' bekommen", rhs)
+				break
+			}
+			t.errExpected(expr.Operator, expr.Rhs, rhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL})
 		}
 	case ast.UN_NOT:
 		if !isOneOf(rhs, ddptypes.WAHRHEITSWERT) {
@@ -287,17 +403,34 @@ func (t *Typechecker) VisitUnaryExpr(expr *ast.UnaryExpr) ast.VisitResult {
 
 		t.latestReturnedType = ddptypes.WAHRHEITSWERT
 	case ast.UN_LOGIC_NOT:
-		if !isOneOf(rhs, ddptypes.ZAHL) {
-			t.errExpected(expr.Operator, expr.Rhs, rhs, ddptypes.ZAHL)
+		if !isOneOf(rhs, ddptypes.ZAHL, ddptypes.BUCHSTABE) {
+			t.errExpected(expr.Operator, expr.Rhs, rhs, ddptypes.ZAHL, ddptypes.BUCHSTABE)
 		}
 
-		t.latestReturnedType = ddptypes.ZAHL
+		if ddptypes.Equal(rhs, ddptypes.BUCHSTABE) {
+			t.latestReturnedType = ddptypes.BUCHSTABE
+		} else {
+			t.latestReturnedType = ddptypes.ZAHL
+		}
 	case ast.UN_LEN:
-		if !ddptypes.IsList(rhs) && !ddptypes.Equal(rhs, ddptypes.TEXT) {
+		if structType, isStruct := ddptypes.CastStruct(rhs); isStruct {
+			// structs have no length, but maybe their size was meant
+			article := "einem"
+			if structType.Gender() == ddptypes.FEMININ {
+				article = "einer"
+			}
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der %s Operator kann nicht auf die Kombination %s angewandt werden, ihre Größe in Bytes liefert 'die Größe von %s %s'", ast.UN_LEN, structType, article, structType)
+		} else if !ddptypes.IsList(rhs) && !ddptypes.Equal(rhs, ddptypes.TEXT) {
 			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der %s Operator erwartet einen Text oder eine Liste als Operanden, nicht %s", ast.UN_LEN, rhs)
 		}
 
 		t.latestReturnedType = ddptypes.ZAHL
+	case ast.UN_PRESENT:
+		if !ddptypes.IsOptional(rhs) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der %s Operator erwartet einen Ausdruck mit optionalem Typ (z.B. vielleicht Zahl), nicht %s", ast.UN_PRESENT, rhs)
+		}
+
+		t.latestReturnedType = ddptypes.WAHRHEITSWERT
 	default:
 		panic(fmt.Errorf("unbekannter unärer Operator '%s'", expr.Operator))
 	}
@@ -308,6 +441,13 @@ func (t *Typechecker) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
 	lhs := t.Evaluate(expr.Lhs)
 	rhs := t.Evaluate(expr.Rhs)
 
+	// the error was already reported
+	// the lhs of a field access is the field name, which is no valid expression on its own
+	if ddptypes.IsInvalid(rhs) || (ddptypes.IsInvalid(lhs) && expr.Operator != ast.BIN_FIELD_ACCESS) {
+		t.latestReturnedType = ddptypes.InvalidType{}
+		return ast.VisitRecurse
+	}
+
 	if overload := t.findOverload(expr.Operator, operand{lhs, expr.Lhs}, operand{rhs, expr.Rhs}); overload != nil {
 		expr.OverloadedBy = overload
 		t.latestReturnedType = overload.Decl.ReturnType
@@ -326,21 +466,64 @@ func (t *Typechecker) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
 
 	switch expr.Operator {
 	case ast.BIN_CONCAT:
-		if (!ddptypes.IsList(lhs) && !ddptypes.IsList(rhs)) && (ddptypes.Equal(lhs, ddptypes.TEXT) || ddptypes.Equal(rhs, ddptypes.TEXT)) { // string, char edge case
+		lhsIsList, rhsIsList := ddptypes.IsList(lhs), ddptypes.IsList(rhs)
+		lhsElem, rhsElem := ddptypes.GetListUnderlying(lhs), ddptypes.GetListUnderlying(rhs)
+		elemsEqual := ddptypes.Equal(lhsElem, rhsElem)
+		// string, char edge case
+		isTextConcat := !lhsIsList && !rhsIsList && (ddptypes.Equal(lhs, ddptypes.TEXT) || ddptypes.Equal(rhs, ddptypes.TEXT))
+
+		t.latestReturnedType = ddptypes.ListType{Underlying: lhsElem}
+		switch {
+		case isTextConcat:
 			validate(ddptypes.TEXT, ddptypes.BUCHSTABE)
 			t.latestReturnedType = ddptypes.TEXT
-		} else { // lists
-			if !ddptypes.Equal(ddptypes.GetListUnderlying(lhs), ddptypes.GetListUnderlying(rhs)) {
-				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Die Typenkombination aus %s und %s passt nicht zum VERKETTET Operator", lhs, rhs)
+		case lhsIsList && rhsIsList:
+			if !elemsEqual {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Eine Liste vom Typ %s kann nicht mit einer Liste vom Typ %s verkettet werden, da ihre Elemente unterschiedliche Typen haben", lhs, rhs)
+			}
+		case lhsIsList:
+			if !elemsEqual {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Ein Wert vom Typ %s kann nicht an eine Liste vom Typ %s angehängt werden, es wird ein Wert vom Typ %s erwartet", rhs, lhs, lhsElem)
+			}
+		case rhsIsList:
+			if !elemsEqual {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Lhs, "Ein Wert vom Typ %s kann nicht vor eine Liste vom Typ %s gehängt werden, es wird ein Wert vom Typ %s erwartet", lhs, rhs, rhsElem)
+			}
+		default: // two scalars form a new list
+			if !elemsEqual {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Werte vom Typ %s und %s können nicht zu einer Liste verkettet werden, da sie unterschiedliche Typen haben", lhs, rhs)
+			} else if !t.checkIsValue(expr.Lhs, lhs) {
+				t.latestReturnedType = ddptypes.InvalidType{}
+			} else {
+				t.checkConcatToList(expr, lhs)
 			}
-			t.latestReturnedType = ddptypes.ListType{Underlying: ddptypes.GetListUnderlying(lhs)}
 		}
-	case ast.BIN_PLUS, ast.BIN_MINUS, ast.BIN_MULT:
+	case ast.BIN_MULT:
+		// Text mal Zahl repeats the Text (or Buchstabe)
+		if isOneOf(lhs, ddptypes.TEXT, ddptypes.BUCHSTABE) {
+			if !ddptypes.Equal(rhs, ddptypes.ZAHL) {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Ein %s kann nur mit einer Zahl multipliziert werden, nicht mit %s", lhs, rhs)
+			}
+			t.latestReturnedType = ddptypes.TEXT
+			break
+		}
+		// Liste mal Zahl repeats the Liste
+		if ddptypes.IsList(lhs) {
+			if !ddptypes.Equal(rhs, ddptypes.ZAHL) {
+				t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Eine Liste kann nur mit einer Zahl multipliziert werden, nicht mit %s", rhs)
+			}
+			t.latestReturnedType = lhs
+			break
+		}
+		fallthrough
+	case ast.BIN_PLUS, ast.BIN_MINUS:
 		validate(ddptypes.ZAHL, ddptypes.KOMMAZAHL)
 
 		if ddptypes.Equal(lhs, ddptypes.ZAHL) && ddptypes.Equal(rhs, ddptypes.ZAHL) {
 			t.latestReturnedType = ddptypes.ZAHL
 		} else {
+			t.checkPrecisionLoss(expr.Lhs)
+			t.checkPrecisionLoss(expr.Rhs)
 			t.latestReturnedType = ddptypes.KOMMAZAHL
 		}
 	case ast.BIN_INDEX:
@@ -350,6 +533,7 @@ func (t *Typechecker) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
 		if !ddptypes.Equal(rhs, ddptypes.ZAHL) {
 			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Der STELLE Operator erwartet eine Zahl als zweiten Operanden, nicht %s", rhs)
 		}
+		t.checkLiteralIndex(expr.Rhs)
 
 		if listType, isList := ddptypes.CastList(lhs); isList {
 			t.latestReturnedType = listType.Underlying
@@ -372,16 +556,18 @@ func (t *Typechecker) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
 	case ast.BIN_FIELD_ACCESS:
 		if ident, isIdent := expr.Lhs.(*ast.Ident); isIdent {
 			if !ddptypes.IsStruct(rhs) {
-				// error was already reported by the resolver
-				t.latestReturnedType = ddptypes.VoidType{}
+				t.errExpr(ddperror.TYP_BAD_FIELD_ACCESS, expr.Rhs, "Der VON Operator erwartet eine Struktur als rechten Operanden, nicht %s", rhs)
+				t.latestReturnedType = ddptypes.InvalidType{}
 			} else {
 				t.latestReturnedType = t.checkFieldAccess(ident, rhs)
 			}
 		} else {
-			t.latestReturnedType = ddptypes.VoidType{}
+			t.latestReturnedType = ddptypes.InvalidType{}
 		}
 	case ast.BIN_DIV, ast.BIN_POW, ast.BIN_LOG:
 		validate(ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		t.checkPrecisionLoss(expr.Lhs)
+		t.checkPrecisionLoss(expr.Rhs)
 		t.latestReturnedType = ddptypes.KOMMAZAHL
 	case ast.BIN_MOD:
 		validate(ddptypes.ZAHL)
@@ -393,27 +579,81 @@ func (t *Typechecker) VisitBinaryExpr(expr *ast.BinaryExpr) ast.VisitResult {
 		validate(ddptypes.ZAHL)
 		t.latestReturnedType = ddptypes.ZAHL
 	case ast.BIN_EQUAL, ast.BIN_UNEQUAL:
+		// values and nichts are compared with optionals as optionals
+		lhs = t.convertToOptional(&expr.Lhs, lhs, rhs)
+		rhs = t.convertToOptional(&expr.Rhs, rhs, lhs)
 		if !ddptypes.Equal(lhs, rhs) {
 			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der '%s' Operator erwartet zwei Operanden gleichen Typs aber hat '%s' und '%s' bekommen", expr.Operator, lhs, rhs)
+		} else if !ddptypes.IsComparable(lhs) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der '%s' Operator kann keine Werte vom Typ '%s' vergleichen", expr.Operator, lhs)
+		} else if ddptypes.Equal(lhs, ddptypes.WAHRHEITSWERT) {
+			t.checkRedundantBoolComparison(expr)
 		}
 		t.latestReturnedType = ddptypes.WAHRHEITSWERT
 	case ast.BIN_GREATER, ast.BIN_LESS, ast.BIN_GREATER_EQ, ast.BIN_LESS_EQ:
-		validate(ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		t.checkOrdered(expr.Operator, expr, operand{lhs, expr.Lhs}, operand{rhs, expr.Rhs})
 		t.latestReturnedType = ddptypes.WAHRHEITSWERT
 	case ast.BIN_LOGIC_AND, ast.BIN_LOGIC_OR, ast.BIN_LOGIC_XOR:
 		validate(ddptypes.ZAHL)
 		t.latestReturnedType = ddptypes.ZAHL
+	case ast.BIN_MAP, ast.BIN_FILTER:
+		t.latestReturnedType = t.checkListCallback(expr, lhs, rhs)
 	default:
 		panic(fmt.Errorf("unbekannter binärer Operator '%s'", expr.Operator))
 	}
 	return ast.VisitRecurse
 }
 
+// checks the operands of the ABGEBILDET and GEFILTERT operators
+// the rhs must be a function that takes an element of the lhs list
+// and for GEFILTERT returns a Wahrheitswert
+// returns the type of the resulting list
+func (t *Typechecker) checkListCallback(expr *ast.BinaryExpr, lhs, rhs ddptypes.Type) ddptypes.Type {
+	listType, isList := ddptypes.CastList(ddptypes.TrueUnderlying(lhs))
+	if !isList {
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Lhs, "Der '%s' Operator erwartet eine Liste als ersten Operanden, nicht %s", expr.Operator, lhs)
+		return ddptypes.InvalidType{}
+	}
+
+	funcType, isFunc := ddptypes.CastFunction(ddptypes.TrueUnderlying(rhs))
+	if !isFunc {
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Der '%s' Operator erwartet eine Funktion als zweiten Operanden, nicht %s", expr.Operator, rhs)
+		return ddptypes.InvalidType{}
+	}
+
+	if len(funcType.Params) != 1 || !ddptypes.Equal(funcType.Params[0], listType.Underlying) {
+		t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr.Rhs, "Der '%s' Operator erwartet eine Funktion mit einem Parameter vom Typ %s, nicht %s", expr.Operator, listType.Underlying, rhs)
+		return ddptypes.InvalidType{}
+	}
+
+	if expr.Operator == ast.BIN_FILTER {
+		if !ddptypes.Equal(funcType.ReturnType, ddptypes.WAHRHEITSWERT) {
+			t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr.Rhs, "Der '%s' Operator erwartet eine Funktion, die einen Wahrheitswert zurückgibt, nicht %s", expr.Operator, funcType.ReturnType)
+		}
+		return lhs
+	}
+
+	// the return values become the elements of the new list
+	switch returnType := ddptypes.TrueUnderlying(funcType.ReturnType); {
+	case ddptypes.IsVoid(returnType), ddptypes.IsList(returnType), ddptypes.IsOptional(returnType),
+		ddptypes.IsEnum(returnType), ddptypes.IsFunction(returnType):
+		t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr.Rhs, "Der '%s' Operator kann keine Liste aus Werten vom Typ %s bilden", expr.Operator, funcType.ReturnType)
+		return ddptypes.InvalidType{}
+	}
+	return ddptypes.ListType{Underlying: funcType.ReturnType}
+}
+
 func (t *Typechecker) VisitTernaryExpr(expr *ast.TernaryExpr) ast.VisitResult {
 	lhs := t.Evaluate(expr.Lhs)
 	mid := t.Evaluate(expr.Mid)
 	rhs := t.Evaluate(expr.Rhs)
 
+	// the error was already reported
+	if ddptypes.IsInvalid(lhs) || ddptypes.IsInvalid(mid) || ddptypes.IsInvalid(rhs) {
+		t.latestReturnedType = ddptypes.InvalidType{}
+		return ast.VisitRecurse
+	}
+
 	if overload := t.findOverload(expr.Operator, operand{lhs, expr.Lhs}, operand{mid, expr.Mid}, operand{rhs, expr.Rhs}); overload != nil {
 		expr.OverloadedBy = overload
 		t.latestReturnedType = overload.Decl.ReturnType
@@ -439,17 +679,29 @@ func (t *Typechecker) VisitTernaryExpr(expr *ast.TernaryExpr) ast.VisitResult {
 			t.latestReturnedType = ddptypes.TEXT
 		}
 	case ast.TER_BETWEEN:
-		if !isOneOf(lhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL) {
-			t.errExpected(expr.Operator, expr.Lhs, lhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		t.checkOrdered(expr.Operator, expr, operand{lhs, expr.Lhs}, operand{mid, expr.Mid}, operand{rhs, expr.Rhs})
+		t.latestReturnedType = ddptypes.WAHRHEITSWERT
+	case ast.TER_PAD:
+		listType, isList := ddptypes.CastList(lhs)
+		if !isList {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Lhs, "Der %s Operator erwartet eine Liste als ersten Operanden, nicht %s", expr.Operator, lhs)
 		}
-		if !isOneOf(mid, ddptypes.ZAHL, ddptypes.KOMMAZAHL) {
-			t.errExpected(expr.Operator, expr.Mid, mid, ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		if !isOneOf(mid, ddptypes.ZAHL) {
+			t.errExpected(expr.Operator, expr.Mid, mid, ddptypes.ZAHL)
 		}
-		if !isOneOf(rhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL) {
-			t.errExpected(expr.Operator, expr.Rhs, rhs, ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+		if isList && !ddptypes.Equal(rhs, listType.Underlying) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Rhs, "Eine %s kann nicht mit einem Wert vom Typ %s aufgefüllt werden", lhs, rhs)
 		}
-		t.latestReturnedType = ddptypes.WAHRHEITSWERT
+		t.latestReturnedType = lhs
 	case ast.TER_FALLS:
+		// "x, falls b, ansonsten nichts" is an optional
+		if isNothingLit(expr.Rhs) && ddptypes.IsValidOptionalUnderlying(lhs) {
+			lhs = t.convertToOptional(&expr.Lhs, lhs, ddptypes.OptionalType{Underlying: lhs})
+		} else if isNothingLit(expr.Lhs) && ddptypes.IsValidOptionalUnderlying(rhs) {
+			rhs = t.convertToOptional(&expr.Rhs, rhs, ddptypes.OptionalType{Underlying: rhs})
+		}
+		lhs = t.convertToOptional(&expr.Lhs, lhs, rhs)
+		rhs = t.convertToOptional(&expr.Rhs, rhs, lhs)
 		if !ddptypes.Equal(lhs, rhs) {
 			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Die linke und rechte Seite des 'falls' Ausdrucks müssen den selben Typ haben, aber es wurde %s und %s gefunden", lhs, rhs)
 		}
@@ -464,7 +716,14 @@ func (t *Typechecker) VisitTernaryExpr(expr *ast.TernaryExpr) ast.VisitResult {
 }
 
 func (t *Typechecker) VisitCastExpr(expr *ast.CastExpr) ast.VisitResult {
-	lhs := t.Evaluate(expr.Lhs)
+	t.checkCast(expr, t.Evaluate(expr.Lhs))
+	t.latestReturnedType = expr.TargetType
+	return ast.VisitRecurse
+}
+
+// reports an error if expr.Lhs (of type lhs) can not be converted to expr.TargetType
+// and sets expr.OverloadedBy if a cast operator overload is used
+func (t *Typechecker) checkCast(expr *ast.CastExpr, lhs ddptypes.Type) {
 	castErr := func() {
 		t.errExpr(ddperror.TYP_BAD_CAST, expr, "Ein Ausdruck vom Typ %s kann nicht in den Typ %s umgewandelt werden", lhs, expr.TargetType)
 	}
@@ -482,25 +741,33 @@ func (t *Typechecker) VisitCastExpr(expr *ast.CastExpr) ast.VisitResult {
 			}
 			operator_overload.Args[overload.Parameters[0].Name.Literal] = expr.Lhs
 			if overload.Parameters[0].Type.IsReference {
-				if ass, isAssignable := isAssignable(expr.Lhs); isAssignable {
+				if ass, isAssignable := isAssignable(expr.Lhs); isAssignable && !t.isCharInText(ass) {
 					operator_overload.Args[overload.Parameters[0].Name.Literal] = ass
 				} else {
 					continue
 				}
 			}
 			expr.OverloadedBy = operator_overload
-			t.latestReturnedType = expr.TargetType
-			return ast.VisitRecurse
+			return
 		}
 	}
 
 	targetTypeDef, isTargetTypeDef := ddptypes.CastTypeDef(expr.TargetType)
 	lhsTypeDef, isLhsTypeDef := ddptypes.CastTypeDef(lhs)
 
-	if ddptypes.IsAny(lhs) || (ddptypes.IsAny(expr.TargetType) && !ddptypes.IsVoid(lhs)) {
+	if optionalType, isOptional := ddptypes.CastOptional(expr.TargetType); isOptional {
+		// values are wrapped in their optional type
+		if !isOneOf(lhs, optionalType.Underlying, expr.TargetType) {
+			castErr()
+		}
+	} else if lhsOptional, isOptional := ddptypes.CastOptional(lhs); isOptional {
+		// optionals are unwrapped or converted to Text ("nichts" if they are empty)
+		if !isOneOf(expr.TargetType, lhsOptional.Underlying, ddptypes.TEXT) {
+			castErr()
+		}
+	} else if (ddptypes.IsAny(lhs) && isValidAnyValue(expr.TargetType)) || (ddptypes.IsAny(expr.TargetType) && isValidAnyValue(lhs)) {
 		// casts from/to any are always valid but might error at runtime
-		t.latestReturnedType = expr.TargetType
-		return ast.VisitRecurse
+		return
 	} else if isTargetTypeDef && isLhsTypeDef {
 		// typedefs can only be converted to/from their underlying type
 		if !ddptypes.Equal(lhsTypeDef.Underlying, expr.TargetType) && !ddptypes.Equal(targetTypeDef.Underlying, lhs) {
@@ -516,42 +783,38 @@ func (t *Typechecker) VisitCastExpr(expr *ast.CastExpr) ast.VisitResult {
 		if !ddptypes.Equal(expr.TargetType, lhsTypeDef.Underlying) {
 			castErr()
 		}
-	} else if ddptypes.IsList(expr.TargetType) { // non-list types can be converted to their list-type with a single element
-		underlying := ddptypes.GetUnderlying(ddptypes.GetListUnderlying(expr.TargetType))
-		if !isOneOf(lhs, underlying) {
+	} else if ddptypes.IsFunction(lhs) || ddptypes.IsFunction(expr.TargetType) {
+		// function values can not be converted
+		if !ddptypes.Equal(lhs, expr.TargetType) {
 			castErr()
 		}
-	} else if primitiveType, isPrimitive := ddptypes.CastPrimitive(expr.TargetType); isPrimitive {
-		// special rules for primitive conversions
-		switch primitiveType {
-		case ddptypes.ZAHL:
-			if !ddptypes.IsPrimitive(lhs) {
-				castErr()
-			}
-		case ddptypes.KOMMAZAHL:
-			if !ddptypes.IsPrimitive(lhs) || !isOneOf(lhs, ddptypes.TEXT, ddptypes.ZAHL, ddptypes.KOMMAZAHL) {
-				castErr()
-			}
-		case ddptypes.WAHRHEITSWERT:
-			if !ddptypes.IsPrimitive(lhs) || !isOneOf(lhs, ddptypes.ZAHL, ddptypes.WAHRHEITSWERT) {
-				castErr()
-			}
-		case ddptypes.BUCHSTABE:
-			if !ddptypes.IsPrimitive(lhs) || !isOneOf(lhs, ddptypes.ZAHL, ddptypes.BUCHSTABE) {
-				castErr()
-			}
-		case ddptypes.TEXT:
-			if !ddptypes.IsPrimitive(lhs) {
+	} else if lhsEnum, isEnum := ddptypes.CastEnum(lhs); isEnum {
+		// enums are converted to the position of the variant (starting at 1) or its name
+		if !isOneOf(expr.TargetType, lhsEnum, ddptypes.ZAHL, ddptypes.TEXT) {
+			castErr()
+		}
+	} else if ddptypes.IsList(expr.TargetType) {
+		underlying := ddptypes.GetUnderlying(ddptypes.GetListUnderlying(expr.TargetType))
+		if lhsList, isList := ddptypes.CastList(lhs); isList && !isOneOf(lhs, underlying) {
+			// lists of primitives are converted element-wise
+			targetElementType, isPrimitive := ddptypes.CastPrimitive(underlying)
+			if !isPrimitive || !ddptypes.IsPrimitive(lhsList.Underlying) || !isValidPrimitiveCast(lhsList.Underlying, targetElementType) {
 				castErr()
 			}
-		default:
+		} else if ddptypes.Equal(lhs, ddptypes.TEXT) && ddptypes.Equal(underlying, ddptypes.BUCHSTABE) {
+			// Texte are decomposed into their Buchstaben
+		} else if !isOneOf(lhs, underlying) { // non-list types can be converted to their list-type with a single element
+			castErr()
+		}
+	} else if primitiveType, isPrimitive := ddptypes.CastPrimitive(expr.TargetType); isPrimitive {
+		// lists of primitives are converted to "a, b, c"
+		isPrimitiveListToText := primitiveType == ddptypes.TEXT && ddptypes.IsList(lhs) && ddptypes.IsPrimitive(ddptypes.GetListUnderlying(lhs))
+		if !isPrimitiveListToText && !isValidPrimitiveCast(lhs, primitiveType) {
 			castErr()
 		}
 	} else {
 		castErr()
 	}
-	t.latestReturnedType = expr.TargetType
-	return ast.VisitRecurse
 }
 
 func (t *Typechecker) VisitTypeOpExpr(expr *ast.TypeOpExpr) ast.VisitResult {
@@ -578,6 +841,8 @@ func (t *Typechecker) VisitTypeCheck(expr *ast.TypeCheck) ast.VisitResult {
 	}
 	if ddptypes.Equal(expr.CheckType, ddptypes.VARIABLE) {
 		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Dieser Ausdruck ist immer 'wahr'")
+	} else if !isValidAnyValue(expr.CheckType) {
+		t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Eine Variable kann keinen Wert vom Typ %s enthalten", expr.CheckType)
 	}
 	t.latestReturnedType = ddptypes.WAHRHEITSWERT
 	return ast.VisitRecurse
@@ -589,31 +854,39 @@ func (t *Typechecker) VisitGrouping(expr *ast.Grouping) ast.VisitResult {
 }
 
 func (t *Typechecker) VisitFuncCall(callExpr *ast.FuncCall) ast.VisitResult {
-	symbol, _, _ := t.CurrentTable.LookupDecl(callExpr.Name)
-	decl := symbol.(*ast.FuncDecl)
+	decl := callExpr.Func
+	if decl == nil {
+		decl, _, _ = t.CurrentTable.LookupFunc(callExpr.Name)
+	}
 
-	for k, expr := range callExpr.Args {
+	for _, k := range callExpr.ArgNames() {
+		expr := callExpr.Args[k]
 		argType := t.Evaluate(expr)
 
-		var paramType ddptypes.ParameterType
+		var (
+			paramType ddptypes.ParameterType
+			paramName token.Token
+		)
 
 		for _, param := range decl.Parameters {
 			if param.Name.Literal == k {
-				paramType = param.Type
+				paramType, paramName = param.Type, param.Name
 				break
 			}
 		}
 
 		if ass, ok := expr.(ast.Assigneable); paramType.IsReference && !ok {
 			t.errExpr(ddperror.TYP_EXPECTED_REFERENCE, expr, "Es wurde ein Referenz-Typ erwartet aber ein Ausdruck gefunden")
-		} else if ass, ok := ass.(*ast.Indexing); paramType.IsReference && ddptypes.Equal(paramType.Type, ddptypes.BUCHSTABE) && ok {
-			lhs := t.Evaluate(ass.Lhs)
-			if ddptypes.Equal(lhs, ddptypes.TEXT) {
-				t.errExpr(ddperror.TYP_INVALID_REFERENCE, expr, "Ein Buchstabe in einem Text kann nicht als Buchstaben Referenz übergeben werden")
-			}
+		} else if paramType.IsReference && ddptypes.Equal(paramType.Type, ddptypes.BUCHSTABE) && ok && t.isCharInText(ass) {
+			t.errExpr(ddperror.TYP_INVALID_REFERENCE, expr, "Ein Buchstabe in einem Text kann nicht als Buchstaben Referenz übergeben werden")
+		}
+		if !paramType.IsReference {
+			argType = t.convertToOptional(&expr, argType, paramType.Type)
+			callExpr.Args[k] = expr
 		}
 		if !ddptypes.Equal(argType, paramType.Type) {
-			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr,
+			paramNote := ddperror.NewNote(paramName.Range, ddperror.MsgDeclaredHere("Der Parameter "+k), decl.Module().FileName)
+			t.errExprWithNotes(ddperror.TYP_TYPE_MISMATCH, expr, []ddperror.Note{paramNote},
 				"Die Funktion %s erwartet einen Wert vom Typ %s für den Parameter %s, aber hat %s bekommen",
 				callExpr.Name,
 				paramType,
@@ -628,7 +901,8 @@ func (t *Typechecker) VisitFuncCall(callExpr *ast.FuncCall) ast.VisitResult {
 }
 
 func (t *Typechecker) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResult {
-	for argName, arg := range expr.Args {
+	for _, argName := range expr.ArgNames() {
+		arg := expr.Args[argName]
 		argType := t.Evaluate(arg)
 
 		var paramType ddptypes.Type
@@ -639,6 +913,8 @@ func (t *Typechecker) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResul
 			}
 		}
 
+		argType = t.convertToOptional(&arg, argType, paramType)
+		expr.Args[argName] = arg
 		if !ddptypes.Equal(argType, paramType) {
 			t.errExpr(ddperror.TYP_TYPE_MISMATCH, arg,
 				"Die Struktur %s erwartet einen Wert vom Typ %s für das Feld %s, aber hat %s bekommen",
@@ -654,8 +930,62 @@ func (t *Typechecker) VisitStructLiteral(expr *ast.StructLiteral) ast.VisitResul
 	return ast.VisitRecurse
 }
 
+func (t *Typechecker) VisitFuncRef(expr *ast.FuncRef) ast.VisitResult {
+	if expr.Func == nil {
+		t.latestReturnedType = ddptypes.InvalidType{}
+		return ast.VisitRecurse
+	}
+
+	params := make([]ddptypes.Type, 0, len(expr.Func.Parameters))
+	for _, param := range expr.Func.Parameters {
+		if param.Type.IsReference {
+			t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr, "Die Funktion %s nimmt Referenzen und kann deshalb nicht als Wert benutzt werden", expr.Func.Name())
+		}
+		params = append(params, param.Type.Type)
+	}
+	t.latestReturnedType = ddptypes.NewFunctionType(params, expr.Func.ReturnType)
+	return ast.VisitRecurse
+}
+
+func (t *Typechecker) VisitFuncValueCall(expr *ast.FuncValueCall) ast.VisitResult {
+	calleeType := t.Evaluate(expr.Callee)
+	funcType, isFunc := ddptypes.CastFunction(calleeType)
+	if !isFunc {
+		for _, arg := range expr.Args {
+			t.Evaluate(arg)
+		}
+		t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr.Callee, "Ein Ausdruck vom Typ %s kann nicht aufgerufen werden", calleeType)
+		t.latestReturnedType = ddptypes.InvalidType{}
+		return ast.VisitRecurse
+	}
+
+	if len(expr.Args) != len(funcType.Params) {
+		t.errExpr(ddperror.TYP_BAD_FUNCTION_VALUE, expr, "Eine %s erwartet %d Argumente, aber hat %d bekommen", funcType, len(funcType.Params), len(expr.Args))
+	}
+	for i := range expr.Args {
+		argType := t.Evaluate(expr.Args[i])
+		if i >= len(funcType.Params) {
+			continue
+		}
+
+		argType = t.convertToOptional(&expr.Args[i], argType, funcType.Params[i])
+		if !ddptypes.Equal(argType, funcType.Params[i]) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr.Args[i],
+				"Eine %s erwartet einen Wert vom Typ %s als %d. Argument, aber hat %s bekommen",
+				funcType,
+				funcType.Params[i],
+				i+1,
+				argType,
+			)
+		}
+	}
+
+	t.latestReturnedType = funcType.ReturnType
+	return ast.VisitRecurse
+}
+
 func (t *Typechecker) VisitBadStmt(stmt *ast.BadStmt) ast.VisitResult {
-	t.latestReturnedType = ddptypes.VoidType{}
+	t.latestReturnedType = ddptypes.InvalidType{}
 	return ast.VisitRecurse
 }
 
@@ -666,6 +996,9 @@ func (t *Typechecker) VisitDeclStmt(stmt *ast.DeclStmt) ast.VisitResult {
 
 func (t *Typechecker) VisitExprStmt(stmt *ast.ExprStmt) ast.VisitResult {
 	stmt.Expr.Accept(t)
+	if t.isPure(stmt.Expr) {
+		t.warn(ddperror.SEM_EXPR_WITHOUT_EFFECT, stmt.Expr.GetRange(), "Ausdruck ohne Wirkung")
+	}
 	return ast.VisitRecurse
 }
 
@@ -675,10 +1008,11 @@ func (t *Typechecker) VisitImportStmt(stmt *ast.ImportStmt) ast.VisitResult {
 
 func (t *Typechecker) VisitAssignStmt(stmt *ast.AssignStmt) ast.VisitResult {
 	rhs := t.Evaluate(stmt.Rhs)
-	stmt.RhsType = rhs
 	target := t.Evaluate(stmt.Var)
+	rhs = t.convertToOptional(&stmt.Rhs, rhs, target)
+	stmt.RhsType = rhs
 
-	if !ddptypes.Equal(target, rhs) && (!ddptypes.Equal(target, ddptypes.VARIABLE) || ddptypes.Equal(rhs, ddptypes.VoidType{})) {
+	if !ddptypes.Equal(target, rhs) && (!ddptypes.Equal(target, ddptypes.VARIABLE) || !isValidAnyValue(rhs)) {
 		t.errExpr(ddperror.TYP_BAD_ASSIGNEMENT, stmt.Rhs,
 			"Ein Wert vom Typ %s kann keiner Variable vom Typ %s zugewiesen werden",
 			rhs,
@@ -755,6 +1089,8 @@ func (t *Typechecker) VisitForStmt(stmt *ast.ForStmt) ast.VisitResult {
 				iter_type,
 				stepType,
 			)
+		} else if ddptypes.Equal(iter_type, ddptypes.KOMMAZAHL) {
+			t.checkInexactStepSize(stmt.StepSize)
 		}
 	}
 	stmt.Body.Accept(t)
@@ -796,9 +1132,16 @@ func (t *Typechecker) VisitReturnStmt(stmt *ast.ReturnStmt) ast.VisitResult {
 	if stmt.Func == nil {
 		return ast.VisitRecurse
 	}
+	if stmt.Value != nil {
+		returnType = t.convertToOptional(&stmt.Value, returnType, stmt.Func.ReturnType)
+	}
 
-	if !ddptypes.Equal(stmt.Func.ReturnType, returnType) &&
-		(!ddptypes.Equal(stmt.Func.ReturnType, ddptypes.VARIABLE) || ddptypes.Equal(returnType, ddptypes.VoidType{})) {
+	if ddptypes.IsVoid(stmt.Func.ReturnType) && stmt.Value != nil {
+		t.err(ddperror.TYP_WRONG_RETURN_TYPE, stmt.Value.GetRange(), "Eine Funktion ohne Rückgabewert kann keinen Wert zurückgeben")
+	} else if !ddptypes.IsVoid(stmt.Func.ReturnType) && stmt.Value == nil {
+		t.err(ddperror.TYP_WRONG_RETURN_TYPE, stmt.Range, fmt.Sprintf("Es muss ein Wert vom Typ %s zurückgegeben werden", ddptypes.Describe(stmt.Func.ReturnType)))
+	} else if !ddptypes.Equal(stmt.Func.ReturnType, returnType) &&
+		(!ddptypes.Equal(stmt.Func.ReturnType, ddptypes.VARIABLE) || !isValidAnyValue(returnType)) {
 		errRange := stmt.Range
 		if stmt.Value != nil {
 			errRange = stmt.Value.GetRange()
@@ -806,8 +1149,8 @@ func (t *Typechecker) VisitReturnStmt(stmt *ast.ReturnStmt) ast.VisitResult {
 
 		t.err(ddperror.TYP_WRONG_RETURN_TYPE, errRange,
 			fmt.Sprintf("Eine Funktion mit Rückgabetyp %s kann keinen Wert vom Typ %s zurückgeben",
-				stmt.Func.ReturnType,
-				returnType),
+				ddptypes.Describe(stmt.Func.ReturnType),
+				ddptypes.Describe(returnType)),
 		)
 	}
 	return ast.VisitRecurse
@@ -817,6 +1160,63 @@ func (*Typechecker) VisitTodoStmt(*ast.TodoStmt) ast.VisitResult {
 	return ast.VisitRecurse
 }
 
+func (t *Typechecker) VisitAssertStmt(stmt *ast.AssertStmt) ast.VisitResult {
+	conditionType := t.Evaluate(stmt.Condition)
+	if !ddptypes.Equal(conditionType, ddptypes.WAHRHEITSWERT) {
+		t.errExpr(ddperror.TYP_BAD_CONDITION, stmt.Condition,
+			"Die Bedingung einer Stelle-sicher-Anweisung muss ein Wahrheitswert sein, war aber vom Typ %s",
+			conditionType,
+		)
+	}
+	if stmt.Message != nil {
+		if messageType := t.Evaluate(stmt.Message); !ddptypes.Equal(messageType, ddptypes.TEXT) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, stmt.Message,
+				"Die Nachricht einer Stelle-sicher-Anweisung muss ein Text sein, war aber vom Typ %s",
+				messageType,
+			)
+		}
+	}
+	return ast.VisitRecurse
+}
+
+// values that are not Texte are converted to Text like in a CastExpr
+func (t *Typechecker) VisitPrintStmt(stmt *ast.PrintStmt) ast.VisitResult {
+	if valueType := t.Evaluate(stmt.Value); !ddptypes.Equal(valueType, ddptypes.TEXT) {
+		cast := &ast.CastExpr{
+			Range:      stmt.Value.GetRange(),
+			TargetType: ddptypes.TEXT,
+			Lhs:        stmt.Value,
+		}
+		t.checkCast(cast, valueType)
+		stmt.Value = cast
+	}
+	return ast.VisitRecurse
+}
+
+// reports an error for every operand of operator (in expr) that can not be ordered (see ddptypes.IsOrdered)
+// and if the operands can not be ordered among each other:
+// Zahlen and Kommazahlen can be mixed, Texte and Buchstaben are only ordered among their own type
+func (t *Typechecker) checkOrdered(operator ast.Operator, expr ast.Expression, operands ...operand) {
+	allOrdered := true
+	for _, op := range operands {
+		if !ddptypes.IsOrdered(op.typ) {
+			t.errExpected(operator, op.expr, op.typ, ddptypes.ZAHL, ddptypes.KOMMAZAHL, ddptypes.BUCHSTABE, ddptypes.TEXT)
+			allOrdered = false
+		}
+	}
+	if !allOrdered {
+		return
+	}
+
+	first := operands[0].typ
+	for _, op := range operands[1:] {
+		if !(ddptypes.IsNumeric(first) && ddptypes.IsNumeric(op.typ)) && !ddptypes.Equal(first, op.typ) {
+			t.errExpr(ddperror.TYP_TYPE_MISMATCH, expr, "Der %s Operator kann %s und %s nicht miteinander vergleichen", operator, first, op.typ)
+			return
+		}
+	}
+}
+
 // checks if t is contained in types
 func isOneOf(t ddptypes.Type, types ...ddptypes.Type) bool {
 	for _, v := range types {
@@ -835,7 +1235,7 @@ func (t *Typechecker) checkFieldAccess(Lhs *ast.Ident, originalType ddptypes.Typ
 		panic(fmt.Sprintf("non struct type (%s) passed to checkFieldAccess", originalType))
 	}
 
-	var fieldType ddptypes.Type = ddptypes.VoidType{}
+	var fieldType ddptypes.Type = ddptypes.InvalidType{}
 
 	for _, field := range structType.Fields {
 		if field.Name == Lhs.Literal.Literal {
@@ -844,14 +1244,14 @@ func (t *Typechecker) checkFieldAccess(Lhs *ast.Ident, originalType ddptypes.Typ
 		}
 	}
 
-	if ddptypes.Equal(fieldType, ddptypes.VoidType{}) {
+	if ddptypes.IsInvalid(fieldType) {
 		article := "Ein"
 		switch structType.Gender() {
 		case ddptypes.FEMININ:
 			article = "Eine"
 		}
 		t.errExpr(ddperror.TYP_BAD_FIELD_ACCESS, Lhs, "%s %s hat kein Feld mit Name %s", article, originalType.String(), Lhs.Literal.Literal)
-		return ddptypes.VoidType{}
+		return ddptypes.InvalidType{}
 	}
 
 	// if the type was imported, check for public/private fields
@@ -872,6 +1272,166 @@ func (t *Typechecker) checkFieldAccess(Lhs *ast.Ident, originalType ddptypes.Typ
 	return fieldType
 }
 
+// biggest Zahl (in absolute value) that can be represented exactly as Kommazahl
+const maxExactFloatInt = 1 << 53
+
+// warns if expr is a Zahl literal that is converted to a Kommazahl
+// but is too big to be represented exactly as such
+func (t *Typechecker) checkPrecisionLoss(expr ast.Expression) {
+	lit, isIntLit := ast.UnwrapGrouping(expr).(*ast.IntLit)
+	if !isIntLit || (lit.Value <= maxExactFloatInt && lit.Value >= -maxExactFloatInt) {
+		return
+	}
+	t.warn(ddperror.TYP_PRECISION_LOSS, lit.GetRange(), fmt.Sprintf("Möglicher Genauigkeitsverlust bei Umwandlung von Zahl zu Kommazahl, da %d betragsmäßig größer als 2^53 ist", lit.Value))
+}
+
+// warns if expr is a Kommazahl literal used as step size of a zählende-Schleife
+// that can not be represented exactly (like 0,1)
+// the counter is incremented by the rounded step size every iteration, so the error accumulates
+// and the counter might miss the end value (0,0 bis 1,0 mit Schrittgröße 0,1 ends with 0,9999999999999999)
+func (t *Typechecker) checkInexactStepSize(expr ast.Expression) {
+	expr = ast.UnwrapGrouping(expr)
+	if unary, isUnary := expr.(*ast.UnaryExpr); isUnary && unary.Operator == ast.UN_NEGATE {
+		expr = ast.UnwrapGrouping(unary.Rhs)
+	}
+	lit, isFloatLit := expr.(*ast.FloatLit)
+	if !isFloatLit {
+		return
+	}
+	exact, ok := new(big.Rat).SetString(strings.Replace(lit.Literal.Literal, ",", ".", 1))
+	if !ok || exact.Cmp(new(big.Rat).SetFloat64(lit.Value)) == 0 {
+		return
+	}
+	t.warn(ddperror.TYP_INEXACT_STEP_SIZE, lit.GetRange(), fmt.Sprintf("Die Schrittgröße %s kann nicht genau als Kommazahl dargestellt werden, die Rundungsfehler summieren sich im Zähler auf, sodass der Endwert eventuell verfehlt wird", lit.Literal.Literal))
+}
+
+// warns about comparisons like 'x gleich wahr' which can be written as 'x' or 'nicht x'
+func (t *Typechecker) checkRedundantBoolComparison(expr *ast.BinaryExpr) {
+	lit, isBoolLit := ast.UnwrapGrouping(expr.Rhs).(*ast.BoolLit)
+	if !isBoolLit {
+		if lit, isBoolLit = ast.UnwrapGrouping(expr.Lhs).(*ast.BoolLit); !isBoolLit {
+			return
+		}
+	}
+
+	suggestion := "den Ausdruck direkt"
+	if lit.Value == (expr.Operator == ast.BIN_UNEQUAL) {
+		suggestion = "'nicht' mit dem Ausdruck"
+	}
+	t.warn(ddperror.TYP_REDUNDANT_BOOL_COMPARISON, expr.GetRange(), fmt.Sprintf("Der Vergleich mit '%s' ist überflüssig, verwende stattdessen %s", lit.Literal.Literal, suggestion))
+}
+
+// reports whether expr certainly has no side effects
+// this is conservative: function calls, overloaded operators
+// and expressions that may cause a runtime error are never pure
+func (t *Typechecker) isPure(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.IntLit, *ast.FloatLit, *ast.BoolLit, *ast.CharLit, *ast.StringLit, *ast.NothingLit, *ast.EnumLit, *ast.FuncRef, *ast.Ident:
+		return true
+	case *ast.Grouping:
+		return t.isPure(expr.Expr)
+	case *ast.UnaryExpr:
+		return expr.OverloadedBy == nil && t.isPure(expr.Rhs)
+	case *ast.BinaryExpr:
+		switch expr.Operator {
+		// ABGEBILDET and GEFILTERT call the function that is passed to them
+		case ast.BIN_MAP, ast.BIN_FILTER:
+			return false
+		// out of range indices and divisions by zero are runtime errors
+		case ast.BIN_INDEX, ast.BIN_SLICE_FROM, ast.BIN_SLICE_TO, ast.BIN_DIV, ast.BIN_MOD:
+			return false
+		}
+		return expr.OverloadedBy == nil && t.isPure(expr.Lhs) && t.isPure(expr.Rhs)
+	case *ast.TernaryExpr:
+		if expr.Operator == ast.TER_SLICE {
+			return false
+		}
+		return expr.OverloadedBy == nil && t.isPure(expr.Lhs) && t.isPure(expr.Mid) && t.isPure(expr.Rhs)
+	case *ast.CastExpr:
+		// casting a Variable to the wrong type or nichts to its underlying type is a runtime error
+		if lhs := t.EvaluateSilent(expr.Lhs); ddptypes.IsAny(lhs) || ddptypes.IsOptional(lhs) {
+			return false
+		}
+		return expr.OverloadedBy == nil && t.isPure(expr.Lhs)
+	}
+	return false
+}
+
+// reports whether expr or one of its subexpressions is a BadExpr
+func containsBadExpr(expr ast.Expression) bool {
+	found := false
+	ast.VisitNode(ast.BadExprVisitorFunc(func(*ast.BadExpr) ast.VisitResult {
+		found = true
+		return ast.VisitBreak
+	}), expr, nil)
+	return found
+}
+
+// implicitly converts *expr of type typ to target if target is an optional type
+// nichts literals get target as their type and values of the
+// underlying type of target are wrapped in a cast to target
+// returns the type of *expr after the conversion
+func (t *Typechecker) convertToOptional(expr *ast.Expression, typ, target ddptypes.Type) ddptypes.Type {
+	optionalType, isOptional := ddptypes.CastOptional(target)
+	if !isOptional {
+		return typ
+	}
+
+	if isNothingLit(*expr) {
+		ast.UnwrapGrouping(*expr).(*ast.NothingLit).Type = target
+		return t.Evaluate(*expr)
+	}
+
+	if ddptypes.Equal(typ, optionalType.Underlying) {
+		*expr = &ast.CastExpr{
+			Range:      (*expr).GetRange(),
+			TargetType: target,
+			Lhs:        *expr,
+		}
+		return target
+	}
+	return typ
+}
+
+// reports wether expr is a (grouped) nichts literal
+func isNothingLit(expr ast.Expression) bool {
+	_, isNothing := ast.UnwrapGrouping(expr).(*ast.NothingLit)
+	return isNothing
+}
+
+// reports wether values of typ can be stored in a Variable
+func isValidAnyValue(typ ddptypes.Type) bool {
+	underlying := ddptypes.TrueUnderlying(typ)
+	return !ddptypes.IsVoid(typ) && !ddptypes.IsOptional(typ) && !ddptypes.IsEnum(underlying) && !ddptypes.IsFunction(underlying)
+}
+
+// reports an error if index is a Zahl literal smaller than 1
+// because indices in DDP start at 1
+func (t *Typechecker) checkLiteralIndex(index ast.Expression) {
+	if lit, isIntLit := ast.UnwrapGrouping(index).(*ast.IntLit); isIntLit && lit.Value < 1 {
+		t.errExpr(ddperror.TYP_BAD_INDEXING, index, "Indizes beginnen bei 1, aber es wurde %d als Index angegeben", lit.Value)
+	}
+}
+
+// special rules for primitive conversions
+func isValidPrimitiveCast(lhs ddptypes.Type, target ddptypes.PrimitiveType) bool {
+	if !ddptypes.IsPrimitive(lhs) {
+		return false
+	}
+
+	switch target {
+	case ddptypes.ZAHL, ddptypes.TEXT:
+		return true
+	case ddptypes.KOMMAZAHL:
+		return isOneOf(lhs, ddptypes.TEXT, ddptypes.ZAHL, ddptypes.KOMMAZAHL)
+	case ddptypes.WAHRHEITSWERT:
+		return isOneOf(lhs, ddptypes.ZAHL, ddptypes.WAHRHEITSWERT)
+	case ddptypes.BUCHSTABE:
+		return isOneOf(lhs, ddptypes.ZAHL, ddptypes.BUCHSTABE, ddptypes.TEXT)
+	}
+	return false
+}
+
 // reports wether the given type from this module of the given table is public
 // should only be called from the global scope
 // and with the SymbolTable that was in use when the type was declared
@@ -879,8 +1439,18 @@ func IsPublicType(typ ddptypes.Type, table *ast.SymbolTable) bool {
 	// a list-type is public if its underlying type is public
 	typ = ddptypes.GetNestedListUnderlying(typ)
 
-	// a struct type is public if a corresponding struct-decl is public or if it was imported from another module
-	if ddptypes.IsTypeAlias(typ) || ddptypes.IsStruct(typ) {
+	// a function type is public if all of its parameter and return types are public
+	if funcType, isFunc := ddptypes.CastFunction(typ); isFunc {
+		for _, param := range funcType.Params {
+			if !IsPublicType(param, table) {
+				return false
+			}
+		}
+		return IsPublicType(funcType.ReturnType, table)
+	}
+
+	// a struct or enum type is public if a corresponding decl is public or if it was imported from another module
+	if ddptypes.IsTypeAlias(typ) || ddptypes.IsStruct(typ) || ddptypes.IsEnum(typ) {
 		// get the corresponding decl from the current scope
 		// because it contains imported types as well
 		decl, _, _ := table.LookupDecl(typ.String())
@@ -895,6 +1465,13 @@ type operand struct {
 	expr ast.Expression
 }
 
+// reports wether ass is a Buchstabe in a Text (t an der Stelle i)
+// which can not be passed as reference, because a Text is not an array of Buchstaben
+func (t *Typechecker) isCharInText(ass ast.Assigneable) bool {
+	indexing, ok := ass.(*ast.Indexing)
+	return ok && ddptypes.Equal(t.Evaluate(indexing.Lhs), ddptypes.TEXT)
+}
+
 func (t *Typechecker) findOverload(operator ast.Operator, operands ...operand) *ast.OperatorOverload {
 	overloads := t.Module.Operators[operator]
 	if len(overloads) > 0 {
@@ -913,7 +1490,7 @@ func (t *Typechecker) findOverload(operator ast.Operator, operands ...operand) *
 				// turn arguments for reference parameters into assigneables
 				operator_overload.Args[overload.Parameters[i].Name.Literal] = operand.expr
 				if overload.Parameters[i].Type.IsReference {
-					if ass, isAssignable := isAssignable(operand.expr); isAssignable {
+					if ass, isAssignable := isAssignable(operand.expr); isAssignable && !t.isCharInText(ass) {
 						operator_overload.Args[overload.Parameters[i].Name.Literal] = ass
 					} else {
 						continue overload_loop
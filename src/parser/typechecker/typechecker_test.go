@@ -0,0 +1,330 @@
+package typechecker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ast"
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// returns a Typechecker for an empty module that passes its errors to errorHandler
+func newTestTypechecker(t *testing.T, errorHandler ddperror.Handler) *Typechecker {
+	return New(&ast.Module{
+		Ast: &ast.Ast{Symbols: ast.NewSymbolTable(nil)},
+	}, errorHandler, t.Name(), new(bool))
+}
+
+func TestPrecisionLossWarning(t *testing.T) {
+	tests := []struct {
+		lhs, rhs ast.Expression
+		operator ast.BinaryOperator
+		warns    bool
+	}{
+		{&ast.IntLit{Value: 1 << 54}, &ast.FloatLit{Value: 1.5}, ast.BIN_PLUS, true},
+		{&ast.FloatLit{Value: 1.5}, &ast.IntLit{Value: -(1 << 54)}, ast.BIN_MULT, true},
+		{&ast.IntLit{Value: 1 << 54}, &ast.IntLit{Value: 2}, ast.BIN_DIV, true},
+		{&ast.Grouping{Expr: &ast.IntLit{Value: 1 << 54}}, &ast.FloatLit{Value: 1.5}, ast.BIN_MINUS, true},
+		{&ast.IntLit{Value: 1 << 53}, &ast.FloatLit{Value: 1.5}, ast.BIN_PLUS, false},
+		{&ast.IntLit{Value: 1 << 54}, &ast.IntLit{Value: 2}, ast.BIN_PLUS, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.operator.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			var warnings []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				warnings = append(warnings, err)
+			})
+
+			given.Evaluate(&ast.BinaryExpr{Lhs: test.lhs, Operator: test.operator, Rhs: test.rhs})
+			if test.warns {
+				assert.Len(warnings, 1)
+				assert.Equal(ddperror.TYP_PRECISION_LOSS, warnings[0].Code)
+				assert.Equal(ddperror.LEVEL_WARN, warnings[0].Level)
+			} else {
+				assert.Empty(warnings)
+			}
+			assert.False(*given.panicMode)
+		})
+	}
+}
+
+func TestRedundantBoolComparisonWarning(t *testing.T) {
+	tests := []struct {
+		lhs, rhs ast.Expression
+		operator ast.BinaryOperator
+		warns    bool
+	}{
+		{&ast.BoolLit{Value: true}, &ast.BoolLit{Value: true}, ast.BIN_EQUAL, true},
+		{&ast.IntLit{Value: 1}, &ast.IntLit{Value: 2}, ast.BIN_EQUAL, false},
+		{&ast.Grouping{Expr: &ast.BoolLit{Value: false}}, &ast.BoolLit{Value: true}, ast.BIN_UNEQUAL, true},
+		{&ast.BoolLit{Value: false}, &ast.IntLit{Value: 2}, ast.BIN_EQUAL, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.operator.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			var warnings []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				if err.Level == ddperror.LEVEL_WARN {
+					warnings = append(warnings, err)
+				}
+			})
+
+			given.Evaluate(&ast.BinaryExpr{Lhs: test.lhs, Operator: test.operator, Rhs: test.rhs})
+			if test.warns {
+				assert.Len(warnings, 1)
+				assert.Equal(ddperror.TYP_REDUNDANT_BOOL_COMPARISON, warnings[0].Code)
+			} else {
+				assert.Empty(warnings)
+			}
+		})
+	}
+}
+
+func TestBuiltinOperators(t *testing.T) {
+	assert := assert.New(t)
+	signatures := BuiltinOperators()
+
+	contains := func(operator ast.Operator, result ddptypes.Type, operands ...ddptypes.Type) bool {
+		for _, signature := range signatures {
+			if signature.Operator == operator && ddptypes.Equal(signature.Result, result) && slices.EqualFunc(operands, signature.Operands, ddptypes.Equal) {
+				return true
+			}
+		}
+		return false
+	}
+
+	assert.True(contains(ast.BIN_PLUS, ddptypes.ZAHL, ddptypes.ZAHL, ddptypes.ZAHL))
+	assert.True(contains(ast.BIN_PLUS, ddptypes.KOMMAZAHL, ddptypes.ZAHL, ddptypes.KOMMAZAHL))
+	assert.True(contains(ast.UN_LEN, ddptypes.ZAHL, ddptypes.TEXT))
+	assert.True(contains(ast.BIN_MULT, ddptypes.TEXT, ddptypes.TEXT, ddptypes.ZAHL))
+	assert.True(contains(ast.BIN_MULT, ddptypes.TEXT, ddptypes.BUCHSTABE, ddptypes.ZAHL))
+	assert.False(contains(ast.BIN_MULT, ddptypes.TEXT, ddptypes.ZAHL, ddptypes.TEXT))
+	assert.False(contains(ast.BIN_MULT, ddptypes.TEXT, ddptypes.TEXT, ddptypes.KOMMAZAHL))
+	assert.True(contains(ast.BIN_MULT, ddptypes.ListType{Underlying: ddptypes.TEXT}, ddptypes.ListType{Underlying: ddptypes.TEXT}, ddptypes.ZAHL))
+	assert.True(contains(ast.BIN_MULT, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ZAHL))
+	assert.False(contains(ast.BIN_MULT, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.KOMMAZAHL))
+	assert.True(contains(ast.TER_PAD, ddptypes.ListType{Underlying: ddptypes.TEXT}, ddptypes.ListType{Underlying: ddptypes.TEXT}, ddptypes.ZAHL, ddptypes.TEXT))
+	assert.False(contains(ast.TER_PAD, ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL}, ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL}, ddptypes.ZAHL, ddptypes.ZAHL))
+	assert.False(contains(ast.TER_PAD, ddptypes.TEXT, ddptypes.TEXT, ddptypes.ZAHL, ddptypes.BUCHSTABE))
+	assert.True(contains(ast.CAST_OP, ddptypes.TEXT, ddptypes.ZAHL))
+	assert.True(contains(ast.TER_BETWEEN, ddptypes.WAHRHEITSWERT, ddptypes.ZAHL, ddptypes.KOMMAZAHL, ddptypes.ZAHL))
+	assert.True(contains(ast.TER_BETWEEN, ddptypes.WAHRHEITSWERT, ddptypes.TEXT, ddptypes.TEXT, ddptypes.TEXT))
+	assert.False(contains(ast.TER_BETWEEN, ddptypes.WAHRHEITSWERT, ddptypes.TEXT, ddptypes.BUCHSTABE, ddptypes.TEXT))
+	assert.False(contains(ast.TER_BETWEEN, ddptypes.WAHRHEITSWERT, ddptypes.WAHRHEITSWERT, ddptypes.WAHRHEITSWERT, ddptypes.WAHRHEITSWERT))
+	assert.True(contains(ast.BIN_LESS, ddptypes.WAHRHEITSWERT, ddptypes.KOMMAZAHL, ddptypes.ZAHL))
+	assert.True(contains(ast.BIN_LESS, ddptypes.WAHRHEITSWERT, ddptypes.BUCHSTABE, ddptypes.BUCHSTABE))
+	assert.True(contains(ast.BIN_GREATER_EQ, ddptypes.WAHRHEITSWERT, ddptypes.TEXT, ddptypes.TEXT))
+	assert.False(contains(ast.BIN_GREATER, ddptypes.WAHRHEITSWERT, ddptypes.TEXT, ddptypes.BUCHSTABE))
+	assert.False(contains(ast.BIN_LESS_EQ, ddptypes.WAHRHEITSWERT, ddptypes.TEXT, ddptypes.ZAHL))
+	assert.False(contains(ast.BIN_LESS, ddptypes.WAHRHEITSWERT, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ListType{Underlying: ddptypes.ZAHL}))
+	assert.True(contains(ast.BIN_EQUAL, ddptypes.WAHRHEITSWERT, ddptypes.ListType{Underlying: ddptypes.TEXT}, ddptypes.ListType{Underlying: ddptypes.TEXT}))
+	assert.True(contains(ast.BIN_INDEX, ddptypes.BUCHSTABE, ddptypes.TEXT, ddptypes.ZAHL))
+	assert.True(contains(ast.BIN_INDEX, ddptypes.ZAHL, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ZAHL))
+	assert.False(contains(ast.BIN_INDEX, ddptypes.BUCHSTABE, ddptypes.TEXT, ddptypes.KOMMAZAHL))
+	assert.True(contains(ast.BIN_UNEQUAL, ddptypes.WAHRHEITSWERT, ddptypes.BUCHSTABE, ddptypes.BUCHSTABE))
+	assert.True(contains(ast.CAST_OP, ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL}, ddptypes.ListType{Underlying: ddptypes.ZAHL}))
+	assert.True(contains(ast.CAST_OP, ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ZAHL))
+	assert.False(contains(ast.BIN_AND, ddptypes.WAHRHEITSWERT, ddptypes.ZAHL, ddptypes.ZAHL))
+	assert.False(contains(ast.CAST_OP, ddptypes.ListType{Underlying: ddptypes.BUCHSTABE}, ddptypes.ListType{Underlying: ddptypes.WAHRHEITSWERT}))
+	assert.False(contains(ast.BIN_FIELD_ACCESS, ddptypes.ZAHL, ddptypes.ZAHL, ddptypes.ZAHL))
+}
+
+func TestNegationOperators(t *testing.T) {
+	signatures := BuiltinOperators()
+
+	// returns the result type of operator applied to rhs or nil if it is not accepted
+	resultOf := func(operator ast.Operator, rhs ddptypes.Type) ddptypes.Type {
+		for _, signature := range signatures {
+			if signature.Operator == operator && len(signature.Operands) == 1 && ddptypes.Equal(signature.Operands[0], rhs) {
+				return signature.Result
+			}
+		}
+		return nil
+	}
+
+	zahlen, kommazahlen := ddptypes.ListType{Underlying: ddptypes.ZAHL}, ddptypes.ListType{Underlying: ddptypes.KOMMAZAHL}
+	operands := []ddptypes.Type{
+		ddptypes.ZAHL,
+		ddptypes.KOMMAZAHL,
+		ddptypes.WAHRHEITSWERT,
+		ddptypes.BUCHSTABE,
+		ddptypes.TEXT,
+		zahlen,
+		kommazahlen,
+		ddptypes.ListType{Underlying: ddptypes.WAHRHEITSWERT},
+	}
+	// the expected result type for each operand, nil if the operand is not accepted
+	tests := map[ast.UnaryOperator][]ddptypes.Type{
+		ast.UN_NEGATE:    {ddptypes.ZAHL, ddptypes.KOMMAZAHL, nil, nil, nil, zahlen, kommazahlen, nil},
+		ast.UN_NOT:       {nil, nil, ddptypes.WAHRHEITSWERT, nil, nil, nil, nil, nil},
+		ast.UN_LOGIC_NOT: {ddptypes.ZAHL, nil, nil, ddptypes.BUCHSTABE, nil, nil, nil, nil},
+	}
+
+	for operator, expected := range tests {
+		t.Run(operator.String(), func(t *testing.T) {
+			assert := assert.New(t)
+			for i, rhs := range operands {
+				result := resultOf(operator, rhs)
+				if expected[i] == nil {
+					assert.Nil(result, "%s", rhs)
+				} else if assert.NotNil(result, "%s", rhs) {
+					assert.True(ddptypes.Equal(expected[i], result), "%s: %s", rhs, result)
+				}
+			}
+		})
+	}
+}
+
+func TestLiteralIndexZero(t *testing.T) {
+	tests := []struct {
+		index  ast.Expression
+		errors bool
+	}{
+		{&ast.IntLit{Value: 0}, true},
+		{&ast.Grouping{Expr: &ast.IntLit{Value: 0}}, true},
+		{&ast.IntLit{Value: 1}, false},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			assert := assert.New(t)
+			var errors []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				errors = append(errors, err)
+			})
+
+			given.Evaluate(&ast.BinaryExpr{Lhs: &ast.StringLit{Value: "Hallo"}, Operator: ast.BIN_INDEX, Rhs: test.index})
+			if test.errors {
+				assert.Len(errors, 1)
+				assert.Equal(ddperror.TYP_BAD_INDEXING, errors[0].Code)
+			} else {
+				assert.Empty(errors)
+			}
+		})
+	}
+}
+
+func TestExprWithoutEffectWarning(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  ast.Expression
+		warns bool
+	}{
+		{"literal", &ast.IntLit{Value: 1}, true},
+		{"binary", &ast.BinaryExpr{Lhs: &ast.IntLit{Value: 1}, Operator: ast.BIN_PLUS, Rhs: &ast.IntLit{Value: 2}}, true},
+		{"grouping", &ast.Grouping{Expr: &ast.UnaryExpr{Operator: ast.UN_NOT, Rhs: &ast.BoolLit{Value: true}}}, true},
+		{"overloaded", &ast.UnaryExpr{Operator: ast.UN_NOT, Rhs: &ast.BoolLit{Value: true}, OverloadedBy: &ast.OperatorOverload{}}, false},
+		{"abgebildet", &ast.BinaryExpr{Lhs: &ast.ListLit{Type: ddptypes.ListType{Underlying: ddptypes.ZAHL}}, Operator: ast.BIN_MAP, Rhs: &ast.FuncRef{Func: funcDecl(ddptypes.ZAHL, ddptypes.ZAHL)}}, false},
+		{"an der Stelle", &ast.BinaryExpr{Lhs: &ast.StringLit{Value: "a"}, Operator: ast.BIN_INDEX, Rhs: &ast.IntLit{Value: 2}}, false},
+		{"durch", &ast.BinaryExpr{Lhs: &ast.IntLit{Value: 1}, Operator: ast.BIN_DIV, Rhs: &ast.IntLit{Value: 0}}, false},
+		{"modulo", &ast.BinaryExpr{Lhs: &ast.IntLit{Value: 1}, Operator: ast.BIN_MOD, Rhs: &ast.IntLit{Value: 0}}, false},
+		{"im Bereich", &ast.TernaryExpr{Lhs: &ast.StringLit{Value: "a"}, Mid: &ast.IntLit{Value: 1}, Rhs: &ast.IntLit{Value: 2}, Operator: ast.TER_SLICE}, false},
+		{"als Text", &ast.CastExpr{Lhs: &ast.IntLit{Value: 1}, TargetType: ddptypes.TEXT}, true},
+		{"Optional als Zahl", &ast.CastExpr{Lhs: &ast.NothingLit{Type: ddptypes.OptionalType{Underlying: ddptypes.ZAHL}}, TargetType: ddptypes.ZAHL}, false},
+		{"Variable als Zahl", &ast.CastExpr{Lhs: &ast.CastExpr{Lhs: &ast.IntLit{Value: 1}, TargetType: ddptypes.Variable{}}, TargetType: ddptypes.ZAHL}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			var warnings []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				if err.Level == ddperror.LEVEL_WARN {
+					warnings = append(warnings, err)
+				}
+			})
+
+			given.TypecheckNode(&ast.ExprStmt{Expr: test.expr})
+			if test.warns {
+				assert.Len(warnings, 1)
+				assert.Equal(ddperror.SEM_EXPR_WITHOUT_EFFECT, warnings[0].Code)
+			} else {
+				assert.Empty(warnings)
+			}
+		})
+	}
+}
+
+func TestPresentOperator(t *testing.T) {
+	tests := []struct {
+		rhs    ast.Expression
+		errors bool
+	}{
+		{&ast.NothingLit{Type: ddptypes.OptionalType{Underlying: ddptypes.ZAHL}}, false},
+		{&ast.Grouping{Expr: &ast.NothingLit{Type: ddptypes.OptionalType{Underlying: ddptypes.BUCHSTABE}}}, false},
+		{&ast.IntLit{Value: 1}, true},
+	}
+
+	for _, test := range tests {
+		t.Run("", func(t *testing.T) {
+			assert := assert.New(t)
+			var errors []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				errors = append(errors, err)
+			})
+
+			result := given.Evaluate(&ast.UnaryExpr{Operator: ast.UN_PRESENT, Rhs: test.rhs})
+			if test.errors {
+				assert.NotEmpty(errors)
+			} else {
+				assert.Empty(errors)
+				assert.Equal(ddptypes.WAHRHEITSWERT, result)
+			}
+		})
+	}
+}
+
+// returns a FuncDecl with the given parameter and return types
+func funcDecl(returnType ddptypes.Type, params ...ddptypes.Type) *ast.FuncDecl {
+	decl := &ast.FuncDecl{ReturnType: returnType}
+	for _, param := range params {
+		decl.Parameters = append(decl.Parameters, ast.ParameterInfo{Type: ddptypes.ParameterType{Type: param}})
+	}
+	return decl
+}
+
+func TestListCallbackOperators(t *testing.T) {
+	zahlen := ddptypes.ListType{Underlying: ddptypes.ZAHL}
+	tests := []struct {
+		name     string
+		operator ast.BinaryOperator
+		lhs      ddptypes.ListType
+		fun      *ast.FuncDecl
+		expected ddptypes.Type // nil if an error is expected
+	}{
+		{"abgebildet", ast.BIN_MAP, zahlen, funcDecl(ddptypes.TEXT, ddptypes.ZAHL), ddptypes.ListType{Underlying: ddptypes.TEXT}},
+		{"gefiltert", ast.BIN_FILTER, zahlen, funcDecl(ddptypes.WAHRHEITSWERT, ddptypes.ZAHL), zahlen},
+		{"falscher Parameter", ast.BIN_MAP, zahlen, funcDecl(ddptypes.ZAHL, ddptypes.TEXT), nil},
+		{"zu viele Parameter", ast.BIN_MAP, zahlen, funcDecl(ddptypes.ZAHL, ddptypes.ZAHL, ddptypes.ZAHL), nil},
+		{"kein Wahrheitswert", ast.BIN_FILTER, zahlen, funcDecl(ddptypes.ZAHL, ddptypes.ZAHL), nil},
+		{"nichts", ast.BIN_MAP, zahlen, funcDecl(ddptypes.VoidType{}, ddptypes.ZAHL), nil},
+		{"Liste von Listen", ast.BIN_MAP, zahlen, funcDecl(zahlen, ddptypes.ZAHL), nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert := assert.New(t)
+			var errors []ddperror.Error
+			given := newTestTypechecker(t, func(err ddperror.Error) {
+				errors = append(errors, err)
+			})
+
+			result := given.Evaluate(&ast.BinaryExpr{
+				Lhs:      &ast.ListLit{Type: test.lhs},
+				Operator: test.operator,
+				Rhs:      &ast.FuncRef{Func: test.fun},
+			})
+			if test.expected == nil {
+				assert.NotEmpty(errors)
+			} else {
+				assert.Empty(errors)
+				assert.Equal(test.expected, result)
+			}
+		})
+	}
+}
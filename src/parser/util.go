@@ -31,6 +31,17 @@ func tokenEqual(t1, t2 *token.Token) bool {
 	return true
 }
 
+// reports wether an argument of type typ is implicitly converted
+// to the optional type of the parameter (see Typechecker.convertToOptional)
+func isOptionalArgument(arg ast.Expression, typ ddptypes.Type, paramType ddptypes.ParameterType) bool {
+	optionalType, isOptional := ddptypes.CastOptional(paramType.Type)
+	if !isOptional || paramType.IsReference {
+		return false
+	}
+	_, isNothing := ast.UnwrapGrouping(arg).(*ast.NothingLit)
+	return isNothing || ddptypes.Equal(typ, optionalType.Underlying)
+}
+
 // converts b to 1 or 0
 func boolToInt(b bool) int {
 	if b {
@@ -22,6 +22,14 @@ type Options struct {
 	// ErrorHandler used during scanning
 	// May be nil
 	ErrorHandler ddperror.Handler
+	// after this many errors the scanner stops and only returns EOF
+	// so that the following passes are not flooded with errors
+	// 0 means no limit
+	MaxErrors uint
+	// Optional, counts the errors reported while scanning
+	// MaxErrors applies to this count, so sharing it between scanners
+	// (e.g. for all modules of an include chain) makes the limit global
+	ErrorCount *uint
 }
 
 func validateOptions(options *Options) error {
@@ -34,6 +42,9 @@ func validateOptions(options *Options) error {
 	if options.ErrorHandler == nil {
 		options.ErrorHandler = ddperror.EmptyHandler
 	}
+	if options.ErrorCount == nil {
+		options.ErrorCount = new(uint)
+	}
 	return nil
 }
 
@@ -47,6 +58,7 @@ func Scan(options Options) ([]token.Token, error) {
 	if scan, err := New(options.FileName, options.Source, options.ErrorHandler, options.ScannerMode); err != nil {
 		return nil, err
 	} else {
+		scan.maxErrors, scan.errorCount = options.MaxErrors, options.ErrorCount
 		return scan.ScanAll(), nil
 	}
 }
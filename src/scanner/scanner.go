@@ -33,8 +33,10 @@ type Scanner struct {
 	startLine        uint // to construct valid ranges
 	startColumn      uint // to construct valid ranges
 	indent           uint
-	shouldIndent     bool // check wether the next whitespace should be counted as indent
-	shouldCapitalize bool // check wether the next character should be capitalized
+	shouldIndent     bool  // check wether the next whitespace should be counted as indent
+	shouldCapitalize bool  // check wether the next character should be capitalized
+	maxErrors        uint  // the scanner stops after this many errors, 0 means no limit
+	errorCount       *uint // counts the errors, may be shared with other scanners
 }
 
 // returns a new scanner, or error if one could not be created
@@ -59,6 +61,8 @@ func New(filePath string, src []byte, errorHandler ddperror.Handler, mode Mode)
 		indent:           0,
 		shouldIndent:     true,
 		shouldCapitalize: true,
+		maxErrors:        0,
+		errorCount:       new(uint),
 	}
 
 	// if src is nil filePath is used to load the src from a file
@@ -85,6 +89,9 @@ func New(filePath string, src []byte, errorHandler ddperror.Handler, mode Mode)
 }
 
 // scan all tokens in the scanners source until EOF occurs
+// the result always contains the COMMENT tokens with their positions
+// so tools like formatters get the full token stream
+// (the parser filters them out itself)
 func (s *Scanner) ScanAll() []token.Token {
 	tokens := make([]token.Token, 0)
 	var tok token.Token
@@ -98,11 +105,12 @@ func (s *Scanner) ScanAll() []token.Token {
 
 // scan the next token from source
 // if all tokens were scanned it returns EOF
+// comments are returned as token.COMMENT and not skipped
 func (s *Scanner) NextToken() token.Token {
 	s.skipWhitespace()
 	s.start, s.startLine, s.startColumn = s.cur, s.line, s.column
 
-	if s.atEnd() {
+	if s.atEnd() || s.errorLimitReached() {
 		return s.newToken(token.EOF)
 	}
 
@@ -166,23 +174,28 @@ func (s *Scanner) scanEscape(quote rune) bool {
 	case 'a', 'b', 'n', 'r', 't', '\\', quote:
 		s.advance()
 		return true
-	default:
-		s.err(
-			ddperror.SYN_MALFORMED_LITERAL,
-			token.Range{
-				Start: token.Position{
-					Line:   s.line,
-					Column: s.column,
-				},
-				End: token.Position{
-					Line:   s.line,
-					Column: s.column + 2,
-				},
-			},
-			fmt.Sprintf("Unbekannte Escape Sequenz '\\%v'", s.peekNext()),
-		)
-		return false
+	case '{', '}': // used to escape interpolations in strings
+		if quote == '"' {
+			s.advance()
+			return true
+		}
 	}
+
+	s.err(
+		ddperror.SYN_MALFORMED_LITERAL,
+		token.Range{
+			Start: token.Position{
+				Line:   s.line,
+				Column: s.column,
+			},
+			End: token.Position{
+				Line:   s.line,
+				Column: s.column + 2,
+			},
+		},
+		fmt.Sprintf("Unbekannte Escape Sequenz '\\%v'", s.peekNext()),
+	)
+	return false
 }
 
 func (s *Scanner) string() token.Token {
@@ -193,6 +206,10 @@ func (s *Scanner) string() token.Token {
 			s.increaseLineBeforeAdvance()
 		} else if s.peek() == '\\' {
 			s.scanEscape('"')
+		} else if s.peek() == '{' {
+			s.advance()
+			s.skipInterpolation()
+			continue
 		}
 		s.advance()
 	}
@@ -205,6 +222,46 @@ func (s *Scanner) string() token.Token {
 	return s.newToken(token.STRING)
 }
 
+// skips the expression of an interpolation {...} inside a string
+// expects the { to be already consumed and stops after the closing }
+// strings inside the expression may contain " and nested interpolations
+func (s *Scanner) skipInterpolation() {
+	for depth := 1; depth > 0 && !s.atEnd(); {
+		switch s.peek() {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '\n':
+			s.increaseLineBeforeAdvance()
+		case '"':
+			s.advance()
+			for !s.atEnd() && s.peek() != '"' {
+				switch s.peek() {
+				case '\n':
+					s.increaseLineBeforeAdvance()
+				case '\\':
+					s.advance()
+				case '{':
+					s.advance()
+					s.skipInterpolation()
+					continue
+				}
+				s.advance()
+			}
+		case '\'':
+			s.advance()
+			for !s.atEnd() && s.peek() != '\'' {
+				if s.peek() == '\\' {
+					s.advance()
+				}
+				s.advance()
+			}
+		}
+		s.advance()
+	}
+}
+
 func (s *Scanner) char() token.Token {
 	gotBackslash := false
 	for !s.atEnd() {
@@ -356,9 +413,13 @@ func (s *Scanner) atEnd() bool {
 }
 
 func (s *Scanner) newToken(tokenType token.TokenType) token.Token {
-	if tokenType == token.DOT || tokenType == token.COLON {
+	// comments are invisible to the capitalization rules
+	// so that a comment after a . does not hide a missing capital letter
+	switch tokenType {
+	case token.DOT, token.COLON:
 		s.shouldCapitalize = true
-	} else {
+	case token.COMMENT:
+	default:
 		s.shouldCapitalize = false
 	}
 
@@ -372,6 +433,7 @@ func (s *Scanner) newToken(tokenType token.TokenType) token.Token {
 }
 
 func (s *Scanner) errorToken(msg string) token.Token {
+	s.countError()
 	return token.Token{
 		Type:      token.ILLEGAL,
 		Literal:   msg,
@@ -429,6 +491,23 @@ func (s *Scanner) err(code ddperror.Code, Range token.Range, msg string) {
 		e.Msg = fmt.Sprintf("Fehler im Alias '%s': %s", string(s.src), e.Msg)
 	}
 	s.errorHandler(e)
+	s.countError()
+}
+
+// counts an error towards s.maxErrors
+// and reports once that the limit was reached
+func (s *Scanner) countError() {
+	*s.errorCount++
+	if s.maxErrors != 0 && *s.errorCount == s.maxErrors {
+		s.errorHandler(ddperror.New(ddperror.SYN_TOO_MANY_ERRORS, ddperror.LEVEL_ERROR, s.currentRange(),
+			fmt.Sprintf("Es wurden %d Fehler gefunden, der Rest des Quellcodes wird nicht mehr gelesen", s.maxErrors), s.file),
+		)
+	}
+}
+
+// wether s.maxErrors errors were already counted
+func (s *Scanner) errorLimitReached() bool {
+	return s.maxErrors != 0 && *s.errorCount >= s.maxErrors
 }
 
 func (s *Scanner) increaseLineBeforeAdvance() {
@@ -0,0 +1,229 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DDP-Projekt/Kompilierer/src/ddperror"
+	"github.com/DDP-Projekt/Kompilierer/src/token"
+	"github.com/stretchr/testify/assert"
+)
+
+func scan(t *testing.T, src string, mode Mode) ([]token.Token, []ddperror.Error) {
+	var errors []ddperror.Error
+	tokens, err := Scan(Options{
+		FileName:    t.Name(),
+		Source:      []byte(src),
+		ScannerMode: mode,
+		ErrorHandler: func(err ddperror.Error) {
+			errors = append(errors, err)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens, errors
+}
+
+func TestScanKeepsComments(t *testing.T) {
+	assert := assert.New(t)
+	tokens, errors := scan(t, "[Modul]\nDie Zahl x ist 1. [ein\nKommentar]\n\t[eingerückt]", ModeStrictCapitalization)
+	assert.Empty(errors)
+
+	var comments []token.Token
+	for _, tok := range tokens {
+		if tok.Type == token.COMMENT {
+			comments = append(comments, tok)
+		}
+	}
+
+	if assert.Len(comments, 3) {
+		assert.Equal("[Modul]", comments[0].Literal)
+		assert.Equal(token.Range{Start: token.Position{Line: 1, Column: 1}, End: token.Position{Line: 1, Column: 8}}, comments[0].Range)
+		assert.Equal("[ein\nKommentar]", comments[1].Literal)
+		assert.Equal(token.Range{Start: token.Position{Line: 2, Column: 19}, End: token.Position{Line: 3, Column: 11}}, comments[1].Range)
+		assert.Equal(uint(1), comments[2].Indent)
+	}
+	assert.Equal(token.DIE, tokens[1].Type)
+}
+
+func TestCommentsDontAffectCapitalization(t *testing.T) {
+	tests := []struct {
+		src    string
+		errors int
+	}{
+		{"Die Zahl x ist 1. [Kommentar] die Zahl y ist 2.", 1},
+		{"Die Zahl x ist 1. [Kommentar] Die Zahl y ist 2.", 0},
+		{"[Kommentar] die Zahl y ist 2.", 1},
+		{"Die Zahl [Kommentar] x ist 1.", 0},
+		{"Die Zahl x ist 1 [Kommentar].", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.src, func(t *testing.T) {
+			_, errors := scan(t, test.src, ModeStrictCapitalization)
+			assert.Len(t, errors, test.errors)
+			for _, err := range errors {
+				assert.Equal(t, ddperror.SYN_EXPECTED_CAPITAL, err.Code)
+			}
+		})
+	}
+}
+
+func TestScanAllTokensValid(t *testing.T) {
+	tests := []string{
+		"",
+		"\n",
+		"Die Zahl x ist 1.\n",
+		"Die Zahl x ist 1.",
+		"[nur ein Kommentar]",
+		"Der Text t ist \"ein\nText\".\n\n\n",
+		"Der Text t ist \"offen",
+		"Der Buchstabe b ist 'zu lang'.",
+		"\tSchreibe x.\r\n    Schreibe y.",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			assert := assert.New(t)
+			tokens, _ := scan(t, src, ModeStrictCapitalization)
+			if assert.NotEmpty(tokens) {
+				assert.Equal(token.EOF, tokens[len(tokens)-1].Type)
+			}
+			for _, tok := range tokens {
+				assert.True(tok.IsValid(), tok.StringVerbose())
+			}
+		})
+	}
+}
+
+func TestScanAllDudenTokensValid(t *testing.T) {
+	files, err := filepath.Glob("../../lib/stdlib/Duden/*.ddp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			tokens, err := Scan(Options{FileName: file, ScannerMode: ModeStrictCapitalization})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, tok := range tokens {
+				if !tok.IsValid() {
+					t.Errorf("invalid token %s", tok.StringVerbose())
+				}
+			}
+		})
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	assert := assert.New(t)
+	src := "Die Zahl x ist 1. die Zahl y ist 2. die Zahl z ist 3. die Zahl w ist 4."
+
+	var errors []ddperror.Error
+	errorCount := uint(0)
+	options := Options{
+		FileName:    t.Name(),
+		Source:      []byte(src),
+		ScannerMode: ModeStrictCapitalization,
+		ErrorHandler: func(err ddperror.Error) {
+			errors = append(errors, err)
+		},
+		MaxErrors:  2,
+		ErrorCount: &errorCount,
+	}
+	tokens, err := Scan(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(errors, 3) {
+		assert.Equal(ddperror.SYN_EXPECTED_CAPITAL, errors[0].Code)
+		assert.Equal(ddperror.SYN_EXPECTED_CAPITAL, errors[1].Code)
+		assert.Equal(ddperror.SYN_TOO_MANY_ERRORS, errors[2].Code)
+	}
+	// the scanner stops after the second wrong "die"
+	if assert.Len(tokens, 14) {
+		assert.Equal(token.DIE, tokens[12].Type)
+		assert.Equal(token.EOF, tokens[13].Type)
+	}
+	assert.Equal(uint(2), errorCount)
+
+	// the count is shared, so a second scanner stops immediately
+	errors = nil
+	options.Source = []byte("die Zahl x ist 1.")
+	tokens, err = Scan(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(errors)
+	if assert.Len(tokens, 1) {
+		assert.Equal(token.EOF, tokens[0].Type)
+	}
+}
+
+func TestZeroTokenInvalid(t *testing.T) {
+	assert := assert.New(t)
+	assert.False((&token.Token{}).IsValid())
+	assert.False((&token.Token{Type: token.EOF}).IsValid())
+	assert.False((*token.Token)(nil).IsValid())
+}
+
+var update = flag.Bool("update", false, "overwrite the .json snapshots in testdata with the current output")
+
+// scans every .ddp file in testdata and compares the tokens
+// to the snapshot in the .json file of the same name
+// run with -update to create or update the snapshots
+func TestScanSnapshots(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.ddp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			tokens, err := Scan(Options{FileName: file, ScannerMode: ModeStrictCapitalization})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// one token per line, so that the diffs of the snapshots stay readable
+			var got bytes.Buffer
+			got.WriteString("[\n")
+			for i, tok := range tokens {
+				line, err := json.Marshal(tok)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got.WriteString("\t")
+				got.Write(line)
+				if i < len(tokens)-1 {
+					got.WriteString(",")
+				}
+				got.WriteString("\n")
+			}
+			got.WriteString("]\n")
+
+			snapshot := strings.TrimSuffix(file, ".ddp") + ".json"
+			if *update {
+				if err := os.WriteFile(snapshot, got.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			expected, err := os.ReadFile(snapshot)
+			if err != nil {
+				t.Fatalf("could not read the snapshot (run with -update to create it): %s", err)
+			}
+			assert.Equal(t, string(expected), got.String())
+		})
+	}
+}
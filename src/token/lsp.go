@@ -0,0 +1,94 @@
+// lsp.go defines helpers to convert positions in a ddp source-file
+// to and from the coordinates used by the language server protocol
+package token
+
+import "unicode/utf8"
+
+// a position as used by the language server protocol
+// Line and Character are 0-based
+// and Character is measured in utf16 code-units not characters
+type LSPPosition struct {
+	Line      uint // 0-based Line index in the corresponding file
+	Character uint // 0-based offset in utf16 code-units in the Line
+}
+
+// a range as used by the language server protocol
+// End is exclusive, which matches the End of a Range produced by the scanner
+type LSPRange struct {
+	Start LSPPosition
+	End   LSPPosition
+}
+
+// converts p to a LSPPosition
+// line must be the source text of the line p is in (without the line break)
+// columns behind the end of line are counted as one code-unit each
+func (p Position) ToLSP(line string) LSPPosition {
+	if !p.IsValid() {
+		return LSPPosition{}
+	}
+
+	character, column := uint(0), uint(1)
+	for len(line) > 0 && column < p.Column {
+		r, w := utf8.DecodeRuneInString(line)
+		line = line[w:]
+		character += runeUTF16Len(r)
+		column++
+	}
+	return LSPPosition{
+		Line:      p.Line - 1,
+		Character: character + (p.Column - column),
+	}
+}
+
+// converts pos to a Position
+// line must be the source text of the line pos is in (without the line break)
+// a Character in the middle of a surrogate pair refers to the character of the pair
+// Characters behind the end of line are counted as one column each
+func PositionFromLSP(pos LSPPosition, line string) Position {
+	character, column := uint(0), uint(1)
+	for len(line) > 0 {
+		r, w := utf8.DecodeRuneInString(line)
+		// pos.Character is inside of r
+		if character+runeUTF16Len(r) > pos.Character {
+			return Position{Line: pos.Line + 1, Column: column}
+		}
+		line = line[w:]
+		character += runeUTF16Len(r)
+		column++
+	}
+	return Position{
+		Line:   pos.Line + 1,
+		Column: column + (pos.Character - character),
+	}
+}
+
+// converts r to a LSPRange
+// startLine and endLine must be the source text of the lines
+// r.Start and r.End are in (they are the same for single-line ranges)
+func (r Range) ToLSP(startLine, endLine string) LSPRange {
+	return LSPRange{
+		Start: r.Start.ToLSP(startLine),
+		End:   r.End.ToLSP(endLine),
+	}
+}
+
+// converts r to a Range
+// startLine and endLine must be the source text of the lines
+// r.Start and r.End are in (they are the same for single-line ranges)
+func RangeFromLSP(r LSPRange, startLine, endLine string) Range {
+	return Range{
+		Start: PositionFromLSP(r.Start, startLine),
+		End:   PositionFromLSP(r.End, endLine),
+	}
+}
+
+// the number of utf16 code-units needed to encode r
+// invalid utf8 is decoded as utf8.RuneError, which (like in the scanner)
+// counts as a single character
+func runeUTF16Len(r rune) uint {
+	// characters outside the basic multilingual plane are encoded as surrogate pair
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,54 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionToLSP(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(LSPPosition{Line: 0, Character: 0}, Position{Line: 1, Column: 1}.ToLSP("Die Zahl x ist 1."))
+	assert.Equal(LSPPosition{Line: 2, Character: 4}, Position{Line: 3, Column: 5}.ToLSP("Die Zahl x ist 1."))
+	// ä is one utf16 code-unit but two bytes
+	assert.Equal(LSPPosition{Line: 0, Character: 5}, Position{Line: 1, Column: 6}.ToLSP("Die Länge"))
+	// 😀 is a surrogate pair
+	assert.Equal(LSPPosition{Line: 0, Character: 3}, Position{Line: 1, Column: 3}.ToLSP("\"😀\" ist ein Text"))
+	assert.Equal(LSPPosition{Line: 0, Character: 4}, Position{Line: 1, Column: 4}.ToLSP("\"😀\" ist ein Text"))
+	// behind the end of the line
+	assert.Equal(LSPPosition{Line: 0, Character: 4}, Position{Line: 1, Column: 4}.ToLSP("ä😀"))
+	assert.Equal(LSPPosition{Line: 0, Character: 2}, Position{Line: 1, Column: 3}.ToLSP(""))
+	// invalid positions
+	assert.Equal(LSPPosition{}, Position{}.ToLSP("abc"))
+}
+
+func TestPositionFromLSP(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(Position{Line: 1, Column: 1}, PositionFromLSP(LSPPosition{Line: 0, Character: 0}, "Die Zahl x ist 1."))
+	assert.Equal(Position{Line: 3, Column: 5}, PositionFromLSP(LSPPosition{Line: 2, Character: 4}, "Die Zahl x ist 1."))
+	assert.Equal(Position{Line: 1, Column: 6}, PositionFromLSP(LSPPosition{Line: 0, Character: 5}, "Die Länge"))
+	assert.Equal(Position{Line: 1, Column: 3}, PositionFromLSP(LSPPosition{Line: 0, Character: 3}, "\"😀\" ist ein Text"))
+	// in the middle of the surrogate pair
+	assert.Equal(Position{Line: 1, Column: 2}, PositionFromLSP(LSPPosition{Line: 0, Character: 2}, "\"😀\" ist ein Text"))
+	// behind the end of the line
+	assert.Equal(Position{Line: 1, Column: 3}, PositionFromLSP(LSPPosition{Line: 0, Character: 3}, "ä😀"))
+	assert.Equal(Position{Line: 1, Column: 4}, PositionFromLSP(LSPPosition{Line: 0, Character: 3}, ""))
+}
+
+func TestRangeLSPRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	line := "Der Text t ist \"Grüße 😀🎉\"."
+	rang := Range{Start: Position{Line: 4, Column: 16}, End: Position{Line: 4, Column: 27}}
+	lspRange := rang.ToLSP(line, line)
+	assert.Equal(LSPRange{Start: LSPPosition{Line: 3, Character: 15}, End: LSPPosition{Line: 3, Character: 28}}, lspRange)
+	assert.Equal(rang, RangeFromLSP(lspRange, line, line))
+
+	startLine, endLine := "Der Text t ist \"ä", "😀\"."
+	rang = Range{Start: Position{Line: 1, Column: 16}, End: Position{Line: 2, Column: 4}}
+	lspRange = rang.ToLSP(startLine, endLine)
+	assert.Equal(LSPRange{Start: LSPPosition{Line: 0, Character: 15}, End: LSPPosition{Line: 1, Character: 4}}, lspRange)
+	assert.Equal(rang, RangeFromLSP(lspRange, startLine, endLine))
+}
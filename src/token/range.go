@@ -29,6 +29,11 @@ func (p Position) IsBehind(pos Position) bool {
 	return p.Line == pos.Line && p.Column > pos.Column
 }
 
+// wether p was set (Line and Column are 1-based, so 0 is invalid)
+func (p Position) IsValid() bool {
+	return p.Line != 0 && p.Column != 0
+}
+
 func (p Position) String() string {
 	return fmt.Sprintf("Pos{L: %d C: %d}", p.Line, p.Column)
 }
@@ -36,13 +41,50 @@ func (p Position) String() string {
 // a range in a ddp source-file
 type Range struct {
 	Start Position // First Character position in the Range
-	End   Position // Last Character position in the Range
+	End   Position // Position behind the last Character in the Range (exclusive)
 }
 
 func (r Range) String() string {
 	return fmt.Sprintf("Range{Start: %s End: %s}", r.Start, r.End)
 }
 
+// wether pos lies in r
+// End is exclusive, so empty ranges contain no position
+// reversed ranges (End before Start) are treated like their normalized counterpart
+func (r Range) Contains(pos Position) bool {
+	r = r.normalized()
+	return !pos.IsBefore(r.Start) && pos.IsBefore(r.End)
+}
+
+// returns the smallest range that spans both a and b
+// reversed ranges are normalized first
+// ranges whose Start is not valid (e.g. the zero Range) are ignored
+func Merge(a, b Range) Range {
+	if !a.Start.IsValid() {
+		return b.normalized()
+	}
+	if !b.Start.IsValid() {
+		return a.normalized()
+	}
+
+	a, b = a.normalized(), b.normalized()
+	if b.Start.IsBefore(a.Start) {
+		a.Start = b.Start
+	}
+	if b.End.IsBehind(a.End) {
+		a.End = b.End
+	}
+	return a
+}
+
+// returns r with Start and End swapped if End comes before Start
+func (r Range) normalized() Range {
+	if r.End.IsBefore(r.Start) {
+		r.Start, r.End = r.End, r.Start
+	}
+	return r
+}
+
 // creates a new range from the first character of begin
 // to the last character of end
 func NewRange(begin, end *Token) Range {
@@ -0,0 +1,69 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newRange(startLine, startCol, endLine, endCol uint) Range {
+	return Range{
+		Start: Position{Line: startLine, Column: startCol},
+		End:   Position{Line: endLine, Column: endCol},
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	assert := assert.New(t)
+
+	single := newRange(1, 5, 1, 9)
+	assert.True(single.Contains(Position{Line: 1, Column: 5}))
+	assert.True(single.Contains(Position{Line: 1, Column: 8}))
+	// End is exclusive
+	assert.False(single.Contains(Position{Line: 1, Column: 9}))
+	assert.False(single.Contains(Position{Line: 1, Column: 4}))
+	assert.False(single.Contains(Position{Line: 2, Column: 6}))
+
+	multi := newRange(2, 10, 4, 3)
+	assert.True(multi.Contains(Position{Line: 2, Column: 10}))
+	assert.True(multi.Contains(Position{Line: 2, Column: 80}))
+	assert.True(multi.Contains(Position{Line: 3, Column: 1}))
+	assert.True(multi.Contains(Position{Line: 4, Column: 2}))
+	assert.False(multi.Contains(Position{Line: 2, Column: 9}))
+	assert.False(multi.Contains(Position{Line: 4, Column: 3}))
+	assert.False(multi.Contains(Position{Line: 1, Column: 20}))
+
+	// empty ranges contain nothing
+	assert.False(newRange(1, 5, 1, 5).Contains(Position{Line: 1, Column: 5}))
+	assert.False(Range{}.Contains(Position{}))
+
+	// reversed ranges behave like the normalized range
+	reversed := newRange(4, 3, 2, 10)
+	assert.True(reversed.Contains(Position{Line: 3, Column: 1}))
+	assert.True(reversed.Contains(Position{Line: 2, Column: 10}))
+	assert.False(reversed.Contains(Position{Line: 4, Column: 3}))
+}
+
+func TestMerge(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(newRange(1, 1, 1, 10), Merge(newRange(1, 1, 1, 3), newRange(1, 8, 1, 10)))
+	// the order of the arguments does not matter
+	assert.Equal(newRange(1, 1, 1, 10), Merge(newRange(1, 8, 1, 10), newRange(1, 1, 1, 3)))
+	// overlapping and contained ranges
+	assert.Equal(newRange(1, 1, 1, 10), Merge(newRange(1, 1, 1, 7), newRange(1, 5, 1, 10)))
+	assert.Equal(newRange(1, 1, 1, 10), Merge(newRange(1, 1, 1, 10), newRange(1, 4, 1, 6)))
+	// multiple lines
+	assert.Equal(newRange(2, 10, 5, 3), Merge(newRange(2, 10, 3, 1), newRange(4, 1, 5, 3)))
+	assert.Equal(newRange(2, 10, 5, 3), Merge(newRange(3, 1, 5, 3), newRange(2, 10, 2, 20)))
+
+	// empty ranges still extend the result
+	assert.Equal(newRange(1, 1, 2, 1), Merge(newRange(1, 1, 1, 3), newRange(2, 1, 2, 1)))
+	// reversed ranges are normalized
+	assert.Equal(newRange(1, 1, 1, 10), Merge(newRange(1, 3, 1, 1), newRange(1, 10, 1, 8)))
+	assert.Equal(newRange(1, 2, 1, 5), Merge(newRange(1, 5, 1, 2), Range{}))
+	// zero ranges are ignored
+	assert.Equal(newRange(1, 2, 1, 5), Merge(Range{}, newRange(1, 2, 1, 5)))
+	assert.Equal(newRange(1, 2, 1, 5), Merge(newRange(1, 2, 1, 5), Range{}))
+	assert.Equal(Range{}, Merge(Range{}, Range{}))
+}
@@ -1,6 +1,7 @@
 package token
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/DDP-Projekt/Kompilierer/src/ddptypes"
@@ -31,6 +32,31 @@ func (t *Token) StringVerbose() string {
 	return fmt.Sprintf("[L: %d C: %d I: %d Lit: \"%s\"] Type: %s", t.Range.Start.Line, t.Range.Start.Column, t.Indent, t.Literal, t.Type)
 }
 
+// the json representation of a Token, used for snapshots in tests
+// Indent and AliasInfo are not included
+type tokenJSON struct {
+	Type    string `json:"type"`    // t.Type.String()
+	Literal string `json:"literal"` // t.Literal
+	Range   string `json:"range"`   // "Zeile:Spalte-Zeile:Spalte"
+}
+
+// implements json.Marshaler
+// the output is meant to be compact and stable
+func (t Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tokenJSON{
+		Type:    t.Type.String(),
+		Literal: t.Literal,
+		Range:   fmt.Sprintf("%d:%d-%d:%d", t.Range.Start.Line, t.Range.Start.Column, t.Range.End.Line, t.Range.End.Column),
+	})
+}
+
+// reports wether t has a valid Range
+// which is the case for every token produced by the scanner
+// but not for the zero value of Token
+func (t *Token) IsValid() bool {
+	return t != nil && t.Range.Start.IsValid() && t.Range.End.IsValid() && !t.Range.End.IsBefore(t.Range.Start)
+}
+
 // t.Range.Start.Line
 func (t *Token) Line() uint {
 	return t.Range.Start.Line
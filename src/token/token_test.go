@@ -0,0 +1,42 @@
+package token
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTypeStringsDistinct(t *testing.T) {
+	assert := assert.New(t)
+
+	seen := make(map[string]TokenType, len(tokenStrings))
+	for i := range tokenStrings {
+		typ := TokenType(i)
+		str := typ.String()
+		if assert.NotEmpty(str, "TokenType %d has no string", i) {
+			other, ok := seen[str]
+			assert.False(ok, "TokenType %d and %d have the same string %q", other, typ, str)
+			seen[str] = typ
+		}
+	}
+	assert.Equal(ELIPSIS, TokenType(len(tokenStrings)-1))
+}
+
+func TestTokenMarshalJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	tok := Token{
+		Type:    STRING,
+		Literal: `"Hallo"`,
+		Indent:  1,
+		Range:   Range{Start: Position{Line: 2, Column: 5}, End: Position{Line: 2, Column: 12}},
+	}
+	got, err := json.Marshal(tok)
+	assert.NoError(err)
+	assert.Equal(`{"type":"ein Text","literal":"\"Hallo\"","range":"2:5-2:12"}`, string(got))
+
+	got, err = json.Marshal([]Token{{Type: EOF}})
+	assert.NoError(err)
+	assert.Equal(`[{"type":"EOF","literal":"","range":"0:0-0:0"}]`, string(got))
+}
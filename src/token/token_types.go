@@ -40,6 +40,9 @@ const (
 	LÄNGE        // Länge von
 	KONTRA       // kontra
 	VERKETTET    // verkettet mit
+	ABGEBILDET   // abgebildet mit
+	GEFILTERT    // gefiltert mit
+	AUFGEFÜLLT   // aufgefüllt bis zur Länge <n> mit <w>
 	ERHÖHE       // +=
 	VERRINGERE   // -=
 	VERVIELFACHE // *=
@@ -154,6 +157,11 @@ const (
 	VARIABLEN
 	WIRD
 	SPÄTER
+	VIELLEICHT
+	VORHANDEN
+	AUFZÄHLUNG
+	SICHER
+	DASS
 
 	DOT     // .
 	COMMA   // ,
@@ -203,6 +211,9 @@ var tokenStrings = [...]string{
 	LÄNGE:        "Länge",
 	KONTRA:       "kontr",
 	VERKETTET:    "verkettet",
+	ABGEBILDET:   "abgebildet",
+	GEFILTERT:    "gefiltert",
+	AUFGEFÜLLT:   "aufgefüllt",
 	ERHÖHE:       "Erhöhe",
 	VERRINGERE:   "Verringere",
 	VERVIELFACHE: "Vervielfache",
@@ -317,6 +328,11 @@ var tokenStrings = [...]string{
 	KEINE:         "keine",
 	WIRD:          "wird",
 	SPÄTER:        "später",
+	VIELLEICHT:    "vielleicht",
+	VORHANDEN:     "vorhanden",
+	AUFZÄHLUNG:    "Aufzählung",
+	SICHER:        "sicher",
+	DASS:          "dass",
 
 	DOT:     ".",
 	COMMA:   ",",
@@ -423,6 +439,10 @@ var KeywordMap = map[string]TokenType{
 	"Ergebnis":       ERGEBNIS,
 	"in":             IN,
 	"verkettet":      VERKETTET,
+	"abgebildet":     ABGEBILDET,
+	"gefiltert":      GEFILTERT,
+	"aufgefüllt":     AUFGEFÜLLT,
+	"aufgefuellt":    AUFGEFÜLLT,
 	"erhöhe":         ERHÖHE,
 	"erhoehe":        ERHÖHE,
 	"verringere":     VERRINGERE,
@@ -485,6 +505,12 @@ var KeywordMap = map[string]TokenType{
 	"wird":           WIRD,
 	"später":         SPÄTER,
 	"spaeter":        SPÄTER,
+	"vielleicht":     VIELLEICHT,
+	"vorhanden":      VORHANDEN,
+	"Aufzählung":     AUFZÄHLUNG,
+	"Aufzaehlung":    AUFZÄHLUNG,
+	"sicher":         SICHER,
+	"dass":           DASS,
 }
 
 func KeywordToTokenType(keyword string) TokenType {
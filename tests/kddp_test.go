@@ -2,12 +2,14 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -81,6 +83,270 @@ func TestMemory(t *testing.T) {
 	})
 }
 
+// compiles some tests twice to llvm-ir and checks that both outputs are identical
+func TestDeterministicIR(t *testing.T) {
+	for _, name := range []string{"enums", "string_compare", "list_repeat"} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join("testdata/kddp", name, name+".ddp")
+			compile := func(out string) string {
+				ctx, cf := context.WithTimeout(context.Background(), time.Second*10)
+				defer cf()
+				cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "kompiliere", path, "-o", out)
+				if output, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("compilation failed: %s\ncompiler output: %s", err, string(output))
+				}
+				defer os.Remove(out)
+				ir, err := os.ReadFile(out)
+				if err != nil {
+					t.Fatalf("Error reading %s: %s", out, err)
+				}
+				return string(ir)
+			}
+
+			first, second := compile(changeExtension(path, ".1.ll")), compile(changeExtension(path, ".2.ll"))
+			if first != second {
+				t.Errorf("Compiling %s twice did not yield the same llvm-ir", path)
+			}
+			if !strings.Contains(first, "@.str.") {
+				t.Errorf("The llvm-ir of %s does not contain any content-named string constants", path)
+			}
+		})
+	}
+}
+
+// dumps a single function and checks that only it and what it references is in the llvm-ir
+func TestDumpFunction(t *testing.T) {
+	ctx, cf := context.WithTimeout(context.Background(), time.Second*10)
+	defer cf()
+	cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "dump-func", "testdata/kddp/argument_order/argument_order.ddp", "--name", "merke")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("dump-func failed: %s\noutput: %s", err, string(out))
+	}
+
+	ir := string(out)
+	if defines := strings.Count(ir, "\ndefine "); defines != 1 {
+		t.Errorf("Expected only merke to be defined, but found %d definitions", defines)
+	}
+	if !strings.Contains(ir, "@merke_mod_") {
+		t.Errorf("The function merke is missing in the llvm-ir")
+	}
+	if !strings.Contains(ir, "@reihenfolge_mod_") {
+		t.Errorf("The global reihenfolge used by merke is missing in the llvm-ir")
+	}
+	if strings.Contains(ir, "verbinde") || strings.Contains(ir, "ddp_ddpmain") {
+		t.Errorf("The llvm-ir contains functions not used by merke")
+	}
+}
+
+// checks that kddp exits with the code documented for the category of the reported error
+func TestExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	other := filepath.Join(dir, "other.ddp")
+	if err := os.WriteFile(other, []byte("2.\n"), os.ModePerm); err != nil {
+		t.Fatalf("Error writing %s: %s", other, err)
+	}
+	tests := map[string]struct {
+		src  string // written to <name>.ddp unless empty
+		args []string
+		code int
+	}{
+		"Syntax":      {"Die Zahl x ist.\n", []string{"check"}, 2},
+		"Semantik":    {"Speichere 1 in x.\n", []string{"check"}, 3},
+		"Typ":         {"Die Zahl x ist \"a\".\n", []string{"kompiliere", "-o", filepath.Join(dir, "Typ.ll")}, 4},
+		"Datei":       {"", []string{"check"}, 5},
+		"Argumente":   {"1.\n", []string{"check", "--unbekannt"}, 1},
+		"Folgefehler": {"Die Zahl x ist.\nDie Zahl y ist \"a\".\n", []string{"check"}, 2},
+		"Unterschied": {"1.\n", []string{"ast-diff", other}, 1},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, name+".ddp")
+			if test.src != "" {
+				if err := os.WriteFile(path, []byte(test.src), os.ModePerm); err != nil {
+					t.Fatalf("Error writing %s: %s", path, err)
+				}
+			}
+
+			ctx, cf := context.WithTimeout(context.Background(), time.Second*10)
+			defer cf()
+			args := append(append([]string{}, test.args...), path)
+			out, err := exec.CommandContext(ctx, "../build/DDP/bin/kddp", args...).CombinedOutput()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("kddp did not fail: %v\noutput: %s", err, string(out))
+			}
+			if got := exitErr.ExitCode(); got != test.code {
+				t.Errorf("Expected exit code %d but got %d\noutput: %s", test.code, got, string(out))
+			}
+		})
+	}
+}
+
+// runs a repl session and checks that earlier inputs with side effects are not repeated
+func TestRepl(t *testing.T) {
+	ctx, cf := context.WithTimeout(context.Background(), time.Minute)
+	defer cf()
+	cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "repl")
+	cmd.Stdin = strings.NewReader(`Die Zahl x ist 1.
+Schreibe "a".
+Speichere x plus 1 in x.
+x
+Binde "Duden/Mathe" ein.
+Die Zahl v ist das kleinste gemeinsame Vielfache von x und 3.
+Schreibe "b".
+x plus v
+Die Zahlen Liste l ist eine Liste, die aus 1 besteht.
+l an der Stelle 5
+x
+`)
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("kddp repl failed: %s\nstderr: %s", err, stderr.String())
+	}
+
+	// the previous inputs are not executed again, so "a" is only written once
+	// the runtime error ends the session, so x is unknown afterwards
+	if expected := "ddp> ddp> addp> ddp> 2\nddp> ddp> ddp> bddp> 8\nddp> ddp> ddp> ddp> \n"; stdout.String() != expected {
+		t.Errorf("Unexpected output %q, expected %q", stdout.String(), expected)
+	}
+	if !strings.Contains(stderr.String(), "die Sitzung wurde zurückgesetzt") {
+		t.Errorf("The session was not reset after the runtime error\nstderr: %s", stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "Der Name 'x' wurde noch nicht als Variable deklariert") {
+		t.Errorf("The session still knew x after the reset\nstderr: %s", stderr.String())
+	}
+}
+
+// runs a program with kddp starte --memstats and checks the reported statistics
+func TestMemStats(t *testing.T) {
+	stdout, stderr := runWithMemStats(t, `Binde "Duden/Ausgabe" ein.
+Die Text Liste l ist eine Liste, die aus "a", "b", "c" besteht.
+Für jede Zahl i von 1 bis 3, mache:
+	Der Text t ist "x" verkettet mit (i als Text).
+	Schreibe t.
+`)
+
+	if stdout != "x1x2x3" {
+		t.Errorf("Unexpected program output: %q", stdout)
+	}
+	// the list and its 3 elements plus t and the temporary Text of i als Text
+	expected := `Speicher-Statistik: ddpstringlist: höchstens 1 gleichzeitig, 0 nie freigegeben
+Speicher-Statistik: ddpstring: höchstens 5 gleichzeitig, 0 nie freigegeben
+`
+	if stderr != expected {
+		t.Errorf("Unexpected memory statistics:\n%s\nexpected:\n%s", stderr, expected)
+	}
+}
+
+// discarded literals are freed at the end of their statement, even in the global scope
+func TestDiscardedLiteralsFreed(t *testing.T) {
+	_, stderr := runWithMemStats(t, `"hallo".
+"hallo".
+"hallo".
+Eine Liste, die aus "a", "b" besteht.
+`)
+
+	// only the list and its elements live at the same time
+	expected := `Speicher-Statistik: ddpstring: höchstens 2 gleichzeitig, 0 nie freigegeben
+Speicher-Statistik: ddpstringlist: höchstens 1 gleichzeitig, 0 nie freigegeben
+`
+	if stderr != expected {
+		t.Errorf("Unexpected memory statistics:\n%s\nexpected:\n%s", stderr, expected)
+	}
+}
+
+// values that are still alive when the program is ended from inside a function are never freed
+// and reported by programs compiled with --speicher-profil
+func TestMemoryProfile(t *testing.T) {
+	dir := t.TempDir()
+	path, exe := filepath.Join(dir, "profile.ddp"), filepath.Join(dir, "profile.exe")
+	src := `Binde "Duden/Ausgabe" ein.
+Binde "Duden/Laufzeit" ein.
+Die Funktion f gibt nichts zurück, macht:
+	Der Text t ist "a" verkettet mit "b".
+	Die Zahlen Liste l ist eine Liste, die aus 1, 2 besteht.
+	Schreibe t.
+	Beende das Programm.
+Und kann so benutzt werden:
+	"f"
+f.
+`
+	if err := os.WriteFile(path, []byte(src), os.ModePerm); err != nil {
+		t.Fatalf("Error writing %s: %s", path, err)
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), time.Second*time.Duration(timeout))
+	defer cf()
+	cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "kompiliere", path, "-o", exe, "--speicher-profil")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("compilation failed: %s\ncompiler output: %s", err, string(output))
+	}
+
+	// returns the lines the program wrote to stderr in sorted order
+	// as the order of the types in the report is not specified
+	run := func(env ...string) []string {
+		ctx, cf := context.WithTimeout(context.Background(), time.Second*time.Duration(timeout))
+		defer cf()
+		cmd := exec.CommandContext(ctx, exe)
+		cmd.Env = append(os.Environ(), env...)
+		var stdout, stderr strings.Builder
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			t.Fatalf("%s failed: %s\noutput: %s%s", exe, err, stdout.String(), stderr.String())
+		}
+		if stdout.String() != "ab" {
+			t.Errorf("Unexpected program output: %q", stdout.String())
+		}
+		lines := strings.Split(strings.TrimSuffix(stderr.String(), "\n"), "\n")
+		slices.Sort(lines)
+		return lines
+	}
+
+	// the Texte and the Zahlen Liste created by f
+	expected := []string{
+		"Speicher-Profil: 1 ddpintlist nie freigegeben",
+		"Speicher-Profil: 2 ddpstring nie freigegeben",
+	}
+	if profile := run(); !slices.Equal(profile, expected) {
+		t.Errorf("Unexpected memory profile:\n%s\nexpected:\n%s", strings.Join(profile, "\n"), strings.Join(expected, "\n"))
+	}
+
+	// DDP_SPEICHER_STATISTIK replaces the profile with the statistics of kddp starte --memstats
+	expected = []string{
+		"Speicher-Statistik: ddpintlist: höchstens 1 gleichzeitig, 1 nie freigegeben",
+		"Speicher-Statistik: ddpstring: höchstens 3 gleichzeitig, 2 nie freigegeben",
+	}
+	if statistics := run("DDP_SPEICHER_STATISTIK=1"); !slices.Equal(statistics, expected) {
+		t.Errorf("Unexpected memory statistics:\n%s\nexpected:\n%s", strings.Join(statistics, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// runs src with kddp starte --memstats and returns the stdout and stderr of the program
+// the warnings of kddp are ignored
+func runWithMemStats(t *testing.T, src string) (string, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "memstats.ddp")
+	if err := os.WriteFile(path, []byte(src), os.ModePerm); err != nil {
+		t.Fatalf("Error writing %s: %s", path, err)
+	}
+
+	ctx, cf := context.WithTimeout(context.Background(), time.Second*time.Duration(timeout))
+	defer cf()
+	cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "starte", "--memstats", "--ignoriere-warnungen", "2027", path)
+	var stdout, stderr strings.Builder
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("kddp starte failed: %s\noutput: %s%s", err, stdout.String(), stderr.String())
+	}
+	return stdout.String(), stderr.String()
+}
+
 func TestBuildExamples(t *testing.T) {
 	root := "../examples"
 	if err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
@@ -158,9 +424,14 @@ func runTests(t *testing.T, ignoreFile, path, root string, d fs.DirEntry, err er
 		ddp_path := filepath.Join(path, filepath.Base(path)) + ".ddp"
 
 		// build dpp file
+		args := []string{"kompiliere", changeExtension(ddp_path, ".ddp"), "-o", changeExtension(ddp_path, ".exe"), "--wortreich"}
+		// read additional kddp arguments (separated by spaces)
+		if extraArgs, err := os.ReadFile(filepath.Join(path, "kddp_args.txt")); err == nil {
+			args = append(args, strings.Fields(string(extraArgs))...)
+		}
 		ctx, cf := context.WithTimeout(context.Background(), time.Second*10)
 		defer cf()
-		cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", "kompiliere", changeExtension(ddp_path, ".ddp"), "-o", changeExtension(ddp_path, ".exe"), "--wortreich")
+		cmd := exec.CommandContext(ctx, "../build/DDP/bin/kddp", args...)
 		// get build output
 		if out, err := cmd.CombinedOutput(); err != nil {
 			if err := ctx.Err(); err != nil {
@@ -195,8 +466,31 @@ func runTests(t *testing.T, ignoreFile, path, root string, d fs.DirEntry, err er
 		}
 		defer input.Close() // close input file
 
+		// read additional environment variables (one NAME=value per line)
+		if env, err := os.ReadFile(filepath.Join(path, "env.txt")); err == nil {
+			cmd.Env = append(os.Environ(), strings.Split(strings.TrimSpace(string(env)), "\n")...)
+		}
+
+		// read the expected exit code if the programm is expected to fail
+		expectedExitCode := 0
+		if exitCode, err := os.ReadFile(filepath.Join(path, "exit_code.txt")); err == nil {
+			if testMemory {
+				return // failing programs don't free their memory
+			}
+			if expectedExitCode, err = strconv.Atoi(strings.TrimSpace(string(exitCode))); err != nil {
+				t.Errorf("Could not parse expected exit code: %s", err)
+				return
+			}
+		}
+
 		// get output
 		out, err := cmd.CombinedOutput()
+		if exitErr, ok := err.(*exec.ExitError); ok && expectedExitCode != 0 && exitErr.ExitCode() == expectedExitCode {
+			err = nil
+		} else if err == nil && expectedExitCode != 0 {
+			t.Errorf("Program exited with 0 instead of %d", expectedExitCode)
+			return
+		}
 		if err != nil {
 			if err := ctx.Err(); err != nil {
 				t.Errorf("context error: %s", err)